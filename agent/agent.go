@@ -12,6 +12,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/rest/client"
 	"github.com/evergreen-ci/evergreen/subprocess"
+	"github.com/evergreen-ci/evergreen/tracing"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
@@ -35,6 +36,7 @@ type Options struct {
 	HeartbeatInterval  time.Duration
 	AgentSleepInterval time.Duration
 	Cleanup            bool
+	MaxConcurrentTasks int
 }
 
 type taskContext struct {
@@ -46,6 +48,12 @@ type taskContext struct {
 	taskDirectory  string
 	timeout        time.Duration
 	timedOut       bool
+	// lastStatus is the status of the last command that ran, which
+	// `if:` conditions can check.
+	lastStatus string
+	// trace is the span of the task's dispatch, which the task's spans
+	// continue from.
+	trace tracing.SpanContext
 	sync.RWMutex
 }
 
@@ -72,6 +80,17 @@ func (a *Agent) Start(ctx context.Context) error {
 	return errors.Wrap(a.loop(ctx), "error in agent loop, exiting")
 }
 
+// RunTask runs a single task, the same way as the agent loop runs the tasks
+// it gets from the API server, and returns once the task is finished. It is
+// used to run a task on a developer's machine with a local communicator.
+func (a *Agent) RunTask(ctx context.Context, td client.TaskData) error {
+	tc := &taskContext{
+		task:   td,
+		logger: a.comm.GetLoggerProducer(ctx, td),
+	}
+	return errors.WithStack(a.runTask(ctx, tc))
+}
+
 func (a *Agent) loop(ctx context.Context) error {
 	agentSleepInterval := defaultAgentSleepInterval
 	if a.opts.AgentSleepInterval != 0 {
@@ -92,34 +111,72 @@ func (a *Agent) loop(ctx context.Context) error {
 	tskCtx, cancel = context.WithCancel(ctx)
 	defer cancel()
 
+	// tasks run in their own goroutines, and the agent only asks for
+	// another task while it has a free slot.
+	slots := a.taskSlots()
+	running := 0
+	finished := make(chan error, slots)
+
+	// before returning, the agent waits for the tasks still running in
+	// other slots, since canceling them would leave them without being
+	// ended.
+	drain := func(err error) error {
+		catcher := grip.NewBasicCatcher()
+		catcher.Add(err)
+		for ; running > 0; running-- {
+			catcher.Add(<-finished)
+		}
+		return catcher.Resolve()
+	}
+
 	timer := time.NewTimer(0)
 	defer timer.Stop()
 	for {
 		select {
 		case <-ctx.Done():
 			grip.Info("agent loop canceled")
+			grip.Warning(message.WrapError(drain(nil), message.Fields{
+				"message": "problem running tasks while the agent loop was canceled",
+			}))
 			return nil
+		case err := <-finished:
+			running--
+			if err != nil {
+				return errors.WithStack(drain(err))
+			}
+			timer.Reset(0)
 		case <-timer.C:
+			if running >= slots {
+				continue
+			}
 			nextTask, err := a.comm.GetNextTask(ctx)
 			if err != nil {
-				return errors.Wrap(err, "error getting next task")
+				return drain(errors.Wrap(err, "error getting next task"))
 			}
 			if nextTask.TaskId != "" {
 				if nextTask.TaskSecret == "" {
-					return errors.New("task response missing secret")
+					return drain(errors.New("task response missing secret"))
 				}
-				tc := taskContext{
+				tc := &taskContext{
 					task: client.TaskData{
 						ID:     nextTask.TaskId,
 						Secret: nextTask.TaskSecret,
 					},
 				}
-				if err := a.resetLogging(lgrCtx, &tc); err != nil {
-					return errors.WithStack(err)
+				if nextTask.TraceParent != "" {
+					tc.trace, err = tracing.ParseTraceParent(nextTask.TraceParent)
+					grip.Warning(message.WrapError(err, message.Fields{
+						"message": "problem parsing trace of task",
+						"task_id": nextTask.TaskId,
+					}))
 				}
-				if err := a.runTask(tskCtx, &tc); err != nil {
-					return errors.WithStack(err)
+				if err := a.resetLogging(lgrCtx, tc); err != nil {
+					return errors.WithStack(drain(err))
 				}
+				running++
+				go func() {
+					finished <- a.runTask(tskCtx, tc)
+				}()
 				timer.Reset(0)
 				continue
 			}
@@ -130,9 +187,23 @@ func (a *Agent) loop(ctx context.Context) error {
 	}
 }
 
+// taskSlots returns the number of tasks the agent runs at once.
+func (a *Agent) taskSlots() int {
+	if a.opts.MaxConcurrentTasks < 1 {
+		return 1
+	}
+	return a.opts.MaxConcurrentTasks
+}
+
 func (a *Agent) resetLogging(ctx context.Context, tc *taskContext) error {
 	tc.logger = a.comm.GetLoggerProducer(ctx, tc.task)
 
+	// the agent's own log only follows the current task when it runs one
+	// task at a time.
+	if a.taskSlots() > 1 {
+		return nil
+	}
+
 	sender, err := GetSender(ctx, a.opts.LogPrefix, tc.task.ID)
 	if err != nil {
 		return errors.Wrap(err, "problem getting sender")
@@ -145,6 +216,12 @@ func (a *Agent) resetLogging(ctx context.Context, tc *taskContext) error {
 }
 
 func (a *Agent) runTask(ctx context.Context, tc *taskContext) (err error) {
+	ctx, span := tracing.StartSpan(tracing.ContextWithRemoteParent(ctx, tc.trace), "agent.RunTask")
+	span.SetAttribute("task_id", tc.task.ID)
+	defer func() {
+		span.SetError(err)
+		span.End()
+	}()
 	defer func() { err = recovery.HandlePanicWithError(recover(), err, "running task") }()
 
 	ctx, cancel := context.WithCancel(ctx)
@@ -163,8 +240,10 @@ func (a *Agent) runTask(ctx context.Context, tc *taskContext) (err error) {
 		return errors.Wrap(err, "problem setting up metrics collection")
 	}
 
-	// Defers are LIFO. We cancel all agent task threads, then any procs started by the agent, then remove the task directory.
+	// Defers are LIFO. We cancel all agent task threads, then any procs started by the agent,
+	// then remove the task's container and directory.
 	defer a.removeTaskDirectory(tc)
+	defer a.removeTaskContainer(tc)
 	defer a.killProcs(tc)
 	defer cancel()
 
@@ -298,7 +377,12 @@ func (a *Agent) killProcs(tc *taskContext) {
 		grip.Infof("cleaning up processes for task: %s", tc.task.ID)
 
 		if tc.task.ID != "" {
-			if err := subprocess.KillSpawnedProcs(tc.task.ID, tc.logger.Task()); err != nil {
+			// only kill the procs of this task when other tasks are still running
+			kill := subprocess.KillSpawnedProcs
+			if a.taskSlots() > 1 {
+				kill = subprocess.KillTaskProcs
+			}
+			if err := kill(tc.task.ID, tc.logger.Task()); err != nil {
 				msg := fmt.Sprintf("Error cleaning up spawned processes (agent-exit): %v", err)
 				grip.Critical(msg)
 			}
@@ -111,6 +111,24 @@ func (s *AgentSuite) TestAgentEndTaskShouldExit() {
 	s.Error(err)
 }
 
+func (s *AgentSuite) TestConcurrentAgentEndTaskShouldExit() {
+	s.a.opts.MaxConcurrentTasks = 2
+	s.mockCommunicator.EndTaskResponse = &apimodels.EndTaskResponse{ShouldExit: true}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := s.a.loop(ctx)
+	s.Error(err)
+}
+
+func (s *AgentSuite) TestTaskSlots() {
+	s.Equal(1, s.a.taskSlots())
+	s.a.opts.MaxConcurrentTasks = -1
+	s.Equal(1, s.a.taskSlots())
+	s.a.opts.MaxConcurrentTasks = 3
+	s.Equal(3, s.a.taskSlots())
+}
+
 func (s *AgentSuite) TestFinishTaskReturnsEndTaskResponse() {
 	endTaskResponse := &apimodels.EndTaskResponse{Message: "end task response"}
 	s.mockCommunicator.EndTaskResponse = endTaskResponse
@@ -224,6 +242,50 @@ func (s *AgentSuite) TestRunPostTaskCommands() {
 	s.Contains(msgs[len(msgs)-1].Message, "Finished running post-task commands")
 }
 
+func (s *AgentSuite) TestRunCommandsConditions() {
+	s.tc.taskConfig = &model.TaskConfig{
+		BuildVariant: &model.BuildVariant{
+			Name: "buildvariant_id",
+			Tags: []string{"linux"},
+		},
+		Task: &task.Task{
+			Id:        "task_id",
+			Requester: evergreen.RepotrackerVersionRequester,
+		},
+		Project: &model.Project{},
+	}
+	commands := []model.PluginCommandConf{
+		{
+			Command:     "shell.exec",
+			DisplayName: "fail",
+			Params:      map[string]interface{}{"script": "exit 1"},
+		},
+		{
+			Command:     "shell.exec",
+			DisplayName: "after failure",
+			Params:      map[string]interface{}{"script": "echo hi"},
+			If:          `status == "failed" && "linux" in tags`,
+		},
+		{
+			Command:     "shell.exec",
+			DisplayName: "patch only",
+			Params:      map[string]interface{}{"script": "echo hi"},
+			If:          `requester == "patch"`,
+		},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_ = s.a.runCommands(ctx, s.tc, commands, false)
+	_ = s.tc.logger.Close()
+	msgs := []string{}
+	for _, msg := range s.mockCommunicator.GetMockMessages()["task_id"] {
+		msgs = append(msgs, msg.Message)
+	}
+	s.Contains(msgs, `Running command ("after failure") shell.exec (step 2 of 3)`)
+	s.Contains(msgs, `Skipping command ("patch only") shell.exec, condition 'requester == "patch"' is false (step 3 of 3)`)
+}
+
 func (s *AgentSuite) TestEndTaskResponse() {
 	factory, ok := command.GetCommandFactory("setup.initial")
 	s.True(ok)
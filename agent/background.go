@@ -84,6 +84,7 @@ func (a *Agent) startIdleTimeoutWatch(ctx context.Context, tc *taskContext, canc
 			if timeSinceLastMessage > timeout {
 				tc.logger.Execution().Errorf("Hit idle timeout (no message on stdout for more than %s)", timeout)
 				tc.reachTimeOut()
+				a.analyzeHang(ctx, tc)
 				return
 			}
 		}
@@ -103,6 +104,7 @@ func (a *Agent) startMaxExecTimeoutWatch(ctx context.Context, tc *taskContext, d
 		case <-timer.C:
 			tc.logger.Execution().Errorf("Hit exec timeout (%s)", d)
 			tc.reachTimeOut()
+			a.analyzeHang(ctx, tc)
 			return
 		}
 	}
@@ -3,10 +3,12 @@ package agent
 import (
 	"context"
 	"fmt"
+	"runtime"
 	"time"
 
 	"github.com/evergreen-ci/evergreen/command"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/tracing"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/recovery"
 	"github.com/pkg/errors"
@@ -32,6 +34,26 @@ func (a *Agent) runCommands(ctx context.Context, tc *taskContext, commands []mod
 			continue
 		}
 
+		// the condition of a function call is checked once, before any
+		// of the function's commands, which have their own conditions.
+		if commandInfo.Function != "" {
+			var run bool
+			run, err = a.checkCondition(tc, commandInfo.If)
+			if err != nil {
+				tc.logger.Task().Errorf("Couldn't evaluate condition of function '%v': %v", commandInfo.Function, err)
+				if isTaskCommands {
+					return err
+				}
+				err = nil
+				continue
+			}
+			if !run {
+				tc.logger.Task().Infof("Skipping function '%v', condition '%v' is false (step %d of %d)",
+					commandInfo.Function, commandInfo.If, i+1, len(commands))
+				continue
+			}
+		}
+
 		for idx, cmd := range cmds {
 			if ctx.Err() != nil {
 				grip.Error("runCommands canceled")
@@ -51,6 +73,22 @@ func (a *Agent) runCommands(ctx context.Context, tc *taskContext, commands []mod
 				continue
 			}
 
+			var run bool
+			run, err = a.checkCondition(tc, cmd.Condition())
+			if err != nil {
+				tc.logger.Task().Errorf("Couldn't evaluate condition of command %s: %v", fullCommandName, err)
+				if isTaskCommands {
+					return err
+				}
+				err = nil
+				continue
+			}
+			if !run {
+				tc.logger.Task().Infof("Skipping command %s, condition '%s' is false (step %d of %d)",
+					fullCommandName, cmd.Condition(), i+1, len(commands))
+				continue
+			}
+
 			if len(cmds) == 1 {
 				tc.logger.Task().Infof("Running command %s (step %d of %d)", fullCommandName, i+1, len(commands))
 			} else {
@@ -76,14 +114,23 @@ func (a *Agent) runCommands(ctx context.Context, tc *taskContext, commands []mod
 			}
 
 			start := time.Now()
-			err = cmd.Execute(ctx, a.comm, tc.logger, tc.taskConfig)
+			cmdCtx, span := tracing.StartSpan(ctx, "agent.Command")
+			span.SetAttribute("command", cmd.Name())
+			span.SetAttribute("display_name", fullCommandName)
+			span.SetAttribute("task_commands", isTaskCommands)
+			err = cmd.Execute(cmdCtx, a.comm, tc.logger, tc.taskConfig)
+			span.SetError(err)
+			span.End()
 
 			tc.logger.Execution().Infof("Finished %v in %v", fullCommandName, time.Since(start).String())
 			if err != nil {
+				tc.setLastStatus(model.ConditionStatusFailed)
 				tc.logger.Task().Errorf("Command failed: %v", err)
 				if isTaskCommands {
 					return errors.Wrap(err, "command failed")
 				}
+			} else {
+				tc.setLastStatus(model.ConditionStatusSuccess)
 			}
 		}
 	}
@@ -106,9 +153,20 @@ func (a *Agent) runTaskCommands(ctx context.Context, tc *taskContext) error {
 		grip.Error("task canceled")
 		return errors.New("task canceled")
 	}
+
+	run, err := a.checkCondition(tc, task.If)
+	if err != nil {
+		tc.logger.Execution().Errorf("Couldn't evaluate condition of task: %v", err)
+		return errors.New("task failed")
+	}
+	if !run {
+		tc.logger.Task().Infof("Skipping task commands, condition '%v' is false.", task.If)
+		return nil
+	}
+
 	tc.logger.Execution().Info("Running task commands.")
 	start := time.Now()
-	err := a.runCommands(ctx, tc, task.Commands, true)
+	err = a.runCommands(ctx, tc, task.Commands, true)
 	tc.logger.Execution().Infof("Finished running task commands in %v.", time.Since(start).String())
 	if err != nil {
 		tc.logger.Execution().Errorf("Task failed: %v", err)
@@ -117,6 +175,20 @@ func (a *Agent) runTaskCommands(ctx context.Context, tc *taskContext) error {
 	return nil
 }
 
+// checkCondition evaluates an `if:` condition of a command or task against
+// the running task.
+func (a *Agent) checkCondition(tc *taskContext, condition string) (bool, error) {
+	conf := tc.taskConfig
+	return model.EvalCondition(condition, model.ConditionContext{
+		Expansions: conf.Expansions,
+		Requester:  conf.Task.Requester,
+		Variant:    conf.BuildVariant.Name,
+		Tags:       conf.BuildVariant.Tags,
+		Status:     tc.getLastStatus(),
+		OS:         runtime.GOOS,
+	})
+}
+
 func (a *Agent) getTimeout(cmd command.Command) time.Duration {
 	if cmd.IdleTimeout() > 0 {
 		return cmd.IdleTimeout()
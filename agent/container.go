@@ -0,0 +1,91 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/evergreen-ci/evergreen/subprocess"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
+	"github.com/pkg/errors"
+)
+
+// containerName returns the name of the container for the task, which is
+// unique to the task's directory.
+func containerName(tc *taskContext) string {
+	return fmt.Sprintf("evg-%s", filepath.Base(tc.taskDirectory))
+}
+
+// containerRunArgs returns the arguments to docker that start the task's
+// container. The container only keeps running until it is removed, and the
+// task's commands are run in it with docker exec.
+func containerRunArgs(tc *taskContext) []string {
+	settings := tc.taskConfig.Distro.Containers
+	args := []string{
+		"run",
+		"--detach",
+		"--name", containerName(tc),
+		"--volume", fmt.Sprintf("%s:%s", tc.taskDirectory, tc.taskDirectory),
+		"--workdir", tc.taskDirectory,
+	}
+	if settings.CPUs > 0 {
+		args = append(args, "--cpus", fmt.Sprintf("%g", settings.CPUs))
+	}
+	if settings.MemoryMB > 0 {
+		args = append(args, "--memory", fmt.Sprintf("%dm", settings.MemoryMB))
+	}
+
+	return append(args, "--entrypoint", "tail", settings.Image, "-f", "/dev/null")
+}
+
+// startTaskContainer starts a fresh container for the task, if the distro
+// runs each task in its own container. The task directory must already
+// exist, since it is bind-mounted into the container at the same path.
+func (a *Agent) startTaskContainer(ctx context.Context, tc *taskContext) error {
+	if tc.taskConfig == nil || !tc.taskConfig.Distro.UsesContainers() {
+		return nil
+	}
+
+	name := containerName(tc)
+	tc.logger.Execution().Infof("Starting container %s from image %s",
+		name, tc.taskConfig.Distro.Containers.Image)
+	if err := a.runDocker(ctx, tc, containerRunArgs(tc)); err != nil {
+		return errors.Wrapf(err, "problem starting container %s", name)
+	}
+
+	tc.taskConfig.Container = name
+	return nil
+}
+
+// removeTaskContainer stops and removes the task's container, along with
+// any processes still running in it. It does not return an error because
+// it is executed at the end of a task run.
+func (a *Agent) removeTaskContainer(tc *taskContext) {
+	if tc.taskConfig == nil || tc.taskConfig.Container == "" {
+		return
+	}
+
+	name := tc.taskConfig.Container
+	grip.Infof("Removing container for completed task: %s", name)
+	if err := a.runDocker(context.Background(), tc, []string{"rm", "--force", name}); err != nil {
+		grip.Criticalf("Error removing container %s for the task: %v", name, err)
+		return
+	}
+	tc.taskConfig.Container = ""
+}
+
+func (a *Agent) runDocker(ctx context.Context, tc *taskContext, args []string) error {
+	cmd, err := subprocess.NewLocalExec("docker", args, nil, "")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	output := tc.logger.SystemWriter(level.Info)
+	defer output.Close()
+	if err = cmd.SetOutput(subprocess.OutputOptions{Output: output, SendErrorToOutput: true}); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(cmd.Run(ctx))
+}
@@ -0,0 +1,48 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainerRunArgs(t *testing.T) {
+	assert := assert.New(t)
+
+	tc := &taskContext{
+		taskDirectory: "/data/mci/abcdef",
+		taskConfig: &model.TaskConfig{
+			Distro: &distro.Distro{
+				Containers: &distro.ContainerSettings{
+					Image: "ubuntu:16.04",
+					Slots: 4,
+				},
+			},
+		},
+	}
+	assert.Equal("evg-abcdef", containerName(tc))
+	assert.Equal([]string{"run", "--detach", "--name", "evg-abcdef",
+		"--volume", "/data/mci/abcdef:/data/mci/abcdef", "--workdir", "/data/mci/abcdef",
+		"--entrypoint", "tail", "ubuntu:16.04", "-f", "/dev/null"}, containerRunArgs(tc))
+
+	tc.taskConfig.Distro.Containers.CPUs = 1.5
+	tc.taskConfig.Distro.Containers.MemoryMB = 2048
+	args := containerRunArgs(tc)
+	assert.Contains(args, "1.5")
+	assert.Contains(args, "2048m")
+}
+
+func TestNoContainerWithoutSettings(t *testing.T) {
+	assert := assert.New(t)
+
+	a := &Agent{}
+	tc := &taskContext{
+		taskDirectory: "/data/mci/abcdef",
+		taskConfig:    &model.TaskConfig{Distro: &distro.Distro{}},
+	}
+	assert.NoError(a.startTaskContainer(nil, tc))
+	assert.Empty(tc.taskConfig.Container)
+	a.removeTaskContainer(tc)
+}
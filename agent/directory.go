@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"os/user"
 	"path/filepath"
@@ -41,6 +42,46 @@ func (a *Agent) createTaskDirectory(tc *taskContext) (string, error) {
 	return newDir, nil
 }
 
+// writeShardTestsFile writes the tests of a task that is a shard of a
+// sharded task to a file in the task directory, one per line, and puts the
+// file's path in the shard_tests_file expansion. For the last shard, it
+// also writes the tests of the other shards, which it excludes, and puts
+// that file's path in the shard_exclude_tests_file expansion.
+func (a *Agent) writeShardTestsFile(tc *taskContext) error {
+	pt := tc.taskConfig.Project.FindProjectTask(tc.taskConfig.Task.DisplayName)
+	if pt == nil || pt.Shard == nil {
+		return nil
+	}
+
+	path := filepath.Join(tc.taskDirectory, shardTestsFileName)
+	if err := writeTestList(path, pt.Shard.Tests); err != nil {
+		return err
+	}
+	tc.logger.Execution().Infof("Wrote %d tests of shard %d of task %s to %s.",
+		len(pt.Shard.Tests), pt.Shard.Index, pt.Shard.Task, path)
+	tc.taskConfig.Expansions.Put("shard_tests_file", path)
+
+	if !pt.Shard.RunsUnlistedTests() {
+		return nil
+	}
+	path = filepath.Join(tc.taskDirectory, shardExcludeTestsFileName)
+	if err := writeTestList(path, pt.Shard.ExcludeTests); err != nil {
+		return err
+	}
+	tc.logger.Execution().Infof("Wrote %d tests that shard %d of task %s excludes to %s.",
+		len(pt.Shard.ExcludeTests), pt.Shard.Index, pt.Shard.Task, path)
+	tc.taskConfig.Expansions.Put("shard_exclude_tests_file", path)
+	return nil
+}
+
+func writeTestList(path string, tests []string) error {
+	data := strings.Join(tests, "\n")
+	if data != "" {
+		data += "\n"
+	}
+	return ioutil.WriteFile(path, []byte(data), 0644)
+}
+
 // removeTaskDirectory removes the folder the agent created for the task it
 // was executing. It does not return an error because it is executed at the end of
 // a task run, and the agent loop will start another task regardless of how this
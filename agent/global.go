@@ -32,7 +32,19 @@ const (
 	// "timeout" command sets should be shut down.
 	defaultCallbackCmdTimeout = 15 * time.Minute
 
+	// hangAnalysisTimeout limits how long the agent collects diagnostics
+	// about the processes of a task that hit a timeout.
+	hangAnalysisTimeout = 10 * time.Minute
+
 	// maxHeartbeats is the number of failed heartbeats after which an agent
 	// reports an error
 	maxHeartbeats = 10
+
+	// shardTestsFileName is the name of the file in the task directory
+	// that lists the tests of a shard of a sharded task.
+	shardTestsFileName = "shard_tests.txt"
+	// shardExcludeTestsFileName is the name of the file in the task
+	// directory that lists the tests the last shard of a sharded task
+	// doesn't run.
+	shardExcludeTestsFileName = "shard_exclude_tests.txt"
 )
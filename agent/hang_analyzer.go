@@ -0,0 +1,210 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/command"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/recovery"
+	"github.com/pkg/errors"
+)
+
+const (
+	// defaultMaxCoreDumpMB limits the total size of the core dumps of a
+	// hang analysis, unless the project sets its own limit.
+	defaultMaxCoreDumpMB = 1024
+
+	// goroutineDumpWait is how long the hang analyzer waits for Go
+	// processes to write their goroutines after it signals them.
+	goroutineDumpWait = 5 * time.Second
+
+	hangAnalysisFile = "hang_analysis.tgz"
+)
+
+// hangProcess is the diagnostics of one of the processes of a hung task.
+type hangProcess struct {
+	Pid           int32    `json:"pid"`
+	Parent        int32    `json:"parent_pid"`
+	Command       string   `json:"command"`
+	RSS           uint64   `json:"rss"`
+	Go            bool     `json:"go"`
+	GoroutineDump bool     `json:"goroutine_dump"`
+	CoreDump      string   `json:"core_dump,omitempty"`
+	Errors        []string `json:"errors,omitempty"`
+}
+
+// analyzeHang collects diagnostics about the processes of a task that hit a
+// timeout, before they are killed and before the project's timeout commands
+// run. It saves the process tree and the kernel stacks of the processes,
+// asks Go processes to write their goroutines to the task log, takes core
+// dumps if the project asks for them, and uploads the bundle as an artifact
+// of the task if the project configures an upload.
+func (a *Agent) analyzeHang(ctx context.Context, tc *taskContext) {
+	defer recovery.LogStackTraceAndContinue("hang analyzer")
+
+	conf := &model.HangAnalysis{}
+	if tc.taskConfig != nil && tc.taskConfig.Project != nil && tc.taskConfig.Project.HangAnalysis != nil {
+		conf = tc.taskConfig.Project.HangAnalysis
+	}
+	if conf.Disabled {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, hangAnalysisTimeout)
+	defer cancel()
+	logger := tc.logger.Execution()
+	logger.Info("Running hang analysis before killing the task's processes.")
+	start := time.Now()
+
+	dir, err := ioutil.TempDir("", "hang-analysis-")
+	if err != nil {
+		logger.Errorf("Error creating hang analysis directory: %v", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	procs := a.collectHangProcesses(ctx, tc, conf, dir)
+	if len(procs) == 0 {
+		logger.Info("The task has no running processes to analyze.")
+		return
+	}
+	for _, p := range procs {
+		logger.Info(message.Fields{
+			"message":        "hung process",
+			"pid":            p.Pid,
+			"parent_pid":     p.Parent,
+			"command":        p.Command,
+			"goroutine_dump": p.GoroutineDump,
+			"core_dump":      p.CoreDump,
+			"errors":         p.Errors,
+		})
+	}
+
+	data, err := json.MarshalIndent(procs, "", "  ")
+	if err == nil {
+		err = ioutil.WriteFile(filepath.Join(dir, "processes.json"), data, 0644)
+	}
+	logger.ErrorWhenf(err != nil, "Error saving hung processes: %v", err)
+
+	if conf.Upload != nil && tc.taskConfig != nil {
+		if err = a.uploadHangAnalysis(ctx, tc, conf, dir); err != nil {
+			logger.Errorf("Error uploading hang analysis: %v", err)
+		}
+	}
+	logger.Infof("Finished hang analysis in %v.", time.Since(start).String())
+}
+
+// collectHangProcesses walks the process tree of the task, and collects the
+// diagnostics of each process into the directory.
+func (a *Agent) collectHangProcesses(ctx context.Context, tc *taskContext, conf *model.HangAnalysis, dir string) []hangProcess {
+	coreBudget := uint64(conf.MaxCoreDumpMB) * 1024 * 1024
+	if conf.MaxCoreDumpMB == 0 {
+		coreBudget = defaultMaxCoreDumpMB * 1024 * 1024
+	}
+
+	procs := []hangProcess{}
+	signaled := false
+	for _, info := range convertProcInfo(message.CollectProcessInfoSelfWithChildren()) {
+		pid := int(info.Pid)
+		if pid == os.Getpid() {
+			continue
+		}
+		// with other tasks running, only look at this task's processes
+		if a.taskSlots() > 1 && !isTaskProcess(tc.task.ID, pid) {
+			continue
+		}
+
+		p := hangProcess{
+			Pid:     info.Pid,
+			Parent:  info.Parent,
+			Command: info.Command,
+			RSS:     info.Memory.RSS,
+		}
+
+		if stack, err := readKernelStack(pid); err != nil {
+			p.Errors = append(p.Errors, fmt.Sprintf("kernel stack: %v", err))
+		} else if err = ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("stack_%d.txt", pid)), stack, 0644); err != nil {
+			p.Errors = append(p.Errors, fmt.Sprintf("saving kernel stack: %v", err))
+		}
+
+		if conf.CoreDumps {
+			if p.RSS > coreBudget {
+				p.Errors = append(p.Errors, "core dump: over the size limit")
+			} else if size, path, err := dumpCore(ctx, pid, dir, coreBudget); err != nil {
+				p.Errors = append(p.Errors, fmt.Sprintf("core dump: %v", err))
+			} else {
+				p.CoreDump = filepath.Base(path)
+				coreBudget -= size
+			}
+		}
+
+		// Go processes exit after writing their goroutines, so they are
+		// signaled once everything else about them is collected.
+		p.Go = isGoProcess(pid)
+		if p.Go {
+			if err := signalGoroutineDump(pid); err != nil {
+				p.Errors = append(p.Errors, fmt.Sprintf("goroutine dump: %v", err))
+			} else {
+				p.GoroutineDump = true
+				signaled = true
+			}
+		}
+
+		procs = append(procs, p)
+	}
+
+	if signaled {
+		tc.logger.Execution().Info("Waiting for the goroutines of Go processes to be written to the task log.")
+		timer := time.NewTimer(goroutineDumpWait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+		case <-timer.C:
+		}
+	}
+	return procs
+}
+
+// uploadHangAnalysis archives the diagnostics in the directory, and uploads
+// the archive with the s3.put command, which attaches it to the task.
+func (a *Agent) uploadHangAnalysis(ctx context.Context, tc *taskContext, conf *model.HangAnalysis, dir string) error {
+	archive := filepath.Join(filepath.Dir(dir), filepath.Base(dir)+"-"+hangAnalysisFile)
+	if err := writeHangArchive(ctx, dir, archive, tc.logger.Execution()); err != nil {
+		return errors.Wrap(err, "problem archiving hang analysis")
+	}
+	defer os.Remove(archive)
+
+	factory, ok := command.GetCommandFactory("s3.put")
+	if !ok {
+		return errors.New("s3.put command is not registered")
+	}
+	cmd := factory()
+	if err := cmd.ParseParams(conf.UploadParams(archive, tc.taskConfig.Task.Id)); err != nil {
+		return errors.Wrap(err, "invalid hang analysis upload")
+	}
+	return errors.WithStack(cmd.Execute(ctx, a.comm, tc.logger, tc.taskConfig))
+}
+
+func writeHangArchive(ctx context.Context, dir, archive string, logger grip.Journaler) error {
+	f, gz, tarWriter, err := util.TarGzWriter(archive)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer func() {
+		logger.CatchError(tarWriter.Close())
+		logger.CatchError(gz.Close())
+		logger.CatchError(f.Close())
+	}()
+
+	_, err = util.BuildArchive(ctx, tarWriter, dir, []string{"*"}, nil, logger)
+	return errors.WithStack(err)
+}
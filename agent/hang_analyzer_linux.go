@@ -0,0 +1,77 @@
+package agent
+
+import (
+	"context"
+	"debug/elf"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"syscall"
+
+	"github.com/evergreen-ci/evergreen/subprocess"
+	"github.com/pkg/errors"
+)
+
+func isTaskProcess(taskID string, pid int) bool {
+	return subprocess.HasTaskMarker(taskID, pid)
+}
+
+// isGoProcess reports whether the executable of the process was built by
+// the Go toolchain, which leaves its build ID in a note section.
+func isGoProcess(pid int) bool {
+	f, err := elf.Open(fmt.Sprintf("/proc/%d/exe", pid))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	for _, name := range []string{".note.go.buildid", ".go.buildinfo", ".gopclntab"} {
+		if f.Section(name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// signalGoroutineDump sends SIGQUIT to a Go process, which makes it write
+// the stacks of its goroutines to stderr and exit.
+func signalGoroutineDump(pid int) error {
+	return errors.WithStack(syscall.Kill(pid, syscall.SIGQUIT))
+}
+
+// readKernelStack returns the kernel stack of the process, which is usually
+// only readable by root.
+func readKernelStack(pid int) ([]byte, error) {
+	stack, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/stack", pid))
+	return stack, errors.WithStack(err)
+}
+
+// dumpCore takes a core dump of the process into the directory with gcore,
+// and removes it if it is larger than the limit.
+func dumpCore(ctx context.Context, pid int, dir string, limit uint64) (uint64, string, error) {
+	gcore, err := exec.LookPath("gcore")
+	if err != nil {
+		return 0, "", errors.New("gcore is not installed")
+	}
+
+	prefix := filepath.Join(dir, "core")
+	out, err := exec.CommandContext(ctx, gcore, "-o", prefix, strconv.Itoa(pid)).CombinedOutput()
+	if err != nil {
+		return 0, "", errors.Wrapf(err, "gcore failed: %s", out)
+	}
+
+	path := fmt.Sprintf("%s.%d", prefix, pid)
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, "", errors.Wrap(err, "gcore did not write a core dump")
+	}
+	size := uint64(info.Size())
+	if size > limit {
+		_ = os.Remove(path)
+		return 0, "", errors.Errorf("core dump of %d bytes is over the size limit", size)
+	}
+	return size, path, nil
+}
@@ -0,0 +1,29 @@
+// +build !linux
+
+package agent
+
+import (
+	"context"
+	"runtime"
+
+	"github.com/pkg/errors"
+)
+
+// The hang analyzer can only inspect processes on linux. Elsewhere, it
+// only records the process tree of the task.
+
+func isTaskProcess(taskID string, pid int) bool { return true }
+
+func isGoProcess(pid int) bool { return false }
+
+func signalGoroutineDump(pid int) error {
+	return errors.Errorf("goroutine dumps are not supported on %s", runtime.GOOS)
+}
+
+func readKernelStack(pid int) ([]byte, error) {
+	return nil, errors.Errorf("kernel stacks are not supported on %s", runtime.GOOS)
+}
+
+func dumpCore(ctx context.Context, pid int, dir string, limit uint64) (uint64, string, error) {
+	return 0, "", errors.Errorf("core dumps are not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectHangProcesses(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test process runs sleep")
+	}
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := &Agent{
+		opts: Options{HostID: "host", HostSecret: "secret", LogPrefix: evergreen.LocalLoggingOverride},
+		comm: client.NewMock("url"),
+	}
+	tc := &taskContext{
+		task:       client.TaskData{ID: "task_id", Secret: "task_secret"},
+		taskConfig: &model.TaskConfig{Project: &model.Project{}},
+	}
+	tc.logger = a.comm.GetLoggerProducer(ctx, tc.task)
+
+	sleep := exec.CommandContext(ctx, "sleep", "30")
+	require.NoError(sleep.Start())
+	defer func() { _ = sleep.Process.Kill() }()
+
+	dir, err := ioutil.TempDir("", "hang-analysis-test")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	procs := a.collectHangProcesses(ctx, tc, &model.HangAnalysis{}, dir)
+	found := false
+	for _, p := range procs {
+		assert.NotEqual(int32(os.Getpid()), p.Pid)
+		if p.Pid == int32(sleep.Process.Pid) {
+			found = true
+			assert.False(p.Go)
+			assert.False(p.GoroutineDump)
+		}
+	}
+	assert.True(found)
+
+	if runtime.GOOS == "linux" {
+		assert.True(isGoProcess(os.Getpid()))
+		assert.False(isGoProcess(sleep.Process.Pid))
+	}
+}
+
+func TestAnalyzeHangDisabled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	comm := client.NewMock("url")
+	a := &Agent{comm: comm}
+	tc := &taskContext{
+		task: client.TaskData{ID: "task_id", Secret: "task_secret"},
+		taskConfig: &model.TaskConfig{Project: &model.Project{
+			HangAnalysis: &model.HangAnalysis{Disabled: true},
+		}},
+	}
+	tc.logger = comm.GetLoggerProducer(ctx, tc.task)
+
+	a.analyzeHang(ctx, tc)
+	_ = tc.logger.Close()
+	assert.Empty(t, comm.GetMockMessages()["task_id"])
+}
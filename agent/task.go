@@ -115,6 +115,18 @@ func (a *Agent) startTask(ctx context.Context, tc *taskContext, complete chan<-
 	tc.taskDirectory = newDir
 	taskConfig.Expansions.Put("workdir", newDir)
 
+	if err = a.writeShardTestsFile(tc); err != nil {
+		tc.logger.Execution().Errorf("error writing shard tests file: %s", err)
+		complete <- evergreen.TaskFailed
+		return
+	}
+
+	if err = a.startTaskContainer(ctx, tc); err != nil {
+		tc.logger.Execution().Errorf("error starting task container: %s", err)
+		complete <- evergreen.TaskSystemFailed
+		return
+	}
+
 	// notify API server that the task has been started.
 	tc.logger.Execution().Info("Reporting task started.")
 	if err = a.comm.StartTask(ctx, tc.task); err != nil {
@@ -193,6 +205,23 @@ func (tc *taskContext) hadTimedOut() bool {
 	return tc.timedOut
 }
 
+func (tc *taskContext) setLastStatus(status string) {
+	tc.Lock()
+	defer tc.Unlock()
+
+	tc.lastStatus = status
+}
+
+func (tc *taskContext) getLastStatus() string {
+	tc.RLock()
+	defer tc.RUnlock()
+
+	if tc.lastStatus == "" {
+		return model.ConditionStatusSuccess
+	}
+	return tc.lastStatus
+}
+
 // getTaskConfig fetches task configuration data required to run the task from the API server.
 func (a *Agent) getTaskConfig(ctx context.Context, tc *taskContext) (*model.TaskConfig, error) {
 	tc.logger.Execution().Info("Fetching distro configuration.")
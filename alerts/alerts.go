@@ -10,6 +10,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/admin"
 	"github.com/evergreen-ci/evergreen/model/alert"
+	"github.com/evergreen-ci/evergreen/model/alertrecord"
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/patch"
@@ -298,6 +299,13 @@ func (qp *QueueProcessor) Deliver(req *alert.AlertRequest, ctx *AlertContext) er
 		// Project-specific alert - use alert configs defined on the project
 		// TODO(EVG-223) patch alerts should go to patch owner
 		alertConfigs = ctx.ProjectRef.Alerts[req.Trigger]
+		// the author of the commit that bisection found is always told
+		if req.Trigger == alertrecord.StepbackCulpritId && ctx.Version != nil && ctx.Version.AuthorEmail != "" {
+			alertConfigs = append([]model.AlertConfig{{
+				Provider: EmailProvider,
+				Settings: bson.M{"rcpt": ctx.Version.AuthorEmail},
+			}}, alertConfigs...)
+		}
 	} else if ctx.Host != nil {
 		// Host-specific alert - use superuser alert configs for now
 		// TODO(EVG-224) spawnhost alerts should go to spawnhost owner
@@ -228,6 +228,12 @@ func getSubject(alertCtx AlertContext) string {
 			alertCtx.ProjectRef.DisplayName,
 			alertCtx.Version.Revision[0:8],
 		)
+	case alertrecord.StepbackCulpritId:
+		return fmt.Sprintf("Stepback Found Culprit: %s on %s // %s @ %s",
+			alertCtx.Task.DisplayName,
+			alertCtx.Build.DisplayName,
+			alertCtx.ProjectRef.DisplayName,
+			alertCtx.Version.Revision[0:8])
 	case alertrecord.SpawnHostTwoHourWarning:
 		return fmt.Sprintf("Your %s host (%s) will expire in two hours.",
 			alertCtx.Host.Distro, alertCtx.Host.Id)
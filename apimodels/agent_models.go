@@ -45,6 +45,9 @@ type NextTaskResponse struct {
 	TaskSecret string `json:"task_secret,omitempty"`
 	ShouldExit bool   `json:"should_exit,omitempty"`
 	Message    string `json:"message,omitempty"`
+	// TraceParent identifies the span of the task's dispatch, which the
+	// agent's spans for the task continue from.
+	TraceParent string `json:"trace_parent,omitempty"`
 }
 
 // EndTaskResponse is what is returned when the task ends
@@ -73,6 +73,16 @@ type CloudCostCalculator interface {
 	CostForDuration(host *host.Host, start time.Time, end time.Time) (float64, error)
 }
 
+// CloudStopStartManager is an interface for cloud managers that can stop an
+// instance without destroying it, and start it again later.
+type CloudStopStartManager interface {
+	// StopInstance stops a running instance, keeping its disks.
+	StopInstance(*host.Host) error
+
+	// StartInstance starts a stopped instance.
+	StartInstance(*host.Host) error
+}
+
 // GetCloudManager returns an implementation of CloudManager for the given provider name.
 // It returns an error if the provider name doesn't have a known implementation.
 func GetCloudManager(providerName string, settings *evergreen.Settings) (CloudManager, error) {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/pkg/errors"
 )
 
 // HostOptions is a struct of options that are commonly passed around when creating a
@@ -53,6 +54,25 @@ func (cloudHost *CloudHost) TerminateInstance() error {
 	return cloudHost.CloudMgr.TerminateInstance(cloudHost.Host)
 }
 
+// StopInstance stops the host, if its provider supports stopping hosts.
+func (cloudHost *CloudHost) StopInstance() error {
+	mgr, ok := cloudHost.CloudMgr.(CloudStopStartManager)
+	if !ok {
+		return errors.Errorf("provider '%s' does not support stopping hosts", cloudHost.Host.Provider)
+	}
+	return mgr.StopInstance(cloudHost.Host)
+}
+
+// StartInstance starts the stopped host, if its provider supports stopping
+// hosts.
+func (cloudHost *CloudHost) StartInstance() error {
+	mgr, ok := cloudHost.CloudMgr.(CloudStopStartManager)
+	if !ok {
+		return errors.Errorf("provider '%s' does not support starting hosts", cloudHost.Host.Provider)
+	}
+	return mgr.StartInstance(cloudHost.Host)
+}
+
 func (cloudHost *CloudHost) GetInstanceStatus() (CloudStatus, error) {
 	return cloudHost.CloudMgr.GetInstanceStatus(cloudHost.Host)
 }
@@ -89,3 +89,24 @@ func TestIsHostReachable(t *testing.T) {
 	})
 
 }
+
+func TestCloudHostStopStart(t *testing.T) {
+	Convey("Stopping and starting hosts should only work with providers that support it", t, func() {
+		Convey("Static hosts can not be stopped or started", func() {
+			h := &host.Host{Id: "static", Provider: evergreen.HostTypeStatic, Status: evergreen.HostRunning}
+			cloudHost, err := GetCloudHost(h, testutil.TestConfig())
+			So(err, ShouldBeNil)
+			So(cloudHost.StopInstance(), ShouldNotBeNil)
+			So(cloudHost.StartInstance(), ShouldNotBeNil)
+			So(h.Status, ShouldEqual, evergreen.HostRunning)
+		})
+
+		Convey("EC2 and mock hosts can be stopped and started", func() {
+			for _, provider := range []string{evergreen.ProviderNameEc2OnDemandNew, evergreen.ProviderNameEc2SpotNew, evergreen.ProviderNameMock} {
+				cloudMgr, err := GetCloudManager(provider, testutil.TestConfig())
+				So(err, ShouldBeNil)
+				So(cloudMgr, ShouldImplement, (*CloudStopStartManager)(nil))
+			}
+		})
+	})
+}
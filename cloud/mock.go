@@ -195,6 +195,46 @@ func (mockMgr *mockManager) TerminateInstance(host *host.Host) error {
 	return errors.WithStack(host.Terminate())
 }
 
+// stop an instance
+func (mockMgr *mockManager) StopInstance(host *host.Host) error {
+	l := mockMgr.mutex
+	l.Lock()
+	defer l.Unlock()
+	instance, ok := mockMgr.Instances[host.Id]
+	if !ok {
+		return errors.Errorf("unable to fetch host: %s", host.Id)
+	}
+	if host.Status != evergreen.HostRunning {
+		return errors.Errorf("Cannot stop %s; host is %s, not running", host.Id, host.Status)
+	}
+
+	instance.Status = StatusStopped
+	instance.IsUp = false
+	mockMgr.Instances[host.Id] = instance
+
+	return errors.WithStack(host.SetStopping())
+}
+
+// start a stopped instance
+func (mockMgr *mockManager) StartInstance(host *host.Host) error {
+	l := mockMgr.mutex
+	l.Lock()
+	defer l.Unlock()
+	instance, ok := mockMgr.Instances[host.Id]
+	if !ok {
+		return errors.Errorf("unable to fetch host: %s", host.Id)
+	}
+	if host.Status != evergreen.HostStopped {
+		return errors.Errorf("Cannot start %s; host is %s, not stopped", host.Id, host.Status)
+	}
+
+	instance.Status = StatusRunning
+	instance.IsUp = true
+	mockMgr.Instances[host.Id] = instance
+
+	return errors.WithStack(host.SetRestarted())
+}
+
 func (mockMgr *mockManager) Configure(settings *evergreen.Settings) error {
 	//no-op. maybe will need to load something from settings in the future.
 	return nil
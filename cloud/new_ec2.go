@@ -428,6 +428,72 @@ func (m *ec2Manager) TerminateInstance(h *host.Host) error {
 	return errors.Wrap(h.Terminate(), "failed to terminate instance in db")
 }
 
+// StopInstance stops a running on-demand EC2 instance. Spot instances can
+// not be stopped.
+func (m *ec2Manager) StopInstance(h *host.Host) error {
+	if h.Status != evergreen.HostRunning {
+		return errors.Errorf("can not stop %s - host is %s, not running", h.Id, h.Status)
+	}
+	if isHostSpot(h) {
+		return errors.Errorf("can not stop %s - spot instances can not be stopped", h.Id)
+	}
+
+	if err := m.client.Create(m.credentials); err != nil {
+		return errors.Wrap(err, "error creating client")
+	}
+	defer m.client.Close()
+
+	if _, err := m.client.StopInstances(&ec2.StopInstancesInput{
+		InstanceIds: []*string{&h.Id},
+	}); err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message":       "error stopping instance",
+			"host":          h.Id,
+			"host_provider": h.Distro.Provider,
+			"distro":        h.Distro.Id,
+		}))
+		return errors.Wrap(err, "error stopping instance")
+	}
+
+	grip.Info(message.Fields{
+		"message": "stopping instance",
+		"host":    h.Id,
+		"distro":  h.Distro.Id,
+	})
+	return errors.Wrap(h.SetStopping(), "failed to mark instance as stopping in db")
+}
+
+// StartInstance starts a stopped EC2 instance.
+func (m *ec2Manager) StartInstance(h *host.Host) error {
+	if h.Status != evergreen.HostStopped {
+		return errors.Errorf("can not start %s - host is %s, not stopped", h.Id, h.Status)
+	}
+
+	if err := m.client.Create(m.credentials); err != nil {
+		return errors.Wrap(err, "error creating client")
+	}
+	defer m.client.Close()
+
+	if _, err := m.client.StartInstances(&ec2.StartInstancesInput{
+		InstanceIds: []*string{&h.Id},
+	}); err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message":       "error starting instance",
+			"host":          h.Id,
+			"host_provider": h.Distro.Provider,
+			"distro":        h.Distro.Id,
+		}))
+		return errors.Wrap(err, "error starting instance")
+	}
+
+	grip.Info(message.Fields{
+		"message": "started instance",
+		"host":    h.Id,
+		"distro":  h.Distro.Id,
+	})
+	return errors.Wrap(h.SetRestarted(), "failed to mark instance as running in db")
+}
+
 func (m *ec2Manager) cancelSpotRequest(h *host.Host) (bool, error) {
 	spotDetails, err := m.client.DescribeSpotInstanceRequests(&ec2.DescribeSpotInstanceRequestsInput{
 		SpotInstanceRequestIds: []*string{makeStringPtr(h.Id)},
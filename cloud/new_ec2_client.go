@@ -37,6 +37,12 @@ type AWSClient interface {
 	// TerminateInstances is a wrapper for ec2.TerminateInstances.
 	TerminateInstances(*ec2.TerminateInstancesInput) (*ec2.TerminateInstancesOutput, error)
 
+	// StopInstances is a wrapper for ec2.StopInstances.
+	StopInstances(*ec2.StopInstancesInput) (*ec2.StopInstancesOutput, error)
+
+	// StartInstances is a wrapper for ec2.StartInstances.
+	StartInstances(*ec2.StartInstancesInput) (*ec2.StartInstancesOutput, error)
+
 	// RequestSpotInstances is a wrapper for ec2.RequestSpotInstances.
 	RequestSpotInstances(*ec2.RequestSpotInstancesInput) (*ec2.RequestSpotInstancesOutput, error)
 
@@ -191,6 +197,54 @@ func (c *awsClientImpl) TerminateInstances(input *ec2.TerminateInstancesInput) (
 	return output, nil
 }
 
+// StopInstances is a wrapper for ec2.StopInstances.
+func (c *awsClientImpl) StopInstances(input *ec2.StopInstancesInput) (*ec2.StopInstancesOutput, error) {
+	var output *ec2.StopInstancesOutput
+	var err error
+	_, err = util.Retry(
+		func() (bool, error) {
+			output, err = c.EC2.StopInstances(input)
+			if err != nil {
+				if ec2err, ok := err.(awserr.Error); ok {
+					grip.Error(message.WrapError(ec2err, message.Fields{
+						"message": "error running StopInstances",
+						"args":    input,
+					}))
+				}
+				return true, err
+			}
+			return false, nil
+		}, awsClientImplRetries, awsClientImplStartPeriod)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
+// StartInstances is a wrapper for ec2.StartInstances.
+func (c *awsClientImpl) StartInstances(input *ec2.StartInstancesInput) (*ec2.StartInstancesOutput, error) {
+	var output *ec2.StartInstancesOutput
+	var err error
+	_, err = util.Retry(
+		func() (bool, error) {
+			output, err = c.EC2.StartInstances(input)
+			if err != nil {
+				if ec2err, ok := err.(awserr.Error); ok {
+					grip.Error(message.WrapError(ec2err, message.Fields{
+						"message": "error running StartInstances",
+						"args":    input,
+					}))
+				}
+				return true, err
+			}
+			return false, nil
+		}, awsClientImplRetries, awsClientImplStartPeriod)
+	if err != nil {
+		return nil, err
+	}
+	return output, nil
+}
+
 // RequestSpotInstances is a wrapper for ec2.RequestSpotInstances.
 func (c *awsClientImpl) RequestSpotInstances(input *ec2.RequestSpotInstancesInput) (*ec2.RequestSpotInstancesOutput, error) {
 	var output *ec2.RequestSpotInstancesOutput
@@ -341,6 +395,8 @@ type awsClientMock struct { //nolint
 	*ec2.DescribeInstancesInput
 	*ec2.CreateTagsInput
 	*ec2.TerminateInstancesInput
+	*ec2.StopInstancesInput
+	*ec2.StartInstancesInput
 	*ec2.RequestSpotInstancesInput
 	*ec2.DescribeSpotInstanceRequestsInput
 	*ec2.CancelSpotInstanceRequestsInput
@@ -402,6 +458,18 @@ func (c *awsClientMock) TerminateInstances(input *ec2.TerminateInstancesInput) (
 	return &ec2.TerminateInstancesOutput{}, nil
 }
 
+// StopInstances is a mock for ec2.StopInstances.
+func (c *awsClientMock) StopInstances(input *ec2.StopInstancesInput) (*ec2.StopInstancesOutput, error) {
+	c.StopInstancesInput = input
+	return &ec2.StopInstancesOutput{}, nil
+}
+
+// StartInstances is a mock for ec2.StartInstances.
+func (c *awsClientMock) StartInstances(input *ec2.StartInstancesInput) (*ec2.StartInstancesOutput, error) {
+	c.StartInstancesInput = input
+	return &ec2.StartInstancesOutput{}, nil
+}
+
 // RequestSpotInstances is a mock for ec2.RequestSpotInstances.
 func (c *awsClientMock) RequestSpotInstances(input *ec2.RequestSpotInstancesInput) (*ec2.RequestSpotInstancesOutput, error) {
 	c.RequestSpotInstancesInput = input
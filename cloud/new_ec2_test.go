@@ -402,6 +402,49 @@ func (s *EC2Suite) TestTerminateInstance() {
 	s.NoError(err)
 }
 
+func (s *EC2Suite) TestStopAndStartInstance() {
+	h := &host.Host{
+		Id:     "host_id",
+		Host:   "old_dns_name",
+		Status: evergreen.HostRunning,
+		Distro: distro.Distro{Provider: evergreen.ProviderNameEc2OnDemand},
+	}
+	s.NoError(h.Insert())
+
+	s.Error(s.onDemandManager.(CloudStopStartManager).StartInstance(h))
+	s.NoError(s.onDemandManager.(CloudStopStartManager).StopInstance(h))
+	mock, ok := s.onDemandOpts.client.(*awsClientMock)
+	s.Require().True(ok)
+	s.Require().NotNil(mock.StopInstancesInput)
+	s.Equal("host_id", *mock.StopInstancesInput.InstanceIds[0])
+	found, err := host.FindOne(host.ById("host_id"))
+	s.NoError(err)
+	s.Equal(evergreen.HostStopping, found.Status)
+
+	s.NoError(h.SetStopped())
+	s.NoError(s.onDemandManager.(CloudStopStartManager).StartInstance(h))
+	s.Require().NotNil(mock.StartInstancesInput)
+	s.Equal("host_id", *mock.StartInstancesInput.InstanceIds[0])
+	found, err = host.FindOne(host.ById("host_id"))
+	s.NoError(err)
+	s.Equal(evergreen.HostRunning, found.Status)
+	s.Empty(found.Host)
+	s.False(found.LastActivityTime.IsZero())
+}
+
+func (s *EC2Suite) TestStopSpotInstance() {
+	h := &host.Host{
+		Id:     "host_id",
+		Status: evergreen.HostRunning,
+		Distro: distro.Distro{Provider: evergreen.ProviderNameEc2Spot},
+	}
+	s.NoError(h.Insert())
+	s.Error(s.spotManager.(CloudStopStartManager).StopInstance(h))
+	mock, ok := s.spotOpts.client.(*awsClientMock)
+	s.Require().True(ok)
+	s.Nil(mock.StopInstancesInput)
+}
+
 func (s *EC2Suite) TestIsUp() {
 	up, err := s.onDemandManager.IsUp(&host.Host{})
 	s.True(up)
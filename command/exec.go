@@ -56,6 +56,10 @@ type simpleExec struct {
 	// allows following commands to execute even if this shell command fails.
 	ContinueOnError bool `mapstructure:"continue_on_err"`
 
+	// container is the container that the process runs in, if the
+	// task runs in one.
+	container string
+
 	base
 }
 
@@ -127,7 +131,13 @@ func (c *simpleExec) getProc(taskID string, logger client.LoggerProducer) (subpr
 	c.Env[subprocess.MarkerTaskID] = taskID
 	c.Env[subprocess.MarkerAgentPID] = strconv.Itoa(os.Getpid())
 
-	proc, err := subprocess.NewLocalExec(c.Binary, c.Args, c.Env, c.WorkingDir)
+	var proc subprocess.Command
+	var err error
+	if c.container != "" {
+		proc, err = subprocess.NewContainerExec(c.container, c.Binary, c.Args, c.Env, c.WorkingDir)
+	} else {
+		proc, err = subprocess.NewLocalExec(c.Binary, c.Args, c.Env, c.WorkingDir)
+	}
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "problem constructing command wrapper")
 	}
@@ -187,6 +197,7 @@ func (c *simpleExec) Execute(ctx context.Context, comm client.Communicator, logg
 		return errors.WithStack(err)
 	}
 
+	c.container = conf.Container
 	proc, closer, err := c.getProc(conf.Task.Id, logger)
 	if err != nil {
 		logger.Execution().Warning(err.Error())
@@ -37,6 +37,11 @@ type Command interface {
 
 	IdleTimeout() time.Duration
 	SetIdleTimeout(time.Duration)
+
+	// Condition is the `if:` condition of the command, which the
+	// agent evaluates before running it.
+	Condition() string
+	SetCondition(string)
 }
 
 // base contains a basic implementation of functionality that is
@@ -45,6 +50,7 @@ type base struct {
 	idleTimeout time.Duration
 	typeName    string
 	displayName string
+	condition   string
 	mu          sync.RWMutex
 }
 
@@ -91,3 +97,17 @@ func (b *base) IdleTimeout() time.Duration {
 
 	return b.idleTimeout
 }
+
+func (b *base) SetCondition(c string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.condition = c
+}
+
+func (b *base) Condition() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return b.condition
+}
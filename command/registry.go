@@ -173,6 +173,7 @@ func (r *commandRegistry) renderCommands(commandInfo model.PluginCommandConf,
 		cmd.SetType(c.Type)
 		cmd.SetDisplayName(c.DisplayName)
 		cmd.SetIdleTimeout(time.Duration(c.TimeoutSecs) * time.Second)
+		cmd.SetCondition(c.If)
 
 		out = append(out, cmd)
 	}
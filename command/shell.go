@@ -124,11 +124,17 @@ func (c *shellExec) Execute(ctx context.Context,
 		opts.Error = logWriterErr
 	}
 
-	env := append(os.Environ(),
+	env := []string{
 		fmt.Sprintf("%s=%s", subprocess.MarkerTaskID, conf.Task.Id),
-		fmt.Sprintf("%s=%d", subprocess.MarkerAgentPID, os.Getpid()))
+		fmt.Sprintf("%s=%d", subprocess.MarkerAgentPID, os.Getpid()),
+	}
 
-	localCmd := subprocess.NewLocalCommand(c.Script, c.WorkingDir, c.Shell, env, true)
+	var localCmd subprocess.Command
+	if conf.Container != "" {
+		localCmd = subprocess.NewContainerCommand(conf.Container, c.Script, c.WorkingDir, c.Shell, env, true)
+	} else {
+		localCmd = subprocess.NewLocalCommand(c.Script, c.WorkingDir, c.Shell, append(os.Environ(), env...), true)
+	}
 	if err = localCmd.SetOutput(opts); err != nil {
 		return err
 	}
@@ -261,6 +261,67 @@ type NewRelicConfig struct {
 	LicenseKey      string `yaml:"license_key"`
 }
 
+// ArtifactSigningConfig holds the AWS credentials the servers use to sign
+// download links for artifacts uploaded to S3. The secret also signs the
+// links to the UI server's download route that are handed out to users.
+// Links are not signed if no key is set.
+type ArtifactSigningConfig struct {
+	Key               string `yaml:"aws_key"`
+	Secret            string `yaml:"aws_secret"`
+	Region            string `yaml:"region"`
+	ExpirationMinutes int    `yaml:"expiration_minutes"`
+}
+
+// Enabled returns true if artifact links should be signed.
+func (c ArtifactSigningConfig) Enabled() bool {
+	return c.Key != ""
+}
+
+// Expiration returns how long signed artifact links are valid for.
+func (c ArtifactSigningConfig) Expiration() time.Duration {
+	if c.ExpirationMinutes <= 0 {
+		return defaultArtifactLinkExpirationMinutes * time.Minute
+	}
+	return time.Duration(c.ExpirationMinutes) * time.Minute
+}
+
+// TracingConfig configures where the spans recorded by a process are
+// exported. Output is "stdout" or the path of a file that spans are appended
+// to; spans are not exported if it is empty.
+type TracingConfig struct {
+	Output string `yaml:"output"`
+}
+
+// GithubAppConfig identifies the GitHub App that Evergreen authenticates as to
+// create check runs for pull request builds. PrivateKey is the PEM encoded
+// key of the app, and APIURL overrides the GitHub API for GitHub Enterprise.
+// Check runs are not created if the app is not configured.
+type GithubAppConfig struct {
+	AppID      int64  `yaml:"app_id"`
+	PrivateKey string `yaml:"private_key"`
+	APIURL     string `yaml:"api_url"`
+}
+
+// Enabled returns true if check runs can be created with the app.
+func (c GithubAppConfig) Enabled() bool {
+	return c.AppID != 0 && c.PrivateKey != ""
+}
+
+// GitlabConfig identifies a self-hosted GitLab that sends merge request
+// webhooks. Token is an API token that can read the GitLab projects and post
+// commit statuses and notes, and WebhookSecret is the secret token of the
+// webhooks. Merge requests are not tested if GitLab is not configured.
+type GitlabConfig struct {
+	URL           string `yaml:"url"`
+	Token         string `yaml:"token"`
+	WebhookSecret string `yaml:"webhook_secret"`
+}
+
+// Enabled returns true if merge requests can be tested through GitLab.
+func (c GitlabConfig) Enabled() bool {
+	return c.URL != "" && c.Token != "" && c.WebhookSecret != ""
+}
+
 // Settings contains all configuration settings for running Evergreen.
 type Settings struct {
 	Database            DBSettings                `yaml:"database"`
@@ -293,6 +354,10 @@ type Settings struct {
 	PprofPort           string                    `yaml:"pprof_port"`
 	GithubPRCreatorOrg  string                    `yaml:"github_pr_creator_org"`
 	NewRelic            NewRelicConfig            `yaml:"new_relic"`
+	ArtifactSigning     ArtifactSigningConfig     `yaml:"artifact_signing"`
+	Tracing             TracingConfig             `yaml:"tracing"`
+	GithubApp           GithubAppConfig           `yaml:"github_app"`
+	Gitlab              GitlabConfig              `yaml:"gitlab"`
 }
 
 // NewSettings builds an in-memory representation of the given settings file.
@@ -656,6 +721,27 @@ var configValidationRules = []configValidator{
 		}
 		return nil
 	},
+
+	func(settings *Settings) error {
+		signing := &settings.ArtifactSigning
+		if !signing.Enabled() {
+			return nil
+		}
+		if signing.Secret == "" {
+			return errors.New("Artifact signing needs an AWS secret")
+		}
+		if signing.ExpirationMinutes < 0 {
+			return errors.New("Artifact link expiration can not be negative")
+		}
+		// S3 does not accept signatures that are valid for more than a week
+		if signing.Expiration() > 7*24*time.Hour {
+			return errors.New("Artifact links can not be valid for more than a week")
+		}
+		if signing.Region == "" {
+			signing.Region = defaultArtifactSigningRegion
+		}
+		return nil
+	},
 }
 
 func sliceContains(slice []string, elem string) bool {
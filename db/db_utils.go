@@ -3,6 +3,7 @@ package db
 import (
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
@@ -20,6 +21,8 @@ var (
 
 // Insert inserts the specified item into the specified collection.
 func Insert(collection string, item interface{}) error {
+	defer observeOperation("insert", collection, time.Now())
+
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
 		return nil
@@ -79,6 +82,8 @@ func DropIndex(collection string, key ...string) error {
 
 // Remove removes one item matching the query from the specified collection.
 func Remove(collection string, query interface{}) error {
+	defer observeOperation("remove", collection, time.Now())
+
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
 		return err
@@ -90,6 +95,8 @@ func Remove(collection string, query interface{}) error {
 
 // RemoveAll removes all items matching the query from the specified collection.
 func RemoveAll(collection string, query interface{}) error {
+	defer observeOperation("remove_all", collection, time.Now())
+
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
 		return err
@@ -105,6 +112,8 @@ func RemoveAll(collection string, query interface{}) error {
 func FindOne(collection string, query interface{},
 	projection interface{}, sort []string, out interface{}) error {
 
+	defer observeOperation("find_one", collection, time.Now())
+
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
 		grip.Errorf("error establishing db connection: %+v", err)
@@ -125,6 +134,8 @@ func FindAll(collection string, query interface{},
 	projection interface{}, sort []string, skip int, limit int,
 	out interface{}) error {
 
+	defer observeOperation("find_all", collection, time.Now())
+
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
 		grip.Errorf("error establishing db connection: %+v", err)
@@ -144,6 +155,8 @@ func FindAll(collection string, query interface{},
 func Update(collection string, query interface{},
 	update interface{}) error {
 
+	defer observeOperation("update", collection, time.Now())
+
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
 		grip.Errorf("error establishing db connection: %+v", err)
@@ -158,6 +171,8 @@ func Update(collection string, query interface{},
 // UpdateId updates one _id-matching document in the collection.
 func UpdateId(collection string, id, update interface{}) error {
 
+	defer observeOperation("update", collection, time.Now())
+
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
 		grip.Errorf("error establishing db connection: %+v", err)
@@ -173,6 +188,8 @@ func UpdateId(collection string, id, update interface{}) error {
 func UpdateAll(collection string, query interface{},
 	update interface{}) (*mgo.ChangeInfo, error) {
 
+	defer observeOperation("update_all", collection, time.Now())
+
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
 		grip.Errorf("error establishing db connection: %+v", err)
@@ -184,10 +201,68 @@ func UpdateAll(collection string, query interface{},
 	return db.C(collection).UpdateAll(query, update)
 }
 
+// maxWriteBatchSize is the most updates sent in each update command.
+const maxWriteBatchSize = 1000
+
+// BulkUpdate is an update of the first document matching its query, as one
+// of the updates of UpdateBulk.
+type BulkUpdate struct {
+	Query  interface{} `bson:"q"`
+	Update interface{} `bson:"u"`
+}
+
+// UpdateBulk runs the updates against the collection in unordered batches,
+// rather than making a round trip for each of them. Updates that don't
+// match a document are not errors.
+func UpdateBulk(collection string, updates []BulkUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	defer observeOperation("update_bulk", collection, time.Now())
+
+	session, db, err := GetGlobalSessionFactory().GetSession()
+	if err != nil {
+		grip.Errorf("error establishing db connection: %+v", err)
+
+		return err
+	}
+	defer session.Close()
+
+	catcher := grip.NewBasicCatcher()
+	for start := 0; start < len(updates); start += maxWriteBatchSize {
+		end := start + maxWriteBatchSize
+		if end > len(updates) {
+			end = len(updates)
+		}
+		res := struct {
+			WriteErrors []struct {
+				Index  int    `bson:"index"`
+				ErrMsg string `bson:"errmsg"`
+			} `bson:"writeErrors"`
+		}{}
+		err = db.Run(bson.D{
+			{Name: "update", Value: collection},
+			{Name: "updates", Value: updates[start:end]},
+			{Name: "ordered", Value: false},
+		}, &res)
+		if err != nil {
+			catcher.Add(errors.Wrapf(err, "error running bulk update of %s", collection))
+			continue
+		}
+		for _, writeErr := range res.WriteErrors {
+			catcher.Add(errors.Errorf("error running update %d of %s: %s", start+writeErr.Index, collection, writeErr.ErrMsg))
+		}
+	}
+	return catcher.Resolve()
+}
+
 // Upsert run the specified update against the collection as an upsert operation.
 func Upsert(collection string, query interface{},
 	update interface{}) (*mgo.ChangeInfo, error) {
 
+	defer observeOperation("upsert", collection, time.Now())
+
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
 		grip.Errorf("error establishing db connection: %+v", err)
@@ -202,6 +277,8 @@ func Upsert(collection string, query interface{},
 // Count run a count command with the specified query against the collection.
 func Count(collection string, query interface{}) (int, error) {
 
+	defer observeOperation("count", collection, time.Now())
+
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
 		grip.Errorf("error establishing db connection: %+v", err)
@@ -218,6 +295,8 @@ func Count(collection string, query interface{}) (int, error) {
 func FindAndModify(collection string, query interface{}, sort []string,
 	change mgo.Change, out interface{}) (*mgo.ChangeInfo, error) {
 
+	defer observeOperation("find_and_modify", collection, time.Now())
+
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
 		grip.Errorf("error establishing db connection: %+v", err)
@@ -280,6 +359,8 @@ func ClearGridCollections(fsPrefix string) error {
 // the results to the given "out" interface (usually a pointer
 // to an array of structs/bson.M)
 func Aggregate(collection string, pipeline interface{}, out interface{}) error {
+	defer observeOperation("aggregate", collection, time.Now())
+
 	session, db, err := GetGlobalSessionFactory().GetSession()
 	if err != nil {
 		err = errors.Wrap(err, "error establishing db connection")
@@ -525,3 +525,41 @@ func TestClearGridFSCollections(t *testing.T) {
 	assert.Error(err)
 	assert.Nil(reader)
 }
+
+func TestUpdateBulk(t *testing.T) {
+	assert := assert.New(t) //nolint
+
+	type doc struct {
+		Id    int `bson:"_id"`
+		Value int `bson:"value"`
+	}
+	collection := "test_collection"
+	assert.NoError(Clear(collection))
+
+	updates := []BulkUpdate{}
+	for i := 0; i < maxWriteBatchSize+10; i++ {
+		assert.NoError(Insert(collection, doc{Id: i}))
+		updates = append(updates, BulkUpdate{
+			Query:  bson.M{"_id": i},
+			Update: bson.M{"$set": bson.M{"value": i * 2}},
+		})
+	}
+	updates = append(updates, BulkUpdate{
+		Query:  bson.M{"_id": -1},
+		Update: bson.M{"$set": bson.M{"value": 1}},
+	})
+	assert.NoError(UpdateBulk(collection, updates))
+	assert.NoError(UpdateBulk(collection, nil))
+
+	out := []doc{}
+	assert.NoError(FindAll(collection, bson.M{}, NoProjection, []string{"_id"}, NoSkip, NoLimit, &out))
+	assert.Len(out, maxWriteBatchSize+10)
+	for _, d := range out {
+		assert.Equal(d.Id*2, d.Value)
+	}
+
+	assert.Error(UpdateBulk(collection, []BulkUpdate{{
+		Query:  bson.M{"_id": 0},
+		Update: bson.M{"$set": bson.M{"_id": 5}},
+	}}))
+}
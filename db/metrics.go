@@ -0,0 +1,19 @@
+package db
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/metrics"
+)
+
+// operationLatency records the duration of the database operations made
+// through this package.
+var operationLatency = metrics.NewHistogramVec("evergreen_db_operation_duration_seconds",
+	"Duration of database operations by operation and collection.",
+	metrics.DefaultLatencyBuckets, "operation", "collection")
+
+// observeOperation records the duration of an operation that started at the
+// given time. It is meant to be deferred at the start of the operation.
+func observeOperation(operation, collection string, start time.Time) {
+	operationLatency.Observe(time.Since(start).Seconds(), operation, collection)
+}
@@ -15,6 +15,7 @@ type (
 const (
 	User            = "mci"
 	GithubPatchUser = "github_pull_request"
+	GitlabPatchUser = "gitlab_merge_request"
 
 	HostRunning         = "running"
 	HostTerminated      = "terminated"
@@ -25,6 +26,8 @@ const (
 	HostUnreachable     = "unreachable"
 	HostQuarantined     = "quarantined"
 	HostDecommissioned  = "decommissioned"
+	HostStopping        = "stopping"
+	HostStopped         = "stopped"
 
 	HostStatusSuccess = "success"
 	HostStatusFailed  = "failed"
@@ -150,7 +153,11 @@ const (
 	// version requester types
 	PatchVersionRequester       = "patch_request"
 	GithubPRRequester           = "github_pull_request"
+	GitlabMRRequester           = "gitlab_merge_request"
 	RepotrackerVersionRequester = "gitter_request"
+	TriggerRequester            = "trigger_request"
+	PeriodicBuildRequester      = "periodic_build_request"
+	GitTagRequester             = "git_tag"
 )
 
 const (
@@ -160,6 +167,9 @@ const (
 	defaultAmboyLocalStorageSize = 1024
 	defaultAmboyQueueName        = "evg.service"
 	defaultAmboyDBName           = "amboy"
+
+	defaultArtifactLinkExpirationMinutes = 15
+	defaultArtifactSigningRegion         = "us-east-1"
 )
 
 // NameTimeFormat is the format in which to log times like instance start time.
@@ -199,5 +209,6 @@ func IsSystemActivator(caller string) bool {
 }
 
 func IsPatchRequester(requester string) bool {
-	return requester == PatchVersionRequester || requester == GithubPRRequester
+	return requester == PatchVersionRequester || requester == GithubPRRequester ||
+		requester == GitlabMRRequester
 }
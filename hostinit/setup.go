@@ -25,6 +25,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/notify"
 	"github.com/evergreen-ci/evergreen/subprocess"
+	"github.com/evergreen-ci/evergreen/tracing"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
@@ -538,7 +539,16 @@ func (init *HostInit) expandScript(s string) (string, error) {
 }
 
 // Provision the host, and update the database accordingly.
-func (init *HostInit) ProvisionHost(ctx context.Context, h *host.Host) error {
+func (init *HostInit) ProvisionHost(ctx context.Context, h *host.Host) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "hostinit.ProvisionHost")
+	span.SetAttribute("host_id", h.Id)
+	span.SetAttribute("distro_id", h.Distro.Id)
+	span.SetAttribute("provider", h.Provider)
+	defer func() {
+		span.SetError(err)
+		span.End()
+	}()
+
 	grip.Infoln(message.Fields{
 		"runner":  RunnerName,
 		"host":    h.Id,
@@ -52,9 +52,11 @@ func buildApp() *cli.App {
 		operations.Fetch(),
 		operations.Evaluate(),
 		operations.Validate(),
+		operations.RunTask(),
 		operations.List(),
 		operations.TestHistory(),
 		operations.LastGreen(),
+		operations.CompareVersions(),
 
 		// Patch creation and management commands (top-level)
 		operations.Patch(),
@@ -0,0 +1,393 @@
+/*
+Package metrics holds the gauges, counters and histograms that describe the
+health of Evergreen, and writes them in the Prometheus text exposition format
+so that they can be scraped from the /metrics endpoint of a process.
+
+Metrics are registered with a Registry, usually the package's Default
+registry. Values that are measured in process, such as request latencies, are
+recorded as they happen. Values that are read from the database, such as the
+length of the task queues, are set by collector functions that the registry
+calls each time it is scraped.
+*/
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	gaugeType     = "gauge"
+	counterType   = "counter"
+	histogramType = "histogram"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the histogram
+// buckets used for request and operation latencies.
+var DefaultLatencyBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// CollectorFunc updates the metrics that are read from outside of the
+// process before the registry is written.
+type CollectorFunc func() error
+
+// Registry holds a set of metrics.
+type Registry struct {
+	mu         sync.Mutex
+	metrics    []metric
+	names      map[string]bool
+	collectors []CollectorFunc
+
+	// collecting serializes the collectors of concurrent scrapes.
+	collecting sync.Mutex
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{names: map[string]bool{}}
+}
+
+// Default is the registry that metrics are registered with by the package
+// level constructors, and that the Handler writes.
+var Default = NewRegistry()
+
+type metric interface {
+	name() string
+	write(io.Writer) error
+}
+
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[m.name()] {
+		panic(fmt.Sprintf("metric '%s' is already registered", m.name()))
+	}
+	r.names[m.name()] = true
+	r.metrics = append(r.metrics, m)
+}
+
+// AddCollector registers a function that is called before each time the
+// registry is written.
+func (r *Registry) AddCollector(c CollectorFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+// Collect runs the registry's collectors. Errors are logged, so that a
+// failing collector doesn't prevent the other metrics from being written.
+func (r *Registry) Collect() {
+	r.mu.Lock()
+	collectors := append([]CollectorFunc{}, r.collectors...)
+	r.mu.Unlock()
+
+	r.collecting.Lock()
+	defer r.collecting.Unlock()
+	for _, c := range collectors {
+		grip.Warning(message.WrapError(c(), message.Fields{
+			"message": "problem collecting metrics",
+		}))
+	}
+}
+
+// Write runs the registry's collectors and writes all of its metrics in the
+// Prometheus text format.
+func (r *Registry) Write(w io.Writer) error {
+	r.Collect()
+
+	r.mu.Lock()
+	metrics := append([]metric{}, r.metrics...)
+	r.mu.Unlock()
+
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].name() < metrics[j].name() })
+	for _, m := range metrics {
+		if err := m.write(w); err != nil {
+			return errors.Wrapf(err, "error writing metric '%s'", m.name())
+		}
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// Vectors of series partitioned by label values
+
+type desc struct {
+	metricName string
+	help       string
+	metricType string
+	labels     []string
+}
+
+func (d *desc) name() string { return d.metricName }
+
+func (d *desc) writeHeader(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.metricName, escapeHelp(d.help), d.metricName, d.metricType)
+	return err
+}
+
+// key joins label values into a map key. The values are checked against
+// the number of labels, since a mismatch is a programming error.
+func (d *desc) key(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metric '%s' has %d labels, got %d values", d.metricName, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// labelPairs formats the labels of a series, with any extra pairs appended.
+func (d *desc) labelPairs(values []string, extra ...string) string {
+	pairs := []string{}
+	for i, l := range d.labels {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, l, escapeLabelValue(values[i])))
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extra[i], escapeLabelValue(extra[i+1])))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+type series struct {
+	labels []string
+	value  float64
+}
+
+// valueVec holds the series of a gauge or a counter.
+type valueVec struct {
+	desc
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+func (v *valueVec) get(values []string) *series {
+	key := v.key(values)
+	s, ok := v.series[key]
+	if !ok {
+		s = &series{labels: append([]string{}, values...)}
+		v.series[key] = s
+	}
+	return s
+}
+
+func (v *valueVec) write(w io.Writer) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err := v.writeHeader(w); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(v.series) {
+		s := v.series[key]
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", v.metricName, v.labelPairs(s.labels), formatFloat(s.value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GaugeVec is a set of values that can go up and down, partitioned by
+// label values.
+type GaugeVec struct {
+	valueVec
+}
+
+// NewGaugeVec creates a gauge vector and registers it with the registry.
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	g := &GaugeVec{valueVec{
+		desc:   desc{metricName: name, help: help, metricType: gaugeType, labels: labels},
+		series: map[string]*series{},
+	}}
+	r.register(g)
+	return g
+}
+
+// NewGaugeVec creates a gauge vector in the default registry.
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return Default.NewGaugeVec(name, help, labels...)
+}
+
+// Set sets the value of the series with the given label values.
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.get(labelValues).value = value
+}
+
+// Reset removes all of the series, so that the values of label combinations
+// that no longer exist are not reported.
+func (g *GaugeVec) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.series = map[string]*series{}
+}
+
+// CounterVec is a set of values that only go up, partitioned by label
+// values.
+type CounterVec struct {
+	valueVec
+}
+
+// NewCounterVec creates a counter vector and registers it with the registry.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{valueVec{
+		desc:   desc{metricName: name, help: help, metricType: counterType, labels: labels},
+		series: map[string]*series{},
+	}}
+	r.register(c)
+	return c
+}
+
+// NewCounterVec creates a counter vector in the default registry.
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return Default.NewCounterVec(name, help, labels...)
+}
+
+// Add adds a non-negative value to the series with the given label values.
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	if value < 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.get(labelValues).value += value
+}
+
+// Inc adds one to the series with the given label values.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// HistogramVec counts observations in buckets, partitioned by label values.
+type HistogramVec struct {
+	desc
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labels []string
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewHistogramVec creates a histogram vector with the given bucket upper
+// bounds and registers it with the registry.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	bounds := append([]float64{}, buckets...)
+	sort.Float64s(bounds)
+	h := &HistogramVec{
+		desc:    desc{metricName: name, help: help, metricType: histogramType, labels: labels},
+		buckets: bounds,
+		series:  map[string]*histogramSeries{},
+	}
+	r.register(h)
+	return h
+}
+
+// NewHistogramVec creates a histogram vector in the default registry.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return Default.NewHistogramVec(name, help, buckets, labels...)
+}
+
+// Observe records a value in the series with the given label values.
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := h.key(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{labels: append([]string{}, labelValues...), counts: make([]uint64, len(h.buckets))}
+		h.series[key] = s
+	}
+	for i, bound := range h.buckets {
+		if value <= bound {
+			s.counts[i]++
+			break
+		}
+	}
+	s.count++
+	s.sum += value
+}
+
+func (h *HistogramVec) write(w io.Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := h.writeHeader(w); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(h.series) {
+		s := h.series[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, h.labelPairs(s.labels, "le", formatFloat(bound)), cumulative); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n%s_sum%s %s\n%s_count%s %d\n",
+			h.metricName, h.labelPairs(s.labels, "le", "+Inf"), s.count,
+			h.metricName, h.labelPairs(s.labels), formatFloat(s.sum),
+			h.metricName, h.labelPairs(s.labels), s.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// Formatting
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpEscaper.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelValueEscaper.Replace(s)
+}
+
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+	switch series := m.(type) {
+	case map[string]*series:
+		for k := range series {
+			keys = append(keys, k)
+		}
+	case map[string]*histogramSeries:
+		for k := range series {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryWrite(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	r := NewRegistry()
+	queue := r.NewGaugeVec("test_queue_length", "Tasks in the queue.", "distro")
+	runs := r.NewCounterVec("test_runs_total", "Runs of a \\ runner\nby name.", "runner")
+	latency := r.NewHistogramVec("test_latency_seconds", "Latency.", []float64{1, 0.1}, "route")
+
+	collected := 0
+	r.AddCollector(func() error {
+		collected++
+		queue.Reset()
+		queue.Set(3, "ubuntu")
+		queue.Set(1.5, `win"dows`)
+		return nil
+	})
+	r.AddCollector(func() error { return errors.New("collector failed") })
+
+	queue.Set(10, "removed by the collector")
+	runs.Inc("scheduler")
+	runs.Add(2, "scheduler")
+	runs.Add(-1, "scheduler")
+	latency.Observe(0.05, "/hosts")
+	latency.Observe(0.5, "/hosts")
+	latency.Observe(5, "/hosts")
+
+	buf := &bytes.Buffer{}
+	require.NoError(r.Write(buf))
+	assert.Equal(1, collected)
+	assert.Equal(`# HELP test_latency_seconds Latency.
+# TYPE test_latency_seconds histogram
+test_latency_seconds_bucket{route="/hosts",le="0.1"} 1
+test_latency_seconds_bucket{route="/hosts",le="1"} 2
+test_latency_seconds_bucket{route="/hosts",le="+Inf"} 3
+test_latency_seconds_sum{route="/hosts"} 5.55
+test_latency_seconds_count{route="/hosts"} 3
+# HELP test_queue_length Tasks in the queue.
+# TYPE test_queue_length gauge
+test_queue_length{distro="ubuntu"} 3
+test_queue_length{distro="win\"dows"} 1.5
+# HELP test_runs_total Runs of a \\ runner\nby name.
+# TYPE test_runs_total counter
+test_runs_total{runner="scheduler"} 3
+`, buf.String())
+
+	assert.Panics(func() { r.NewGaugeVec("test_queue_length", "duplicate") })
+	assert.Panics(func() { queue.Set(1, "ubuntu", "extra") })
+}
+
+func TestHandlers(t *testing.T) {
+	assert := assert.New(t)
+
+	r := NewRegistry()
+	latency := r.NewHistogramVec("test_request_seconds", "Request latency.", DefaultLatencyBuckets, "route", "method", "code")
+	handler := InstrumentHandler(latency, "/hosts/{host_id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/hosts/h1", nil))
+
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(http.StatusOK, w.Code)
+	assert.Equal(ContentType, w.Header().Get("Content-Type"))
+	assert.Contains(w.Body.String(), `test_request_seconds_count{route="/hosts/{host_id}",method="GET",code="404"} 1`)
+}
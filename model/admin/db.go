@@ -27,6 +27,7 @@ var (
 	schedulerKey                    = bsonutil.MustHaveTag(ServiceFlags{}, "SchedulerDisabled")
 	githubPRTestingDisabledKey      = bsonutil.MustHaveTag(ServiceFlags{}, "GithubPRTestingDisabled")
 	repotrackerPushEventDisabledKey = bsonutil.MustHaveTag(ServiceFlags{}, "RepotrackerPushEventDisabled")
+	gitlabMRTestingDisabledKey      = bsonutil.MustHaveTag(ServiceFlags{}, "GitlabMRTestingDisabled")
 )
 
 var settingsQuery = db.Query(bson.M{idKey: systemSettingsDocID})
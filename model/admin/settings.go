@@ -21,6 +21,7 @@ type ServiceFlags struct {
 	SchedulerDisabled            bool `bson:"scheduler_disabled" json:"scheduler_disabled"`
 	GithubPRTestingDisabled      bool `bson:"github_pr_testing_disabled" json:"github_pr_testing_disabled"`
 	RepotrackerPushEventDisabled bool `bson:"repotracker_push_event_disabled" json:"repotracker_push_event_disabled"`
+	GitlabMRTestingDisabled      bool `bson:"gitlab_mr_testing_disabled" json:"gitlab_mr_testing_disabled"`
 }
 
 // supported banner themes in Evergreen
@@ -19,6 +19,7 @@ var (
 	FirstTaskTypeFailureId = "first_tasktype_failure"
 	TaskFailTransitionId   = "task_transition_failure"
 	LastRevisionNotFound   = "last_revision_not_found"
+	StepbackCulpritId      = "stepback_culprit"
 )
 
 // Host triggers
@@ -45,6 +45,15 @@ func ByTaskIdWithoutExecution(id string) db.Q {
 	})
 }
 
+// ByTaskIds returns a query for entries with any of the given Task Ids
+func ByTaskIds(ids []string) db.Q {
+	return db.Query(bson.M{
+		TaskIdKey: bson.M{
+			"$in": ids,
+		},
+	})
+}
+
 // ByBuildId returns all entries with the given Build Id, sorted by Task name
 func ByBuildId(id string) db.Q {
 	return db.Query(bson.D{{BuildIdKey, id}}).Sort([]string{TaskNameKey})
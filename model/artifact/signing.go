@@ -0,0 +1,161 @@
+package artifact
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/evergreen-ci/evergreen"
+	"github.com/pkg/errors"
+)
+
+// s3Host is the host of the path-style links that s3.put attaches.
+const s3Host = "s3.amazonaws.com"
+
+// S3Location returns the bucket and key of a file that was uploaded to S3,
+// parsed from its link. It returns false for files stored anywhere else.
+func (f *File) S3Location() (string, string, bool) {
+	u, err := url.Parse(f.Link)
+	if err != nil || u.Host != s3Host {
+		return "", "", false
+	}
+	parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+// StripHiddenFiles returns the files that users may see. Files with private
+// visibility are only shown to logged in users.
+func StripHiddenFiles(files []File, hasUser bool) []File {
+	publicFiles := []File{}
+	for _, file := range files {
+		switch {
+		case file.Visibility == None:
+			continue
+		case file.Visibility == Private && !hasUser:
+			continue
+		default:
+			publicFiles = append(publicFiles, file)
+		}
+	}
+	return publicFiles
+}
+
+// LinkSigner signs the links of files stored in S3, so that they can be
+// downloaded without AWS credentials until the signature expires.
+type LinkSigner struct {
+	conf evergreen.ArtifactSigningConfig
+	svc  *s3.S3
+}
+
+// NewLinkSigner returns a signer using the given configuration.
+func NewLinkSigner(conf evergreen.ArtifactSigningConfig) (*LinkSigner, error) {
+	if !conf.Enabled() {
+		return nil, errors.New("artifact signing is not configured")
+	}
+	region := conf.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Credentials: credentials.NewStaticCredentials(conf.Key, conf.Secret, ""),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "problem creating AWS session")
+	}
+	return &LinkSigner{conf: conf, svc: s3.New(sess)}, nil
+}
+
+// Sign returns the file with its link replaced by a signed, expiring link,
+// and whether the file is stored in S3. Files stored anywhere else are
+// returned unchanged.
+func (s *LinkSigner) Sign(f File) (File, bool, error) {
+	bucket, key, ok := f.S3Location()
+	if !ok {
+		return f, false, nil
+	}
+	req, _ := s.svc.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	link, err := req.Presign(s.conf.Expiration())
+	if err != nil {
+		return f, false, errors.Wrapf(err, "problem signing link for '%s'", f.Name)
+	}
+	f.Link = link
+	return f, true, nil
+}
+
+// Download is an artifact that a download link was signed for, and the user
+// it was signed for.
+type Download struct {
+	TaskID string
+	UserID string
+	File   File
+}
+
+// DownloadLink returns the file with its link replaced by a link to the
+// server's artifact download route, which records that the user downloaded
+// the file and redirects to a signed link, and whether the file is stored
+// in S3. The download link is valid until the signature expires. Files
+// stored anywhere else are returned unchanged.
+func (s *LinkSigner) DownloadLink(rootURL, taskID, userID string, f File, now time.Time) (File, bool) {
+	if _, _, ok := f.S3Location(); !ok {
+		return f, false
+	}
+	query := url.Values{}
+	query.Set("name", f.Name)
+	query.Set("link", f.Link)
+	query.Set("user", userID)
+	query.Set("expires", strconv.FormatInt(now.Add(s.conf.Expiration()).Unix(), 10))
+	query.Set("signature", s.downloadSignature(taskID, query))
+	f.Link = fmt.Sprintf("%s/artifact/%s?%s", strings.TrimSuffix(rootURL, "/"), url.PathEscape(taskID), query.Encode())
+	return f, true
+}
+
+// VerifyDownload returns the download that the query of a download link of
+// the task was signed for, if its signature is valid and it hasn't expired.
+func (s *LinkSigner) VerifyDownload(taskID string, query url.Values, now time.Time) (*Download, error) {
+	signature, err := hex.DecodeString(query.Get("signature"))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid signature")
+	}
+	expected, _ := hex.DecodeString(s.downloadSignature(taskID, query))
+	if !hmac.Equal(signature, expected) {
+		return nil, errors.New("invalid signature")
+	}
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid expiration")
+	}
+	if now.After(time.Unix(expires, 0)) {
+		return nil, errors.New("download link has expired")
+	}
+	return &Download{
+		TaskID: taskID,
+		UserID: query.Get("user"),
+		File:   File{Name: query.Get("name"), Link: query.Get("link")},
+	}, nil
+}
+
+func (s *LinkSigner) downloadSignature(taskID string, query url.Values) string {
+	mac := hmac.New(sha256.New, []byte(s.conf.Secret))
+	for _, part := range []string{taskID, query.Get("name"), query.Get("link"), query.Get("user"), query.Get("expires")} {
+		// prefix each part with its length so that parts can't be shifted
+		// into each other
+		fmt.Fprintf(mac, "%d:%s", len(part), part)
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
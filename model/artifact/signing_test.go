@@ -0,0 +1,140 @@
+package artifact
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3Location(t *testing.T) {
+	assert := assert.New(t)
+
+	f := File{Link: "https://s3.amazonaws.com/mciuploads/project/task/file.tgz"}
+	bucket, key, ok := f.S3Location()
+	assert.True(ok)
+	assert.Equal("mciuploads", bucket)
+	assert.Equal("project/task/file.tgz", key)
+
+	for _, link := range []string{
+		"http://placekitten.com/800/600",
+		"https://s3.amazonaws.com/mciuploads",
+		"https://s3.amazonaws.com/mciuploads/",
+		"not a url %%",
+	} {
+		f = File{Link: link}
+		_, _, ok = f.S3Location()
+		assert.False(ok, link)
+	}
+}
+
+func TestStripHiddenFiles(t *testing.T) {
+	assert := assert.New(t)
+	files := []File{
+		{Name: "public", Visibility: Public},
+		{Name: "default"},
+		{Name: "private", Visibility: Private},
+		{Name: "none", Visibility: None},
+	}
+
+	stripped := StripHiddenFiles(files, false)
+	assert.Len(stripped, 2)
+	assert.Equal("public", stripped[0].Name)
+	assert.Equal("default", stripped[1].Name)
+
+	stripped = StripHiddenFiles(files, true)
+	assert.Len(stripped, 3)
+	assert.Equal("private", stripped[2].Name)
+}
+
+func TestLinkSigner(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	_, err := NewLinkSigner(evergreen.ArtifactSigningConfig{})
+	assert.Error(err)
+
+	signer, err := NewLinkSigner(evergreen.ArtifactSigningConfig{
+		Key:               "key",
+		Secret:            "secret",
+		ExpirationMinutes: 5,
+	})
+	require.NoError(err)
+
+	// files stored outside of S3 are not signed
+	f := File{Name: "kitten", Link: "http://placekitten.com/800/600"}
+	signed, ok, err := signer.Sign(f)
+	assert.NoError(err)
+	assert.False(ok)
+	assert.Equal(f, signed)
+
+	f = File{Name: "archive", Link: "https://s3.amazonaws.com/mciuploads/project/file.tgz", Visibility: Private}
+	signed, ok, err = signer.Sign(f)
+	require.NoError(err)
+	assert.True(ok)
+	assert.Equal("archive", signed.Name)
+	assert.Equal(Private, signed.Visibility)
+
+	u, err := url.Parse(signed.Link)
+	require.NoError(err)
+	assert.Contains(u.Host+u.Path, "mciuploads")
+	assert.Contains(u.Path, "project/file.tgz")
+	assert.Equal("300", u.Query().Get("X-Amz-Expires"))
+	assert.NotEmpty(u.Query().Get("X-Amz-Signature"))
+}
+
+func TestDownloadLink(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	signer, err := NewLinkSigner(evergreen.ArtifactSigningConfig{
+		Key:               "key",
+		Secret:            "secret",
+		ExpirationMinutes: 5,
+	})
+	require.NoError(err)
+	now := time.Now()
+
+	// files stored outside of S3 are not linked through the server
+	f := File{Name: "kitten", Link: "http://placekitten.com/800/600"}
+	linked, ok := signer.DownloadLink("https://evergreen.example.com/", "task 1", "user", f, now)
+	assert.False(ok)
+	assert.Equal(f, linked)
+
+	f = File{Name: "archive", Link: "https://s3.amazonaws.com/mciuploads/project/file.tgz", Visibility: Private}
+	linked, ok = signer.DownloadLink("https://evergreen.example.com/", "task 1", "user", f, now)
+	assert.True(ok)
+	assert.Equal("archive", linked.Name)
+	assert.Equal(Private, linked.Visibility)
+
+	u, err := url.Parse(linked.Link)
+	require.NoError(err)
+	assert.Equal("evergreen.example.com", u.Host)
+	assert.Equal("/artifact/task 1", u.Path)
+
+	download, err := signer.VerifyDownload("task 1", u.Query(), now.Add(time.Minute))
+	require.NoError(err)
+	assert.Equal(&Download{TaskID: "task 1", UserID: "user", File: File{Name: "archive", Link: f.Link}}, download)
+
+	// expired links, links of other tasks and changed links are rejected
+	_, err = signer.VerifyDownload("task 1", u.Query(), now.Add(10*time.Minute))
+	assert.Error(err)
+	_, err = signer.VerifyDownload("task 2", u.Query(), now)
+	assert.Error(err)
+	query := u.Query()
+	query.Set("user", "other")
+	_, err = signer.VerifyDownload("task 1", query, now)
+	assert.Error(err)
+	query = u.Query()
+	query.Set("link", "https://s3.amazonaws.com/mciuploads/project/secret.tgz")
+	_, err = signer.VerifyDownload("task 1", query, now)
+	assert.Error(err)
+
+	other, err := NewLinkSigner(evergreen.ArtifactSigningConfig{Key: "key", Secret: "other"})
+	require.NoError(err)
+	_, err = other.VerifyDownload("task 1", u.Query(), now)
+	assert.Error(err)
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/pkg/errors"
+)
+
+// SignArtifactLinks replaces the links of a task's files that are stored in
+// S3 with signed links to the artifact download route, which records each
+// download and redirects to an expiring S3 link, so that users can download
+// them without AWS credentials. Callers strip the files the user may not see
+// first. The files of private projects are only signed for logged in users.
+// Links are left as they are if artifact signing is not configured.
+func SignArtifactLinks(taskID string, files []artifact.File, projectRef *ProjectRef, u *user.DBUser, settings *evergreen.Settings) ([]artifact.File, error) {
+	if projectRef != nil && projectRef.Private && u == nil {
+		return nil, errors.Errorf("project '%s' is private", projectRef.Identifier)
+	}
+	if settings == nil || !settings.ArtifactSigning.Enabled() || len(files) == 0 {
+		return files, nil
+	}
+
+	signer, err := artifact.NewLinkSigner(settings.ArtifactSigning)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	userID := ""
+	if u != nil {
+		userID = u.Id
+	}
+	now := time.Now()
+	signedFiles := make([]artifact.File, 0, len(files))
+	for _, f := range files {
+		signedFile, _ := signer.DownloadLink(settings.Ui.Url, taskID, userID, f, now)
+		signedFiles = append(signedFiles, signedFile)
+	}
+	return signedFiles, nil
+}
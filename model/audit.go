@@ -2,10 +2,12 @@ package model
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/anser/bsonutil"
 	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2/bson"
@@ -78,10 +80,11 @@ func AuditHostTaskConsistency() ([]HostTaskInconsistency, error) {
 
 // loadHostTaskMapping queries the DB for hosts with tasks, the tasks assigned in the hosts'
 // running task fields, all running (or dispatched) tasks, and the hosts in those tasks'
-// host id field. Returns a mapping of host Ids to task Ids and task Ids to host Ids,
-// representing both directions of the relationship.
-func loadHostTaskMapping() (map[string]string, map[string]string, error) {
-	hostToTask := map[string]string{}
+// host id field. Returns a mapping of host Ids to the Ids of the tasks in their running
+// task and task slots, and task Ids to host Ids, representing both directions of the
+// relationship.
+func loadHostTaskMapping() (map[string][]string, map[string]string, error) {
+	hostToTask := map[string][]string{}
 	hostTaskIds := []string{}
 	taskToHost := map[string]string{}
 	taskHostIds := []string{}
@@ -94,7 +97,7 @@ func loadHostTaskMapping() (map[string]string, map[string]string, error) {
 	}
 
 	for _, h := range runningHosts {
-		hostTaskIds = append(hostTaskIds, h.RunningTask)
+		hostTaskIds = append(hostTaskIds, h.RunningTaskIds()...)
 	}
 	hostsTasks, err := task.Find(task.ByIds(hostTaskIds))
 	if err != nil {
@@ -118,9 +121,9 @@ func loadHostTaskMapping() (map[string]string, map[string]string, error) {
 
 	// we only want to have running hosts that are not empty.
 	for _, h := range append(runningHosts, tasksHosts...) {
-		// if the running task is empty don't add it to the map
-		if h.RunningTask != "" {
-			hostToTask[h.Id] = h.RunningTask
+		// if the host isn't running any tasks don't add it to the map
+		if ids := h.RunningTaskIds(); len(ids) > 0 {
+			hostToTask[h.Id] = ids
 		}
 	}
 
@@ -129,31 +132,33 @@ func loadHostTaskMapping() (map[string]string, map[string]string, error) {
 
 // auditHostMapping takes a mapping of hosts->tasks and tasks->hosts and
 // returns descriptions of any inconsistencies.
-func auditHostTaskMapping(hostToTask, taskToHost map[string]string) []HostTaskInconsistency {
+func auditHostTaskMapping(hostToTask map[string][]string, taskToHost map[string]string) []HostTaskInconsistency {
 	found := []HostTaskInconsistency{}
 	// cases where a host thinks its running a task that it isn't
-	for h, t := range hostToTask {
-		cachedTask, ok := taskToHost[t]
-		if !ok {
-			// host thinks it is running a task that does not exist
-			found = append(found, HostTaskInconsistency{
-				Host:          h,
-				HostTaskCache: t,
-			})
-		} else {
-			if cachedTask != h {
+	for h, tasks := range hostToTask {
+		for _, t := range tasks {
+			cachedTask, ok := taskToHost[t]
+			if !ok {
+				// host thinks it is running a task that does not exist
 				found = append(found, HostTaskInconsistency{
 					Host:          h,
 					HostTaskCache: t,
-					Task:          t,
-					TaskHostCache: cachedTask,
 				})
+			} else {
+				if cachedTask != h {
+					found = append(found, HostTaskInconsistency{
+						Host:          h,
+						HostTaskCache: t,
+						Task:          t,
+						TaskHostCache: cachedTask,
+					})
+				}
 			}
 		}
 	}
 	// cases where a task thinks it is running on a host that isnt running it
 	for t, h := range taskToHost {
-		cachedHost, ok := hostToTask[h]
+		cachedTasks, ok := hostToTask[h]
 		if !ok {
 			// task thinks it is running on a host that does not exist
 			found = append(found, HostTaskInconsistency{
@@ -161,12 +166,12 @@ func auditHostTaskMapping(hostToTask, taskToHost map[string]string) []HostTaskIn
 				TaskHostCache: h,
 			})
 		} else {
-			if cachedHost != t {
+			if !util.StringSliceContains(cachedTasks, t) {
 				found = append(found, HostTaskInconsistency{
 					Task:          t,
 					TaskHostCache: h,
 					Host:          h,
-					HostTaskCache: cachedHost,
+					HostTaskCache: strings.Join(cachedTasks, ","),
 				})
 			}
 		}
@@ -15,12 +15,12 @@ import (
 func TestHostTaskAuditing(t *testing.T) {
 	Convey("With pre-made sets of mappings", t, func() {
 		Convey("a valid mapping should return no inconsistencies", func() {
-			h2t := map[string]string{"h1": "t1", "h2": "t2", "h3": "t3"}
+			h2t := map[string][]string{"h1": {"t1"}, "h2": {"t2"}, "h3": {"t3"}}
 			t2h := map[string]string{"t1": "h1", "t2": "h2", "t3": "h3"}
 			So(len(auditHostTaskMapping(h2t, t2h)), ShouldEqual, 0)
 		})
 		Convey("a mismapped host should return one inconsistency", func() {
-			h2t := map[string]string{"h1": "t1", "h2": "t2", "h3": "t3", "h4": "t1"}
+			h2t := map[string][]string{"h1": {"t1"}, "h2": {"t2"}, "h3": {"t3"}, "h4": {"t1"}}
 			t2h := map[string]string{"t1": "h4", "t2": "h2", "t3": "h3"}
 			out := auditHostTaskMapping(h2t, t2h)
 			So(len(out), ShouldEqual, 1)
@@ -29,13 +29,18 @@ func TestHostTaskAuditing(t *testing.T) {
 			})
 		})
 		Convey("a swapped host and task should return four inconsistencies", func() {
-			h2t := map[string]string{"h1": "t3", "h2": "t2", "h3": "t1"}
+			h2t := map[string][]string{"h1": {"t3"}, "h2": {"t2"}, "h3": {"t1"}}
 			t2h := map[string]string{"t1": "h1", "t2": "h2", "t3": "h3"}
 			out := auditHostTaskMapping(h2t, t2h)
 			So(len(out), ShouldEqual, 4)
 		})
+		Convey("a host running tasks in its task slots should return no inconsistencies", func() {
+			h2t := map[string][]string{"h1": {"t1", "t2"}, "h2": {"t3"}}
+			t2h := map[string]string{"t1": "h1", "t2": "h1", "t3": "h2"}
+			So(len(auditHostTaskMapping(h2t, t2h)), ShouldEqual, 0)
+		})
 		Convey("one empty mapping should return inconsistencies", func() {
-			h2t := map[string]string{"h1": "t1", "h2": "t2", "h3": "t3"}
+			h2t := map[string][]string{"h1": {"t1"}, "h2": {"t2"}, "h3": {"t3"}}
 			out := auditHostTaskMapping(h2t, nil)
 			So(len(out), ShouldEqual, 3)
 			Convey("with reasonable error language", func() {
@@ -72,7 +77,7 @@ func TestHostTaskAuditing(t *testing.T) {
 				h2t, t2h, err := loadHostTaskMapping()
 				So(err, ShouldBeNil)
 				So(len(h2t), ShouldEqual, 1)
-				So(h2t["h1"], ShouldEqual, "t1")
+				So(h2t["h1"], ShouldResemble, []string{"t1"})
 				So(len(t2h), ShouldEqual, 0)
 			})
 		})
@@ -93,8 +98,8 @@ func TestHostTaskAuditing(t *testing.T) {
 				h2t, t2h, err := loadHostTaskMapping()
 				So(err, ShouldBeNil)
 				So(len(h2t), ShouldEqual, 2)
-				So(h2t["h1"], ShouldEqual, "t1")
-				So(h2t["h2"], ShouldEqual, "t2")
+				So(h2t["h1"], ShouldResemble, []string{"t1"})
+				So(h2t["h2"], ShouldResemble, []string{"t2"})
 				So(len(t2h), ShouldEqual, 2)
 				So(t2h["t1"], ShouldEqual, "h1")
 				So(t2h["t2"], ShouldEqual, "h2")
@@ -144,7 +149,7 @@ func TestHostTaskAuditing(t *testing.T) {
 			So(len(h2t), ShouldEqual, 1)
 			So(len(t2h), ShouldEqual, 1)
 			So(t2h["task1"], ShouldEqual, "")
-			So(h2t["host1"], ShouldEqual, "task1")
+			So(h2t["host1"], ShouldResemble, []string{"task1"})
 
 		})
 	})
@@ -22,8 +22,10 @@ var (
 
 	UserDataKey = bsonutil.MustHaveTag(Distro{}, "UserData")
 
-	SpawnAllowedKey = bsonutil.MustHaveTag(Distro{}, "SpawnAllowed")
-	ExpansionsKey   = bsonutil.MustHaveTag(Distro{}, "Expansions")
+	SpawnAllowedKey       = bsonutil.MustHaveTag(Distro{}, "SpawnAllowed")
+	ExpansionsKey         = bsonutil.MustHaveTag(Distro{}, "Expansions")
+	ContainersKey         = bsonutil.MustHaveTag(Distro{}, "Containers")
+	MaxConcurrentTasksKey = bsonutil.MustHaveTag(Distro{}, "MaxConcurrentTasks")
 
 	// bson fields for the UserData struct
 	UserDataFileKey     = bsonutil.MustHaveTag(UserData{}, "File")
@@ -34,6 +34,20 @@ type Distro struct {
 
 	SpawnAllowed bool        `bson:"spawn_allowed" json:"spawn_allowed,omitempty" mapstructure:"spawn_allowed,omitempty"`
 	Expansions   []Expansion `bson:"expansions,omitempty" json:"expansions,omitempty" mapstructure:"expansions,omitempty"`
+
+	Containers         *ContainerSettings `bson:"containers,omitempty" json:"containers,omitempty" mapstructure:"containers,omitempty"`
+	MaxConcurrentTasks int                `bson:"max_concurrent_tasks,omitempty" json:"max_concurrent_tasks,omitempty" mapstructure:"max_concurrent_tasks,omitempty"`
+}
+
+// ContainerSettings configure a distro to run each task in a fresh
+// container on the host, so that one host can run several tasks at once.
+// The host runs one agent for each slot, and the task directory is
+// bind-mounted into the task's container at the same path.
+type ContainerSettings struct {
+	Image    string  `bson:"image" json:"image" mapstructure:"image"`
+	Slots    int     `bson:"slots" json:"slots" mapstructure:"slots"`
+	CPUs     float64 `bson:"cpus,omitempty" json:"cpus,omitempty" mapstructure:"cpus,omitempty"`
+	MemoryMB int     `bson:"memory_mb,omitempty" json:"memory_mb,omitempty" mapstructure:"memory_mb,omitempty"`
 }
 
 type ValidateFormat string
@@ -58,6 +72,33 @@ func (d *Distro) GenerateName() string {
 	return fmt.Sprintf("evg-%s-%s-%d", d.Id, time.Now().Format(evergreen.NameTimeFormat), rand.Int())
 }
 
+// UsesContainers returns true if the distro runs each task in its own
+// container.
+func (d *Distro) UsesContainers() bool {
+	return d.Containers != nil
+}
+
+// HasTaskSlots returns true if hosts of the distro track the set of tasks
+// running in their slots, rather than a single running task.
+func (d *Distro) HasTaskSlots() bool {
+	return d.UsesContainers() || d.MaxConcurrentTasks > 1
+}
+
+// TaskSlots returns the number of tasks that a host of the distro can run
+// at once.
+func (d *Distro) TaskSlots() int {
+	if d.Containers != nil {
+		if d.Containers.Slots < 1 {
+			return 1
+		}
+		return d.Containers.Slots
+	}
+	if d.MaxConcurrentTasks < 1 {
+		return 1
+	}
+	return d.MaxConcurrentTasks
+}
+
 func (d *Distro) IsWindows() bool {
 	// XXX: if this is-windows check is updated, make sure to also update
 	// public/static/js/spawned_hosts.js as well
@@ -0,0 +1,45 @@
+package event
+
+import (
+	"time"
+
+	"github.com/mongodb/grip"
+)
+
+const (
+	// resource type
+	ResourceTypeBuild = "BUILD"
+
+	// event types
+	BuildFinished = "BUILD_FINISHED"
+)
+
+// BuildEventData implements EventData.
+type BuildEventData struct {
+	// necessary for IsValid
+	ResourceType string `bson:"r_type" json:"resource_type"`
+	Status       string `bson:"s,omitempty" json:"status,omitempty"`
+}
+
+func (d BuildEventData) IsValid() bool {
+	return d.ResourceType == ResourceTypeBuild
+}
+
+func LogBuildEvent(buildId string, eventType string, eventData BuildEventData) {
+	eventData.ResourceType = ResourceTypeBuild
+	event := Event{
+		ResourceId: buildId,
+		Timestamp:  time.Now(),
+		EventType:  eventType,
+		Data:       DataWrapper{eventData},
+	}
+
+	if err := NewDBEventLogger(AllLogCollection).LogEvent(event); err != nil {
+		grip.Errorf("Error logging build event: %+v", err)
+	}
+}
+
+// LogBuildFinished records that the build finished with the given status.
+func LogBuildFinished(buildId, status string) {
+	LogBuildEvent(buildId, BuildFinished, BuildEventData{Status: status})
+}
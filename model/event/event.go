@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/evergreen-ci/evergreen/db"
 	"github.com/mongodb/anser/bsonutil"
 	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 )
 
@@ -16,18 +18,25 @@ const (
 )
 
 type Event struct {
-	Timestamp  time.Time   `bson:"ts" json:"timestamp"`
-	ResourceId string      `bson:"r_id" json:"resource_id"`
-	EventType  string      `bson:"e_type" json:"event_type"`
-	Data       DataWrapper `bson:"data" json:"data"`
+	ID         bson.ObjectId `bson:"_id,omitempty" json:"-"`
+	Timestamp  time.Time     `bson:"ts" json:"timestamp"`
+	ResourceId string        `bson:"r_id" json:"resource_id"`
+	EventType  string        `bson:"e_type" json:"event_type"`
+	Data       DataWrapper   `bson:"data" json:"data"`
+
+	// ProcessedAt is when the event was evaluated against the
+	// notification subscriptions.
+	ProcessedAt time.Time `bson:"processed_at,omitempty" json:"processed_at,omitempty"`
 }
 
 var (
 	// bson fields for the event struct
-	TimestampKey  = bsonutil.MustHaveTag(Event{}, "Timestamp")
-	ResourceIdKey = bsonutil.MustHaveTag(Event{}, "ResourceId")
-	TypeKey       = bsonutil.MustHaveTag(Event{}, "EventType")
-	DataKey       = bsonutil.MustHaveTag(Event{}, "Data")
+	IdKey          = bsonutil.MustHaveTag(Event{}, "ID")
+	TimestampKey   = bsonutil.MustHaveTag(Event{}, "Timestamp")
+	ResourceIdKey  = bsonutil.MustHaveTag(Event{}, "ResourceId")
+	TypeKey        = bsonutil.MustHaveTag(Event{}, "EventType")
+	DataKey        = bsonutil.MustHaveTag(Event{}, "Data")
+	ProcessedAtKey = bsonutil.MustHaveTag(Event{}, "ProcessedAt")
 
 	// resource type key.  this doesn't exist a part of the event struct,
 	// but has to be the same for all of the event types
@@ -59,6 +68,12 @@ func (dw DataWrapper) MarshalJSON() ([]byte, error) {
 		return json.Marshal(event)
 	case *AdminEventData:
 		return json.Marshal(event)
+	case *BuildEventData:
+		return json.Marshal(event)
+	case *VersionEventData:
+		return json.Marshal(event)
+	case *PatchEventData:
+		return json.Marshal(event)
 	default:
 		return nil, errors.Errorf("cannot marshal data of type %T", dw.Data)
 	}
@@ -70,7 +85,8 @@ func (dw DataWrapper) GetBSON() (interface{}, error) {
 
 func (dw *DataWrapper) SetBSON(raw bson.Raw) error {
 	impls := []interface{}{&TaskEventData{}, &HostEventData{}, &DistroEventData{}, &SchedulerEventData{},
-		&TaskSystemResourceData{}, &TaskProcessResourceData{}, &AdminEventData{}}
+		&TaskSystemResourceData{}, &TaskProcessResourceData{}, &AdminEventData{}, &BuildEventData{},
+		&VersionEventData{}, &PatchEventData{}}
 
 	for _, impl := range impls {
 		err := raw.Unmarshal(impl)
@@ -89,3 +105,25 @@ func (dw *DataWrapper) SetBSON(raw bson.Raw) error {
 	}
 	return errors.Errorf("No suitable type for %#v", m)
 }
+
+// MarkProcessed claims the event to be evaluated against the notification
+// subscriptions, and returns false if it was already processed, so that
+// the event is only evaluated once even if notifiers run at the same time.
+func (e *Event) MarkProcessed() (bool, error) {
+	if e.ID == "" {
+		return false, errors.New("event has no id")
+	}
+	processedAt := time.Now()
+	err := db.Update(AllLogCollection, bson.M{
+		IdKey:          e.ID,
+		ProcessedAtKey: bson.M{"$exists": false},
+	}, bson.M{"$set": bson.M{ProcessedAtKey: processedAt}})
+	if err == mgo.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	e.ProcessedAt = processedAt
+	return true, nil
+}
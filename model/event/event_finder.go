@@ -171,3 +171,16 @@ func TaskProcessInfoEvents(taskId string, ts time.Time, limit, sort int) db.Q {
 
 	return db.Query(filter).Sort([]string{sortSpec}).Limit(limit)
 }
+
+// Notification Events
+
+// UnprocessedEvents returns the events of the given types logged since the
+// given time that have not yet been evaluated against notification
+// subscriptions, oldest first.
+func UnprocessedEvents(since time.Time, eventTypes []string) db.Q {
+	return db.Query(bson.M{
+		TimestampKey:   bson.M{"$gte": since},
+		TypeKey:        bson.M{"$in": eventTypes},
+		ProcessedAtKey: bson.M{"$exists": false},
+	}).Sort([]string{TimestampKey})
+}
@@ -0,0 +1,45 @@
+package event
+
+import (
+	"time"
+
+	"github.com/mongodb/grip"
+)
+
+const (
+	// resource type
+	ResourceTypePatch = "PATCH"
+
+	// event types
+	PatchFinished = "PATCH_FINISHED"
+)
+
+// PatchEventData implements EventData.
+type PatchEventData struct {
+	// necessary for IsValid
+	ResourceType string `bson:"r_type" json:"resource_type"`
+	Status       string `bson:"s,omitempty" json:"status,omitempty"`
+}
+
+func (d PatchEventData) IsValid() bool {
+	return d.ResourceType == ResourceTypePatch
+}
+
+func LogPatchEvent(patchId string, eventType string, eventData PatchEventData) {
+	eventData.ResourceType = ResourceTypePatch
+	event := Event{
+		ResourceId: patchId,
+		Timestamp:  time.Now(),
+		EventType:  eventType,
+		Data:       DataWrapper{eventData},
+	}
+
+	if err := NewDBEventLogger(AllLogCollection).LogEvent(event); err != nil {
+		grip.Errorf("Error logging patch event: %+v", err)
+	}
+}
+
+// LogPatchFinished records that the patch finished with the given status.
+func LogPatchFinished(patchId, status string) {
+	LogPatchEvent(patchId, PatchFinished, PatchEventData{Status: status})
+}
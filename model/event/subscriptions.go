@@ -0,0 +1,254 @@
+package event
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	SubscriptionsCollection = "subscriptions"
+
+	// triggers
+	TriggerOutcome         = "outcome"
+	TriggerFailure         = "failure"
+	TriggerRegression      = "regression"
+	TriggerFirstFailure    = "first-failure"
+	TriggerExceedsDuration = "exceeds-duration"
+
+	// selector types
+	SelectorProject = "project"
+	SelectorVariant = "variant"
+	SelectorTaskTag = "task-tag"
+	SelectorOwner   = "owner"
+	SelectorID      = "id"
+
+	// subscriber types
+	SubscriberEmail   = "email"
+	SubscriberSlack   = "slack"
+	SubscriberWebhook = "webhook"
+)
+
+var (
+	// SubscriptionResourceTypes are the resource types users can subscribe
+	// to.
+	SubscriptionResourceTypes = []string{
+		ResourceTypeVersion,
+		ResourceTypePatch,
+		ResourceTypeBuild,
+		ResourceTypeTask,
+		ResourceTypeHost,
+	}
+
+	// triggersByResourceType are the triggers that can be evaluated for
+	// each resource type.
+	triggersByResourceType = map[string][]string{
+		ResourceTypeVersion: {TriggerOutcome, TriggerFailure, TriggerExceedsDuration},
+		ResourceTypePatch:   {TriggerOutcome, TriggerFailure, TriggerExceedsDuration},
+		ResourceTypeBuild:   {TriggerOutcome, TriggerFailure, TriggerExceedsDuration},
+		ResourceTypeTask: {TriggerOutcome, TriggerFailure, TriggerRegression,
+			TriggerFirstFailure, TriggerExceedsDuration},
+		ResourceTypeHost: {TriggerOutcome, TriggerFailure},
+	}
+
+	// selectorsByResourceType are the selectors that can narrow down a
+	// subscription to each resource type.
+	selectorsByResourceType = map[string][]string{
+		ResourceTypeVersion: {SelectorProject, SelectorOwner, SelectorID},
+		ResourceTypePatch:   {SelectorProject, SelectorOwner, SelectorID},
+		ResourceTypeBuild:   {SelectorProject, SelectorVariant, SelectorOwner, SelectorID},
+		ResourceTypeTask:    {SelectorProject, SelectorVariant, SelectorTaskTag, SelectorOwner, SelectorID},
+		ResourceTypeHost:    {SelectorOwner, SelectorID},
+	}
+
+	// requiredSelectorsByResourceType are the selectors that a subscription
+	// to each resource type must have at least one of, so that it doesn't
+	// apply to the resources of every project.
+	requiredSelectorsByResourceType = map[string][]string{
+		ResourceTypeVersion: {SelectorProject, SelectorID},
+		ResourceTypePatch:   {SelectorProject, SelectorID},
+		ResourceTypeBuild:   {SelectorProject, SelectorID},
+		ResourceTypeTask:    {SelectorProject, SelectorID},
+		ResourceTypeHost:    {SelectorOwner, SelectorID},
+	}
+
+	subscriberTypes = []string{SubscriberEmail, SubscriberSlack, SubscriberWebhook}
+)
+
+// Subscription is a user's request to be notified when events about a
+// resource match a trigger.
+type Subscription struct {
+	ID           bson.ObjectId `bson:"_id" json:"id"`
+	Owner        string        `bson:"owner" json:"owner"`
+	ResourceType string        `bson:"resource_type" json:"resource_type"`
+	Trigger      string        `bson:"trigger" json:"trigger"`
+	// Selectors all have to match a resource for the subscription to apply
+	// to it.
+	Selectors  []Selector `bson:"selectors,omitempty" json:"selectors,omitempty"`
+	Subscriber Subscriber `bson:"subscriber" json:"subscriber"`
+	// DurationSecs is the threshold of the exceeds-duration trigger.
+	DurationSecs int `bson:"duration_secs,omitempty" json:"duration_secs,omitempty"`
+}
+
+// Selector narrows down the resources a subscription applies to.
+type Selector struct {
+	Type string `bson:"type" json:"type"`
+	Data string `bson:"data" json:"data"`
+}
+
+// Subscriber is where a subscription's notifications are sent: an email
+// address, a slack channel or user, or a webhook URL.
+type Subscriber struct {
+	Type   string `bson:"type" json:"type"`
+	Target string `bson:"target" json:"target"`
+}
+
+var (
+	subscriptionIdKey           = bsonutil.MustHaveTag(Subscription{}, "ID")
+	subscriptionOwnerKey        = bsonutil.MustHaveTag(Subscription{}, "Owner")
+	subscriptionResourceTypeKey = bsonutil.MustHaveTag(Subscription{}, "ResourceType")
+)
+
+// Validate returns an error if the subscription cannot be evaluated.
+func (s *Subscription) Validate() error {
+	catcher := grip.NewSimpleCatcher()
+	if s.Owner == "" {
+		catcher.Add(errors.New("subscription has no owner"))
+	}
+
+	triggers, ok := triggersByResourceType[s.ResourceType]
+	if !ok {
+		catcher.Add(errors.Errorf("'%s' is not a valid resource type", s.ResourceType))
+	} else if !util.StringSliceContains(triggers, s.Trigger) {
+		catcher.Add(errors.Errorf("'%s' is not a valid trigger for %s", s.Trigger, s.ResourceType))
+	}
+	if s.Trigger == TriggerExceedsDuration && s.DurationSecs <= 0 {
+		catcher.Add(errors.New("exceeds-duration subscriptions must have a positive duration"))
+	}
+
+	required := false
+	for _, selector := range s.Selectors {
+		if !util.StringSliceContains(selectorsByResourceType[s.ResourceType], selector.Type) {
+			catcher.Add(errors.Errorf("'%s' is not a valid selector for %s", selector.Type, s.ResourceType))
+		}
+		if selector.Data == "" {
+			catcher.Add(errors.Errorf("%s selector has no data", selector.Type))
+		}
+		if util.StringSliceContains(requiredSelectorsByResourceType[s.ResourceType], selector.Type) {
+			required = true
+		}
+	}
+	if ok && !required {
+		catcher.Add(errors.Errorf("%s subscriptions must have one of the selectors %s", s.ResourceType,
+			strings.Join(requiredSelectorsByResourceType[s.ResourceType], ", ")))
+	}
+
+	catcher.Add(s.Subscriber.Validate())
+	return catcher.Resolve()
+}
+
+// Validate returns an error if notifications cannot be sent to the
+// subscriber.
+func (s Subscriber) Validate() error {
+	if !util.StringSliceContains(subscriberTypes, s.Type) {
+		return errors.Errorf("'%s' is not a valid subscriber type", s.Type)
+	}
+	if s.Target == "" {
+		return errors.Errorf("%s subscriber has no target", s.Type)
+	}
+
+	switch s.Type {
+	case SubscriberEmail:
+		if !strings.Contains(s.Target, "@") {
+			return errors.Errorf("'%s' is not an email address", s.Target)
+		}
+	case SubscriberWebhook:
+		u, err := url.Parse(s.Target)
+		if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+			return errors.Errorf("'%s' is not a valid https webhook URL", s.Target)
+		}
+		if u.Hostname() == "localhost" {
+			return errors.Errorf("webhook '%s' is not a public address", s.Target)
+		}
+		if ip := net.ParseIP(u.Hostname()); ip != nil && !IsPublicWebhookIP(ip) {
+			return errors.Errorf("webhook '%s' is not a public address", s.Target)
+		}
+	}
+	return nil
+}
+
+// IsPublicWebhookIP returns true if webhooks may be sent to the IP address.
+// Webhooks can't be sent to loopback, private or link-local addresses, which
+// include the cloud providers' metadata services, so that subscribers can't
+// reach the services on Evergreen's own network.
+func IsPublicWebhookIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
+
+// SelectorData returns the data of the subscription's selectors of the given
+// type.
+func (s *Subscription) SelectorData(selectorType string) []string {
+	data := []string{}
+	for _, selector := range s.Selectors {
+		if selector.Type == selectorType {
+			data = append(data, selector.Data)
+		}
+	}
+	return data
+}
+
+// Upsert inserts the subscription, or replaces the subscription with the
+// same ID.
+func (s *Subscription) Upsert() error {
+	if s.ID == "" {
+		s.ID = bson.NewObjectId()
+	}
+	_, err := db.Upsert(SubscriptionsCollection, bson.M{subscriptionIdKey: s.ID}, s)
+	return errors.WithStack(err)
+}
+
+// FindSubscriptionById returns the subscription with the given ID, or nil if
+// there is none.
+func FindSubscriptionById(id string) (*Subscription, error) {
+	if !bson.IsObjectIdHex(id) {
+		return nil, errors.Errorf("'%s' is not a valid subscription id", id)
+	}
+	s := &Subscription{}
+	err := db.FindOneQ(SubscriptionsCollection, db.Query(bson.M{subscriptionIdKey: bson.ObjectIdHex(id)}), s)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	return s, errors.WithStack(err)
+}
+
+// FindSubscriptionsByOwner returns the subscriptions of a user.
+func FindSubscriptionsByOwner(owner string) ([]Subscription, error) {
+	subscriptions := []Subscription{}
+	err := db.FindAllQ(SubscriptionsCollection, db.Query(bson.M{subscriptionOwnerKey: owner}), &subscriptions)
+	return subscriptions, errors.WithStack(err)
+}
+
+// FindSubscriptionsByResourceType returns all subscriptions to a resource
+// type.
+func FindSubscriptionsByResourceType(resourceType string) ([]Subscription, error) {
+	subscriptions := []Subscription{}
+	err := db.FindAllQ(SubscriptionsCollection, db.Query(bson.M{subscriptionResourceTypeKey: resourceType}), &subscriptions)
+	return subscriptions, errors.WithStack(err)
+}
+
+// RemoveSubscription removes the subscription with the given ID.
+func RemoveSubscription(id string) error {
+	if !bson.IsObjectIdHex(id) {
+		return errors.Errorf("'%s' is not a valid subscription id", id)
+	}
+	return errors.WithStack(db.Remove(SubscriptionsCollection, bson.M{subscriptionIdKey: bson.ObjectIdHex(id)}))
+}
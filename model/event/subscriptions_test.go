@@ -0,0 +1,148 @@
+package event
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type subscriptionsSuite struct {
+	subscription Subscription
+	suite.Suite
+}
+
+func TestSubscriptions(t *testing.T) {
+	suite.Run(t, &subscriptionsSuite{})
+}
+
+func (s *subscriptionsSuite) SetupTest() {
+	s.NoError(db.Clear(SubscriptionsCollection))
+	s.subscription = Subscription{
+		Owner:        "me",
+		ResourceType: ResourceTypeTask,
+		Trigger:      TriggerFailure,
+		Selectors: []Selector{
+			{Type: SelectorProject, Data: "mci"},
+			{Type: SelectorOwner, Data: "me"},
+		},
+		Subscriber: Subscriber{
+			Type:   SubscriberEmail,
+			Target: "me@example.com",
+		},
+	}
+}
+
+func (s *subscriptionsSuite) TestValidate() {
+	s.NoError(s.subscription.Validate())
+
+	s.subscription.Trigger = TriggerExceedsDuration
+	s.Error(s.subscription.Validate())
+	s.subscription.DurationSecs = 60
+	s.NoError(s.subscription.Validate())
+
+	s.subscription.ResourceType = ResourceTypeHost
+	s.Error(s.subscription.Validate())
+	s.subscription.ResourceType = "nope"
+	s.Error(s.subscription.Validate())
+}
+
+func (s *subscriptionsSuite) TestValidateSelectors() {
+	s.subscription.Selectors = append(s.subscription.Selectors, Selector{Type: SelectorTaskTag})
+	s.Error(s.subscription.Validate())
+
+	s.subscription.Selectors = []Selector{{Type: "distro", Data: "archlinux"}}
+	s.Error(s.subscription.Validate())
+
+	s.subscription.Selectors = []Selector{{Type: SelectorOwner, Data: "me"}}
+	s.Error(s.subscription.Validate())
+	s.subscription.Selectors = []Selector{{Type: SelectorID, Data: "t1"}}
+	s.NoError(s.subscription.Validate())
+	s.subscription.Selectors = nil
+	s.Error(s.subscription.Validate())
+
+	s.subscription.ResourceType = ResourceTypeHost
+	s.subscription.Trigger = TriggerOutcome
+	s.subscription.Selectors = []Selector{{Type: SelectorOwner, Data: "me"}}
+	s.NoError(s.subscription.Validate())
+}
+
+func (s *subscriptionsSuite) TestValidateSubscriber() {
+	s.NoError(Subscriber{Type: SubscriberSlack, Target: "#evergreen"}.Validate())
+	s.NoError(Subscriber{Type: SubscriberWebhook, Target: "https://example.com/hook"}.Validate())
+
+	s.Error(Subscriber{Type: SubscriberEmail, Target: "me"}.Validate())
+	s.Error(Subscriber{Type: SubscriberSlack}.Validate())
+	s.Error(Subscriber{Type: SubscriberWebhook, Target: "example.com/hook"}.Validate())
+	s.Error(Subscriber{Type: SubscriberWebhook, Target: "http://example.com/hook"}.Validate())
+	s.Error(Subscriber{Type: SubscriberWebhook, Target: "https://localhost:8080/hook"}.Validate())
+	s.Error(Subscriber{Type: SubscriberWebhook, Target: "https://127.0.0.1/hook"}.Validate())
+	s.Error(Subscriber{Type: SubscriberWebhook, Target: "https://10.1.2.3/hook"}.Validate())
+	s.Error(Subscriber{Type: SubscriberWebhook, Target: "https://169.254.169.254/latest/meta-data"}.Validate())
+	s.Error(Subscriber{Type: SubscriberWebhook, Target: "https://[::1]/hook"}.Validate())
+	s.NoError(Subscriber{Type: SubscriberWebhook, Target: "https://203.0.113.7/hook"}.Validate())
+	s.Error(Subscriber{Type: "pager", Target: "me"}.Validate())
+}
+
+func (s *subscriptionsSuite) TestSelectorData() {
+	s.Equal([]string{"mci"}, s.subscription.SelectorData(SelectorProject))
+	s.Empty(s.subscription.SelectorData(SelectorVariant))
+}
+
+func (s *subscriptionsSuite) TestMarkProcessedClaimsOnce() {
+	s.NoError(db.Clear(AllLogCollection))
+	LogTaskFinished("t1", "h1", "failed")
+	events, err := Find(AllLogCollection, UnprocessedEvents(time.Now().Add(-time.Minute), []string{TaskFinished}))
+	s.NoError(err)
+	s.Require().Len(events, 1)
+
+	claimed, err := events[0].MarkProcessed()
+	s.NoError(err)
+	s.True(claimed)
+	s.False(events[0].ProcessedAt.IsZero())
+
+	other := events[0]
+	claimed, err = other.MarkProcessed()
+	s.NoError(err)
+	s.False(claimed)
+
+	events, err = Find(AllLogCollection, UnprocessedEvents(time.Now().Add(-time.Minute), []string{TaskFinished}))
+	s.NoError(err)
+	s.Empty(events)
+}
+
+func (s *subscriptionsSuite) TestUpsertFindAndRemove() {
+	s.NoError(s.subscription.Upsert())
+	s.NotEqual(bson.ObjectId(""), s.subscription.ID)
+
+	other := s.subscription
+	other.ID = ""
+	other.Owner = "you"
+	other.ResourceType = ResourceTypeBuild
+	s.NoError(other.Upsert())
+
+	found, err := FindSubscriptionById(s.subscription.ID.Hex())
+	s.NoError(err)
+	s.Require().NotNil(found)
+	s.Equal("me", found.Owner)
+	s.Len(found.Selectors, 2)
+
+	s.subscription.Trigger = TriggerOutcome
+	s.NoError(s.subscription.Upsert())
+	subscriptions, err := FindSubscriptionsByOwner("me")
+	s.NoError(err)
+	s.Require().Len(subscriptions, 1)
+	s.Equal(TriggerOutcome, subscriptions[0].Trigger)
+
+	subscriptions, err = FindSubscriptionsByResourceType(ResourceTypeBuild)
+	s.NoError(err)
+	s.Require().Len(subscriptions, 1)
+	s.Equal("you", subscriptions[0].Owner)
+
+	s.NoError(RemoveSubscription(s.subscription.ID.Hex()))
+	found, err = FindSubscriptionById(s.subscription.ID.Hex())
+	s.NoError(err)
+	s.Nil(found)
+}
@@ -11,17 +11,18 @@ const (
 	ResourceTypeTask = "TASK"
 
 	// event types
-	TaskCreated         = "TASK_CREATED"
-	TaskDispatched      = "TASK_DISPATCHED"
-	TaskUndispatched    = "TASK_UNDISPATCHED"
-	TaskStarted         = "TASK_STARTED"
-	TaskFinished        = "TASK_FINISHED"
-	TaskRestarted       = "TASK_RESTARTED"
-	TaskActivated       = "TASK_ACTIVATED"
-	TaskDeactivated     = "TASK_DEACTIVATED"
-	TaskAbortRequest    = "TASK_ABORT_REQUEST"
-	TaskScheduled       = "TASK_SCHEDULED"
-	TaskPriorityChanged = "TASK_PRIORITY_CHANGED"
+	TaskCreated            = "TASK_CREATED"
+	TaskDispatched         = "TASK_DISPATCHED"
+	TaskUndispatched       = "TASK_UNDISPATCHED"
+	TaskStarted            = "TASK_STARTED"
+	TaskFinished           = "TASK_FINISHED"
+	TaskRestarted          = "TASK_RESTARTED"
+	TaskActivated          = "TASK_ACTIVATED"
+	TaskDeactivated        = "TASK_DEACTIVATED"
+	TaskAbortRequest       = "TASK_ABORT_REQUEST"
+	TaskScheduled          = "TASK_SCHEDULED"
+	TaskPriorityChanged    = "TASK_PRIORITY_CHANGED"
+	TaskArtifactDownloaded = "TASK_ARTIFACT_DOWNLOADED"
 )
 
 // implements Data
@@ -33,6 +34,7 @@ type TaskEventData struct {
 	Status       string    `bson:"s,omitempty" json:"status,omitempty"`
 	Timestamp    time.Time `bson:"ts,omitempty" json:"timestamp,omitempty"`
 	Priority     int64     `bson:"pri,omitempty" json:"priority,omitempty"`
+	Artifacts    []string  `bson:"arts,omitempty" json:"artifacts,omitempty"`
 }
 
 func (self TaskEventData) IsValid() bool {
@@ -58,6 +60,12 @@ func LogTaskPriority(taskId, user string, priority int64) {
 	LogTaskEvent(taskId, TaskPriorityChanged, TaskEventData{UserId: user, Priority: priority})
 }
 
+// LogTaskArtifactDownloaded records that the user downloaded the named
+// artifact of the task through a signed link.
+func LogTaskArtifactDownloaded(taskId, user, artifact string) {
+	LogTaskEvent(taskId, TaskArtifactDownloaded, TaskEventData{UserId: user, Artifacts: []string{artifact}})
+}
+
 func LogTaskCreated(taskId string) {
 	LogTaskEvent(taskId, TaskCreated, TaskEventData{})
 }
@@ -0,0 +1,45 @@
+package event
+
+import (
+	"time"
+
+	"github.com/mongodb/grip"
+)
+
+const (
+	// resource type
+	ResourceTypeVersion = "VERSION"
+
+	// event types
+	VersionFinished = "VERSION_FINISHED"
+)
+
+// VersionEventData implements EventData.
+type VersionEventData struct {
+	// necessary for IsValid
+	ResourceType string `bson:"r_type" json:"resource_type"`
+	Status       string `bson:"s,omitempty" json:"status,omitempty"`
+}
+
+func (d VersionEventData) IsValid() bool {
+	return d.ResourceType == ResourceTypeVersion
+}
+
+func LogVersionEvent(versionId string, eventType string, eventData VersionEventData) {
+	eventData.ResourceType = ResourceTypeVersion
+	event := Event{
+		ResourceId: versionId,
+		Timestamp:  time.Now(),
+		EventType:  eventType,
+		Data:       DataWrapper{eventData},
+	}
+
+	if err := NewDBEventLogger(AllLogCollection).LogEvent(event); err != nil {
+		grip.Errorf("Error logging version event: %+v", err)
+	}
+}
+
+// LogVersionFinished records that the version finished with the given status.
+func LogVersionFinished(versionId, status string) {
+	LogVersionEvent(versionId, VersionFinished, VersionEventData{Status: status})
+}
@@ -30,6 +30,7 @@ var (
 	ProviderKey              = bsonutil.MustHaveTag(Host{}, "Provider")
 	ProvisionedKey           = bsonutil.MustHaveTag(Host{}, "Provisioned")
 	RunningTaskKey           = bsonutil.MustHaveTag(Host{}, "RunningTask")
+	RunningTasksKey          = bsonutil.MustHaveTag(Host{}, "RunningTasks")
 	PidKey                   = bsonutil.MustHaveTag(Host{}, "Pid")
 	TaskDispatchTimeKey      = bsonutil.MustHaveTag(Host{}, "TaskDispatchTime")
 	CreateTimeKey            = bsonutil.MustHaveTag(Host{}, "CreationTime")
@@ -52,8 +53,12 @@ var (
 	ProjectKey               = bsonutil.MustHaveTag(Host{}, "Project")
 	ProvisionOptionsKey      = bsonutil.MustHaveTag(Host{}, "ProvisionOptions")
 	StartTimeKey             = bsonutil.MustHaveTag(Host{}, "StartTime")
+	LastActivityTimeKey      = bsonutil.MustHaveTag(Host{}, "LastActivityTime")
 )
 
+// firstSlotTaskKey exists on hosts with any task in a task slot.
+var firstSlotTaskKey = RunningTasksKey + ".0"
+
 // === Queries ===
 
 // All is a query that returns all hosts
@@ -96,9 +101,10 @@ func ByUserWithUnterminatedStatus(user string) db.Q {
 // Evergreen hosts without an assigned task.
 var IsAvailableAndFree = db.Query(
 	bson.M{
-		RunningTaskKey: bson.M{"$exists": false},
-		StatusKey:      evergreen.HostRunning,
-		StartedByKey:   evergreen.User,
+		RunningTaskKey:   bson.M{"$exists": false},
+		firstSlotTaskKey: bson.M{"$exists": false},
+		StatusKey:        evergreen.HostRunning,
+		StartedByKey:     evergreen.User,
 	},
 ).Sort([]string{"-" + LTCTimeKey})
 
@@ -107,10 +113,11 @@ var IsAvailableAndFree = db.Query(
 func ByAvailableForDistro(d string) db.Q {
 	distroIdKey := fmt.Sprintf("%v.%v", DistroKey, distro.IdKey)
 	return db.Query(bson.M{
-		distroIdKey:    d,
-		RunningTaskKey: bson.M{"$exists": false},
-		StatusKey:      evergreen.HostRunning,
-		StartedByKey:   evergreen.User,
+		distroIdKey:      d,
+		RunningTaskKey:   bson.M{"$exists": false},
+		firstSlotTaskKey: bson.M{"$exists": false},
+		StatusKey:        evergreen.HostRunning,
+		StartedByKey:     evergreen.User,
 	}).Sort([]string{"-" + LTCTimeKey})
 }
 
@@ -118,9 +125,10 @@ func ByAvailableForDistro(d string) db.Q {
 // Evergreen hosts without an assigned task.
 var IsFree = db.Query(
 	bson.M{
-		RunningTaskKey: bson.M{"$exists": false},
-		StartedByKey:   evergreen.User,
-		StatusKey:      evergreen.HostRunning,
+		RunningTaskKey:   bson.M{"$exists": false},
+		firstSlotTaskKey: bson.M{"$exists": false},
+		StartedByKey:     evergreen.User,
+		StatusKey:        evergreen.HostRunning,
 	},
 )
 
@@ -167,10 +175,38 @@ var IsRunningAndSpawned = db.Query(
 	},
 )
 
+// IsRunningUserHost is a query that returns all running hosts spawned by
+// an Evergreen user.
+var IsRunningUserHost = db.Query(
+	bson.M{
+		StartedByKey: bson.M{"$ne": evergreen.User},
+		StatusKey:    evergreen.HostRunning,
+	},
+)
+
+// IsStopping is a query that returns all hosts that are being stopped.
+var IsStopping = db.Query(
+	bson.M{StatusKey: evergreen.HostStopping},
+)
+
+// IsRestartedWithoutDNSName is a query that returns all running spawn hosts
+// that have been started again after being stopped, and are waiting for their
+// new DNS name.
+var IsRestartedWithoutDNSName = db.Query(
+	bson.M{
+		StartedByKey: bson.M{"$ne": evergreen.User},
+		StatusKey:    evergreen.HostRunning,
+		DNSKey:       "",
+	},
+)
+
 // IsRunningTask is a query that returns all running hosts with a running task
 var IsRunningTask = db.Query(
 	bson.M{
-		RunningTaskKey: bson.M{"$exists": true},
+		"$or": []bson.M{
+			{RunningTaskKey: bson.M{"$exists": true}},
+			{firstSlotTaskKey: bson.M{"$exists": true}},
+		},
 	},
 )
 
@@ -178,8 +214,9 @@ var IsRunningTask = db.Query(
 // running task that are marked for decommissioning.
 var IsDecommissioned = db.Query(
 	bson.M{
-		RunningTaskKey: bson.M{"$exists": false},
-		StatusKey:      evergreen.HostDecommissioned},
+		RunningTaskKey:   bson.M{"$exists": false},
+		firstSlotTaskKey: bson.M{"$exists": false},
+		StatusKey:        evergreen.HostDecommissioned},
 )
 
 // IsTerminated is a query that returns all hosts that are terminated
@@ -221,9 +258,15 @@ func ByIds(ids []string) db.Q {
 	})
 }
 
-// ByRunningTaskId returns a host running the task with the given id.
+// ByRunningTaskId returns a host running the task with the given id, either
+// as its running task or in one of its task slots.
 func ByRunningTaskId(taskId string) db.Q {
-	return db.Query(bson.D{{Name: RunningTaskKey, Value: taskId}})
+	return db.Query(bson.M{
+		"$or": []bson.M{
+			{RunningTaskKey: taskId},
+			{RunningTasksKey: taskId},
+		},
+	})
 }
 
 // ByDynamicWithinTime is a query that returns all dynamic hosts running between a certain time and another time.
@@ -477,6 +520,37 @@ func statsByDistroPipeline() []bson.M {
 	}
 }
 
+// statsByProviderPipeline returns a pipeline that will group all hosts that
+// are not terminated by status and provider, and return the count of hosts
+func statsByProviderPipeline() []bson.M {
+	return []bson.M{
+		{
+			"$match": bson.M{
+				StatusKey: bson.M{"$ne": evergreen.HostTerminated},
+			},
+		},
+		{
+			"$group": bson.M{
+				"_id": bson.M{
+					"provider": "$" + ProviderKey,
+					"status":   "$" + StatusKey,
+				},
+				"count": bson.M{
+					"$sum": 1,
+				},
+			},
+		},
+		{
+			"$project": bson.M{
+				"provider": "$_id.provider",
+				"status":   "$_id.status",
+				"count":    1,
+				"_id":      0,
+			},
+		},
+	}
+}
+
 // QueryWithFullTaskPipeline returns a pipeline to match hosts and embeds the
 // task document within the host, if it's running a task
 func QueryWithFullTaskPipeline(match bson.M) []bson.M {
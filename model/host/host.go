@@ -36,6 +36,9 @@ type Host struct {
 
 	// the task that is currently running on the host
 	RunningTask string `bson:"running_task,omitempty" json:"running_task,omitempty"`
+	// the tasks running in the host's slots, for hosts of distros that
+	// run several tasks at once
+	RunningTasks []string `bson:"running_tasks,omitempty" json:"running_tasks,omitempty"`
 	// the full task struct that is running on the host (only populated by certain aggregations)
 	RunningTaskFull *task.Task `bson:"task_full,omitempty" json:"task_full,omitempty"`
 
@@ -73,6 +76,10 @@ type Host struct {
 
 	// if set, the time at which the host first became unreachable
 	UnreachableSince time.Time `bson:"unreachable_since,omitempty" json:"unreachable_since"`
+
+	// for spawn hosts, the last time that the host was started or that a
+	// user was seen logged in to it
+	LastActivityTime time.Time `bson:"last_activity_time,omitempty" json:"last_activity_time"`
 }
 
 // ProvisionOptions is struct containing options about how a new host should be set up.
@@ -108,7 +115,7 @@ const (
 func (h *Host) IdleTime() time.Duration {
 
 	// if the host is currently running a task, it is not idle
-	if h.RunningTask != "" {
+	if len(h.RunningTaskIds()) > 0 {
 		return time.Duration(0)
 	}
 
@@ -146,6 +153,76 @@ func (h *Host) SetStatus(status string) error {
 	)
 }
 
+// SetStopping marks the host as being stopped by its cloud provider.
+func (h *Host) SetStopping() error {
+	return h.SetStatus(evergreen.HostStopping)
+}
+
+// SetStopped marks the host as stopped, once its cloud provider has finished
+// stopping it.
+func (h *Host) SetStopped() error {
+	return h.SetStatus(evergreen.HostStopped)
+}
+
+// SetRestarted marks a stopped host as running again. The host gets a new DNS
+// name when it starts, so the old one is cleared for the host monitor to set.
+func (h *Host) SetRestarted() error {
+	if err := h.SetStatus(evergreen.HostRunning); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err := UpdateOne(
+		bson.M{
+			IdKey: h.Id,
+		},
+		bson.M{
+			"$set": bson.M{
+				DNSKey:              "",
+				LastActivityTimeKey: now,
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	h.Host = ""
+	h.LastActivityTime = now
+	return nil
+}
+
+// SetLastActivityTime records that a user was active on the host.
+func (h *Host) SetLastActivityTime(activity time.Time) error {
+	err := UpdateOne(
+		bson.M{
+			IdKey: h.Id,
+		},
+		bson.M{
+			"$set": bson.M{
+				LastActivityTimeKey: activity,
+			},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	h.LastActivityTime = activity
+	return nil
+}
+
+// GetElapsedIdleTime returns how long it has been since a user was last
+// active on the host, counting from when the host started if no one has been.
+func (h *Host) GetElapsedIdleTime() time.Duration {
+	since := h.CreationTime
+	if h.StartTime.After(since) {
+		since = h.StartTime
+	}
+	if h.LastActivityTime.After(since) {
+		since = h.LastActivityTime
+	}
+	return time.Since(since)
+}
+
 // SetInitializing marks the host as initializing. Only allow this
 // if the host is uninitialized.
 func (h *Host) SetInitializing() error {
@@ -381,6 +458,122 @@ func (host *Host) UpdateRunningTask(prevTaskId, newTaskId string,
 	return true, nil
 }
 
+// HasFreeSlot returns true if the host can be assigned another task.
+func (host *Host) HasFreeSlot() bool {
+	if !host.Distro.HasTaskSlots() {
+		return host.RunningTask == ""
+	}
+	return len(host.RunningTasks) < host.Distro.TaskSlots()
+}
+
+// FreeSlots returns the number of tasks that can still be assigned to the
+// host.
+func (host *Host) FreeSlots() int {
+	if !host.Distro.HasTaskSlots() {
+		if host.RunningTask == "" {
+			return 1
+		}
+		return 0
+	}
+	free := host.Distro.TaskSlots() - len(host.RunningTasks)
+	if free < 0 {
+		return 0
+	}
+	return free
+}
+
+// RunningTaskIds returns the ids of all of the tasks running on the host,
+// both its running task and the tasks in its task slots.
+func (host *Host) RunningTaskIds() []string {
+	ids := []string{}
+	if host.RunningTask != "" {
+		ids = append(ids, host.RunningTask)
+	}
+	return append(ids, host.RunningTasks...)
+}
+
+// AddRunningTask assigns the task to one of the free task slots of the
+// host. It returns false if all of the slots were taken, or the task was
+// already running on the host.
+func (host *Host) AddRunningTask(newTaskId string) (bool, error) {
+	if newTaskId == "" {
+		return false, errors.New("cannot add a running task with an empty id")
+	}
+
+	// the slot after the last one must be empty for there to be room
+	lastSlotKey := fmt.Sprintf("%s.%d", RunningTasksKey, host.Distro.TaskSlots()-1)
+	err := UpdateOne(
+		bson.M{
+			IdKey:           host.Id,
+			lastSlotKey:     bson.M{"$exists": false},
+			RunningTasksKey: bson.M{"$ne": newTaskId},
+		},
+		bson.M{
+			"$push": bson.M{RunningTasksKey: newTaskId},
+		})
+	if err != nil {
+		if err == mgo.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	event.LogHostRunningTaskSet(host.Id, newTaskId)
+	host.RunningTasks = append(host.RunningTasks, newTaskId)
+
+	return true, nil
+}
+
+// RemoveRunningTask frees the task slot of a finished task and updates
+// the last task completed fields.
+func (host *Host) RemoveRunningTask(prevTaskId string, finishTime time.Time) error {
+	err := UpdateOne(
+		bson.M{
+			IdKey: host.Id,
+		},
+		bson.M{
+			"$set": bson.M{
+				LTCKey:     prevTaskId,
+				LTCTimeKey: finishTime,
+			},
+			"$pull": bson.M{
+				RunningTasksKey: prevTaskId,
+			},
+		})
+
+	if err != nil {
+		return err
+	}
+
+	event.LogHostRunningTaskCleared(host.Id, prevTaskId)
+	tasks := []string{}
+	for _, t := range host.RunningTasks {
+		if t != prevTaskId {
+			tasks = append(tasks, t)
+		}
+	}
+	host.RunningTasks = tasks
+	host.LastTaskCompleted = prevTaskId
+	host.LastTaskCompletedTime = finishTime
+
+	return nil
+}
+
+// IsRunningTask returns true if the task is running on the host, either as
+// its running task or in one of its task slots.
+func (host *Host) IsRunningTask(taskId string) bool {
+	return host.RunningTask == taskId || util.StringSliceContains(host.RunningTasks, taskId)
+}
+
+// ClearTask frees the host of a finished task, clearing its task slot if it
+// ran in one and the running task otherwise.
+func (host *Host) ClearTask(taskId string, finishTime time.Time) error {
+	if util.StringSliceContains(host.RunningTasks, taskId) {
+		return host.RemoveRunningTask(taskId, finishTime)
+	}
+	return host.ClearRunningTask(taskId, finishTime)
+}
+
 // SetAgentRevision sets the updated agent revision for the host
 func (h *Host) SetAgentRevision(agentRevision string) error {
 	err := UpdateOne(bson.M{IdKey: h.Id},
@@ -645,6 +838,23 @@ func (h *Host) DisablePoisonedHost() error {
 	return errors.WithStack(h.SetDecommissioned())
 }
 
+// StatsByProvider is the number of hosts of a provider in a status.
+type StatsByProvider struct {
+	Provider string `bson:"provider" json:"provider"`
+	Status   string `bson:"status" json:"status"`
+	Count    int    `bson:"count" json:"count"`
+}
+
+// GetStatsByProvider returns counts of hosts that are not terminated broken
+// down by provider and status
+func GetStatsByProvider() ([]StatsByProvider, error) {
+	stats := []StatsByProvider{}
+	if err := db.Aggregate(Collection, statsByProviderPipeline(), &stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
 // GetStatsByDistro returns counts of up hosts broken down by distro
 func GetStatsByDistro() ([]StatsByDistro, error) {
 	stats := []StatsByDistro{}
@@ -842,6 +842,32 @@ func TestHostElapsedCommTime(t *testing.T) {
 	assert.InDelta(int64(7*time.Minute), int64(hostWithOnlyCreateTime.GetElapsedCommunicationTime()), float64(1*time.Millisecond))
 }
 
+func TestHostTaskSlots(t *testing.T) {
+	assert := assert.New(t)
+
+	h := Host{Id: "h"}
+	assert.Equal(1, h.FreeSlots())
+	assert.Empty(h.RunningTaskIds())
+	h.RunningTask = "t1"
+	assert.Equal(0, h.FreeSlots())
+	assert.Equal([]string{"t1"}, h.RunningTaskIds())
+	assert.Equal(time.Duration(0), h.IdleTime())
+
+	h = Host{
+		Id:           "slots",
+		Distro:       distro.Distro{MaxConcurrentTasks: 3},
+		RunningTasks: []string{"t1", "t2"},
+		CreationTime: time.Now().Add(-time.Hour),
+	}
+	assert.Equal(1, h.FreeSlots())
+	assert.True(h.HasFreeSlot())
+	assert.Equal([]string{"t1", "t2"}, h.RunningTaskIds())
+	assert.Equal(time.Duration(0), h.IdleTime())
+	h.RunningTasks = append(h.RunningTasks, "t3")
+	assert.Equal(0, h.FreeSlots())
+	assert.False(h.HasFreeSlot())
+}
+
 func TestHostUpsert(t *testing.T) {
 	assert := assert.New(t) // nolint
 	const hostID = "upsertTest"
@@ -10,6 +10,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/version"
@@ -64,11 +65,17 @@ func SetBuildActivation(buildId string, active bool, caller string) error {
 
 	// If activating a task, set the ActivatedBy field to be the caller
 	if active {
+		query := bson.M{
+			task.BuildIdKey: buildId,
+			task.StatusKey:  evergreen.TaskUndispatched,
+		}
+		// tasks that their commit's changes don't affect are only
+		// activated by users
+		if evergreen.IsSystemActivator(caller) {
+			query[task.PathFilteredKey] = bson.M{"$ne": true}
+		}
 		_, err = task.UpdateAll(
-			bson.M{
-				task.BuildIdKey: buildId,
-				task.StatusKey:  evergreen.TaskUndispatched,
-			},
+			query,
 			bson.M{"$set": bson.M{task.ActivatedKey: active, task.ActivatedByKey: caller}},
 		)
 	} else {
@@ -161,13 +168,18 @@ func MarkVersionCompleted(versionId string, finishTime time.Time) error {
 			status = evergreen.VersionFailed
 		}
 	}
-	return version.UpdateOne(
+	err = version.UpdateOne(
 		bson.M{version.IdKey: versionId},
 		bson.M{"$set": bson.M{
 			version.FinishTimeKey: finishTime,
 			version.StatusKey:     status,
 		}},
 	)
+	if err != nil {
+		return err
+	}
+	event.LogVersionFinished(versionId, status)
+	return nil
 }
 
 // SetBuildPriority updates the priority field of all tasks associated with the given build id.
@@ -443,6 +455,12 @@ func CreateBuildFromVersion(project *Project, v *version.Version, taskIds TaskId
 	rev := v.Revision
 	if evergreen.IsPatchRequester(v.Requester) {
 		rev = fmt.Sprintf("patch_%s_%s", v.Revision, v.Id)
+	} else if v.Requester == evergreen.TriggerRequester {
+		rev = fmt.Sprintf("trigger_%s_%s", v.Revision, v.Id)
+	} else if v.Requester == evergreen.PeriodicBuildRequester {
+		rev = fmt.Sprintf("periodic_%s_%s", v.Revision, v.Id)
+	} else if v.Requester == evergreen.GitTagRequester {
+		rev = fmt.Sprintf("git_tag_%s_%s", v.Revision, v.Id)
 	}
 
 	// create a new build id
@@ -722,6 +740,7 @@ func TryMarkPatchBuildFinished(b *build.Build, finishTime time.Time, updates *St
 	if err := patch.TryMarkFinished(v.Id, finishTime, status); err != nil {
 		return errors.WithStack(err)
 	}
+	event.LogPatchFinished(v.Id, status)
 	updates.PatchNewStatus = status
 
 	return nil
@@ -767,6 +786,7 @@ func createDisplayTask(id string, displayName string, execTasks []string,
 		Version:             v.Id,
 		Revision:            v.Revision,
 		Project:             p.Identifier,
+		Requester:           v.Requester,
 		DisplayOnly:         true,
 		ExecutionTasks:      execTasks,
 		Status:              evergreen.TaskUndispatched,
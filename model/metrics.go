@@ -0,0 +1,121 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/metrics"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var (
+	// dispatchLatencyBuckets are the upper bounds, in seconds, of the time
+	// that tasks wait in the queue before they are dispatched.
+	dispatchLatencyBuckets = []float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600, 7200, 14400}
+
+	taskDispatchLatency = metrics.NewHistogramVec("evergreen_task_dispatch_latency_seconds",
+		"Time between a task being scheduled and being dispatched to a host, by distro.",
+		dispatchLatencyBuckets, "distro")
+
+	taskQueueLength = metrics.NewGaugeVec("evergreen_task_queue_length",
+		"Number of tasks in the queue of a distro.", "distro")
+	taskQueueOldestAge = metrics.NewGaugeVec("evergreen_task_queue_oldest_task_age_seconds",
+		"Time since the oldest task in the queue of a distro was scheduled.", "distro")
+	hostsByStatus = metrics.NewGaugeVec("evergreen_hosts",
+		"Number of hosts that are not terminated, by status and provider.", "status", "provider")
+	runnerDuration = metrics.NewGaugeVec("evergreen_runner_duration_seconds",
+		"Duration of the last completed iteration of a background runner.", "runner")
+	runnerFinished = metrics.NewGaugeVec("evergreen_runner_last_finished_timestamp_seconds",
+		"Time the last iteration of a background runner completed, in seconds since the epoch.", "runner")
+)
+
+func init() {
+	metrics.Default.AddCollector(collectTaskQueueMetrics)
+	metrics.Default.AddCollector(collectHostMetrics)
+	metrics.Default.AddCollector(collectRunnerMetrics)
+}
+
+// observeDispatchLatency records the time that a task waited between being
+// scheduled and being dispatched.
+func observeDispatchLatency(t *task.Task, distroId string, dispatchedAt time.Time) {
+	if util.IsZeroTime(t.ScheduledTime) {
+		return
+	}
+	taskDispatchLatency.Observe(dispatchedAt.Sub(t.ScheduledTime).Seconds(), distroId)
+}
+
+// taskQueueAge is the scheduled time of the oldest task queued for a distro.
+type taskQueueAge struct {
+	Distro string    `bson:"_id"`
+	Oldest time.Time `bson:"oldest"`
+}
+
+func collectTaskQueueMetrics() error {
+	queues, err := FindAllTaskQueues()
+	if err != nil {
+		return errors.Wrap(err, "error finding task queues")
+	}
+
+	taskQueueLength.Reset()
+	taskQueueOldestAge.Reset()
+	taskIds := []string{}
+	for _, queue := range queues {
+		taskQueueLength.Set(float64(queue.Length()), queue.Distro)
+		for _, item := range queue.Queue {
+			taskIds = append(taskIds, item.Id)
+		}
+	}
+	if len(taskIds) == 0 {
+		return nil
+	}
+
+	ages := []taskQueueAge{}
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			task.IdKey:            bson.M{"$in": taskIds},
+			task.ScheduledTimeKey: bson.M{"$gt": util.ZeroTime},
+		}},
+		{"$group": bson.M{
+			"_id":    "$" + task.DistroIdKey,
+			"oldest": bson.M{"$min": "$" + task.ScheduledTimeKey},
+		}},
+	}
+	if err = db.Aggregate(task.Collection, pipeline, &ages); err != nil {
+		return errors.Wrap(err, "error finding the oldest queued tasks")
+	}
+	now := time.Now()
+	for _, age := range ages {
+		taskQueueOldestAge.Set(now.Sub(age.Oldest).Seconds(), age.Distro)
+	}
+	return nil
+}
+
+func collectHostMetrics() error {
+	stats, err := host.GetStatsByProvider()
+	if err != nil {
+		return errors.Wrap(err, "error finding host stats")
+	}
+	hostsByStatus.Reset()
+	for _, s := range stats {
+		hostsByStatus.Set(float64(s.Count), s.Status, s.Provider)
+	}
+	return nil
+}
+
+func collectRunnerMetrics() error {
+	runtimes, err := FindEveryProcessRuntime()
+	if err != nil {
+		return errors.Wrap(err, "error finding runner runtimes")
+	}
+	runnerDuration.Reset()
+	runnerFinished.Reset()
+	for _, r := range runtimes {
+		runnerDuration.Set(r.Runtime.Seconds(), r.Id)
+		runnerFinished.Set(float64(r.FinishedAt.Unix()), r.Id)
+	}
+	return nil
+}
@@ -35,6 +35,9 @@ var (
 	ActivatedKey       = bsonutil.MustHaveTag(Patch{}, "Activated")
 	PatchedConfigKey   = bsonutil.MustHaveTag(Patch{}, "PatchedConfig")
 	githubPatchDataKey = bsonutil.MustHaveTag(Patch{}, "GithubPatchData")
+	gitlabPatchDataKey = bsonutil.MustHaveTag(Patch{}, "GitlabPatchData")
+	EstimatedFinishKey = bsonutil.MustHaveTag(Patch{}, "EstimatedFinish")
+	QueuePositionKey   = bsonutil.MustHaveTag(Patch{}, "QueuePosition")
 
 	// BSON fields for the module patch struct
 	ModulePatchNameKey    = bsonutil.MustHaveTag(ModulePatch{}, "ModuleName")
@@ -59,6 +62,10 @@ var (
 	githubPatchHeadHashKey  = bsonutil.MustHaveTag(GithubPatch{}, "HeadHash")
 	githubPatchAuthorKey    = bsonutil.MustHaveTag(GithubPatch{}, "Author")
 	githubPatchDiffURLKey   = bsonutil.MustHaveTag(GithubPatch{}, "DiffURL")
+
+	// BSON fields for GitlabPatch
+	gitlabPatchProjectIDKey = bsonutil.MustHaveTag(GitlabPatch{}, "ProjectID")
+	gitlabPatchMRNumberKey  = bsonutil.MustHaveTag(GitlabPatch{}, "MRNumber")
 )
 
 // Query Validation
@@ -207,3 +214,54 @@ func ByGithubPRAndCreatedBefore(t time.Time, owner, repo string, prNumber int) d
 		bsonutil.GetDottedKeyName(githubPatchDataKey, githubPatchPRNumberKey):  prNumber,
 	})
 }
+
+// ByGitlabMRAndCreatedBefore finds the patches of a GitLab merge request
+// that were created before the given time.
+func ByGitlabMRAndCreatedBefore(t time.Time, projectID, mrNumber int) db.Q {
+	return db.Query(bson.M{
+		CreateTimeKey: bson.M{
+			"$lt": t,
+		},
+		bsonutil.GetDottedKeyName(gitlabPatchDataKey, gitlabPatchProjectIDKey): projectID,
+		bsonutil.GetDottedKeyName(gitlabPatchDataKey, gitlabPatchMRNumberKey):  mrNumber,
+	})
+}
+
+// Estimate is when a patch's tasks are expected to finish, and the lowest
+// position of its tasks in the task queues.
+type Estimate struct {
+	Finish        time.Time
+	QueuePosition int
+}
+
+// SetEstimatesForVersions copies the estimates of the versions with the
+// given IDs to their patches in bulk.
+func SetEstimatesForVersions(estimates map[string]Estimate) error {
+	updates := make([]db.BulkUpdate, 0, len(estimates))
+	for versionId, est := range estimates {
+		updates = append(updates, db.BulkUpdate{
+			Query: bson.M{VersionKey: versionId},
+			Update: bson.M{"$set": bson.M{
+				EstimatedFinishKey: est.Finish,
+				QueuePositionKey:   est.QueuePosition,
+			}},
+		})
+	}
+	return db.UpdateBulk(Collection, updates)
+}
+
+// ClearEstimates unsets the estimates of every patch but the patches of the
+// given versions.
+func ClearEstimates(keepVersions []string) error {
+	_, err := UpdateAll(
+		bson.M{
+			EstimatedFinishKey: bson.M{"$exists": true},
+			VersionKey:         bson.M{"$nin": keepVersions},
+		},
+		bson.M{"$unset": bson.M{
+			EstimatedFinishKey: 1,
+			QueuePositionKey:   1,
+		}},
+	)
+	return err
+}
@@ -92,6 +92,11 @@ type githubIntent struct {
 	// Title is the title of the Github PR
 	Title string `bson:"Title"`
 
+	// Alias is the alias whose variants and tasks the patch runs. It is
+	// only set for patches requested by pull request comments, and is
+	// GithubAlias otherwise.
+	Alias string `bson:"alias,omitempty"`
+
 	// CreatedAt is the time that this intent was stored in the database
 	CreatedAt time.Time `bson:"created_at"`
 
@@ -173,6 +178,32 @@ func NewGithubIntent(msgDeliveryID string, event *github.PullRequestEvent) (Inte
 	}, nil
 }
 
+// NewGithubCommentIntent creates an Intent to run the variants and tasks of
+// the alias on the pull request, as requested by a comment from the user.
+func NewGithubCommentIntent(msgDeliveryID, user, alias string, pr *github.PullRequest) (Intent, error) {
+	if pr == nil || pr.Base == nil {
+		return nil, errors.New("pull request document is malformed/missing data")
+	}
+	if alias == "" {
+		return nil, errors.New("alias must not be empty")
+	}
+
+	// the pull request event carries the same data as the pull request
+	intent, err := NewGithubIntent(msgDeliveryID, &github.PullRequestEvent{
+		Action:      github.String("comment"),
+		Number:      pr.Number,
+		Repo:        pr.Base.Repo,
+		Sender:      &github.User{Login: github.String(user)},
+		PullRequest: pr,
+	})
+	if err != nil {
+		return nil, err
+	}
+	intent.(*githubIntent).Alias = alias
+
+	return intent, nil
+}
+
 // SetProcessed should be called by an amboy queue after creating a patch from an intent.
 func (g *githubIntent) SetProcessed() error {
 	g.Processed = true
@@ -263,5 +294,8 @@ func (g *githubIntent) NewPatch() *Patch {
 }
 
 func (g *githubIntent) GetAlias() string {
+	if g.Alias != "" {
+		return g.Alias
+	}
 	return GithubAlias
 }
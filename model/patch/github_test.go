@@ -8,6 +8,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/google/go-github/github"
 	"github.com/stretchr/testify/suite"
 	"gopkg.in/mgo.v2/bson"
 )
@@ -116,6 +117,42 @@ func (s *GithubSuite) TestNewGithubIntent() {
 	s.Equal(s.url, patchDoc.GithubPatchData.DiffURL)
 }
 
+func (s *GithubSuite) TestNewGithubCommentIntent() {
+	event := testutil.NewGithubPREvent(s.pr, s.baseRepo, s.headRepo, s.hash, s.user, s.url, s.title)
+	pr := event.PullRequest
+	pr.Number = event.Number
+
+	intent, err := NewGithubCommentIntent("1", s.user, "lint", pr)
+	s.Nil(intent)
+	s.Error(err)
+
+	pr.Base = &github.PullRequestBranch{Repo: event.Repo}
+	intent, err = NewGithubCommentIntent("1", s.user, "", pr)
+	s.Nil(intent)
+	s.Error(err)
+
+	intent, err = NewGithubCommentIntent("1", "", "lint", pr)
+	s.Nil(intent)
+	s.Error(err)
+
+	intent, err = NewGithubCommentIntent("1", s.user, "lint", pr)
+	s.NoError(err)
+	s.Require().NotNil(intent)
+	githubIntent, ok := intent.(*githubIntent)
+	s.Require().True(ok)
+	s.Equal("1", githubIntent.MsgID)
+	s.Equal(s.baseRepo, githubIntent.BaseRepoName)
+	s.Equal(s.headRepo, githubIntent.HeadRepoName)
+	s.Equal(s.pr, githubIntent.PRNumber)
+	s.Equal(s.user, githubIntent.User)
+	s.Equal("lint", intent.GetAlias())
+	s.Equal(evergreen.GithubPRRequester, intent.RequesterIdentity())
+
+	intent, err = NewGithubIntent("2", event)
+	s.NoError(err)
+	s.Equal(GithubAlias, intent.GetAlias())
+}
+
 func (s *GithubSuite) TestInsert() {
 	intent, err := NewGithubIntent("1", testutil.NewGithubPREvent(s.pr, s.baseRepo, s.headRepo, s.hash, s.user, s.url, s.title))
 	s.NoError(err)
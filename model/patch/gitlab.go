@@ -0,0 +1,234 @@
+package patch
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// GitlabIntentType represents patch intents created for GitLab.
+	GitlabIntentType = "gitlab"
+
+	// merge request webhook actions that change the merge request's commits
+	GitlabMRActionOpen   = "open"
+	GitlabMRActionReopen = "reopen"
+	GitlabMRActionUpdate = "update"
+)
+
+// GitlabMergeRequestEvent is the payload of a GitLab merge request webhook.
+type GitlabMergeRequestEvent struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		ID       int    `json:"id"`
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		ID                int    `json:"id"`
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID          int    `json:"iid"`
+		TargetBranch string `json:"target_branch"`
+		Title        string `json:"title"`
+		URL          string `json:"url"`
+		Action       string `json:"action"`
+		// OldRev is only set on updates that push new commits.
+		OldRev     string `json:"oldrev"`
+		LastCommit struct {
+			ID string `json:"id"`
+		} `json:"last_commit"`
+	} `json:"object_attributes"`
+}
+
+// ChangesCommits returns true if the event opens the merge request, or
+// pushes new commits to it.
+func (e *GitlabMergeRequestEvent) ChangesCommits() bool {
+	switch e.ObjectAttributes.Action {
+	case GitlabMRActionOpen, GitlabMRActionReopen:
+		return true
+	case GitlabMRActionUpdate:
+		return e.ObjectAttributes.OldRev != ""
+	}
+	return false
+}
+
+// gitlabIntent represents an intent to create a patch build as a result of a
+// GitLab merge request webhook. These intents are processed asynchronously
+// by an amboy queue.
+type gitlabIntent struct {
+	// ID is created by the driver and has no special meaning to the application.
+	DocumentID bson.ObjectId `bson:"_id"`
+
+	// MsgID identifies the merge request's head commit. GitLab webhooks
+	// have no delivery id, so redelivered webhooks have the same MsgID.
+	MsgID string `bson:"msg_id"`
+
+	// ProjectID is GitLab's id of the project the merge request targets,
+	// and ProjectName is its full path, ex: mongodb/mongo
+	ProjectID   int    `bson:"project_id"`
+	ProjectName string `bson:"project_name"`
+
+	// MRNumber is the merge request's number within the project.
+	MRNumber int `bson:"mr_number"`
+
+	// TargetBranch is the branch the merge request will be merged into
+	TargetBranch string `bson:"target_branch"`
+
+	// User is the username of the GitLab user that triggered the webhook,
+	// and UserID is GitLab's id of the user
+	User   string `bson:"user"`
+	UserID int    `bson:"user_id"`
+
+	// HeadHash is the hash of the most recent commit of the merge request
+	HeadHash string `bson:"head_hash"`
+
+	// Title is the title of the merge request
+	Title string `bson:"title"`
+
+	// URL is the web URL of the merge request
+	URL string `bson:"url"`
+
+	// CreatedAt is the time that this intent was stored in the database
+	CreatedAt time.Time `bson:"created_at"`
+
+	// Processed indicates whether a patch intent has been processed by the amboy queue.
+	Processed bool `bson:"processed"`
+
+	// ProcessedAt is the time that this intent was processed
+	ProcessedAt time.Time `bson:"processed_at"`
+
+	// IntentType indicates the type of the patch intent, i.e. GitlabIntentType
+	IntentType string `bson:"intent_type"`
+}
+
+// BSON fields for the patches
+// nolint
+var (
+	gitlabDocumentIDKey  = bsonutil.MustHaveTag(gitlabIntent{}, "DocumentID")
+	gitlabProcessedKey   = bsonutil.MustHaveTag(gitlabIntent{}, "Processed")
+	gitlabProcessedAtKey = bsonutil.MustHaveTag(gitlabIntent{}, "ProcessedAt")
+)
+
+// NewGitlabIntent creates an Intent from a GitLab merge request webhook, or
+// returns an error if some part of the event is invalid.
+func NewGitlabIntent(event *GitlabMergeRequestEvent) (Intent, error) {
+	if event == nil {
+		return nil, errors.New("merge request event is missing")
+	}
+	mr := event.ObjectAttributes
+	if event.Project.ID == 0 {
+		return nil, errors.New("project id must not be 0")
+	}
+	if len(strings.Split(event.Project.PathWithNamespace, "/")) < 2 {
+		return nil, errors.New("project name is invalid (expected [namespace]/[project])")
+	}
+	if mr.IID == 0 {
+		return nil, errors.New("merge request number must not be 0")
+	}
+	if mr.TargetBranch == "" {
+		return nil, errors.New("target branch must not be empty")
+	}
+	if mr.LastCommit.ID == "" {
+		return nil, errors.New("head hash must not be empty")
+	}
+	if event.User.Username == "" {
+		return nil, errors.New("gitlab username must not be empty")
+	}
+	if event.User.ID == 0 {
+		return nil, errors.New("gitlab user id must not be 0")
+	}
+
+	return &gitlabIntent{
+		DocumentID:   bson.NewObjectId(),
+		MsgID:        fmt.Sprintf("%d-%d-%s", event.Project.ID, mr.IID, mr.LastCommit.ID),
+		ProjectID:    event.Project.ID,
+		ProjectName:  event.Project.PathWithNamespace,
+		MRNumber:     mr.IID,
+		TargetBranch: mr.TargetBranch,
+		User:         event.User.Username,
+		UserID:       event.User.ID,
+		HeadHash:     mr.LastCommit.ID,
+		Title:        mr.Title,
+		URL:          mr.URL,
+		IntentType:   GitlabIntentType,
+	}, nil
+}
+
+// SetProcessed should be called by an amboy queue after creating a patch from an intent.
+func (g *gitlabIntent) SetProcessed() error {
+	g.Processed = true
+	g.ProcessedAt = time.Now()
+	return updateOneIntent(
+		bson.M{gitlabDocumentIDKey: g.DocumentID},
+		bson.M{"$set": bson.M{
+			gitlabProcessedKey:   g.Processed,
+			gitlabProcessedAtKey: g.ProcessedAt,
+		}},
+	)
+}
+
+// IsProcessed returns whether a patch exists for this intent.
+func (g *gitlabIntent) IsProcessed() bool {
+	return g.Processed
+}
+
+// GetType returns the patch intent, i.e. GitlabIntentType.
+func (g *gitlabIntent) GetType() string {
+	return g.IntentType
+}
+
+// Insert inserts a patch intent in the database.
+func (g *gitlabIntent) Insert() error {
+	g.CreatedAt = time.Now()
+	err := db.Insert(IntentCollection, g)
+	if err != nil {
+		g.CreatedAt = time.Time{}
+		return err
+	}
+
+	return nil
+}
+
+func (g *gitlabIntent) ID() string {
+	return g.MsgID
+}
+
+func (g *gitlabIntent) ShouldFinalizePatch() bool {
+	return true
+}
+
+func (g *gitlabIntent) RequesterIdentity() string {
+	return evergreen.GitlabMRRequester
+}
+
+func (g *gitlabIntent) NewPatch() *Patch {
+	return &Patch{
+		Id:          bson.NewObjectId(),
+		Description: fmt.Sprintf("'%s' merge request !%d by %s: %s (%s)", g.ProjectName, g.MRNumber, g.User, g.Title, g.URL),
+		Author:      evergreen.GitlabPatchUser,
+		Status:      evergreen.PatchCreated,
+		GitlabPatchData: GitlabPatch{
+			ProjectID:    g.ProjectID,
+			MRNumber:     g.MRNumber,
+			Project:      g.ProjectName,
+			TargetBranch: g.TargetBranch,
+			HeadHash:     g.HeadHash,
+			Author:       g.User,
+			AuthorID:     g.UserID,
+			URL:          g.URL,
+		},
+	}
+}
+
+// GetAlias returns the alias of pull requests, since merge requests run the
+// same variants and tasks.
+func (g *gitlabIntent) GetAlias() string {
+	return GithubAlias
+}
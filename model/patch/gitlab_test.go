@@ -0,0 +1,91 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGitlabMergeRequestEvent() *GitlabMergeRequestEvent {
+	event := &GitlabMergeRequestEvent{ObjectKind: "merge_request"}
+	event.User.ID = 1
+	event.User.Username = "root"
+	event.Project.ID = 3
+	event.Project.PathWithNamespace = "evergreen-ci/evergreen"
+	event.ObjectAttributes.IID = 12
+	event.ObjectAttributes.TargetBranch = "master"
+	event.ObjectAttributes.Title = "Add GitLab merge request testing"
+	event.ObjectAttributes.URL = "https://gitlab.example.com/evergreen-ci/evergreen/merge_requests/12"
+	event.ObjectAttributes.Action = GitlabMRActionOpen
+	event.ObjectAttributes.LastCommit.ID = "da1560886d4f094c3e6c9ef40349f7d38b5d27d7"
+	return event
+}
+
+func TestNewGitlabIntent(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	intent, err := NewGitlabIntent(newTestGitlabMergeRequestEvent())
+	require.NoError(err)
+	assert.Equal(GitlabIntentType, intent.GetType())
+	assert.Equal("3-12-da1560886d4f094c3e6c9ef40349f7d38b5d27d7", intent.ID())
+	assert.Equal(evergreen.GitlabMRRequester, intent.RequesterIdentity())
+	assert.Equal(GithubAlias, intent.GetAlias())
+	assert.True(intent.ShouldFinalizePatch())
+	assert.False(intent.IsProcessed())
+
+	p := intent.NewPatch()
+	assert.Equal(evergreen.GitlabPatchUser, p.Author)
+	assert.Equal(evergreen.PatchCreated, p.Status)
+	assert.Equal("'evergreen-ci/evergreen' merge request !12 by root: Add GitLab merge request testing (https://gitlab.example.com/evergreen-ci/evergreen/merge_requests/12)", p.Description)
+	assert.Equal(GitlabPatch{
+		ProjectID:    3,
+		MRNumber:     12,
+		Project:      "evergreen-ci/evergreen",
+		TargetBranch: "master",
+		HeadHash:     "da1560886d4f094c3e6c9ef40349f7d38b5d27d7",
+		Author:       "root",
+		AuthorID:     1,
+		URL:          "https://gitlab.example.com/evergreen-ci/evergreen/merge_requests/12",
+	}, p.GitlabPatchData)
+
+	_, err = NewGitlabIntent(nil)
+	assert.Error(err)
+	for _, invalidate := range []func(*GitlabMergeRequestEvent){
+		func(e *GitlabMergeRequestEvent) { e.Project.ID = 0 },
+		func(e *GitlabMergeRequestEvent) { e.Project.PathWithNamespace = "evergreen" },
+		func(e *GitlabMergeRequestEvent) { e.ObjectAttributes.IID = 0 },
+		func(e *GitlabMergeRequestEvent) { e.ObjectAttributes.TargetBranch = "" },
+		func(e *GitlabMergeRequestEvent) { e.ObjectAttributes.LastCommit.ID = "" },
+		func(e *GitlabMergeRequestEvent) { e.User.Username = "" },
+		func(e *GitlabMergeRequestEvent) { e.User.ID = 0 },
+	} {
+		event := newTestGitlabMergeRequestEvent()
+		invalidate(event)
+		intent, err = NewGitlabIntent(event)
+		assert.Error(err)
+		assert.Nil(intent)
+	}
+}
+
+func TestGitlabMergeRequestEventChangesCommits(t *testing.T) {
+	assert := assert.New(t)
+
+	event := newTestGitlabMergeRequestEvent()
+	assert.True(event.ChangesCommits())
+
+	event.ObjectAttributes.Action = GitlabMRActionReopen
+	assert.True(event.ChangesCommits())
+
+	event.ObjectAttributes.Action = GitlabMRActionUpdate
+	assert.False(event.ChangesCommits())
+	event.ObjectAttributes.OldRev = "0f6b5c7f1e4ff4a5a5a1b7e9ef6d3d8a0a1b2c3d"
+	assert.True(event.ChangesCommits())
+
+	event.ObjectAttributes.Action = "close"
+	assert.False(event.ChangesCommits())
+	event.ObjectAttributes.Action = "merge"
+	assert.False(event.ChangesCommits())
+}
@@ -45,6 +45,12 @@ type Patch struct {
 	Activated       bool           `bson:"activated"`
 	PatchedConfig   string         `bson:"patched_config"`
 	GithubPatchData GithubPatch    `bson:"github_patch_data,omitempty"`
+	GitlabPatchData GitlabPatch    `bson:"gitlab_patch_data,omitempty"`
+
+	// EstimatedFinish and QueuePosition are copied from the patch's version
+	// on each scheduler pass.
+	EstimatedFinish time.Time `bson:"est_finish,omitempty"`
+	QueuePosition   int       `bson:"queue_pos,omitempty"`
 }
 
 // GithubPatch stores patch data for patches create from GitHub pull requests
@@ -59,6 +65,20 @@ type GithubPatch struct {
 	DiffURL   string `bson:"diff_url"`
 }
 
+// GitlabPatch stores patch data for patches created from GitLab merge
+// requests. ProjectID is GitLab's id of the project the merge request
+// targets, and MRNumber is the merge request's number within the project.
+type GitlabPatch struct {
+	ProjectID    int    `bson:"project_id"`
+	MRNumber     int    `bson:"mr_number"`
+	Project      string `bson:"project"`
+	TargetBranch string `bson:"target_branch"`
+	HeadHash     string `bson:"head_hash"`
+	Author       string `bson:"author"`
+	AuthorID     int    `bson:"author_id"`
+	URL          string `bson:"url"`
+}
+
 // ModulePatch stores request details for a patch
 type ModulePatch struct {
 	ModuleName string   `bson:"name"`
@@ -251,6 +271,21 @@ func (p *Patch) ConfigChanged(remotePath string) bool {
 	return false
 }
 
+// FilesChanged returns the names of the files that the patch changes in
+// the project's repository, excluding changes to modules.
+func (p *Patch) FilesChanged() []string {
+	files := []string{}
+	for _, patchPart := range p.Patches {
+		if patchPart.ModuleName != "" {
+			continue
+		}
+		for _, summary := range patchPart.PatchSet.Summary {
+			files = append(files, summary.Name)
+		}
+	}
+	return files
+}
+
 // SetActivated sets the patch to activated in the db
 func (p *Patch) SetActivated(versionId string) error {
 	p.Version = versionId
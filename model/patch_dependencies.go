@@ -3,7 +3,11 @@ package model
 import "github.com/mongodb/grip"
 
 type dependencyIncluder struct {
-	Project  *Project
+	Project *Project
+	// Mainline includes only the tasks that the tasks depend on, as the
+	// tasks of a mainline version wait for, ignoring whether they can be
+	// patched.
+	Mainline bool
 	included map[TVPair]bool
 }
 
@@ -48,6 +52,14 @@ func (di *dependencyIncluder) handle(pair TVPair) bool {
 		return false // task not found in project--skip it.
 	}
 
+	if di.Mainline {
+		di.included[pair] = true
+		for _, dep := range di.expandDependencies(pair, bvt.DependsOn) {
+			di.handle(dep)
+		}
+		return true
+	}
+
 	if patchable := bvt.Patchable; patchable != nil && !*patchable {
 		di.included[pair] = false
 		return false // task cannot be patched, so skip it
@@ -98,7 +110,7 @@ func (di *dependencyIncluder) expandDependencies(pair TVPair, depends []TaskDepe
 	deps := []TVPair{}
 	for _, d := range depends {
 		// don't automatically add dependencies if they are marked patch_optional
-		if d.PatchOptional {
+		if d.PatchOptional && !di.Mainline {
 			continue
 		}
 		switch {
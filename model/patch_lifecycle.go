@@ -201,6 +201,20 @@ func IncludePatchDependencies(project *Project, tvpairs []TVPair) []TVPair {
 // with the patch applied
 func MakePatchedConfig(p *patch.Patch, remoteConfigPath, projectConfig string) (
 	*Project, error) {
+	data, err := MakePatchedFile(p, remoteConfigPath, projectConfig)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	project := &Project{}
+	if err = LoadProjectInto(data, p.Project, project); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return project, nil
+}
+
+// MakePatchedFile takes in the path to a file of the project's repository and
+// its current contents, and returns its contents with the patch applied.
+func MakePatchedFile(p *patch.Patch, remoteConfigPath, projectConfig string) ([]byte, error) {
 	for _, patchPart := range p.Patches {
 		// we only need to patch the main project and not any other modules
 		if patchPart.ModuleName != "" {
@@ -301,11 +315,7 @@ func MakePatchedConfig(p *patch.Patch, remoteConfigPath, projectConfig string) (
 		if err != nil {
 			return nil, errors.Wrap(err, "could not read patched config file")
 		}
-		project := &Project{}
-		if err = LoadProjectInto(data, p.Project, project); err != nil {
-			return nil, errors.WithStack(err)
-		}
-		return project, nil
+		return data, nil
 	}
 	return nil, errors.New("no patch on project")
 }
@@ -329,12 +339,16 @@ func FinalizePatch(p *patch.Patch, requester string, githubOauthToken string) (*
 		return nil, errors.WithStack(err)
 	}
 
-	gitCommit, err := thirdparty.GetCommitEvent(githubOauthToken, projectRef.Owner, projectRef.Repo, p.Githash)
-	if err != nil {
-		return nil, errors.Wrap(err, "Couldn't fetch commit information")
-	}
-	if gitCommit == nil {
-		return nil, errors.New("Couldn't fetch commit information; git commit doesn't exist")
+	// the base commits of merge request patches are found through the
+	// GitLab API, so only commits of GitHub repositories are checked
+	if requester != evergreen.GitlabMRRequester {
+		gitCommit, err := thirdparty.GetCommitEvent(githubOauthToken, projectRef.Owner, projectRef.Repo, p.Githash)
+		if err != nil {
+			return nil, errors.Wrap(err, "Couldn't fetch commit information")
+		}
+		if gitCommit == nil {
+			return nil, errors.New("Couldn't fetch commit information; git commit doesn't exist")
+		}
 	}
 
 	patchVersion := &version.Version{
@@ -436,3 +450,22 @@ func CancelPatchesWithGithubPatchData(createdBefore time.Time, owner, repo strin
 
 	return nil
 }
+
+// CancelPatchesWithGitlabPatchData runs CancelPatch on the patches of a
+// GitLab merge request that were created before the given time.
+func CancelPatchesWithGitlabPatchData(createdBefore time.Time, projectID, mrNumber int) error {
+	patches, err := patch.Find(patch.ByGitlabMRAndCreatedBefore(createdBefore, projectID, mrNumber))
+	if err != nil {
+		return errors.Wrap(err, "initial patch fetch failed")
+	}
+
+	for i := range patches {
+		if patches[i].Version != "" {
+			if err = CancelPatch(&patches[i], "gitlab-mr"); err != nil {
+				return errors.Wrap(err, "patch cancellation failed")
+			}
+		}
+	}
+
+	return nil
+}
@@ -33,6 +33,7 @@ const (
 type Project struct {
 	Enabled         bool                       `yaml:"enabled,omitempty" bson:"enabled"`
 	Stepback        bool                       `yaml:"stepback,omitempty" bson:"stepback"`
+	StepbackMode    string                     `yaml:"stepback_mode,omitempty" bson:"stepback_mode,omitempty"`
 	BatchTime       int                        `yaml:"batchtime,omitempty" bson:"batch_time"`
 	Owner           string                     `yaml:"owner,omitempty" bson:"owner_name"`
 	Repo            string                     `yaml:"repo,omitempty" bson:"repo_name"`
@@ -52,11 +53,44 @@ type Project struct {
 	Functions       map[string]*YAMLCommandSet `yaml:"functions,omitempty" bson:"functions"`
 	Tasks           []ProjectTask              `yaml:"tasks,omitempty" bson:"tasks"`
 	ExecTimeoutSecs int                        `yaml:"exec_timeout_secs,omitempty" bson:"exec_timeout_secs"`
+	HangAnalysis    *HangAnalysis              `yaml:"hang_analysis,omitempty" bson:"hang_analysis,omitempty"`
 
 	// Flag that indicates a project as requiring user authentication
 	Private bool `yaml:"private,omitempty" bson:"private"`
 }
 
+// HangAnalysis configures the diagnostics that the agent collects about the
+// processes of a task that hit a timeout, before it kills them.
+type HangAnalysis struct {
+	// Disabled turns off hang analysis for the project.
+	Disabled bool `yaml:"disabled,omitempty" bson:"disabled"`
+
+	// CoreDumps is set to take core dumps of the task's processes, whose
+	// total size is limited to MaxCoreDumpMB.
+	CoreDumps     bool `yaml:"core_dumps,omitempty" bson:"core_dumps"`
+	MaxCoreDumpMB int  `yaml:"max_core_dump_mb,omitempty" bson:"max_core_dump_mb"`
+
+	// Upload holds the parameters of the s3.put command that uploads the
+	// diagnostics and attaches them to the task, without the local file.
+	// The diagnostics are only logged if it is not set.
+	Upload map[string]interface{} `yaml:"upload,omitempty" bson:"upload"`
+}
+
+// UploadParams returns the parameters of the s3.put command that uploads
+// the diagnostics of a task from the local file.
+func (h *HangAnalysis) UploadParams(localFile, taskID string) map[string]interface{} {
+	params := map[string]interface{}{
+		"content_type": "application/x-gzip",
+		"display_name": "Hang analysis",
+		"remote_file":  taskID + "/hang_analysis.tgz",
+	}
+	for k, v := range h.Upload {
+		params[k] = v
+	}
+	params["local_file"] = localFile
+	return params
+}
+
 // Unmarshalled from the "tasks" list in an individual build variant
 type BuildVariantTask struct {
 	// Name has to match the name field of one of the tasks specified at
@@ -75,6 +109,10 @@ type BuildVariantTask struct {
 
 	// the distros that the task can be run on
 	Distros []string `yaml:"distros,omitempty" bson:"distros"`
+
+	// Paths and IgnorePaths override the task's filters of changed files
+	Paths       []string `yaml:"paths,omitempty" bson:"paths,omitempty"`
+	IgnorePaths []string `yaml:"ignore_paths,omitempty" bson:"ignore_paths,omitempty"`
 }
 
 type DisplayTask struct {
@@ -98,6 +136,10 @@ func (bvt *BuildVariantTask) Populate(pt ProjectTask) {
 	if bvt.Patchable == nil {
 		bvt.Patchable = pt.Patchable
 	}
+	if len(bvt.Paths) == 0 && len(bvt.IgnorePaths) == 0 {
+		bvt.Paths = pt.Paths
+		bvt.IgnorePaths = pt.IgnorePaths
+	}
 	// TODO these are copied but unused until EVG-578 is completed
 	if bvt.ExecTimeoutSecs == 0 {
 		bvt.ExecTimeoutSecs = pt.ExecTimeoutSecs
@@ -138,6 +180,11 @@ type BuildVariant struct {
 	Tags        []string          `yaml:"tags,omitempty" bson:"tags"`
 	Push        bool              `yaml:"push,omitempty" bson:"push"`
 
+	// Paths and IgnorePaths are gitignore-style patterns of the changed
+	// files that the variant's tasks run for; see AffectedBy.
+	Paths       []string `yaml:"paths,omitempty" bson:"paths,omitempty"`
+	IgnorePaths []string `yaml:"ignore_paths,omitempty" bson:"ignore_paths,omitempty"`
+
 	// Use a *int for 2 possible states
 	// nil - not overriding the project setting
 	// non-nil - overriding the project setting with this BatchTime
@@ -198,6 +245,10 @@ type PluginCommandConf struct {
 
 	// Vars defines variables that can be used within commands.
 	Vars map[string]string `yaml:"vars,omitempty" bson:"vars"`
+
+	// If is a condition that must hold for the command to run. It is
+	// evaluated by the agent right before the command would run.
+	If string `yaml:"if,omitempty" bson:"if,omitempty"`
 }
 
 type ArtifactInstructions struct {
@@ -283,6 +334,14 @@ type ProjectTask struct {
 	Requires        []TaskRequirement   `yaml:"requires,omitempty" bson:"requires"`
 	Commands        []PluginCommandConf `yaml:"commands,omitempty" bson:"commands"`
 	Tags            []string            `yaml:"tags,omitempty" bson:"tags"`
+	If              string              `yaml:"if,omitempty" bson:"if,omitempty"`
+	Paths           []string            `yaml:"paths,omitempty" bson:"paths,omitempty"`
+	IgnorePaths     []string            `yaml:"ignore_paths,omitempty" bson:"ignore_paths,omitempty"`
+
+	// TestSharding splits the task's tests across execution tasks when a
+	// version is created, and Shard describes one of those execution tasks.
+	TestSharding *TestShardingConfig `yaml:"test_sharding,omitempty" bson:"test_sharding,omitempty"`
+	Shard        *TestShard          `yaml:"shard,omitempty" bson:"shard,omitempty"`
 
 	// Use a *bool so that there are 3 possible states:
 	//   1. nil   = not overriding the project setting (default)
@@ -399,6 +458,12 @@ func NewTaskIdTable(p *Project, v *version.Version) TaskIdConfig {
 
 		if evergreen.IsPatchRequester(v.Requester) {
 			rev = fmt.Sprintf("patch_%s_%s", v.Revision, v.Id)
+		} else if v.Requester == evergreen.TriggerRequester {
+			rev = fmt.Sprintf("trigger_%s_%s", v.Revision, v.Id)
+		} else if v.Requester == evergreen.PeriodicBuildRequester {
+			rev = fmt.Sprintf("periodic_%s_%s", v.Revision, v.Id)
+		} else if v.Requester == evergreen.GitTagRequester {
+			rev = fmt.Sprintf("git_tag_%s_%s", v.Revision, v.Id)
 		}
 		for _, t := range bv.Tasks {
 			// create a unique Id for each task
@@ -464,6 +529,12 @@ func generateIdsForVariant(vt TVPair, proj *Project, v *version.Version, tasks T
 	rev := v.Revision
 	if v.Requester == evergreen.PatchVersionRequester {
 		rev = fmt.Sprintf("patch_%s_%s", v.Revision, v.Id)
+	} else if v.Requester == evergreen.TriggerRequester {
+		rev = fmt.Sprintf("trigger_%s_%s", v.Revision, v.Id)
+	} else if v.Requester == evergreen.PeriodicBuildRequester {
+		rev = fmt.Sprintf("periodic_%s_%s", v.Revision, v.Id)
+	} else if v.Requester == evergreen.GitTagRequester {
+		rev = fmt.Sprintf("git_tag_%s_%s", v.Revision, v.Id)
 	}
 	for _, t := range projBV.Tasks {
 		// create Ids for each task that can run on the variant and is requested by the patch.
@@ -524,6 +595,14 @@ func populateExpansions(d *distro.Distro, v *version.Version, bv *BuildVariant,
 	} else {
 		expansions.Put("revision_order_id", strconv.Itoa(v.RevisionOrderNumber))
 	}
+	if v.Requester == evergreen.PeriodicBuildRequester {
+		expansions.Put("is_periodic", "true")
+	}
+	if v.Requester == evergreen.GitTagRequester {
+		expansions.Put("triggered_by_git_tag", "true")
+		expansions.Put("tag", v.GitTag)
+	}
+	addTriggerExpansions(expansions, v.TriggeredBy)
 
 	for _, e := range d.Expansions {
 		expansions.Put(e.Key, e.Value)
@@ -813,6 +892,10 @@ func (p *Project) BuildProjectTVPairs(patchDoc *patch.Patch, alias string) {
 
 	tasks := extractDisplayTasks(pairs, patchDoc.Tasks, patchDoc.BuildVariants, p)
 
+	// skip the tasks that the patch's changes don't affect, unless other
+	// tasks depend on them
+	tasks = p.withoutUnaffectedTasks(tasks, patchDoc.FilesChanged())
+
 	// update variant and tasks to include dependencies
 	tasks.ExecTasks = IncludePatchDependencies(p, tasks.ExecTasks)
 
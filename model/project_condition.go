@@ -0,0 +1,478 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+// Conditions are used in a project file to limit when commands, function calls and tasks run,
+// with an `if:` expression that the agent evaluates when the task runs.
+// Formally, we define the syntax as:
+//   Condition := Or
+//   Or        := And ("||" And)*
+//   And       := Not ("&&" Not)*
+//   Not       := "!" Not | Compare
+//   Compare   := Value (("==" | "!=" | "in") Value)
+//   Value     := "(" Or ")" | "true" | "false" | <quoted string> | "${" <expansion> "}" | <variable>
+//
+// Strings are quoted with single or double quotes. Expansions are strings, and are empty
+// when they are not set. The variables are:
+//   requester  "patch", "github_pr", "gitlab_mr", "mainline", "trigger", "periodic_build"
+//              or "git_tag"
+//   variant    the name of the build variant
+//   tags       the list of the build variant's tags
+//   status     "success" or "failed", the status of the previous command
+//   os         the operating system of the host, e.g. "linux", "windows" or "darwin"
+//
+// For example:
+//   requester == "patch"
+//   ${run_coverage} == "true" && "linux" in tags
+//   status == "failed" || !(os == "windows")
+
+// ConditionType is the type of a value in a condition.
+type ConditionType string
+
+const (
+	ConditionString ConditionType = "string"
+	ConditionBool   ConditionType = "bool"
+	ConditionList   ConditionType = "list"
+)
+
+// Operators of conditions.
+const (
+	ConditionEqual    = "=="
+	ConditionNotEqual = "!="
+	ConditionIn       = "in"
+	ConditionAnd      = "&&"
+	ConditionOr       = "||"
+	ConditionNot      = "!"
+)
+
+// Values of the requester and status variables of conditions.
+const (
+	ConditionRequesterPatch         = "patch"
+	ConditionRequesterGithubPR      = "github_pr"
+	ConditionRequesterGitlabMR      = "gitlab_mr"
+	ConditionRequesterMainline      = "mainline"
+	ConditionRequesterTrigger       = "trigger"
+	ConditionRequesterPeriodicBuild = "periodic_build"
+	ConditionRequesterGitTag        = "git_tag"
+
+	ConditionStatusSuccess = "success"
+	ConditionStatusFailed  = "failed"
+)
+
+// ConditionVariables maps the variables of conditions to their types.
+var ConditionVariables = map[string]ConditionType{
+	"requester": ConditionString,
+	"variant":   ConditionString,
+	"tags":      ConditionList,
+	"status":    ConditionString,
+	"os":        ConditionString,
+}
+
+// ConditionVariableValues holds the values of the variables that can only
+// take a fixed set of values.
+var ConditionVariableValues = map[string][]string{
+	"requester": {ConditionRequesterPatch, ConditionRequesterGithubPR, ConditionRequesterGitlabMR,
+		ConditionRequesterMainline, ConditionRequesterTrigger, ConditionRequesterPeriodicBuild,
+		ConditionRequesterGitTag},
+	"status": {ConditionStatusSuccess, ConditionStatusFailed},
+}
+
+// ConditionNode is a node of a parsed condition.
+type ConditionNode interface {
+	String() string
+}
+
+// ConditionLiteral is a quoted string or a boolean.
+type ConditionLiteral struct {
+	Value interface{}
+}
+
+// ConditionVariable is one of the ConditionVariables.
+type ConditionVariable struct {
+	Name string
+}
+
+// ConditionExpansion is the value of an expansion.
+type ConditionExpansion struct {
+	Name string
+}
+
+// ConditionNegation negates a boolean.
+type ConditionNegation struct {
+	Operand ConditionNode
+}
+
+// ConditionBinary applies a binary operator.
+type ConditionBinary struct {
+	Op          string
+	Left, Right ConditionNode
+}
+
+func (n ConditionLiteral) String() string {
+	if s, ok := n.Value.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("%v", n.Value)
+}
+func (n ConditionVariable) String() string  { return n.Name }
+func (n ConditionExpansion) String() string { return "${" + n.Name + "}" }
+func (n ConditionNegation) String() string  { return "!" + n.Operand.String() }
+func (n ConditionBinary) String() string {
+	return fmt.Sprintf("(%v %v %v)", n.Left, n.Op, n.Right)
+}
+
+// ParseCondition parses a condition into its syntax tree. The types of the
+// values are not checked until the condition is evaluated.
+func ParseCondition(expr string) (ConditionNode, error) {
+	tokens, err := lexCondition(expr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid condition '%s'", expr)
+	}
+	p := &conditionParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err == nil && !p.done() {
+		err = errors.Errorf("unexpected '%s'", p.peek().text)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid condition '%s'", expr)
+	}
+	return node, nil
+}
+
+type conditionTokenKind int
+
+const (
+	conditionOperator conditionTokenKind = iota
+	conditionQuoted
+	conditionIdent
+	conditionExpansion
+)
+
+type conditionToken struct {
+	kind conditionTokenKind
+	text string
+}
+
+// lexCondition splits a condition into tokens.
+func lexCondition(expr string) ([]conditionToken, error) {
+	tokens := []conditionToken{}
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, conditionToken{kind: conditionOperator, text: string(r)})
+			i++
+		case strings.HasPrefix(string(runes[i:]), ConditionEqual),
+			strings.HasPrefix(string(runes[i:]), ConditionNotEqual),
+			strings.HasPrefix(string(runes[i:]), ConditionAnd),
+			strings.HasPrefix(string(runes[i:]), ConditionOr):
+			tokens = append(tokens, conditionToken{kind: conditionOperator, text: string(runes[i : i+2])})
+			i += 2
+		case r == '!':
+			tokens = append(tokens, conditionToken{kind: conditionOperator, text: ConditionNot})
+			i++
+		case r == '\'' || r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, conditionToken{kind: conditionQuoted, text: string(runes[i+1 : end])})
+			i = end + 1
+		case r == '$':
+			end := i
+			for end < len(runes) && runes[end] != '}' {
+				end++
+			}
+			if i+1 >= len(runes) || runes[i+1] != '{' || end == len(runes) || end == i+2 {
+				return nil, errors.Errorf("invalid expansion at position %d", i)
+			}
+			tokens = append(tokens, conditionToken{kind: conditionExpansion, text: string(runes[i+2 : end])})
+			i = end + 1
+		case isConditionIdentRune(r):
+			end := i
+			for end < len(runes) && isConditionIdentRune(runes[end]) {
+				end++
+			}
+			text := string(runes[i:end])
+			kind := conditionIdent
+			if text == ConditionIn {
+				kind = conditionOperator
+			}
+			tokens = append(tokens, conditionToken{kind: kind, text: text})
+			i = end
+		default:
+			return nil, errors.Errorf("unexpected '%c' at position %d", r, i)
+		}
+	}
+	return tokens, nil
+}
+
+func isConditionIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+type conditionParser struct {
+	tokens []conditionToken
+	pos    int
+}
+
+func (p *conditionParser) done() bool { return p.pos >= len(p.tokens) }
+
+func (p *conditionParser) peek() conditionToken {
+	if p.done() {
+		return conditionToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+// accept consumes the next token if it is the given operator.
+func (p *conditionParser) accept(op string) bool {
+	if !p.done() && p.peek().kind == conditionOperator && p.peek().text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *conditionParser) parseOr() (ConditionNode, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.accept(ConditionOr) {
+		var right ConditionNode
+		right, err = p.parseAnd()
+		left = ConditionBinary{Op: ConditionOr, Left: left, Right: right}
+	}
+	return left, err
+}
+
+func (p *conditionParser) parseAnd() (ConditionNode, error) {
+	left, err := p.parseNot()
+	for err == nil && p.accept(ConditionAnd) {
+		var right ConditionNode
+		right, err = p.parseNot()
+		left = ConditionBinary{Op: ConditionAnd, Left: left, Right: right}
+	}
+	return left, err
+}
+
+func (p *conditionParser) parseNot() (ConditionNode, error) {
+	if p.accept(ConditionNot) {
+		operand, err := p.parseNot()
+		return ConditionNegation{Operand: operand}, err
+	}
+	return p.parseCompare()
+}
+
+func (p *conditionParser) parseCompare() (ConditionNode, error) {
+	left, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{ConditionEqual, ConditionNotEqual, ConditionIn} {
+		if p.accept(op) {
+			right, err := p.parseValue()
+			return ConditionBinary{Op: op, Left: left, Right: right}, err
+		}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseValue() (ConditionNode, error) {
+	if p.done() {
+		return nil, errors.New("unexpected end of condition")
+	}
+	if p.accept("(") {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, errors.New("missing ')'")
+		}
+		return node, nil
+	}
+
+	tok := p.peek()
+	p.pos++
+	switch tok.kind {
+	case conditionQuoted:
+		return ConditionLiteral{Value: tok.text}, nil
+	case conditionExpansion:
+		return ConditionExpansion{Name: tok.text}, nil
+	case conditionIdent:
+		switch tok.text {
+		case "true":
+			return ConditionLiteral{Value: true}, nil
+		case "false":
+			return ConditionLiteral{Value: false}, nil
+		}
+		return ConditionVariable{Name: tok.text}, nil
+	}
+	return nil, errors.Errorf("unexpected '%s'", tok.text)
+}
+
+// ConditionContext holds the values that conditions are evaluated against.
+type ConditionContext struct {
+	Expansions *util.Expansions
+	// Requester is the requester of the task's version, such as
+	// evergreen.PatchVersionRequester.
+	Requester string
+	Variant   string
+	Tags      []string
+	// Status is the status of the previous command.
+	Status string
+	OS     string
+}
+
+// ConditionRequester returns the value of the requester variable of
+// conditions for the requester of a version.
+func ConditionRequester(requester string) string {
+	switch requester {
+	case evergreen.PatchVersionRequester:
+		return ConditionRequesterPatch
+	case evergreen.GithubPRRequester:
+		return ConditionRequesterGithubPR
+	case evergreen.GitlabMRRequester:
+		return ConditionRequesterGitlabMR
+	case evergreen.RepotrackerVersionRequester:
+		return ConditionRequesterMainline
+	case evergreen.TriggerRequester:
+		return ConditionRequesterTrigger
+	case evergreen.PeriodicBuildRequester:
+		return ConditionRequesterPeriodicBuild
+	case evergreen.GitTagRequester:
+		return ConditionRequesterGitTag
+	}
+	return requester
+}
+
+// EvalCondition parses and evaluates a condition. An empty condition is
+// always true.
+func EvalCondition(expr string, ctx ConditionContext) (bool, error) {
+	if strings.TrimSpace(expr) == "" {
+		return true, nil
+	}
+	node, err := ParseCondition(expr)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	value, err := evalConditionNode(node, ctx)
+	if err != nil {
+		return false, errors.Wrapf(err, "problem evaluating condition '%s'", expr)
+	}
+	result, ok := value.(bool)
+	if !ok {
+		return false, errors.Errorf("condition '%s' is a %s, not a bool", expr, conditionValueType(value))
+	}
+	return result, nil
+}
+
+func evalConditionNode(node ConditionNode, ctx ConditionContext) (interface{}, error) {
+	switch n := node.(type) {
+	case ConditionLiteral:
+		return n.Value, nil
+	case ConditionExpansion:
+		if ctx.Expansions == nil {
+			return "", nil
+		}
+		return ctx.Expansions.Get(n.Name), nil
+	case ConditionVariable:
+		switch n.Name {
+		case "requester":
+			return ConditionRequester(ctx.Requester), nil
+		case "variant":
+			return ctx.Variant, nil
+		case "tags":
+			return append([]string{}, ctx.Tags...), nil
+		case "status":
+			return ctx.Status, nil
+		case "os":
+			return ctx.OS, nil
+		}
+		return nil, errors.Errorf("unknown variable '%s'", n.Name)
+	case ConditionNegation:
+		operand, err := evalConditionBool(n.Operand, ctx)
+		if err != nil {
+			return nil, err
+		}
+		return !operand, nil
+	case ConditionBinary:
+		return evalConditionBinary(n, ctx)
+	}
+	return nil, errors.Errorf("unknown condition node %T", node)
+}
+
+func evalConditionBinary(n ConditionBinary, ctx ConditionContext) (interface{}, error) {
+	if n.Op == ConditionAnd || n.Op == ConditionOr {
+		left, err := evalConditionBool(n.Left, ctx)
+		if err != nil {
+			return nil, err
+		}
+		// the right operand is only evaluated if it can change the result
+		if (n.Op == ConditionAnd && !left) || (n.Op == ConditionOr && left) {
+			return left, nil
+		}
+		return evalConditionBool(n.Right, ctx)
+	}
+
+	left, err := evalConditionNode(n.Left, ctx)
+	if err != nil {
+		return nil, err
+	}
+	right, err := evalConditionNode(n.Right, ctx)
+	if err != nil {
+		return nil, err
+	}
+	leftType, rightType := conditionValueType(left), conditionValueType(right)
+
+	switch n.Op {
+	case ConditionEqual, ConditionNotEqual:
+		if leftType != rightType || leftType == ConditionList {
+			return nil, errors.Errorf("cannot compare a %s to a %s in '%v'", leftType, rightType, n)
+		}
+		return (left == right) == (n.Op == ConditionEqual), nil
+	case ConditionIn:
+		list, ok := right.([]string)
+		if leftType != ConditionString || !ok {
+			return nil, errors.Errorf("'in' needs a string and a list, not a %s and a %s in '%v'",
+				leftType, rightType, n)
+		}
+		return util.StringSliceContains(list, left.(string)), nil
+	}
+	return nil, errors.Errorf("unknown operator '%s'", n.Op)
+}
+
+func evalConditionBool(node ConditionNode, ctx ConditionContext) (bool, error) {
+	value, err := evalConditionNode(node, ctx)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, errors.Errorf("'%v' is a %s, not a bool", node, conditionValueType(value))
+	}
+	return b, nil
+}
+
+func conditionValueType(value interface{}) ConditionType {
+	switch value.(type) {
+	case bool:
+		return ConditionBool
+	case []string:
+		return ConditionList
+	}
+	return ConditionString
+}
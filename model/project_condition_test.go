@@ -0,0 +1,88 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCondition(t *testing.T) {
+	assert := assert.New(t)
+
+	node, err := ParseCondition(`requester == "patch" || !(${a} != 'b') && "x" in tags`)
+	assert.NoError(err)
+	assert.Equal(`((requester == "patch") || (!(${a} != "b") && ("x" in tags)))`, node.String())
+
+	for _, expr := range []string{
+		`requester ==`,
+		`(status == "failed"`,
+		`"unterminated`,
+		`${}`,
+		`status = "failed"`,
+		`status == "failed" true`,
+	} {
+		_, err = ParseCondition(expr)
+		assert.Error(err, expr)
+	}
+}
+
+func TestEvalCondition(t *testing.T) {
+	assert := assert.New(t)
+	expansions := util.NewExpansions(map[string]string{"run_coverage": "true"})
+	ctx := ConditionContext{
+		Expansions: expansions,
+		Requester:  evergreen.PatchVersionRequester,
+		Variant:    "ubuntu",
+		Tags:       []string{"linux", "nightly"},
+		Status:     ConditionStatusSuccess,
+		OS:         "linux",
+	}
+
+	for expr, expected := range map[string]bool{
+		``:                        true,
+		`requester == "patch"`:    true,
+		`requester == 'mainline'`: false,
+		`${run_coverage} == "true" && "linux" in tags`: true,
+		`${unset} == ""`: true,
+		`"windows" in tags || variant == "ubuntu"`: true,
+		`!(os == "linux")`:                         false,
+		`status != "failed" && true`:               true,
+		`false || false`:                           false,
+	} {
+		result, err := EvalCondition(expr, ctx)
+		assert.NoError(err, expr)
+		assert.Equal(expected, result, expr)
+	}
+
+	for _, expr := range []string{
+		`requester`,
+		`unknown == "x"`,
+		`tags == "linux"`,
+		`"linux" in variant`,
+		`!requester`,
+		`true == "true"`,
+	} {
+		_, err := EvalCondition(expr, ctx)
+		assert.Error(err, expr)
+	}
+
+	for requester, value := range map[string]string{
+		evergreen.PatchVersionRequester:       ConditionRequesterPatch,
+		evergreen.GithubPRRequester:           ConditionRequesterGithubPR,
+		evergreen.GitlabMRRequester:           ConditionRequesterGitlabMR,
+		evergreen.RepotrackerVersionRequester: ConditionRequesterMainline,
+		evergreen.TriggerRequester:            ConditionRequesterTrigger,
+		evergreen.PeriodicBuildRequester:      ConditionRequesterPeriodicBuild,
+		evergreen.GitTagRequester:             ConditionRequesterGitTag,
+	} {
+		assert.Equal(value, ConditionRequester(requester))
+		assert.Contains(ConditionVariableValues["requester"], value)
+	}
+
+	// the right side is not evaluated if it cannot change the result
+	result, err := EvalCondition(`false && unknown == "x"`, ctx)
+	assert.NoError(err)
+	assert.False(result)
+}
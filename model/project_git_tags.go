@@ -0,0 +1,102 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// GitTagDefinition creates a version of the project when a tag matching its
+// pattern is pushed to the project's repository. The version runs the tasks
+// of the alias, using the project's config at the tagged commit.
+type GitTagDefinition struct {
+	// Pattern is a regular expression that the names of the tags it
+	// builds match, e.g. "^v[0-9]+\.[0-9]+\.[0-9]+$".
+	Pattern string `bson:"pattern" json:"pattern"`
+	Alias   string `bson:"alias" json:"alias"`
+}
+
+// GitTag is a tag of a repository, and the revision of the commit it points
+// to.
+type GitTag struct {
+	Name     string
+	Revision string
+}
+
+// Validate returns an error if the git tag definition is not valid.
+func (d *GitTagDefinition) Validate() error {
+	catcher := grip.NewSimpleCatcher()
+	if d.Pattern == "" {
+		catcher.Add(errors.New("pattern can't be empty"))
+	} else if _, err := regexp.Compile(d.Pattern); err != nil {
+		catcher.Add(errors.Wrapf(err, "pattern '%s' is not a valid regular expression", d.Pattern))
+	}
+	if d.Alias == "" {
+		catcher.Add(errors.New("alias can't be empty"))
+	}
+	return catcher.Resolve()
+}
+
+// Matches returns true if the definition builds the tag.
+func (d *GitTagDefinition) Matches(tag string) bool {
+	re, err := regexp.Compile(d.Pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(tag)
+}
+
+// SelectTasks returns the tasks of the project that a version created for a
+// git tag runs.
+func (d *GitTagDefinition) SelectTasks(p *Project) (TaskVariantPairs, error) {
+	tasks, err := selectBranchTipTasks(p, d.Alias, nil, nil)
+	return tasks, errors.Wrapf(err, "error selecting the tasks of git tag pattern '%s'", d.Pattern)
+}
+
+// GitTagDefinitionFor returns the first of the project's git tag definitions
+// that builds the tag, or nil if the project does not build it.
+func (projectRef *ProjectRef) GitTagDefinitionFor(tag string) *GitTagDefinition {
+	for i := range projectRef.GitTagVersions {
+		if projectRef.GitTagVersions[i].Matches(tag) {
+			return &projectRef.GitTagVersions[i]
+		}
+	}
+	return nil
+}
+
+// invalidGitTagIdChars matches the characters of a tag that are replaced in
+// the ids of its version, and of the version's builds and tasks, which are
+// used in URLs.
+var invalidGitTagIdChars = regexp.MustCompile(`[^A-Za-z0-9_.]`)
+
+// GitTagVersionId returns the id of the version that a project creates for a
+// git tag. Ids are deterministic so that a tag seen by both a push event and
+// the repotracker creates a single version. Characters that can't be in ids,
+// like '/', are replaced, and a hash of the tag keeps tags that only differ
+// in those characters from having the same id; the version's GitTag has the
+// tag's name.
+func GitTagVersionId(project, tag string) string {
+	hash := sha256.Sum256([]byte(tag))
+	return fmt.Sprintf("%s_tag_%s_%s", util.CleanName(project),
+		invalidGitTagIdChars.ReplaceAllString(tag, "_"), hex.EncodeToString(hash[:])[:10])
+}
+
+// FindGitTagProjectRefs returns the enabled projects of the repository that
+// build git tags.
+func FindGitTagProjectRefs(owner, repo string) ([]ProjectRef, error) {
+	projectRefs := []ProjectRef{}
+	err := db.FindAllQ(ProjectRefCollection, db.Query(bson.M{
+		ProjectRefOwnerKey:          owner,
+		ProjectRefRepoKey:           repo,
+		ProjectRefEnabledKey:        true,
+		ProjectRefGitTagVersionsKey: bson.M{"$exists": true, "$ne": []GitTagDefinition{}},
+	}), &projectRefs)
+	return projectRefs, errors.Wrapf(err, "error finding projects of %s/%s that build git tags", owner, repo)
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitTagDefinitionValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError((&GitTagDefinition{Pattern: `^v[0-9]+\.[0-9]+\.[0-9]+$`, Alias: "release"}).Validate())
+	assert.NoError((&GitTagDefinition{Pattern: "rc", Alias: "release"}).Validate())
+
+	for _, invalid := range []GitTagDefinition{
+		{Alias: "release"},
+		{Pattern: "^v[0-9+$", Alias: "release"},
+		{Pattern: "^v"},
+	} {
+		assert.Error(invalid.Validate(), "%+v", invalid)
+	}
+}
+
+func TestGitTagDefinitionFor(t *testing.T) {
+	assert := assert.New(t)
+
+	ref := &ProjectRef{
+		Identifier: "server",
+		GitTagVersions: []GitTagDefinition{
+			{Pattern: `^v[0-9]+\.[0-9]+\.[0-9]+$`, Alias: "release"},
+			{Pattern: `-rc[0-9]+$`, Alias: "candidate"},
+			{Pattern: "[", Alias: "invalid"},
+		},
+	}
+
+	definition := ref.GitTagDefinitionFor("v1.2.3")
+	if assert.NotNil(definition) {
+		assert.Equal("release", definition.Alias)
+	}
+	definition = ref.GitTagDefinitionFor("v1.2.3-rc1")
+	if assert.NotNil(definition) {
+		assert.Equal("candidate", definition.Alias)
+	}
+	assert.Nil(ref.GitTagDefinitionFor("nightly"))
+	assert.Nil((&ProjectRef{}).GitTagDefinitionFor("v1.2.3"))
+}
+
+func TestGitTagVersionId(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Regexp(`^server_tag_v1\.2\.3_[0-9a-f]{10}$`, GitTagVersionId("server", "v1.2.3"))
+	assert.Equal(GitTagVersionId("server", "v1.2.3"), GitTagVersionId("server", "v1.2.3"))
+	assert.NotEqual(GitTagVersionId("server", "v1.2.3"), GitTagVersionId("server", "v1.2.4"))
+
+	// tags that only differ in the characters that are replaced have
+	// different ids, which never have slashes
+	assert.Regexp(`^server_tag_release_1_0_[0-9a-f]{10}$`, GitTagVersionId("server", "release/1-0"))
+	assert.NotEqual(GitTagVersionId("server", "v1-0"), GitTagVersionId("server", "v1_0"))
+	assert.NotEqual(GitTagVersionId("server", "a/b"), GitTagVersionId("server", "a_b"))
+}
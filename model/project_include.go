@@ -0,0 +1,349 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// This file contains the resolution of the top-level `include` directive of
+// project configurations. Before a configuration is parsed, the files that
+// it includes, and the files that those include in turn, are merged into a
+// single configuration with no includes, so that selectors, matrices and
+// tags are evaluated over the definitions of all of the files.
+
+const includeKey = "include"
+
+// includeDefinitions are the top-level lists whose entries are definitions
+// named by the given field, which may only be defined once across files.
+var includeDefinitions = map[string]struct {
+	kind  string
+	names []string
+}{
+	"tasks":         {kind: "task", names: []string{"name"}},
+	"buildvariants": {kind: "build variant", names: []string{"name", "matrix_name"}},
+	"axes":          {kind: "axis", names: []string{"id"}},
+	"modules":       {kind: "module", names: []string{"name"}},
+}
+
+// Include names a file whose definitions are merged into the project
+// configuration that includes it. The file is read from the project's
+// repository at the revision of the configuration, or, if a module is
+// named, from the module's repository at the given ref, which defaults to
+// the module's ref or branch.
+type Include struct {
+	FileName string `yaml:"filename"`
+	Module   string `yaml:"module,omitempty"`
+	Ref      string `yaml:"ref,omitempty"`
+}
+
+func (inc Include) String() string {
+	if inc.Module == "" {
+		return inc.FileName
+	}
+	return fmt.Sprintf("%s@%s:%s", inc.Module, inc.Ref, inc.FileName)
+}
+
+// IncludeFetcher returns the contents of an included file. The module is
+// nil for files in the project's own repository.
+type IncludeFetcher func(inc Include, module *Module) ([]byte, error)
+
+// GithubIncludeFetcher returns an IncludeFetcher that reads files of the
+// project's repository at the given revision, and files of modules at the
+// included ref, from github.
+func GithubIncludeFetcher(oauthToken, owner, repo, revision string) IncludeFetcher {
+	return func(inc Include, module *Module) ([]byte, error) {
+		if module == nil {
+			return thirdparty.GetGithubFileContents(oauthToken, owner, repo, inc.FileName, revision)
+		}
+
+		moduleOwner, moduleRepo, err := thirdparty.ParseGithubRepoURL(module.Repo)
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem finding repository of module '%s'", module.Name)
+		}
+		return thirdparty.GetGithubFileContents(oauthToken, moduleOwner, moduleRepo, inc.FileName, inc.Ref)
+	}
+}
+
+// ResolveIncludes merges the files that a project configuration includes,
+// along with the files that they include, into a single configuration.
+// Configurations without includes are returned unchanged. Each file is
+// merged once, even if several files include it. It is an error for the
+// includes to form a cycle, for two files to define the same task, build
+// variant, function, axis or module, or for two files to set a top-level
+// field to different values.
+func ResolveIncludes(yml []byte, fetch IncludeFetcher) ([]byte, error) {
+	root := yaml.MapSlice{}
+	if err := yaml.Unmarshal(yml, &root); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if _, ok := lookupYAMLKey(root, includeKey); !ok {
+		return yml, nil
+	}
+
+	r := &includeResolver{
+		fetch:   fetch,
+		merged:  map[string]bool{},
+		origins: map[string]string{},
+	}
+	r.mergeFile("the main configuration", root, nil, Include{})
+
+	if len(r.errs) > 0 {
+		buf := bytes.Buffer{}
+		for _, e := range r.errs {
+			if len(r.errs) > 1 {
+				buf.WriteString("\n\t")
+			}
+			buf.WriteString(e)
+		}
+		if len(r.errs) > 1 {
+			return nil, errors.Errorf("include errors: %v", buf.String())
+		}
+		return nil, errors.Errorf("include error: %v", buf.String())
+	}
+
+	out, err := yaml.Marshal(r.result)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem marshaling merged configuration")
+	}
+	return out, nil
+}
+
+type includeResolver struct {
+	fetch  IncludeFetcher
+	result yaml.MapSlice
+	// merged holds the files that have been merged into the result
+	merged map[string]bool
+	// origins maps definitions and fields to the file that set them
+	origins map[string]string
+	errs    []string
+}
+
+// mergeFile merges the definitions of a file into the result, and then the
+// files it includes. The stack holds the chain of files that included it.
+func (r *includeResolver) mergeFile(name string, doc yaml.MapSlice, stack []string, parent Include) {
+	var includes []Include
+	for _, item := range doc {
+		key, _ := item.Key.(string)
+		if key == includeKey {
+			if err := convertYAML(item.Value, &includes); err != nil {
+				r.errs = append(r.errs, fmt.Sprintf("invalid includes in %s: %v", name, err))
+			}
+			continue
+		}
+		r.mergeField(name, key, item.Value)
+	}
+
+	for _, inc := range includes {
+		if inc.FileName == "" {
+			r.errs = append(r.errs, fmt.Sprintf("include in %s must name a file", name))
+			continue
+		}
+		// files of a module include other files of the same module
+		if inc.Module == "" && parent.Module != "" {
+			inc.Module = parent.Module
+			inc.Ref = parent.Ref
+		}
+
+		var module *Module
+		if inc.Module != "" {
+			module = r.findModule(inc.Module)
+			if module == nil {
+				r.errs = append(r.errs, fmt.Sprintf("%s includes a file from module '%s', which is not defined",
+					name, inc.Module))
+				continue
+			}
+			if inc.Ref == "" {
+				inc.Ref = module.Ref
+			}
+			if inc.Ref == "" {
+				inc.Ref = module.Branch
+			}
+		}
+
+		incName := inc.String()
+		if includeStackContains(stack, incName) {
+			r.errs = append(r.errs, fmt.Sprintf("include cycle: %s -> %s",
+				strings.Join(stack, " -> "), incName))
+			continue
+		}
+		if r.merged[incName] {
+			continue
+		}
+		r.merged[incName] = true
+
+		data, err := r.fetch(inc, module)
+		if err != nil {
+			r.errs = append(r.errs, fmt.Sprintf("problem reading %s, included by %s: %v", incName, name, err))
+			continue
+		}
+		incDoc := yaml.MapSlice{}
+		if err = yaml.Unmarshal(data, &incDoc); err != nil {
+			r.errs = append(r.errs, fmt.Sprintf("problem parsing %s: %v", incName, err))
+			continue
+		}
+
+		r.mergeFile(incName, incDoc, append(stack, incName), inc)
+	}
+}
+
+// mergeField merges a top-level field of a file into the result.
+func (r *includeResolver) mergeField(name, key string, value interface{}) {
+	existing, ok := lookupYAMLKey(r.result, key)
+
+	if def, isDefinition := includeDefinitions[key]; isDefinition {
+		entries, isList := value.([]interface{})
+		if !isList {
+			r.errs = append(r.errs, fmt.Sprintf("'%s' in %s must be a list", key, name))
+			return
+		}
+		for _, entry := range entries {
+			defName := definitionName(entry, def.names)
+			if defName == "" {
+				continue
+			}
+			originKey := key + "." + defName
+			if origin, defined := r.origins[originKey]; defined {
+				r.errs = append(r.errs, fmt.Sprintf("%s '%s' is defined in both %s and %s",
+					def.kind, defName, origin, name))
+				continue
+			}
+			r.origins[originKey] = name
+		}
+		if ok {
+			existingEntries, _ := existing.([]interface{})
+			value = append(existingEntries, entries...)
+		}
+		r.setField(key, value)
+		return
+	}
+
+	switch key {
+	case "functions":
+		fns, isMap := value.(yaml.MapSlice)
+		if !isMap {
+			r.errs = append(r.errs, fmt.Sprintf("'functions' in %s must be a map", name))
+			return
+		}
+		for _, fn := range fns {
+			fnName := fmt.Sprintf("%v", fn.Key)
+			originKey := "functions." + fnName
+			if origin, defined := r.origins[originKey]; defined {
+				r.errs = append(r.errs, fmt.Sprintf("function '%s' is defined in both %s and %s",
+					fnName, origin, name))
+				continue
+			}
+			r.origins[originKey] = name
+		}
+		if ok {
+			existingFns, _ := existing.(yaml.MapSlice)
+			value = append(existingFns, fns...)
+		}
+		r.setField(key, value)
+	case "ignore":
+		// ignore lists are combined, and may be a single string
+		patterns, isList := value.([]interface{})
+		if !isList {
+			patterns = []interface{}{value}
+		}
+		if ok {
+			existingPatterns, isList := existing.([]interface{})
+			if !isList {
+				existingPatterns = []interface{}{existing}
+			}
+			patterns = append(existingPatterns, patterns...)
+		}
+		r.setField(key, patterns)
+	default:
+		if ok {
+			if !sameYAML(existing, value) {
+				r.errs = append(r.errs, fmt.Sprintf("'%s' is set to different values in %s and %s",
+					key, r.origins[key], name))
+			}
+			return
+		}
+		r.origins[key] = name
+		r.setField(key, value)
+	}
+}
+
+func (r *includeResolver) setField(key string, value interface{}) {
+	for i := range r.result {
+		if r.result[i].Key == key {
+			r.result[i].Value = value
+			return
+		}
+	}
+	r.result = append(r.result, yaml.MapItem{Key: key, Value: value})
+}
+
+// findModule returns the module of the merged configuration with the given
+// name, or nil if it is not defined.
+func (r *includeResolver) findModule(name string) *Module {
+	value, ok := lookupYAMLKey(r.result, "modules")
+	if !ok {
+		return nil
+	}
+	modules := []Module{}
+	if err := convertYAML(value, &modules); err != nil {
+		return nil
+	}
+	for i := range modules {
+		if modules[i].Name == name {
+			return &modules[i]
+		}
+	}
+	return nil
+}
+
+func includeStackContains(stack []string, name string) bool {
+	for _, s := range stack {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+func lookupYAMLKey(doc yaml.MapSlice, key string) (interface{}, bool) {
+	for _, item := range doc {
+		if item.Key == key {
+			return item.Value, true
+		}
+	}
+	return nil, false
+}
+
+// definitionName returns the value of the first of the fields that names
+// a definition.
+func definitionName(entry interface{}, fields []string) string {
+	doc, ok := entry.(yaml.MapSlice)
+	if !ok {
+		return ""
+	}
+	for _, field := range fields {
+		if value, ok := lookupYAMLKey(doc, field); ok {
+			return fmt.Sprintf("%v", value)
+		}
+	}
+	return ""
+}
+
+// convertYAML converts a generic YAML value into the output type.
+func convertYAML(value interface{}, out interface{}) error {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return errors.WithStack(yaml.Unmarshal(data, out))
+}
+
+func sameYAML(a, b interface{}) bool {
+	aData, aErr := yaml.Marshal(a)
+	bData, bErr := yaml.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aData, bData)
+}
@@ -0,0 +1,175 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIncludeFetcher returns an IncludeFetcher that serves the files of the
+// map, keyed by the include's string form.
+func fakeIncludeFetcher(files map[string]string) IncludeFetcher {
+	return func(inc Include, module *Module) ([]byte, error) {
+		data, ok := files[inc.String()]
+		if !ok {
+			return nil, errors.Errorf("no file %s", inc.String())
+		}
+		return []byte(data), nil
+	}
+}
+
+func TestResolveIncludesWithoutIncludes(t *testing.T) {
+	yml := []byte("tasks:\n- name: compile\n")
+	out, err := ResolveIncludes(yml, fakeIncludeFetcher(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, yml, out)
+}
+
+func TestResolveIncludesMergesDefinitions(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	main := `
+include:
+  - filename: tasks.yml
+  - filename: variants.yml
+buildvariants:
+- name: linux
+  run_on: linux-distro
+  tasks:
+  - name: "compile"
+ignore:
+  - "*.md"
+`
+	files := map[string]string{
+		"tasks.yml": `
+include:
+  - filename: functions.yml
+tasks:
+- name: compile
+  tags: ["build"]
+  commands:
+  - func: build
+- name: test
+  tags: ["build"]
+ignore: "docs/*"
+`,
+		"functions.yml": `
+functions:
+  build:
+    command: shell.exec
+`,
+		"variants.yml": `
+include:
+  - filename: tasks.yml
+buildvariants:
+- name: osx
+  run_on: osx-distro
+  tasks:
+  - name: ".build"
+`,
+	}
+
+	out, err := ResolveIncludes([]byte(main), fakeIncludeFetcher(files))
+	require.NoError(err)
+
+	p := &Project{}
+	require.NoError(LoadProjectInto(out, "", p))
+	assert.Len(p.Tasks, 2)
+	assert.Len(p.BuildVariants, 2)
+	assert.Contains(p.Functions, "build")
+	assert.Equal([]string{"*.md", "docs/*"}, []string(p.Ignore))
+
+	osx := p.FindBuildVariant("osx")
+	require.NotNil(osx)
+	assert.Len(osx.Tasks, 2)
+}
+
+func TestResolveIncludesFromModules(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	main := `
+modules:
+- name: tools
+  repo: git@github.com:evergreen-ci/tools.git
+  branch: master
+include:
+  - filename: evergreen/tasks.yml
+    module: tools
+  - filename: evergreen/variants.yml
+    module: tools
+    ref: v1.0
+`
+	files := map[string]string{
+		"tools@master:evergreen/tasks.yml": `
+include:
+  - filename: evergreen/more_tasks.yml
+tasks:
+- name: lint
+`,
+		"tools@master:evergreen/more_tasks.yml": `
+tasks:
+- name: format
+`,
+		"tools@v1.0:evergreen/variants.yml": `
+buildvariants:
+- name: linux
+  tasks:
+  - name: lint
+`,
+	}
+
+	out, err := ResolveIncludes([]byte(main), fakeIncludeFetcher(files))
+	require.NoError(err)
+
+	p := &Project{}
+	require.NoError(LoadProjectInto(out, "", p))
+	assert.NotNil(p.FindProjectTask("lint"))
+	assert.NotNil(p.FindProjectTask("format"))
+	assert.NotNil(p.FindBuildVariant("linux"))
+}
+
+func TestResolveIncludesErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	// cycles name the chain of files
+	_, err := ResolveIncludes([]byte("include:\n- filename: a.yml\n"), fakeIncludeFetcher(map[string]string{
+		"a.yml": "include:\n- filename: b.yml\n",
+		"b.yml": "include:\n- filename: a.yml\n",
+	}))
+	if assert.Error(err) {
+		assert.Contains(err.Error(), "include cycle: a.yml -> b.yml -> a.yml")
+	}
+
+	// definitions may only be in one file
+	_, err = ResolveIncludes([]byte("include:\n- filename: a.yml\ntasks:\n- name: compile\n"),
+		fakeIncludeFetcher(map[string]string{
+			"a.yml": "tasks:\n- name: compile\nfunctions:\n  build: {}\n",
+		}))
+	if assert.Error(err) {
+		assert.Contains(err.Error(), "task 'compile' is defined in both the main configuration and a.yml")
+	}
+
+	// other fields may only be set to the same value
+	_, err = ResolveIncludes([]byte("include:\n- filename: a.yml\nstepback: true\n"),
+		fakeIncludeFetcher(map[string]string{"a.yml": "stepback: false\n"}))
+	if assert.Error(err) {
+		assert.Contains(err.Error(), "'stepback' is set to different values in the main configuration and a.yml")
+	}
+	_, err = ResolveIncludes([]byte("include:\n- filename: a.yml\nstepback: true\n"),
+		fakeIncludeFetcher(map[string]string{"a.yml": "stepback: true\n"}))
+	assert.NoError(err)
+
+	// module files need a defined module
+	_, err = ResolveIncludes([]byte("include:\n- filename: a.yml\n  module: tools\n"), fakeIncludeFetcher(nil))
+	if assert.Error(err) {
+		assert.Contains(err.Error(), "module 'tools', which is not defined")
+	}
+
+	// unresolved includes are reported when parsing
+	p := &Project{}
+	assert.Error(LoadProjectInto([]byte("include:\n- filename: a.yml\n"), "", p))
+}
@@ -39,6 +39,7 @@ import (
 type parserProject struct {
 	Enabled         bool                       `yaml:"enabled"`
 	Stepback        bool                       `yaml:"stepback"`
+	StepbackMode    string                     `yaml:"stepback_mode"`
 	BatchTime       int                        `yaml:"batchtime"`
 	Owner           string                     `yaml:"owner"`
 	Repo            string                     `yaml:"repo"`
@@ -58,9 +59,14 @@ type parserProject struct {
 	Functions       map[string]*YAMLCommandSet `yaml:"functions"`
 	Tasks           []parserTask               `yaml:"tasks"`
 	ExecTimeoutSecs int                        `yaml:"exec_timeout_secs"`
+	HangAnalysis    *HangAnalysis              `yaml:"hang_analysis"`
 
 	// Matrix code
 	Axes []matrixAxis `yaml:"axes"`
+
+	// Include is only set when the includes were not resolved, which is
+	// an error.
+	Include []Include `yaml:"include"`
 }
 
 // parserTask represents an intermediary state of task definitions.
@@ -73,6 +79,11 @@ type parserTask struct {
 	Requires        taskSelectors       `yaml:"requires"`
 	Commands        []PluginCommandConf `yaml:"commands"`
 	Tags            parserStringSlice   `yaml:"tags"`
+	If              string              `yaml:"if"`
+	Paths           parserStringSlice   `yaml:"paths"`
+	IgnorePaths     parserStringSlice   `yaml:"ignore_paths"`
+	TestSharding    *TestShardingConfig `yaml:"test_sharding"`
+	Shard           *TestShard          `yaml:"shard"`
 	Patchable       *bool               `yaml:"patchable"`
 	Stepback        *bool               `yaml:"stepback"`
 }
@@ -224,6 +235,8 @@ type parserBV struct {
 	Modules      parserStringSlice `yaml:"modules"`
 	Disabled     bool              `yaml:"disabled"`
 	Push         bool              `yaml:"push"`
+	Paths        parserStringSlice `yaml:"paths"`
+	IgnorePaths  parserStringSlice `yaml:"ignore_paths"`
 	BatchTime    *int              `yaml:"batchtime"`
 	Stepback     *bool             `yaml:"stepback"`
 	RunOn        parserStringSlice `yaml:"run_on"`
@@ -281,6 +294,8 @@ type parserBVTask struct {
 	Stepback        *bool              `yaml:"stepback"`
 	Distros         parserStringSlice  `yaml:"distros"`
 	RunOn           parserStringSlice  `yaml:"run_on"` // Alias for "Distros" TODO: deprecate Distros
+	Paths           parserStringSlice  `yaml:"paths"`
+	IgnorePaths     parserStringSlice  `yaml:"ignore_paths"`
 }
 
 // UnmarshalYAML allows the YAML parser to read both a single selector string or
@@ -410,6 +425,7 @@ func translateProject(pp *parserProject) (*Project, []error) {
 	proj := &Project{
 		Enabled:         pp.Enabled,
 		Stepback:        pp.Stepback,
+		StepbackMode:    pp.StepbackMode,
 		BatchTime:       pp.BatchTime,
 		Owner:           pp.Owner,
 		Repo:            pp.Repo,
@@ -427,11 +443,15 @@ func translateProject(pp *parserProject) (*Project, []error) {
 		Modules:         pp.Modules,
 		Functions:       pp.Functions,
 		ExecTimeoutSecs: pp.ExecTimeoutSecs,
+		HangAnalysis:    pp.HangAnalysis,
 	}
 	tse := NewParserTaskSelectorEvaluator(pp.Tasks)
 	ase := NewAxisSelectorEvaluator(pp.Axes)
 	regularBVs, matrices := sieveMatrixVariants(pp.BuildVariants)
 	var evalErrs, errs []error
+	if len(pp.Include) > 0 {
+		evalErrs = append(evalErrs, errors.New("project includes other files, which were not resolved"))
+	}
 	matrixVariants, errs := buildMatrixVariants(pp.Axes, ase, matrices)
 	evalErrs = append(evalErrs, errs...)
 	pp.BuildVariants = append(regularBVs, matrixVariants...)
@@ -469,6 +489,11 @@ func evaluateTasks(tse *taskSelectorEvaluator, vse *variantSelectorEvaluator,
 			ExecTimeoutSecs: pt.ExecTimeoutSecs,
 			Commands:        pt.Commands,
 			Tags:            pt.Tags,
+			If:              pt.If,
+			Paths:           pt.Paths,
+			IgnorePaths:     pt.IgnorePaths,
+			TestSharding:    pt.TestSharding,
+			Shard:           pt.Shard,
 			Patchable:       pt.Patchable,
 			Stepback:        pt.Stepback,
 		}
@@ -495,6 +520,8 @@ func evaluateBuildVariants(tse *taskSelectorEvaluator, vse *variantSelectorEvalu
 			Modules:     pbv.Modules,
 			Disabled:    pbv.Disabled,
 			Push:        pbv.Push,
+			Paths:       pbv.Paths,
+			IgnorePaths: pbv.IgnorePaths,
 			BatchTime:   pbv.BatchTime,
 			Stepback:    pbv.Stepback,
 			RunOn:       pbv.RunOn,
@@ -624,6 +651,8 @@ func evaluateBVTasks(tse *taskSelectorEvaluator, vse *variantSelectorEvaluator,
 				ExecTimeoutSecs: pt.ExecTimeoutSecs,
 				Stepback:        pt.Stepback,
 				Distros:         pt.Distros,
+				Paths:           pt.Paths,
+				IgnorePaths:     pt.IgnorePaths,
 			}
 			t.DependsOn, errs = evaluateDependsOn(tse, vse, pt.DependsOn)
 			evalErrs = append(evalErrs, errs...)
@@ -0,0 +1,181 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/util"
+	ignore "github.com/sabhiram/go-git-ignore"
+)
+
+// AffectedBy returns true if the variant's paths filters match any of the
+// changed files; see affectedByFiles.
+func (bv *BuildVariant) AffectedBy(files []string) bool {
+	return affectedByFiles(bv.Paths, bv.IgnorePaths, files)
+}
+
+// AffectedBy returns true if the task's paths filters match any of the
+// changed files; see affectedByFiles. The task must be populated from its
+// project task first.
+func (bvt *BuildVariantTask) AffectedBy(files []string) bool {
+	return affectedByFiles(bvt.Paths, bvt.IgnorePaths, files)
+}
+
+// affectedByFiles returns true if any of the changed files matches the
+// paths patterns, and does not match the ignore_paths patterns. Everything
+// is affected when there are no patterns, or the changed files are unknown.
+func affectedByFiles(paths, ignorePaths, files []string) bool {
+	if (len(paths) == 0 && len(ignorePaths) == 0) || len(files) == 0 {
+		return true
+	}
+
+	// CompileIgnoreLines has a silly API: it always returns a nil error.
+	var matcher, ignorer *ignore.GitIgnore
+	if len(paths) > 0 {
+		matcher, _ = ignore.CompileIgnoreLines(paths...)
+	}
+	if len(ignorePaths) > 0 {
+		ignorer, _ = ignore.CompileIgnoreLines(ignorePaths...)
+	}
+	for _, f := range files {
+		if matcher != nil && !matcher.MatchesPath(f) {
+			continue
+		}
+		if ignorer != nil && ignorer.MatchesPath(f) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// HasPathFilters returns true if any variant or task of the project filters
+// its changed files.
+func (p *Project) HasPathFilters() bool {
+	for _, t := range p.Tasks {
+		if len(t.Paths) > 0 || len(t.IgnorePaths) > 0 {
+			return true
+		}
+	}
+	for _, bv := range p.BuildVariants {
+		if len(bv.Paths) > 0 || len(bv.IgnorePaths) > 0 {
+			return true
+		}
+		for _, bvt := range bv.Tasks {
+			if len(bvt.Paths) > 0 || len(bvt.IgnorePaths) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UnaffectedTasks returns the names of the variant's tasks that the changed
+// files don't affect, along with its display tasks whose execution tasks
+// are all unaffected.
+func (p *Project) UnaffectedTasks(variant string, files []string) []string {
+	bv := p.FindBuildVariant(variant)
+	if bv == nil || len(files) == 0 {
+		return nil
+	}
+	return withUnaffectedDisplayTasks(bv, p.unaffectedExecTasks(bv, files))
+}
+
+// UnaffectedMainlineTasks returns the names of the tasks of each variant
+// that the changed files don't affect, like UnaffectedTasks, except for the
+// tasks that affected tasks depend on, which have to run for them.
+func (p *Project) UnaffectedMainlineTasks(files []string) map[string][]string {
+	if len(files) == 0 {
+		return nil
+	}
+
+	unaffected := map[TVPair]bool{}
+	affected := []TVPair{}
+	for i := range p.BuildVariants {
+		bv := &p.BuildVariants[i]
+		if bv.Disabled {
+			continue
+		}
+		names := p.unaffectedExecTasks(bv, files)
+		for _, bvt := range bv.Tasks {
+			pair := TVPair{Variant: bv.Name, TaskName: bvt.Name}
+			if util.StringSliceContains(names, bvt.Name) {
+				unaffected[pair] = true
+			} else {
+				affected = append(affected, pair)
+			}
+		}
+	}
+	di := &dependencyIncluder{Project: p, Mainline: true}
+	for _, pair := range di.Include(affected) {
+		delete(unaffected, pair)
+	}
+
+	byVariant := map[string][]string{}
+	for i := range p.BuildVariants {
+		bv := &p.BuildVariants[i]
+		names := []string{}
+		for _, bvt := range bv.Tasks {
+			if unaffected[TVPair{Variant: bv.Name, TaskName: bvt.Name}] {
+				names = append(names, bvt.Name)
+			}
+		}
+		byVariant[bv.Name] = withUnaffectedDisplayTasks(bv, names)
+	}
+	return byVariant
+}
+
+// unaffectedExecTasks returns the names of the variant's tasks that the
+// changed files don't affect.
+func (p *Project) unaffectedExecTasks(bv *BuildVariant, files []string) []string {
+	variantAffected := bv.AffectedBy(files)
+	unaffected := []string{}
+	for _, bvt := range bv.Tasks {
+		if spec := p.FindProjectTask(bvt.Name); spec != nil {
+			bvt.Populate(*spec)
+		}
+		if !variantAffected || !bvt.AffectedBy(files) {
+			unaffected = append(unaffected, bvt.Name)
+		}
+	}
+	return unaffected
+}
+
+// withUnaffectedDisplayTasks adds the variant's display tasks whose
+// execution tasks are all unaffected to the unaffected tasks.
+func withUnaffectedDisplayTasks(bv *BuildVariant, unaffected []string) []string {
+	for _, dt := range bv.DisplayTasks {
+		if len(dt.ExecutionTasks) == 0 {
+			continue
+		}
+		if len(util.StringSliceIntersection(dt.ExecutionTasks, unaffected)) == len(dt.ExecutionTasks) {
+			unaffected = append(unaffected, dt.Name)
+		}
+	}
+	return unaffected
+}
+
+// withoutUnaffectedTasks removes the tasks that the changed files don't
+// affect from the pairs.
+func (p *Project) withoutUnaffectedTasks(pairs TaskVariantPairs, files []string) TaskVariantPairs {
+	if len(files) == 0 || !p.HasPathFilters() {
+		return pairs
+	}
+
+	unaffected := map[TVPair]bool{}
+	for _, bv := range p.BuildVariants {
+		for _, name := range p.UnaffectedTasks(bv.Name, files) {
+			unaffected[TVPair{Variant: bv.Name, TaskName: name}] = true
+		}
+	}
+
+	filtered := TaskVariantPairs{}
+	for _, pair := range pairs.ExecTasks {
+		if !unaffected[pair] {
+			filtered.ExecTasks = append(filtered.ExecTasks, pair)
+		}
+	}
+	for _, pair := range pairs.DisplayTasks {
+		if !unaffected[pair] {
+			filtered.DisplayTasks = append(filtered.DisplayTasks, pair)
+		}
+	}
+	return filtered
+}
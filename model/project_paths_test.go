@@ -0,0 +1,151 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAffectedByFiles(t *testing.T) {
+	assert := assert.New(t)
+
+	files := []string{"src/server/main.go", "docs/README.md"}
+
+	assert.True(affectedByFiles(nil, nil, files))
+	assert.True(affectedByFiles([]string{"src/client/"}, nil, nil))
+	assert.True(affectedByFiles([]string{"src/server/"}, nil, files))
+	assert.True(affectedByFiles([]string{"*.go"}, nil, files))
+	assert.False(affectedByFiles([]string{"src/client/"}, nil, files))
+	assert.True(affectedByFiles(nil, []string{"docs/"}, files))
+	assert.False(affectedByFiles(nil, []string{"docs/", "*.go"}, files))
+	assert.False(affectedByFiles([]string{"src/"}, []string{"*.go"}, files))
+}
+
+func TestUnaffectedTasks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	yml := `
+tasks:
+- name: compile
+- name: server_test
+  paths: ["src/server/"]
+- name: client_test
+  paths: ["src/client/"]
+- name: lint
+  ignore_paths: ["docs/"]
+buildvariants:
+- name: linux
+  tasks:
+  - name: compile
+  - name: server_test
+  - name: client_test
+  - name: lint
+  display_tasks:
+  - name: tests
+    execution_tasks: ["server_test", "client_test"]
+  - name: checks
+    execution_tasks: ["lint"]
+- name: docs
+  paths: ["docs/"]
+  tasks:
+  - name: compile
+- name: windows
+  tasks:
+  - name: compile
+    ignore_paths: ["*.md"]
+`
+	p := &Project{}
+	require.NoError(LoadProjectInto([]byte(yml), "", p))
+	assert.True(p.HasPathFilters())
+	assert.False((&Project{}).HasPathFilters())
+
+	assert.Equal([]string{"client_test"}, p.UnaffectedTasks("linux", []string{"src/server/main.go"}))
+	assert.Equal([]string{"server_test", "client_test", "lint", "tests", "checks"}, p.UnaffectedTasks("linux", []string{"docs/README.md"}))
+	assert.Empty(p.UnaffectedTasks("linux", nil))
+	assert.Equal([]string{"compile"}, p.UnaffectedTasks("docs", []string{"src/server/main.go"}))
+	assert.Empty(p.UnaffectedTasks("docs", []string{"docs/README.md"}))
+	assert.Equal([]string{"compile"}, p.UnaffectedTasks("windows", []string{"docs/README.md"}))
+	assert.Nil(p.UnaffectedTasks("nonexistent", []string{"docs/README.md"}))
+
+	pairs := TaskVariantPairs{
+		ExecTasks: TVPairSet{
+			{Variant: "linux", TaskName: "compile"},
+			{Variant: "linux", TaskName: "server_test"},
+			{Variant: "linux", TaskName: "client_test"},
+			{Variant: "docs", TaskName: "compile"},
+		},
+		DisplayTasks: TVPairSet{
+			{Variant: "linux", TaskName: "tests"},
+			{Variant: "linux", TaskName: "checks"},
+		},
+	}
+	filtered := p.withoutUnaffectedTasks(pairs, []string{"src/server/main.go"})
+	assert.Equal(TVPairSet{
+		{Variant: "linux", TaskName: "compile"},
+		{Variant: "linux", TaskName: "server_test"},
+	}, filtered.ExecTasks)
+	assert.Equal(pairs.DisplayTasks, filtered.DisplayTasks)
+	filtered = p.withoutUnaffectedTasks(pairs, []string{"docs/README.md"})
+	assert.Equal(TVPairSet{
+		{Variant: "linux", TaskName: "compile"},
+		{Variant: "docs", TaskName: "compile"},
+	}, filtered.ExecTasks)
+	assert.Empty(filtered.DisplayTasks)
+	assert.Equal(pairs, p.withoutUnaffectedTasks(pairs, nil))
+}
+
+func TestUnaffectedMainlineTasks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	yml := `
+tasks:
+- name: compile
+  paths: ["src/"]
+- name: docs
+  paths: ["docs/"]
+- name: package
+  paths: ["packaging/"]
+- name: publish_docs
+  paths: ["docs/"]
+  depends_on:
+  - name: docs
+  - name: package
+    variant: linux
+    patch_optional: true
+- name: lint
+  paths: ["src/"]
+buildvariants:
+- name: linux
+  tasks:
+  - name: compile
+  - name: package
+    depends_on:
+    - name: compile
+  - name: lint
+  display_tasks:
+  - name: checks
+    execution_tasks: ["lint"]
+- name: site
+  tasks:
+  - name: docs
+  - name: publish_docs
+`
+	p := &Project{}
+	require.NoError(LoadProjectInto([]byte(yml), "", p))
+
+	// publish_docs depends on package in another variant, which depends on
+	// compile, so neither is filtered even though they're unaffected
+	unaffected := p.UnaffectedMainlineTasks([]string{"docs/README.md"})
+	assert.Equal([]string{"lint", "checks"}, unaffected["linux"])
+	assert.Empty(unaffected["site"])
+	assert.Equal([]string{"compile", "package", "lint", "checks"}, p.UnaffectedTasks("linux", []string{"docs/README.md"}))
+
+	unaffected = p.UnaffectedMainlineTasks([]string{"src/main.go"})
+	assert.Equal([]string{"package"}, unaffected["linux"])
+	assert.Equal([]string{"docs", "publish_docs"}, unaffected["site"])
+
+	assert.Nil(p.UnaffectedMainlineTasks(nil))
+}
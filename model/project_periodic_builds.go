@@ -0,0 +1,118 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// periodicBuildIdFormat is the layout of the scheduled time in the ids
+	// of periodic versions.
+	periodicBuildIdFormat = "2006_01_02_15_04"
+
+	// periodicBuildMaxCatchUp is how far back runs of periodic builds that
+	// were missed are made up, so that a long outage doesn't create a
+	// version for every run that was missed.
+	periodicBuildMaxCatchUp = 24 * time.Hour
+)
+
+var validPeriodicBuildId = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// PeriodicBuildDefinition creates a version of the project on a schedule,
+// whether or not there are new commits, using the project's config at the
+// tip of its branch. The version runs every task unless it is limited to an
+// alias, or to build variants and task names.
+type PeriodicBuildDefinition struct {
+	// ID distinguishes the versions created by each of a project's
+	// periodic builds.
+	ID string `bson:"id" json:"id"`
+	// Cron is the schedule of the periodic build in cron syntax, evaluated
+	// in UTC, e.g. "0 2 * * *" or "@weekly".
+	Cron string `bson:"cron" json:"cron"`
+
+	Alias         string   `bson:"alias,omitempty" json:"alias,omitempty"`
+	BuildVariants []string `bson:"build_variants,omitempty" json:"build_variants,omitempty"`
+	Tasks         []string `bson:"tasks,omitempty" json:"tasks,omitempty"`
+
+	// Message is the description of the versions it creates, instead of
+	// the message of the commit they run.
+	Message string `bson:"message,omitempty" json:"message,omitempty"`
+}
+
+// Validate returns an error if the periodic build is not valid.
+func (d *PeriodicBuildDefinition) Validate() error {
+	catcher := grip.NewSimpleCatcher()
+	if strings.TrimSpace(d.ID) == "" {
+		catcher.Add(errors.New("id can't be empty"))
+	} else if !validPeriodicBuildId.MatchString(d.ID) {
+		catcher.Add(errors.Errorf("id '%s' may only contain letters, numbers and underscores", d.ID))
+	}
+	if _, err := util.ParseCron(d.Cron); err != nil {
+		catcher.Add(err)
+	}
+	if d.Alias != "" && (len(d.BuildVariants) > 0 || len(d.Tasks) > 0) {
+		catcher.Add(errors.New("a periodic build can't be limited to both an alias and build variants or tasks"))
+	}
+	return catcher.Resolve()
+}
+
+// DueRuns returns the minutes after the last run, up to and including the
+// minute of now, at which the periodic build was scheduled to run, in order,
+// so that runs missed while the servers were down or busy are still made.
+// Without a last run, only the minute of now is considered. Runs are made up
+// for at most the periodicBuildMaxCatchUp before now.
+func (d *PeriodicBuildDefinition) DueRuns(lastRun, now time.Time) []time.Time {
+	schedule, err := util.ParseCron(d.Cron)
+	if err != nil {
+		return nil
+	}
+	now = now.UTC().Truncate(time.Minute)
+	start := now
+	if !lastRun.IsZero() {
+		start = lastRun.UTC().Truncate(time.Minute).Add(time.Minute)
+	}
+	if earliest := now.Add(time.Minute - periodicBuildMaxCatchUp); start.Before(earliest) {
+		start = earliest
+	}
+
+	runs := []time.Time{}
+	for t := start; !t.After(now); t = t.Add(time.Minute) {
+		if schedule.Matches(t) {
+			runs = append(runs, t)
+		}
+	}
+	return runs
+}
+
+// SelectTasks returns the tasks of the project that a version created by the
+// periodic build runs.
+func (d *PeriodicBuildDefinition) SelectTasks(p *Project) (TaskVariantPairs, error) {
+	tasks, err := selectBranchTipTasks(p, d.Alias, d.BuildVariants, d.Tasks)
+	return tasks, errors.Wrapf(err, "error selecting the tasks of periodic build '%s'", d.ID)
+}
+
+// PeriodicVersionId returns the id of the version that a periodic build of a
+// project creates at its scheduled time. Ids are deterministic so that the
+// periodic build creates a single version each time it is scheduled.
+func PeriodicVersionId(project, definitionId string, scheduled time.Time) string {
+	return util.CleanName(fmt.Sprintf("%s_periodic_%s_%s", project, definitionId, scheduled.UTC().Format(periodicBuildIdFormat)))
+}
+
+// FindPeriodicBuildProjectRefs returns the enabled projects that have
+// periodic builds.
+func FindPeriodicBuildProjectRefs() ([]ProjectRef, error) {
+	projectRefs := []ProjectRef{}
+	err := db.FindAllQ(ProjectRefCollection, db.Query(bson.M{
+		ProjectRefEnabledKey:        true,
+		ProjectRefPeriodicBuildsKey: bson.M{"$exists": true, "$ne": []PeriodicBuildDefinition{}},
+	}), &projectRefs)
+	return projectRefs, errors.Wrap(err, "error finding projects with periodic builds")
+}
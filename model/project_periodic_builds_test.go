@@ -0,0 +1,106 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeriodicBuildDefinitionValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError((&PeriodicBuildDefinition{ID: "nightly", Cron: "0 2 * * *"}).Validate())
+	assert.NoError((&PeriodicBuildDefinition{ID: "weekly_perf", Cron: "@weekly", Alias: "perf"}).Validate())
+	assert.NoError((&PeriodicBuildDefinition{ID: "nightly", Cron: "0 2 * * *", BuildVariants: []string{"linux"}, Tasks: []string{"test"}}).Validate())
+
+	for _, invalid := range []PeriodicBuildDefinition{
+		{Cron: "0 2 * * *"},
+		{ID: "nightly build", Cron: "0 2 * * *"},
+		{ID: "nightly"},
+		{ID: "nightly", Cron: "0 25 * * *"},
+		{ID: "nightly", Cron: "0 2 * * *", Alias: "perf", Tasks: []string{"test"}},
+	} {
+		assert.Error(invalid.Validate(), "%+v", invalid)
+	}
+}
+
+func TestPeriodicBuildDefinitionDueRuns(t *testing.T) {
+	assert := assert.New(t)
+
+	nightly := PeriodicBuildDefinition{ID: "nightly", Cron: "0 2 * * *"}
+	scheduled := time.Date(2018, time.March, 5, 2, 0, 0, 0, time.UTC)
+	assert.Equal([]time.Time{scheduled}, nightly.DueRuns(time.Time{}, scheduled.Add(30*time.Second)))
+	assert.Empty(nightly.DueRuns(time.Time{}, scheduled.Add(time.Minute)))
+	assert.Empty(nightly.DueRuns(scheduled, scheduled.Add(time.Hour)))
+
+	// runs missed since the last run are made up
+	assert.Equal([]time.Time{scheduled}, nightly.DueRuns(scheduled.Add(-24*time.Hour), scheduled.Add(5*time.Minute)))
+	hourly := PeriodicBuildDefinition{ID: "hourly", Cron: "@hourly"}
+	assert.Equal([]time.Time{scheduled.Add(time.Hour), scheduled.Add(2 * time.Hour)},
+		hourly.DueRuns(scheduled, scheduled.Add(150*time.Minute)))
+
+	// but not for longer than a day
+	assert.Len(hourly.DueRuns(scheduled.Add(-30*24*time.Hour), scheduled), 24)
+
+	invalid := PeriodicBuildDefinition{ID: "nightly", Cron: "nightly"}
+	assert.Empty(invalid.DueRuns(time.Time{}, scheduled))
+}
+
+func TestPeriodicBuildDefinitionSelectTasks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	p := &Project{
+		Identifier: "server",
+		Tasks: []ProjectTask{
+			{Name: "compile"},
+			{Name: "test", DependsOn: []TaskDependency{{Name: "compile"}}},
+			{Name: "perf"},
+		},
+		BuildVariants: []BuildVariant{
+			{
+				Name:         "linux",
+				Tasks:        []BuildVariantTask{{Name: "compile"}, {Name: "test"}, {Name: "perf"}},
+				DisplayTasks: []DisplayTask{{Name: "all", ExecutionTasks: []string{"test"}}},
+			},
+			{Name: "windows", Tasks: []BuildVariantTask{{Name: "compile"}, {Name: "test"}}},
+		},
+	}
+
+	definition := PeriodicBuildDefinition{ID: "nightly", Cron: "@nightly"}
+	tasks, err := definition.SelectTasks(p)
+	require.NoError(err)
+	assert.Len(tasks.ExecTasks, 5)
+
+	// dependencies of the selected tasks are included
+	definition.BuildVariants = []string{"windows"}
+	definition.Tasks = []string{"test"}
+	tasks, err = definition.SelectTasks(p)
+	require.NoError(err)
+	assert.Len(tasks.ExecTasks, 2)
+	assert.Contains(tasks.ExecTasks, TVPair{Variant: "windows", TaskName: "compile"})
+	assert.Contains(tasks.ExecTasks, TVPair{Variant: "windows", TaskName: "test"})
+	assert.Empty(tasks.DisplayTasks)
+
+	// selecting a display task runs its execution tasks
+	definition.BuildVariants = nil
+	definition.Tasks = []string{"all"}
+	tasks, err = definition.SelectTasks(p)
+	require.NoError(err)
+	assert.Equal(TVPairSet{{Variant: "linux", TaskName: "all"}}, tasks.DisplayTasks)
+	assert.Contains(tasks.ExecTasks, TVPair{Variant: "linux", TaskName: "test"})
+	assert.NotContains(tasks.ExecTasks, TVPair{Variant: "linux", TaskName: "perf"})
+
+	definition.Tasks = []string{"lint"}
+	_, err = definition.SelectTasks(p)
+	assert.Error(err)
+}
+
+func TestPeriodicVersionId(t *testing.T) {
+	assert := assert.New(t)
+
+	scheduled := time.Date(2018, time.March, 5, 2, 0, 0, 0, time.FixedZone("EST", -5*60*60))
+	assert.Equal("mongo_tools_periodic_nightly_2018_03_05_07_00", PeriodicVersionId("mongo-tools", "nightly", scheduled))
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"net/url"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/mongodb/anser/bsonutil"
@@ -37,6 +38,27 @@ type ProjectRef struct {
 	// the set of alert deliveries to be processed for that trigger.
 	Alerts map[string][]AlertConfig `bson:"alert_settings" json:"alert_config,omitempty"`
 
+	// Triggers create versions of this project when versions, builds or
+	// tasks of other projects finish.
+	Triggers []TriggerDefinition `bson:"triggers,omitempty" json:"triggers,omitempty"`
+
+	// PeriodicBuilds create versions of this project on a schedule, even
+	// when there are no new commits.
+	PeriodicBuilds []PeriodicBuildDefinition `bson:"periodic_builds,omitempty" json:"periodic_builds,omitempty"`
+
+	// PeriodicBuildsLastRun is the last time each of the periodic builds,
+	// by ID, was scheduled to run and created its version.
+	PeriodicBuildsLastRun map[string]time.Time `bson:"periodic_builds_last_run,omitempty" json:"periodic_builds_last_run,omitempty"`
+
+	// GitTagVersions create versions of this project when matching tags are
+	// pushed to its repository.
+	GitTagVersions []GitTagDefinition `bson:"git_tag_versions,omitempty" json:"git_tag_versions,omitempty"`
+
+	// CommentCommandUsers are GitHub users who can control pull request
+	// patches with comments, in addition to users with write access to the
+	// repository.
+	CommentCommandUsers []string `bson:"comment_command_users,omitempty" json:"comment_command_users,omitempty"`
+
 	// RepoDetails contain the details of the status of the consistency
 	// between what is in GitHub and what is in Evergreen
 	RepotrackerError *RepositoryErrorDetails `bson:"repotracker_error" json:"repotracker_error"`
@@ -72,22 +94,27 @@ type EmailAlertData struct {
 
 var (
 	// bson fields for the ProjectRef struct
-	ProjectRefOwnerKey              = bsonutil.MustHaveTag(ProjectRef{}, "Owner")
-	ProjectRefRepoKey               = bsonutil.MustHaveTag(ProjectRef{}, "Repo")
-	ProjectRefBranchKey             = bsonutil.MustHaveTag(ProjectRef{}, "Branch")
-	ProjectRefRepoKindKey           = bsonutil.MustHaveTag(ProjectRef{}, "RepoKind")
-	ProjectRefEnabledKey            = bsonutil.MustHaveTag(ProjectRef{}, "Enabled")
-	ProjectRefPrivateKey            = bsonutil.MustHaveTag(ProjectRef{}, "Private")
-	ProjectRefBatchTimeKey          = bsonutil.MustHaveTag(ProjectRef{}, "BatchTime")
-	ProjectRefIdentifierKey         = bsonutil.MustHaveTag(ProjectRef{}, "Identifier")
-	ProjectRefDisplayNameKey        = bsonutil.MustHaveTag(ProjectRef{}, "DisplayName")
-	ProjectRefDeactivatePreviousKey = bsonutil.MustHaveTag(ProjectRef{}, "DeactivatePrevious")
-	ProjectRefRemotePathKey         = bsonutil.MustHaveTag(ProjectRef{}, "RemotePath")
-	ProjectRefTrackedKey            = bsonutil.MustHaveTag(ProjectRef{}, "Tracked")
-	ProjectRefLocalConfig           = bsonutil.MustHaveTag(ProjectRef{}, "LocalConfig")
-	ProjectRefAlertsKey             = bsonutil.MustHaveTag(ProjectRef{}, "Alerts")
-	ProjectRefRepotrackerError      = bsonutil.MustHaveTag(ProjectRef{}, "RepotrackerError")
-	ProjectRefAdminsKey             = bsonutil.MustHaveTag(ProjectRef{}, "Admins")
+	ProjectRefOwnerKey               = bsonutil.MustHaveTag(ProjectRef{}, "Owner")
+	ProjectRefRepoKey                = bsonutil.MustHaveTag(ProjectRef{}, "Repo")
+	ProjectRefBranchKey              = bsonutil.MustHaveTag(ProjectRef{}, "Branch")
+	ProjectRefRepoKindKey            = bsonutil.MustHaveTag(ProjectRef{}, "RepoKind")
+	ProjectRefEnabledKey             = bsonutil.MustHaveTag(ProjectRef{}, "Enabled")
+	ProjectRefPrivateKey             = bsonutil.MustHaveTag(ProjectRef{}, "Private")
+	ProjectRefBatchTimeKey           = bsonutil.MustHaveTag(ProjectRef{}, "BatchTime")
+	ProjectRefIdentifierKey          = bsonutil.MustHaveTag(ProjectRef{}, "Identifier")
+	ProjectRefDisplayNameKey         = bsonutil.MustHaveTag(ProjectRef{}, "DisplayName")
+	ProjectRefDeactivatePreviousKey  = bsonutil.MustHaveTag(ProjectRef{}, "DeactivatePrevious")
+	ProjectRefRemotePathKey          = bsonutil.MustHaveTag(ProjectRef{}, "RemotePath")
+	ProjectRefTrackedKey             = bsonutil.MustHaveTag(ProjectRef{}, "Tracked")
+	ProjectRefLocalConfig            = bsonutil.MustHaveTag(ProjectRef{}, "LocalConfig")
+	ProjectRefAlertsKey              = bsonutil.MustHaveTag(ProjectRef{}, "Alerts")
+	ProjectRefRepotrackerError       = bsonutil.MustHaveTag(ProjectRef{}, "RepotrackerError")
+	ProjectRefAdminsKey              = bsonutil.MustHaveTag(ProjectRef{}, "Admins")
+	ProjectRefTriggersKey            = bsonutil.MustHaveTag(ProjectRef{}, "Triggers")
+	ProjectRefPeriodicBuildsKey      = bsonutil.MustHaveTag(ProjectRef{}, "PeriodicBuilds")
+	ProjectRefPeriodicBuildsLastRun  = bsonutil.MustHaveTag(ProjectRef{}, "PeriodicBuildsLastRun")
+	ProjectRefGitTagVersionsKey      = bsonutil.MustHaveTag(ProjectRef{}, "GitTagVersions")
+	ProjectRefCommentCommandUsersKey = bsonutil.MustHaveTag(ProjectRef{}, "CommentCommandUsers")
 )
 
 const (
@@ -237,28 +264,52 @@ func (projectRef *ProjectRef) Upsert() error {
 		},
 		bson.M{
 			"$set": bson.M{
-				ProjectRefRepoKindKey:           projectRef.RepoKind,
-				ProjectRefEnabledKey:            projectRef.Enabled,
-				ProjectRefPrivateKey:            projectRef.Private,
-				ProjectRefBatchTimeKey:          projectRef.BatchTime,
-				ProjectRefOwnerKey:              projectRef.Owner,
-				ProjectRefRepoKey:               projectRef.Repo,
-				ProjectRefBranchKey:             projectRef.Branch,
-				ProjectRefDisplayNameKey:        projectRef.DisplayName,
-				ProjectRefDeactivatePreviousKey: projectRef.DeactivatePrevious,
-				ProjectRefTrackedKey:            projectRef.Tracked,
-				ProjectRefRemotePathKey:         projectRef.RemotePath,
-				ProjectRefTrackedKey:            projectRef.Tracked,
-				ProjectRefLocalConfig:           projectRef.LocalConfig,
-				ProjectRefAlertsKey:             projectRef.Alerts,
-				ProjectRefRepotrackerError:      projectRef.RepotrackerError,
-				ProjectRefAdminsKey:             projectRef.Admins,
+				ProjectRefRepoKindKey:            projectRef.RepoKind,
+				ProjectRefEnabledKey:             projectRef.Enabled,
+				ProjectRefPrivateKey:             projectRef.Private,
+				ProjectRefBatchTimeKey:           projectRef.BatchTime,
+				ProjectRefOwnerKey:               projectRef.Owner,
+				ProjectRefRepoKey:                projectRef.Repo,
+				ProjectRefBranchKey:              projectRef.Branch,
+				ProjectRefDisplayNameKey:         projectRef.DisplayName,
+				ProjectRefDeactivatePreviousKey:  projectRef.DeactivatePrevious,
+				ProjectRefTrackedKey:             projectRef.Tracked,
+				ProjectRefRemotePathKey:          projectRef.RemotePath,
+				ProjectRefTrackedKey:             projectRef.Tracked,
+				ProjectRefLocalConfig:            projectRef.LocalConfig,
+				ProjectRefAlertsKey:              projectRef.Alerts,
+				ProjectRefRepotrackerError:       projectRef.RepotrackerError,
+				ProjectRefAdminsKey:              projectRef.Admins,
+				ProjectRefTriggersKey:            projectRef.Triggers,
+				ProjectRefPeriodicBuildsKey:      projectRef.PeriodicBuilds,
+				ProjectRefGitTagVersionsKey:      projectRef.GitTagVersions,
+				ProjectRefCommentCommandUsersKey: projectRef.CommentCommandUsers,
 			},
 		},
 	)
 	return err
 }
 
+// SetPeriodicBuildLastRun records the last time that one of the project's
+// periodic builds was scheduled to run and created its version.
+func (projectRef *ProjectRef) SetPeriodicBuildLastRun(id string, scheduled time.Time) error {
+	err := db.Update(
+		ProjectRefCollection,
+		bson.M{ProjectRefIdentifierKey: projectRef.Identifier},
+		bson.M{"$set": bson.M{
+			fmt.Sprintf("%s.%s", ProjectRefPeriodicBuildsLastRun, id): scheduled,
+		}},
+	)
+	if err != nil {
+		return errors.Wrapf(err, "error setting the last run of periodic build '%s'", id)
+	}
+	if projectRef.PeriodicBuildsLastRun == nil {
+		projectRef.PeriodicBuildsLastRun = map[string]time.Time{}
+	}
+	projectRef.PeriodicBuildsLastRun[id] = scheduled
+	return nil
+}
+
 // ProjectRef returns a string representation of a ProjectRef
 func (projectRef *ProjectRef) String() string {
 	return projectRef.Identifier
@@ -0,0 +1,297 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// testShardingHistoryLimit is the number of recent mainline runs of a
+// sharded task, or of its shards, whose test results are used to split its
+// tests.
+const testShardingHistoryLimit = 100
+
+// TestShardingConfig splits a task's tests across execution tasks, which
+// are grouped under a display task with the task's name. Tests are only
+// known from the task's stored test results, so a task without any runs
+// isn't split.
+type TestShardingConfig struct {
+	// Shards is the number of execution tasks, or with TargetSecs, the
+	// most execution tasks.
+	Shards int `yaml:"shards,omitempty" bson:"shards,omitempty"`
+
+	// TargetSecs is the wall-clock time that each execution task should
+	// take; the number of execution tasks adapts to meet it.
+	TargetSecs int `yaml:"target_secs,omitempty" bson:"target_secs,omitempty"`
+}
+
+// TestShard is the part of a sharded task's tests that one of its
+// execution tasks runs. The agent passes the tests to the task's commands
+// in the ${shard_tests} expansion and in the ${shard_tests_file} file.
+// Tests added since the shards were split are in none of the lists, so the
+// last shard runs every test except the other shards' tests, which it gets
+// in the ${shard_exclude_tests} expansion and ${shard_exclude_tests_file}
+// file.
+type TestShard struct {
+	Task         string   `yaml:"task" bson:"task"`
+	Index        int      `yaml:"index" bson:"index"`
+	Count        int      `yaml:"count" bson:"count"`
+	Tests        []string `yaml:"tests,omitempty" bson:"tests,omitempty"`
+	ExcludeTests []string `yaml:"exclude_tests,omitempty" bson:"exclude_tests,omitempty"`
+}
+
+// RunsUnlistedTests returns true if the shard runs the tests that are in no
+// shard, excluding the tests of the other shards.
+func (s *TestShard) RunsUnlistedTests() bool {
+	return s.Index == s.Count-1
+}
+
+// ShardTaskName returns the name of the execution task for a shard of
+// a task.
+func ShardTaskName(name string, index int) string {
+	return fmt.Sprintf("%s_shard_%d", name, index)
+}
+
+// ExpandTestSharding replaces each of the project's tasks that shards its
+// tests with an execution task per shard, split by the durations of the
+// task's tests in its recent mainline runs.
+func (p *Project) ExpandTestSharding() error {
+	for _, pt := range p.Tasks {
+		if pt.TestSharding == nil {
+			continue
+		}
+		durations, err := testDurations(p.Identifier, pt.Name)
+		if err != nil {
+			return errors.Wrapf(err, "error finding test durations for task '%s'", pt.Name)
+		}
+		shards := shardTests(durations, pt.TestSharding.shardCount(durations))
+		if len(shards) < 2 {
+			continue
+		}
+		p.shardTask(pt.Name, shards)
+	}
+	return nil
+}
+
+// testDurations returns the average durations in seconds of the tests in
+// the recent mainline runs of a task or its shards.
+func testDurations(projectID, name string) (map[string]float64, error) {
+	tasks, err := task.Find(db.Query(bson.M{
+		task.ProjectKey:     projectID,
+		task.RequesterKey:   evergreen.RepotrackerVersionRequester,
+		task.DisplayNameKey: bson.M{"$regex": fmt.Sprintf("^%s(_shard_[0-9]+)?$", regexp.QuoteMeta(name))},
+		task.StatusKey:      bson.M{"$in": evergreen.CompletedStatuses},
+	}).WithFields(task.IdKey).Sort([]string{"-" + task.FinishTimeKey}).Limit(testShardingHistoryLimit))
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding tasks")
+	}
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+	ids := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.Id)
+	}
+	results, err := testresult.Find(testresult.ByTaskIDs(ids))
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding test results")
+	}
+
+	totals := map[string]float64{}
+	counts := map[string]int{}
+	for _, result := range results {
+		if result.TestFile == "" || result.EndTime < result.StartTime {
+			continue
+		}
+		totals[result.TestFile] += result.EndTime - result.StartTime
+		counts[result.TestFile]++
+	}
+	for test := range totals {
+		totals[test] /= float64(counts[test])
+	}
+	return totals, nil
+}
+
+// shardCount returns the number of execution tasks to split the tests
+// into.
+func (c *TestShardingConfig) shardCount(durations map[string]float64) int {
+	n := c.Shards
+	if c.TargetSecs > 0 {
+		total := 0.0
+		for _, d := range durations {
+			total += d
+		}
+		n = int(math.Ceil(total / float64(c.TargetSecs)))
+		if c.Shards > 0 && n > c.Shards {
+			n = c.Shards
+		}
+	}
+	if n > len(durations) {
+		n = len(durations)
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// shardTests splits the tests into n lists of about equal total duration,
+// assigning the longest tests first to the list with the least total.
+func shardTests(durations map[string]float64, n int) [][]string {
+	if len(durations) == 0 || n < 1 {
+		return nil
+	}
+
+	tests := make([]string, 0, len(durations))
+	for test := range durations {
+		tests = append(tests, test)
+	}
+	sort.Slice(tests, func(i, j int) bool {
+		if durations[tests[i]] != durations[tests[j]] {
+			return durations[tests[i]] > durations[tests[j]]
+		}
+		return tests[i] < tests[j]
+	})
+
+	shards := make([][]string, n)
+	totals := make([]float64, n)
+	for _, test := range tests {
+		min := 0
+		for i := range totals {
+			if totals[i] < totals[min] {
+				min = i
+			}
+		}
+		shards[min] = append(shards[min], test)
+		totals[min] += durations[test]
+	}
+	for _, shard := range shards {
+		sort.Strings(shard)
+	}
+	return shards
+}
+
+// shardTask replaces the task with an execution task per shard, in the
+// project and in each variant. The last shard runs every test that isn't
+// assigned to another shard. The shards are grouped under a display task
+// with the task's name, or under the display task that had the task.
+// Dependencies on the task become dependencies on all of its shards.
+func (p *Project) shardTask(name string, shards [][]string) {
+	names := make([]string, 0, len(shards))
+	for i := range shards {
+		names = append(names, ShardTaskName(name, i))
+	}
+
+	tasks := []ProjectTask{}
+	for _, pt := range p.Tasks {
+		pt.DependsOn = shardDependencies(pt.DependsOn, name, names)
+		pt.Requires = shardRequirements(pt.Requires, name, names)
+		if pt.Name != name {
+			tasks = append(tasks, pt)
+			continue
+		}
+		exclude := []string{}
+		for i, tests := range shards {
+			shard := pt
+			shard.Name = names[i]
+			shard.TestSharding = nil
+			shard.Shard = &TestShard{Task: name, Index: i, Count: len(shards), Tests: tests}
+			if shard.Shard.RunsUnlistedTests() {
+				sort.Strings(exclude)
+				shard.Shard.ExcludeTests = exclude
+			} else {
+				exclude = append(exclude, tests...)
+			}
+			tasks = append(tasks, shard)
+		}
+	}
+	p.Tasks = tasks
+
+	for i, bv := range p.BuildVariants {
+		bvts := []BuildVariantTask{}
+		found := false
+		for _, bvt := range bv.Tasks {
+			bvt.DependsOn = shardDependencies(bvt.DependsOn, name, names)
+			bvt.Requires = shardRequirements(bvt.Requires, name, names)
+			if bvt.Name != name {
+				bvts = append(bvts, bvt)
+				continue
+			}
+			found = true
+			for _, shardName := range names {
+				shard := bvt
+				shard.Name = shardName
+				bvts = append(bvts, shard)
+			}
+		}
+		p.BuildVariants[i].Tasks = bvts
+		if !found {
+			continue
+		}
+
+		grouped := false
+		displayTasks := []DisplayTask{}
+		for _, dt := range bv.DisplayTasks {
+			execTasks := []string{}
+			for _, et := range dt.ExecutionTasks {
+				if et == name {
+					execTasks = append(execTasks, names...)
+					grouped = true
+					continue
+				}
+				execTasks = append(execTasks, et)
+			}
+			displayTasks = append(displayTasks, DisplayTask{Name: dt.Name, ExecutionTasks: execTasks})
+		}
+		if !grouped {
+			displayTasks = append(displayTasks, DisplayTask{Name: name, ExecutionTasks: names})
+		}
+		p.BuildVariants[i].DisplayTasks = displayTasks
+	}
+}
+
+func shardDependencies(deps []TaskDependency, name string, names []string) []TaskDependency {
+	if len(deps) == 0 {
+		return deps
+	}
+	out := []TaskDependency{}
+	for _, dep := range deps {
+		if dep.Name != name {
+			out = append(out, dep)
+			continue
+		}
+		for _, shardName := range names {
+			shard := dep
+			shard.Name = shardName
+			out = append(out, shard)
+		}
+	}
+	return out
+}
+
+func shardRequirements(reqs []TaskRequirement, name string, names []string) []TaskRequirement {
+	if len(reqs) == 0 {
+		return reqs
+	}
+	out := []TaskRequirement{}
+	for _, req := range reqs {
+		if req.Name != name {
+			out = append(out, req)
+			continue
+		}
+		for _, shardName := range names {
+			shard := req
+			shard.Name = shardName
+			out = append(out, shard)
+		}
+	}
+	return out
+}
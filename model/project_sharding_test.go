@@ -0,0 +1,173 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestShardTests(t *testing.T) {
+	assert := assert.New(t)
+
+	durations := map[string]float64{
+		"a": 10,
+		"b": 8,
+		"c": 6,
+		"d": 4,
+		"e": 2,
+	}
+	assert.Equal([][]string{{"a"}, {"b", "e"}, {"c", "d"}}, shardTests(durations, 3))
+	assert.Equal([][]string{{"a", "b", "c", "d", "e"}}, shardTests(durations, 1))
+	assert.Nil(shardTests(nil, 3))
+
+	assert.Equal(4, (&TestShardingConfig{Shards: 4}).shardCount(durations))
+	assert.Equal(5, (&TestShardingConfig{Shards: 8}).shardCount(durations))
+	assert.Equal(3, (&TestShardingConfig{TargetSecs: 12}).shardCount(durations))
+	assert.Equal(2, (&TestShardingConfig{Shards: 2, TargetSecs: 12}).shardCount(durations))
+	assert.Equal(1, (&TestShardingConfig{TargetSecs: 60}).shardCount(durations))
+	assert.Equal(1, (&TestShardingConfig{Shards: 4}).shardCount(nil))
+}
+
+func TestShardTask(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	yml := `
+tasks:
+- name: compile
+- name: unit
+  depends_on:
+  - name: compile
+  test_sharding:
+    shards: 2
+- name: integration
+  test_sharding:
+    target_secs: 600
+- name: report
+  depends_on:
+  - name: unit
+buildvariants:
+- name: linux
+  tasks:
+  - name: compile
+  - name: unit
+  - name: integration
+  - name: report
+- name: windows
+  tasks:
+  - name: compile
+  - name: integration
+  display_tasks:
+  - name: tests
+    execution_tasks: ["integration"]
+`
+	p := &Project{}
+	require.NoError(LoadProjectInto([]byte(yml), "project", p))
+	p.shardTask("unit", [][]string{{"a", "c"}, {"b"}})
+	p.shardTask("integration", [][]string{{"x"}, {"y"}, {"z"}})
+
+	shard := p.FindProjectTask("unit_shard_1")
+	require.NotNil(shard)
+	assert.Nil(shard.TestSharding)
+	assert.Equal(&TestShard{Task: "unit", Index: 1, Count: 2, Tests: []string{"b"}, ExcludeTests: []string{"a", "c"}}, shard.Shard)
+	shard = p.FindProjectTask("unit_shard_0")
+	require.NotNil(shard)
+	assert.Equal(&TestShard{Task: "unit", Index: 0, Count: 2, Tests: []string{"a", "c"}}, shard.Shard)
+	assert.False(shard.Shard.RunsUnlistedTests())
+	assert.Equal([]TaskDependency{{Name: "compile"}}, shard.DependsOn)
+	assert.Nil(p.FindProjectTask("unit"))
+	report := p.FindProjectTask("report")
+	require.NotNil(report)
+	assert.Equal([]string{"unit_shard_0", "unit_shard_1"}, []string{report.DependsOn[0].Name, report.DependsOn[1].Name})
+
+	linux := p.FindBuildVariant("linux")
+	require.NotNil(linux)
+	names := []string{}
+	for _, bvt := range linux.Tasks {
+		names = append(names, bvt.Name)
+	}
+	assert.Equal([]string{"compile", "unit_shard_0", "unit_shard_1",
+		"integration_shard_0", "integration_shard_1", "integration_shard_2", "report"}, names)
+	assert.Equal([]DisplayTask{
+		{Name: "unit", ExecutionTasks: []string{"unit_shard_0", "unit_shard_1"}},
+		{Name: "integration", ExecutionTasks: []string{"integration_shard_0", "integration_shard_1", "integration_shard_2"}},
+	}, linux.DisplayTasks)
+
+	windows := p.FindBuildVariant("windows")
+	require.NotNil(windows)
+	assert.Equal([]DisplayTask{
+		{Name: "tests", ExecutionTasks: []string{"integration_shard_0", "integration_shard_1", "integration_shard_2"}},
+	}, windows.DisplayTasks)
+
+	// the shards survive storing the project's config in a version
+	out, err := yaml.Marshal(p)
+	require.NoError(err)
+	loaded := &Project{}
+	require.NoError(LoadProjectInto(out, "project", loaded))
+	shard = loaded.FindProjectTask("integration_shard_2")
+	require.NotNil(shard)
+	assert.Equal(&TestShard{Task: "integration", Index: 2, Count: 3, Tests: []string{"z"}, ExcludeTests: []string{"x", "y"}}, shard.Shard)
+	assert.True(shard.Shard.RunsUnlistedTests())
+}
+
+func TestExpandTestSharding(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(task.Collection, testresult.Collection))
+	defer func() {
+		assert.NoError(db.ClearCollections(task.Collection, testresult.Collection))
+	}()
+
+	for _, tsk := range []task.Task{
+		{Id: "t1", DisplayName: "unit", Project: "project", Requester: evergreen.RepotrackerVersionRequester,
+			Status: evergreen.TaskSucceeded, FinishTime: time.Now().Add(-time.Hour)},
+		{Id: "t2", DisplayName: "unit_shard_1", Project: "project", Requester: evergreen.RepotrackerVersionRequester,
+			Status: evergreen.TaskFailed, FinishTime: time.Now()},
+		{Id: "t3", DisplayName: "unit", Project: "project", Requester: evergreen.PatchVersionRequester,
+			Status: evergreen.TaskSucceeded, FinishTime: time.Now()},
+		{Id: "t4", DisplayName: "unit_tests", Project: "project", Requester: evergreen.RepotrackerVersionRequester,
+			Status: evergreen.TaskSucceeded, FinishTime: time.Now()},
+	} {
+		require.NoError(tsk.Insert())
+	}
+	for _, result := range []testresult.TestResult{
+		{TaskID: "t1", TestFile: "a", StartTime: 0, EndTime: 10},
+		{TaskID: "t1", TestFile: "b", StartTime: 0, EndTime: 4},
+		{TaskID: "t1", TestFile: "c", StartTime: 0, EndTime: 4},
+		{TaskID: "t2", TestFile: "a", StartTime: 0, EndTime: 6},
+		{TaskID: "t3", TestFile: "patch", StartTime: 0, EndTime: 100},
+		{TaskID: "t4", TestFile: "other", StartTime: 0, EndTime: 100},
+	} {
+		require.NoError(result.Insert())
+	}
+
+	durations, err := testDurations("project", "unit")
+	require.NoError(err)
+	assert.Equal(map[string]float64{"a": 8, "b": 4, "c": 4}, durations)
+
+	p := &Project{
+		Identifier: "project",
+		Tasks: []ProjectTask{
+			{Name: "unit", TestSharding: &TestShardingConfig{Shards: 2}},
+			{Name: "new", TestSharding: &TestShardingConfig{Shards: 2}},
+		},
+		BuildVariants: []BuildVariant{
+			{Name: "linux", Tasks: []BuildVariantTask{{Name: "unit"}, {Name: "new"}}},
+		},
+	}
+	require.NoError(p.ExpandTestSharding())
+	require.Len(p.Tasks, 3)
+	assert.Equal([]string{"a"}, p.Tasks[0].Shard.Tests)
+	assert.Equal([]string{"b", "c"}, p.Tasks[1].Shard.Tests)
+	assert.Equal([]string{"a"}, p.Tasks[1].Shard.ExcludeTests)
+	// tasks without any history run all of their tests
+	assert.Equal("new", p.Tasks[2].Name)
+	assert.Nil(p.Tasks[2].Shard)
+}
@@ -0,0 +1,219 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	TriggerLevelVersion = "version"
+	TriggerLevelBuild   = "build"
+	TriggerLevelTask    = "task"
+
+	// triggerExpansionPrefix prefixes the expansions that pass information
+	// about the upstream version to the tasks of a triggered version.
+	triggerExpansionPrefix = "trigger_"
+)
+
+// TriggerDefinition creates a version of the project it is defined on when
+// a version, build or task of an upstream project finishes with the given
+// status. The new version uses the project's config at the tip of its
+// branch, and runs every task unless it is limited to a build variant or an
+// alias.
+type TriggerDefinition struct {
+	// Project is the identifier of the upstream project.
+	Project string `bson:"project" json:"project"`
+	// Level is one of "version", "build" or "task".
+	Level string `bson:"level" json:"level"`
+	// Status is the status that the upstream version, build or task must
+	// finish with. Any finished status matches if it is empty.
+	Status string `bson:"status,omitempty" json:"status,omitempty"`
+	// BuildVariantRegex and TaskRegex limit the upstream builds and tasks
+	// that cause the trigger.
+	BuildVariantRegex string `bson:"variant_regex,omitempty" json:"variant_regex,omitempty"`
+	TaskRegex         string `bson:"task_regex,omitempty" json:"task_regex,omitempty"`
+
+	// BuildVariant and Alias limit the tasks created in the new version to
+	// a build variant, or to the tasks matched by a patch alias.
+	BuildVariant string `bson:"build_variant,omitempty" json:"build_variant,omitempty"`
+	Alias        string `bson:"alias,omitempty" json:"alias,omitempty"`
+}
+
+var (
+	triggerDefinitionProjectKey = bsonutil.MustHaveTag(TriggerDefinition{}, "Project")
+
+	invalidExpansionChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+)
+
+// Validate returns an error if the trigger is not valid for the given
+// downstream project.
+func (t *TriggerDefinition) Validate(downstreamProject string) error {
+	catcher := grip.NewSimpleCatcher()
+	if strings.TrimSpace(t.Project) == "" {
+		catcher.Add(errors.New("upstream project can't be empty"))
+	}
+	if t.Project == downstreamProject {
+		catcher.Add(errors.New("a project can't trigger itself"))
+	}
+	if !util.StringSliceContains([]string{TriggerLevelVersion, TriggerLevelBuild, TriggerLevelTask}, t.Level) {
+		catcher.Add(errors.Errorf("invalid level '%s'", t.Level))
+	}
+	if t.Status != "" && !util.StringSliceContains(evergreen.CompletedStatuses, t.Status) {
+		catcher.Add(errors.Errorf("invalid status '%s'", t.Status))
+	}
+	if _, err := regexp.Compile(t.BuildVariantRegex); err != nil {
+		catcher.Add(errors.Wrap(err, "invalid variant regex"))
+	}
+	if _, err := regexp.Compile(t.TaskRegex); err != nil {
+		catcher.Add(errors.Wrap(err, "invalid task regex"))
+	}
+	if t.TaskRegex != "" && t.Level != TriggerLevelTask {
+		catcher.Add(errors.New("a task regex can only be used by task level triggers"))
+	}
+	if t.BuildVariantRegex != "" && t.Level == TriggerLevelVersion {
+		catcher.Add(errors.New("a variant regex can't be used by version level triggers"))
+	}
+	if t.BuildVariant != "" && t.Alias != "" {
+		catcher.Add(errors.New("a trigger can't be limited to both a build variant and an alias"))
+	}
+	return catcher.Resolve()
+}
+
+// Matches returns true if an upstream version, build or task that finished
+// with the given status, on the given build variant and with the given task
+// name, causes the trigger.
+func (t *TriggerDefinition) Matches(level, status, buildVariant, taskName string) bool {
+	if t.Level != level {
+		return false
+	}
+	if !util.StringSliceContains(evergreen.CompletedStatuses, status) {
+		return false
+	}
+	if t.Status != "" && t.Status != status {
+		return false
+	}
+	if t.BuildVariantRegex != "" {
+		matched, err := regexp.MatchString(t.BuildVariantRegex, buildVariant)
+		if err != nil || !matched {
+			return false
+		}
+	}
+	if t.TaskRegex != "" {
+		matched, err := regexp.MatchString(t.TaskRegex, taskName)
+		if err != nil || !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// DownstreamTasks returns the tasks of the downstream project that a version
+// created by the trigger runs.
+func (t *TriggerDefinition) DownstreamTasks(p *Project) (TaskVariantPairs, error) {
+	variants := []string{}
+	if t.BuildVariant != "" {
+		variants = append(variants, t.BuildVariant)
+	}
+	tasks, err := selectBranchTipTasks(p, t.Alias, variants, nil)
+	return tasks, errors.Wrap(err, "error selecting the tasks of the trigger")
+}
+
+// selectBranchTipTasks returns the tasks of a version that is created outside
+// of the repotracker, selected either by a patch alias, or by build variant
+// and task names. All of the non-disabled build variants and all of their
+// tasks are selected if no names are given.
+func selectBranchTipTasks(p *Project, alias string, variants, taskNames []string) (TaskVariantPairs, error) {
+	tasks := TaskVariantPairs{}
+	if alias != "" {
+		pairs, err := p.BuildProjectTVPairsWithAlias(alias)
+		if err != nil {
+			return tasks, errors.Wrapf(err, "error finding tasks for alias '%s'", alias)
+		}
+		tasks.ExecTasks = pairs
+	} else {
+		for _, bv := range p.BuildVariants {
+			if bv.Disabled || (len(variants) > 0 && !util.StringSliceContains(variants, bv.Name)) {
+				continue
+			}
+			for _, bvt := range bv.Tasks {
+				if len(taskNames) > 0 && !util.StringSliceContains(taskNames, bvt.Name) {
+					continue
+				}
+				tasks.ExecTasks = append(tasks.ExecTasks, TVPair{Variant: bv.Name, TaskName: bvt.Name})
+			}
+			for _, dt := range bv.DisplayTasks {
+				if len(taskNames) > 0 && !util.StringSliceContains(taskNames, dt.Name) {
+					continue
+				}
+				tasks.DisplayTasks = append(tasks.DisplayTasks, TVPair{Variant: bv.Name, TaskName: dt.Name})
+				// a selected display task runs all of its execution tasks
+				if len(taskNames) > 0 {
+					for _, et := range dt.ExecutionTasks {
+						tasks.ExecTasks = append(tasks.ExecTasks, TVPair{Variant: bv.Name, TaskName: et})
+					}
+				}
+			}
+		}
+	}
+	if len(tasks.ExecTasks) == 0 {
+		return tasks, errors.New("no tasks are selected")
+	}
+	tasks.ExecTasks = IncludePatchDependencies(p, tasks.ExecTasks)
+	return tasks, nil
+}
+
+// DownstreamVersionId returns the id of the version that the trigger at the
+// given index of a project's triggers creates for an upstream version, build
+// or task. Ids are deterministic so that a trigger creates a single version
+// for each upstream version, build or task.
+func DownstreamVersionId(downstreamProject string, index int, upstreamId string) string {
+	return util.CleanName(fmt.Sprintf("%s_trigger_%d_%s", downstreamProject, index, upstreamId))
+}
+
+// FindDownstreamProjectRefs returns the enabled projects that have triggers
+// on the given upstream project.
+func FindDownstreamProjectRefs(upstreamProject string) ([]ProjectRef, error) {
+	projectRefs := []ProjectRef{}
+	err := db.FindAllQ(ProjectRefCollection, db.Query(bson.M{
+		ProjectRefEnabledKey: true,
+		bsonutil.GetDottedKeyName(ProjectRefTriggersKey, triggerDefinitionProjectKey): upstreamProject,
+	}), &projectRefs)
+	return projectRefs, errors.Wrapf(err, "error finding projects triggered by %s", upstreamProject)
+}
+
+// addTriggerExpansions passes the revision and artifacts of the upstream
+// version that triggered a version to its tasks.
+func addTriggerExpansions(expansions *util.Expansions, info *version.TriggerInfo) {
+	if info == nil {
+		return
+	}
+	expansions.Put(triggerExpansionPrefix+"upstream_project", info.Project)
+	expansions.Put(triggerExpansionPrefix+"upstream_version", info.Version)
+	expansions.Put(triggerExpansionPrefix+"upstream_revision", info.Revision)
+	expansions.Put(triggerExpansionPrefix+"upstream_status", info.Status)
+	if info.Build != "" {
+		expansions.Put(triggerExpansionPrefix+"upstream_build", info.Build)
+	}
+	if info.Task != "" {
+		expansions.Put(triggerExpansionPrefix+"upstream_task", info.Task)
+	}
+	for _, a := range info.Artifacts {
+		expansions.Put(triggerArtifactExpansion(a.Name), a.Link)
+	}
+}
+
+// triggerArtifactExpansion returns the name of the expansion that holds the
+// link to an upstream artifact.
+func triggerArtifactExpansion(name string) string {
+	return triggerExpansionPrefix + "artifact_" + invalidExpansionChars.ReplaceAllString(name, "_")
+}
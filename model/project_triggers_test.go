@@ -0,0 +1,132 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTriggerDefinitionValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	trigger := TriggerDefinition{Project: "server", Level: TriggerLevelTask, Status: evergreen.TaskSucceeded,
+		BuildVariantRegex: "^linux", TaskRegex: "compile"}
+	assert.NoError(trigger.Validate("driver"))
+	assert.Error(trigger.Validate("server"))
+
+	for _, invalid := range []TriggerDefinition{
+		{Level: TriggerLevelVersion},
+		{Project: "server", Level: "commit"},
+		{Project: "server", Level: TriggerLevelVersion, Status: evergreen.TaskStarted},
+		{Project: "server", Level: TriggerLevelBuild, BuildVariantRegex: "("},
+		{Project: "server", Level: TriggerLevelBuild, TaskRegex: "compile"},
+		{Project: "server", Level: TriggerLevelVersion, BuildVariantRegex: "linux"},
+		{Project: "server", Level: TriggerLevelVersion, BuildVariant: "linux", Alias: "smoke"},
+	} {
+		assert.Error(invalid.Validate("driver"), "%+v", invalid)
+	}
+}
+
+func TestTriggerDefinitionMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	trigger := TriggerDefinition{Project: "server", Level: TriggerLevelTask, BuildVariantRegex: "^linux", TaskRegex: "^compile$"}
+	assert.True(trigger.Matches(TriggerLevelTask, evergreen.TaskSucceeded, "linux-64", "compile"))
+	assert.True(trigger.Matches(TriggerLevelTask, evergreen.TaskFailed, "linux-64", "compile"))
+	assert.False(trigger.Matches(TriggerLevelTask, evergreen.TaskStarted, "linux-64", "compile"))
+	assert.False(trigger.Matches(TriggerLevelTask, evergreen.TaskSucceeded, "windows", "compile"))
+	assert.False(trigger.Matches(TriggerLevelTask, evergreen.TaskSucceeded, "linux-64", "compile_all"))
+	assert.False(trigger.Matches(TriggerLevelBuild, evergreen.BuildSucceeded, "linux-64", ""))
+
+	trigger = TriggerDefinition{Project: "server", Level: TriggerLevelVersion, Status: evergreen.VersionSucceeded}
+	assert.True(trigger.Matches(TriggerLevelVersion, evergreen.VersionSucceeded, "", ""))
+	assert.False(trigger.Matches(TriggerLevelVersion, evergreen.VersionFailed, "", ""))
+}
+
+func TestTriggerDefinitionDownstreamTasks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	p := &Project{
+		Identifier: "driver",
+		Tasks: []ProjectTask{
+			{Name: "compile"},
+			{Name: "test", DependsOn: []TaskDependency{{Name: "compile"}}},
+		},
+		BuildVariants: []BuildVariant{
+			{
+				Name:         "linux",
+				Tasks:        []BuildVariantTask{{Name: "compile"}, {Name: "test"}},
+				DisplayTasks: []DisplayTask{{Name: "all", ExecutionTasks: []string{"test"}}},
+			},
+			{Name: "windows", Tasks: []BuildVariantTask{{Name: "compile"}}},
+			{Name: "disabled", Disabled: true, Tasks: []BuildVariantTask{{Name: "compile"}}},
+		},
+	}
+
+	trigger := TriggerDefinition{Project: "server", Level: TriggerLevelVersion}
+	tasks, err := trigger.DownstreamTasks(p)
+	require.NoError(err)
+	assert.Len(tasks.ExecTasks, 3)
+	assert.Equal(TVPairSet{{Variant: "linux", TaskName: "all"}}, tasks.DisplayTasks)
+	for _, pair := range tasks.ExecTasks {
+		assert.NotEqual("disabled", pair.Variant)
+	}
+
+	trigger.BuildVariant = "windows"
+	tasks, err = trigger.DownstreamTasks(p)
+	require.NoError(err)
+	assert.Equal(TVPairSet{{Variant: "windows", TaskName: "compile"}}, tasks.ExecTasks)
+	assert.Empty(tasks.DisplayTasks)
+
+	trigger.BuildVariant = "macos"
+	_, err = trigger.DownstreamTasks(p)
+	assert.Error(err)
+}
+
+func TestAddTriggerExpansions(t *testing.T) {
+	assert := assert.New(t)
+
+	expansions := util.NewExpansions(map[string]string{})
+	addTriggerExpansions(expansions, nil)
+	assert.Empty(*expansions)
+
+	addTriggerExpansions(expansions, &version.TriggerInfo{
+		Project:  "server",
+		Level:    TriggerLevelTask,
+		Status:   evergreen.TaskSucceeded,
+		Version:  "server_abcdef",
+		Build:    "server_linux_abcdef",
+		Task:     "server_linux_compile_abcdef",
+		Revision: "abcdef",
+		Artifacts: []version.TriggerArtifact{
+			{Name: "server.tgz", Link: "https://example.com/server.tgz"},
+			{Name: "debug symbols", Link: "https://example.com/debug.tgz"},
+		},
+	})
+	assert.Equal("server", expansions.Get("trigger_upstream_project"))
+	assert.Equal("server_abcdef", expansions.Get("trigger_upstream_version"))
+	assert.Equal("abcdef", expansions.Get("trigger_upstream_revision"))
+	assert.Equal(evergreen.TaskSucceeded, expansions.Get("trigger_upstream_status"))
+	assert.Equal("server_linux_abcdef", expansions.Get("trigger_upstream_build"))
+	assert.Equal("server_linux_compile_abcdef", expansions.Get("trigger_upstream_task"))
+	assert.Equal("https://example.com/server.tgz", expansions.Get("trigger_artifact_server_tgz"))
+	assert.Equal("https://example.com/debug.tgz", expansions.Get("trigger_artifact_debug_symbols"))
+}
+
+func TestVersionIsTriggeredBy(t *testing.T) {
+	assert := assert.New(t)
+
+	v := &version.Version{Identifier: "driver"}
+	assert.True(v.IsTriggeredBy("driver"))
+	assert.False(v.IsTriggeredBy("server"))
+
+	v.TriggeredBy = &version.TriggerInfo{Project: "server", Chain: []string{"tools", "server"}}
+	assert.True(v.IsTriggeredBy("server"))
+	assert.True(v.IsTriggeredBy("tools"))
+	assert.False(v.IsTriggeredBy("docs"))
+}
@@ -0,0 +1,189 @@
+package model
+
+import (
+	"math"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/alert"
+	"github.com/evergreen-ci/evergreen/model/alertrecord"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	// StepbackModeLinear steps back one commit at a time, until the task
+	// passes. It is the default.
+	StepbackModeLinear = "linear"
+	// StepbackModeBisect activates the task halfway between the last pass and
+	// the first failure, until it isolates the commit that broke the task.
+	StepbackModeBisect = "bisect"
+)
+
+// ValidStepbackModes are the stepback modes that projects can choose.
+var ValidStepbackModes = []string{"", StepbackModeLinear, StepbackModeBisect}
+
+// doBisectStepback continues the bisection of the commits between the last
+// pass and the first failure of a task that just finished. It activates the
+// run of the task halfway between them, or, once no commits are left between
+// them, records the first failure as the culprit and alerts its author.
+func doBisectStepback(t *task.Task) error {
+	var lastPass, firstFailure *task.Task
+	var err error
+	switch t.Status {
+	case evergreen.TaskFailed:
+		firstFailure = t
+		lastPass, err = task.FindOne(task.ByBeforeRevisionWithStatusesAndRequester(t.RevisionOrderNumber,
+			task.CompletedStatuses, t.BuildVariant, t.DisplayName, t.Project, t.Requester))
+		if err != nil {
+			return errors.Wrapf(err, "error finding previous run of %s", t.Id)
+		}
+		// If the task never passed, we should not step back, because it
+		// could step back ad infinitum.
+		if lastPass == nil || lastPass.Status != evergreen.TaskSucceeded {
+			return nil
+		}
+	case evergreen.TaskSucceeded:
+		lastPass = t
+		firstFailure, err = task.FindOne(task.ByAfterRevisionWithStatusesAndRequester(t.RevisionOrderNumber,
+			task.CompletedStatuses, t.BuildVariant, t.DisplayName, t.Project, t.Requester))
+		if err != nil {
+			return errors.Wrapf(err, "error finding next run of %s", t.Id)
+		}
+		if firstFailure == nil || firstFailure.Status != evergreen.TaskFailed {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	between, err := task.Find(task.ByRevisionRange(lastPass.RevisionOrderNumber, firstFailure.RevisionOrderNumber,
+		t.BuildVariant, t.DisplayName, t.Project, t.Requester))
+	if err != nil {
+		return errors.Wrapf(err, "error finding runs of %s to bisect", t.Id)
+	}
+
+	next := bisectCandidate(between)
+	if next == nil {
+		return errors.WithStack(recordStepbackCulprit(firstFailure))
+	}
+	if next.Activated {
+		// a previous step of the bisection is still running
+		return nil
+	}
+
+	grip.Info(message.Fields{
+		"message":       "bisecting task failure",
+		"task":          t.Id,
+		"last_pass":     lastPass.Id,
+		"first_failure": firstFailure.Id,
+		"activating":    next.Id,
+		"remaining":     len(between),
+	})
+	return errors.WithStack(activateStepbackTask(next))
+}
+
+// bisectCandidate returns the run of a task to activate next among the
+// unfinished runs between the last pass and the first failure. It returns a
+// run that is already activated if there is one, and nil once there are no
+// runs left to bisect. Blacklisted runs are skipped.
+func bisectCandidate(between []task.Task) *task.Task {
+	candidates := []task.Task{}
+	for _, t := range between {
+		if t.Activated && !task.IsFinished(t) {
+			return &t
+		}
+		if t.Activated || t.Priority < 0 {
+			continue
+		}
+		candidates = append(candidates, t)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	return &candidates[len(candidates)/2]
+}
+
+// activateStepbackTask activates a task for stepback. Display tasks are
+// activated through their execution tasks.
+func activateStepbackTask(t *task.Task) error {
+	if !t.DisplayOnly {
+		return errors.WithStack(SetActiveState(t.Id, evergreen.StepbackTaskActivator, true))
+	}
+	catcher := grip.NewSimpleCatcher()
+	for _, execTask := range t.ExecutionTasks {
+		catcher.Add(SetActiveState(execTask, evergreen.StepbackTaskActivator, true))
+	}
+	return catcher.Resolve()
+}
+
+// recordStepbackCulprit records the commit of the first failure of a task on
+// it and on the failures that followed, and alerts the author of the commit.
+func recordStepbackCulprit(firstFailure *task.Task) error {
+	if firstFailure.StepbackCulprit != nil {
+		return nil
+	}
+
+	v, err := version.FindOne(version.ById(firstFailure.Version))
+	if err != nil {
+		return errors.Wrapf(err, "error finding version for %s", firstFailure.Id)
+	}
+	if v == nil {
+		return errors.Errorf("version %s of task %s not found", firstFailure.Version, firstFailure.Id)
+	}
+	culprit := task.StepbackCulprit{
+		TaskId:      firstFailure.Id,
+		Revision:    v.Revision,
+		Author:      v.Author,
+		AuthorEmail: v.AuthorEmail,
+	}
+
+	// the failures up to the next pass are caused by the same commit
+	nextPass, err := task.FindOne(task.ByAfterRevisionWithStatusesAndRequester(firstFailure.RevisionOrderNumber,
+		[]string{evergreen.TaskSucceeded}, firstFailure.BuildVariant, firstFailure.DisplayName,
+		firstFailure.Project, firstFailure.Requester))
+	if err != nil {
+		return errors.Wrapf(err, "error finding next pass of %s", firstFailure.Id)
+	}
+	before := math.MaxInt32
+	if nextPass != nil {
+		before = nextPass.RevisionOrderNumber
+	}
+	failures, err := task.Find(task.ByRevisionRange(firstFailure.RevisionOrderNumber-1, before,
+		firstFailure.BuildVariant, firstFailure.DisplayName, firstFailure.Project, firstFailure.Requester).
+		WithFields(task.IdKey, task.StatusKey))
+	if err != nil {
+		return errors.Wrapf(err, "error finding failures after %s", firstFailure.Id)
+	}
+	taskIds := []string{}
+	for _, t := range failures {
+		if t.Status == evergreen.TaskFailed {
+			taskIds = append(taskIds, t.Id)
+		}
+	}
+	if err = task.SetStepbackCulprit(taskIds, culprit); err != nil {
+		return errors.Wrap(err, "error recording stepback culprit")
+	}
+
+	grip.Info(message.Fields{
+		"message":  "bisection found culprit",
+		"task":     firstFailure.Id,
+		"revision": culprit.Revision,
+		"author":   culprit.Author,
+		"failures": taskIds,
+	})
+	return errors.Wrap(alert.EnqueueAlertRequest(&alert.AlertRequest{
+		Id:        bson.NewObjectId(),
+		Trigger:   alertrecord.StepbackCulpritId,
+		TaskId:    firstFailure.Id,
+		Execution: firstFailure.Execution,
+		BuildId:   firstFailure.BuildId,
+		VersionId: firstFailure.Version,
+		ProjectId: firstFailure.Project,
+		CreatedAt: time.Now(),
+	}), "error queueing stepback culprit alert")
+}
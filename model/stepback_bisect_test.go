@@ -0,0 +1,180 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/alert"
+	"github.com/evergreen-ci/evergreen/model/alertrecord"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestBisectCandidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(bisectCandidate(nil))
+	assert.Nil(bisectCandidate([]task.Task{{Id: "blacklisted", Priority: -1}}))
+
+	between := []task.Task{{Id: "t2"}, {Id: "t3"}, {Id: "t4"}, {Id: "t5", Priority: -1}}
+	assert.Equal("t3", bisectCandidate(between).Id)
+
+	// a run that was activated but has not finished yet is returned, so
+	// that the bisection waits for it
+	between[0].Activated = true
+	next := bisectCandidate(between)
+	assert.Equal("t2", next.Id)
+	assert.True(next.Activated)
+
+	between[0].Status = evergreen.TaskSucceeded
+	assert.Equal("t4", bisectCandidate(between).Id)
+}
+
+// insertBisectTasks inserts nine runs of a task, in a version and build of
+// their own, of which only the first and the last ran.
+func insertBisectTasks(t *testing.T) {
+	require.NoError(t, db.ClearCollections(task.Collection, build.Collection, version.Collection, alert.Collection))
+	for i := 1; i <= 9; i++ {
+		v := &version.Version{
+			Id:          fmt.Sprintf("v%d", i),
+			Revision:    fmt.Sprintf("revision%d", i),
+			Author:      fmt.Sprintf("author%d", i),
+			AuthorEmail: fmt.Sprintf("author%d@example.com", i),
+		}
+		require.NoError(t, v.Insert())
+		tsk := &task.Task{
+			Id:                  fmt.Sprintf("t%d", i),
+			BuildId:             fmt.Sprintf("b%d", i),
+			Version:             v.Id,
+			Project:             "project",
+			BuildVariant:        "variant",
+			DisplayName:         "compile",
+			Requester:           evergreen.RepotrackerVersionRequester,
+			RevisionOrderNumber: i,
+			Status:              evergreen.TaskUndispatched,
+			DispatchTime:        util.ZeroTime,
+		}
+		if i == 1 || i == 9 {
+			tsk.Activated = true
+			tsk.Status = evergreen.TaskSucceeded
+		}
+		if i == 9 {
+			tsk.Status = evergreen.TaskFailed
+		}
+		require.NoError(t, tsk.Insert())
+		b := &build.Build{
+			Id:      tsk.BuildId,
+			Version: v.Id,
+			Tasks:   []build.TaskCache{{Id: tsk.Id, Activated: tsk.Activated, Status: tsk.Status}},
+		}
+		require.NoError(t, b.Insert())
+	}
+}
+
+// finishBisectTask marks a run activated by the bisection as finished and
+// evaluates stepback for it.
+func finishBisectTask(t *testing.T, p *Project, id, status string) {
+	tsk, err := task.FindOne(task.ById(id))
+	require.NoError(t, err)
+	require.NotNil(t, tsk)
+	require.True(t, tsk.Activated)
+	require.Equal(t, evergreen.StepbackTaskActivator, tsk.ActivatedBy)
+	require.NoError(t, task.UpdateOne(bson.M{task.IdKey: id}, bson.M{"$set": bson.M{task.StatusKey: status}}))
+	tsk.Status = status
+	require.NoError(t, evalStepback(tsk, p, "", status, false))
+}
+
+func TestBisectStepback(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	insertBisectTasks(t)
+	p := &Project{Identifier: "project", Stepback: true, StepbackMode: StepbackModeBisect}
+
+	lastFailure, err := task.FindOne(task.ById("t9"))
+	require.NoError(err)
+	require.NoError(evalStepback(lastFailure, p, "", evergreen.TaskFailed, false))
+
+	// t5 is halfway between t1 and t9, t3 between t1 and t5, and t4 between
+	// t3 and t5
+	finishBisectTask(t, p, "t5", evergreen.TaskFailed)
+	finishBisectTask(t, p, "t3", evergreen.TaskSucceeded)
+	finishBisectTask(t, p, "t4", evergreen.TaskFailed)
+
+	for _, id := range []string{"t2", "t6", "t7", "t8"} {
+		tsk, err := task.FindOne(task.ById(id))
+		require.NoError(err)
+		assert.False(tsk.Activated, id)
+	}
+	for _, id := range []string{"t4", "t5", "t9"} {
+		tsk, err := task.FindOne(task.ById(id))
+		require.NoError(err)
+		require.NotNil(tsk.StepbackCulprit, id)
+		assert.Equal("t4", tsk.StepbackCulprit.TaskId)
+		assert.Equal("revision4", tsk.StepbackCulprit.Revision)
+		assert.Equal("author4", tsk.StepbackCulprit.Author)
+		assert.Equal("author4@example.com", tsk.StepbackCulprit.AuthorEmail)
+	}
+
+	alerts := []alert.AlertRequest{}
+	require.NoError(db.FindAllQ(alert.Collection, db.Query(bson.M{}), &alerts))
+	require.Len(alerts, 1)
+	assert.Equal(alertrecord.StepbackCulpritId, alerts[0].Trigger)
+	assert.Equal("t4", alerts[0].TaskId)
+}
+
+func TestBisectStepbackRespectsStepbackSetting(t *testing.T) {
+	require := require.New(t)
+	insertBisectTasks(t)
+	p := &Project{Identifier: "project", Stepback: false, StepbackMode: StepbackModeBisect}
+
+	lastFailure, err := task.FindOne(task.ById("t9"))
+	require.NoError(err)
+	require.NoError(evalStepback(lastFailure, p, "", evergreen.TaskFailed, false))
+
+	activated, err := task.Count(task.ByActivation(true))
+	require.NoError(err)
+	require.Equal(2, activated)
+}
+
+func TestActivateStepbackDisplayTask(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	require.NoError(db.ClearCollections(task.Collection, build.Collection))
+
+	dt := &task.Task{
+		Id:             "display",
+		BuildId:        "build",
+		DisplayOnly:    true,
+		ExecutionTasks: []string{"exec1", "exec2"},
+		Status:         evergreen.TaskUndispatched,
+	}
+	require.NoError(dt.Insert())
+	for _, id := range dt.ExecutionTasks {
+		execTask := &task.Task{
+			Id:           id,
+			BuildId:      "build",
+			Status:       evergreen.TaskUndispatched,
+			DispatchTime: util.ZeroTime,
+		}
+		require.NoError(execTask.Insert())
+	}
+	b := &build.Build{
+		Id:    "build",
+		Tasks: []build.TaskCache{{Id: dt.Id}},
+	}
+	require.NoError(b.Insert())
+
+	require.NoError(activateStepbackTask(dt))
+	for _, id := range []string{"display", "exec1", "exec2"} {
+		tsk, err := task.FindOne(task.ById(id))
+		require.NoError(err)
+		assert.True(tsk.Activated, id)
+	}
+}
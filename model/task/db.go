@@ -22,42 +22,50 @@ const (
 
 var (
 	// BSON fields for the task struct
-	IdKey                  = bsonutil.MustHaveTag(Task{}, "Id")
-	SecretKey              = bsonutil.MustHaveTag(Task{}, "Secret")
-	CreateTimeKey          = bsonutil.MustHaveTag(Task{}, "CreateTime")
-	DispatchTimeKey        = bsonutil.MustHaveTag(Task{}, "DispatchTime")
-	PushTimeKey            = bsonutil.MustHaveTag(Task{}, "PushTime")
-	ScheduledTimeKey       = bsonutil.MustHaveTag(Task{}, "ScheduledTime")
-	StartTimeKey           = bsonutil.MustHaveTag(Task{}, "StartTime")
-	FinishTimeKey          = bsonutil.MustHaveTag(Task{}, "FinishTime")
-	VersionKey             = bsonutil.MustHaveTag(Task{}, "Version")
-	ProjectKey             = bsonutil.MustHaveTag(Task{}, "Project")
-	RevisionKey            = bsonutil.MustHaveTag(Task{}, "Revision")
-	LastHeartbeatKey       = bsonutil.MustHaveTag(Task{}, "LastHeartbeat")
-	ActivatedKey           = bsonutil.MustHaveTag(Task{}, "Activated")
-	BuildIdKey             = bsonutil.MustHaveTag(Task{}, "BuildId")
-	DistroIdKey            = bsonutil.MustHaveTag(Task{}, "DistroId")
-	BuildVariantKey        = bsonutil.MustHaveTag(Task{}, "BuildVariant")
-	DependsOnKey           = bsonutil.MustHaveTag(Task{}, "DependsOn")
-	NumDepsKey             = bsonutil.MustHaveTag(Task{}, "NumDependents")
-	DisplayNameKey         = bsonutil.MustHaveTag(Task{}, "DisplayName")
-	HostIdKey              = bsonutil.MustHaveTag(Task{}, "HostId")
-	ExecutionKey           = bsonutil.MustHaveTag(Task{}, "Execution")
-	RestartsKey            = bsonutil.MustHaveTag(Task{}, "Restarts")
-	OldTaskIdKey           = bsonutil.MustHaveTag(Task{}, "OldTaskId")
-	ArchivedKey            = bsonutil.MustHaveTag(Task{}, "Archived")
-	RevisionOrderNumberKey = bsonutil.MustHaveTag(Task{}, "RevisionOrderNumber")
-	RequesterKey           = bsonutil.MustHaveTag(Task{}, "Requester")
-	StatusKey              = bsonutil.MustHaveTag(Task{}, "Status")
-	DetailsKey             = bsonutil.MustHaveTag(Task{}, "Details")
-	AbortedKey             = bsonutil.MustHaveTag(Task{}, "Aborted")
-	TimeTakenKey           = bsonutil.MustHaveTag(Task{}, "TimeTaken")
-	ExpectedDurationKey    = bsonutil.MustHaveTag(Task{}, "ExpectedDuration")
-	PriorityKey            = bsonutil.MustHaveTag(Task{}, "Priority")
-	ActivatedByKey         = bsonutil.MustHaveTag(Task{}, "ActivatedBy")
-	CostKey                = bsonutil.MustHaveTag(Task{}, "Cost")
-	ExecutionTasksKey      = bsonutil.MustHaveTag(Task{}, "ExecutionTasks")
-	DisplayOnlyKey         = bsonutil.MustHaveTag(Task{}, "DisplayOnly")
+	IdKey                   = bsonutil.MustHaveTag(Task{}, "Id")
+	SecretKey               = bsonutil.MustHaveTag(Task{}, "Secret")
+	CreateTimeKey           = bsonutil.MustHaveTag(Task{}, "CreateTime")
+	DispatchTimeKey         = bsonutil.MustHaveTag(Task{}, "DispatchTime")
+	PushTimeKey             = bsonutil.MustHaveTag(Task{}, "PushTime")
+	ScheduledTimeKey        = bsonutil.MustHaveTag(Task{}, "ScheduledTime")
+	StartTimeKey            = bsonutil.MustHaveTag(Task{}, "StartTime")
+	FinishTimeKey           = bsonutil.MustHaveTag(Task{}, "FinishTime")
+	VersionKey              = bsonutil.MustHaveTag(Task{}, "Version")
+	ProjectKey              = bsonutil.MustHaveTag(Task{}, "Project")
+	RevisionKey             = bsonutil.MustHaveTag(Task{}, "Revision")
+	LastHeartbeatKey        = bsonutil.MustHaveTag(Task{}, "LastHeartbeat")
+	ActivatedKey            = bsonutil.MustHaveTag(Task{}, "Activated")
+	BuildIdKey              = bsonutil.MustHaveTag(Task{}, "BuildId")
+	DistroIdKey             = bsonutil.MustHaveTag(Task{}, "DistroId")
+	BuildVariantKey         = bsonutil.MustHaveTag(Task{}, "BuildVariant")
+	DependsOnKey            = bsonutil.MustHaveTag(Task{}, "DependsOn")
+	NumDepsKey              = bsonutil.MustHaveTag(Task{}, "NumDependents")
+	DisplayNameKey          = bsonutil.MustHaveTag(Task{}, "DisplayName")
+	HostIdKey               = bsonutil.MustHaveTag(Task{}, "HostId")
+	ExecutionKey            = bsonutil.MustHaveTag(Task{}, "Execution")
+	RestartsKey             = bsonutil.MustHaveTag(Task{}, "Restarts")
+	OldTaskIdKey            = bsonutil.MustHaveTag(Task{}, "OldTaskId")
+	ArchivedKey             = bsonutil.MustHaveTag(Task{}, "Archived")
+	RevisionOrderNumberKey  = bsonutil.MustHaveTag(Task{}, "RevisionOrderNumber")
+	RequesterKey            = bsonutil.MustHaveTag(Task{}, "Requester")
+	StatusKey               = bsonutil.MustHaveTag(Task{}, "Status")
+	DetailsKey              = bsonutil.MustHaveTag(Task{}, "Details")
+	AbortedKey              = bsonutil.MustHaveTag(Task{}, "Aborted")
+	TimeTakenKey            = bsonutil.MustHaveTag(Task{}, "TimeTaken")
+	ExpectedDurationKey     = bsonutil.MustHaveTag(Task{}, "ExpectedDuration")
+	EstimatedStartKey       = bsonutil.MustHaveTag(Task{}, "EstimatedStart")
+	EstimatedFinishKey      = bsonutil.MustHaveTag(Task{}, "EstimatedFinish")
+	QueuePositionKey        = bsonutil.MustHaveTag(Task{}, "QueuePosition")
+	FirstEstimatedStartKey  = bsonutil.MustHaveTag(Task{}, "FirstEstimatedStart")
+	FirstEstimatedFinishKey = bsonutil.MustHaveTag(Task{}, "FirstEstimatedFinish")
+	PriorityKey             = bsonutil.MustHaveTag(Task{}, "Priority")
+	ActivatedByKey          = bsonutil.MustHaveTag(Task{}, "ActivatedBy")
+	CostKey                 = bsonutil.MustHaveTag(Task{}, "Cost")
+	ExecutionTasksKey       = bsonutil.MustHaveTag(Task{}, "ExecutionTasks")
+	DisplayOnlyKey          = bsonutil.MustHaveTag(Task{}, "DisplayOnly")
+	StepbackCulpritKey      = bsonutil.MustHaveTag(Task{}, "StepbackCulprit")
+	TraceKey                = bsonutil.MustHaveTag(Task{}, "Trace")
+	PathFilteredKey         = bsonutil.MustHaveTag(Task{}, "PathFiltered")
 
 	// BSON fields for the test result struct
 	TestResultStatusKey    = bsonutil.MustHaveTag(TestResult{}, "Status")
@@ -283,6 +291,39 @@ func ByBeforeRevisionWithStatusesAndRequester(revisionOrder int, statuses []stri
 	}).Sort([]string{"-" + RevisionOrderNumberKey})
 }
 
+// ByAfterRevisionWithStatusesAndRequester returns a query for the runs of a
+// task after the given revision order number with one of the given statuses,
+// ordered from oldest to newest.
+func ByAfterRevisionWithStatusesAndRequester(revisionOrder int, statuses []string, buildVariant, displayName, project, requester string) db.Q {
+	return db.Query(bson.M{
+		BuildVariantKey: buildVariant,
+		DisplayNameKey:  displayName,
+		RequesterKey:    requester,
+		RevisionOrderNumberKey: bson.M{
+			"$gt": revisionOrder,
+		},
+		StatusKey: bson.M{
+			"$in": statuses,
+		},
+		ProjectKey: project,
+	}).Sort([]string{RevisionOrderNumberKey})
+}
+
+// ByRevisionRange returns a query for the runs of a task with revision order
+// numbers strictly between the given ones, ordered from oldest to newest.
+func ByRevisionRange(after, before int, buildVariant, displayName, project, requester string) db.Q {
+	return db.Query(bson.M{
+		BuildVariantKey: buildVariant,
+		DisplayNameKey:  displayName,
+		RequesterKey:    requester,
+		RevisionOrderNumberKey: bson.M{
+			"$gt": after,
+			"$lt": before,
+		},
+		ProjectKey: project,
+	}).Sort([]string{RevisionOrderNumberKey})
+}
+
 // ByTimeRun returns all tasks that are running in between two given times.
 func ByTimeRun(startTime, endTime time.Time) db.Q {
 	return db.Query(
@@ -10,6 +10,7 @@ import (
 	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/tracing"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
@@ -99,18 +100,51 @@ type Task struct {
 	// how long we expect the task to take from start to finish
 	ExpectedDuration time.Duration `bson:"expected_duration,omitempty" json:"expected_duration,omitempty"`
 
+	// when the scheduler last expected the task to start and finish, and
+	// the task's lowest position in the task queues at the time
+	EstimatedStart  time.Time `bson:"est_start,omitempty" json:"est_start,omitempty"`
+	EstimatedFinish time.Time `bson:"est_finish,omitempty" json:"est_finish,omitempty"`
+	QueuePosition   int       `bson:"queue_pos,omitempty" json:"queue_pos,omitempty"`
+	// when the scheduler first expected the task to start and finish, which
+	// the task's actual start and finish are measured against
+	FirstEstimatedStart  time.Time `bson:"first_est_start,omitempty" json:"first_est_start,omitempty"`
+	FirstEstimatedFinish time.Time `bson:"first_est_finish,omitempty" json:"first_est_finish,omitempty"`
+
 	// an estimate of what the task cost to run, hidden from JSON views for now
 	Cost float64 `bson:"cost,omitempty" json:"-"`
 
 	// test results embedded from the testresults collection
 	LocalTestResults []TestResult `bson:"-" json:"test_results"`
 
+	// StepbackCulprit is set on failed tasks once bisection stepback has
+	// isolated the commit that caused the failure
+	StepbackCulprit *StepbackCulprit `bson:"stepback_culprit,omitempty" json:"stepback_culprit,omitempty"`
+
+	// Trace is the span that the spans recorded for the task continue
+	// from: its version's creation until it is dispatched, and its
+	// dispatch after that.
+	Trace *tracing.SpanContext `bson:"trace,omitempty" json:"trace,omitempty"`
+
+	// PathFiltered is set on mainline tasks whose paths filters match none
+	// of the files changed by their commit. They are not activated along
+	// with their build by Evergreen, only by users.
+	PathFiltered bool `bson:"path_filtered,omitempty" json:"path_filtered,omitempty"`
+
 	// display task fields
 	DisplayOnly    bool     `bson:"display_only,omitempty" json:"display_only,omitempty"`
 	ExecutionTasks []string `bson:"execution_tasks,omitempty" json:"execution_tasks,omitempty"`
 	DisplayTask    *Task    `bson:"-" json:"-"` // this is a local pointer from an exec to display task
 }
 
+// StepbackCulprit identifies the first run of a task that failed after it last
+// passed, and the commit it ran on.
+type StepbackCulprit struct {
+	TaskId      string `bson:"task_id" json:"task_id"`
+	Revision    string `bson:"revision" json:"revision"`
+	Author      string `bson:"author" json:"author"`
+	AuthorEmail string `bson:"author_email" json:"author_email"`
+}
+
 // Dependency represents a task that must be completed before the owning
 // task can be scheduled.
 type Dependency struct {
@@ -361,6 +395,74 @@ func (t *Task) PreviousCompletedTask(project string,
 		t.DisplayName, project))
 }
 
+// Estimate is when a task is expected to start and finish, and its lowest
+// position in the task queues, or 0 if it isn't queued.
+type Estimate struct {
+	Start         time.Time
+	Finish        time.Time
+	QueuePosition int
+}
+
+// SetEstimates records the estimates of the tasks with the given IDs in
+// bulk. The first estimates of a task are kept until its estimates are
+// cleared.
+func SetEstimates(estimates map[string]Estimate) error {
+	updates := make([]db.BulkUpdate, 0, 2*len(estimates))
+	for id, est := range estimates {
+		updates = append(updates,
+			db.BulkUpdate{
+				Query: bson.M{IdKey: id},
+				Update: bson.M{"$set": bson.M{
+					EstimatedStartKey:  est.Start,
+					EstimatedFinishKey: est.Finish,
+					QueuePositionKey:   est.QueuePosition,
+				}},
+			},
+			db.BulkUpdate{
+				Query: bson.M{
+					IdKey:                   id,
+					FirstEstimatedFinishKey: bson.M{"$exists": false},
+				},
+				Update: bson.M{"$set": bson.M{
+					FirstEstimatedStartKey:  est.Start,
+					FirstEstimatedFinishKey: est.Finish,
+				}},
+			},
+		)
+	}
+	return db.UpdateBulk(Collection, updates)
+}
+
+// FindFinishedWithEstimates returns the tasks that finished after the
+// scheduler estimated when they would.
+func FindFinishedWithEstimates() ([]Task, error) {
+	return Find(db.Query(bson.M{
+		EstimatedFinishKey: bson.M{"$exists": true},
+		StatusKey:          bson.M{"$in": CompletedStatuses},
+	}).WithFields(IdKey, StartTimeKey, FinishTimeKey, EstimatedStartKey, EstimatedFinishKey,
+		FirstEstimatedStartKey, FirstEstimatedFinishKey))
+}
+
+// ClearEstimates unsets the estimates of every task but the given ones.
+func ClearEstimates(keep []string) error {
+	_, err := UpdateAll(
+		bson.M{
+			EstimatedFinishKey: bson.M{"$exists": true},
+			IdKey:              bson.M{"$nin": keep},
+		},
+		bson.M{
+			"$unset": bson.M{
+				EstimatedStartKey:       1,
+				EstimatedFinishKey:      1,
+				QueuePositionKey:        1,
+				FirstEstimatedStartKey:  1,
+				FirstEstimatedFinishKey: 1,
+			},
+		},
+	)
+	return err
+}
+
 // SetExpectedDuration updates the expected duration field for the task
 func (t *Task) SetExpectedDuration(duration time.Duration) error {
 	return UpdateOne(
@@ -965,6 +1067,50 @@ func (t *Task) SetCost(cost float64) error {
 	)
 }
 
+// SetStepbackCulprit records the culprit found by bisection stepback on the
+// tasks with the given ids.
+func SetStepbackCulprit(taskIds []string, culprit StepbackCulprit) error {
+	_, err := UpdateAll(
+		bson.M{
+			IdKey: bson.M{"$in": taskIds},
+		},
+		bson.M{"$set": bson.M{StepbackCulpritKey: culprit}},
+	)
+	return errors.WithStack(err)
+}
+
+// SetTrace records the span that the task's later spans continue from.
+func (t *Task) SetTrace(sc tracing.SpanContext) error {
+	t.Trace = &sc
+	return UpdateOne(
+		bson.M{IdKey: t.Id},
+		bson.M{"$set": bson.M{TraceKey: sc}},
+	)
+}
+
+// SetTraceForVersion records the span that created a version on all of the
+// version's tasks.
+func SetTraceForVersion(versionId string, sc tracing.SpanContext) error {
+	_, err := UpdateAll(
+		bson.M{VersionKey: versionId},
+		bson.M{"$set": bson.M{TraceKey: sc}},
+	)
+	return errors.WithStack(err)
+}
+
+// SetPathFilteredForBuild marks the build's tasks with the given names as
+// filtered by the files changed by their commit.
+func SetPathFilteredForBuild(buildId string, displayNames []string) error {
+	_, err := UpdateAll(
+		bson.M{
+			BuildIdKey:     buildId,
+			DisplayNameKey: bson.M{"$in": displayNames},
+		},
+		bson.M{"$set": bson.M{PathFilteredKey: true}},
+	)
+	return errors.WithStack(err)
+}
+
 // AbortBuild sets the abort flag on all tasks associated with the build which are in an abortable
 // state
 func AbortBuild(buildId string) error {
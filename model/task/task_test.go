@@ -964,3 +964,35 @@ func TestMergeTestResultsBulk(t *testing.T) {
 	}
 	assert.Equal(3, count)
 }
+
+func TestSetEstimatesKeepsFirstEstimates(t *testing.T) {
+	assert := assert.New(t) //nolint
+
+	assert.NoError(db.Clear(Collection))
+	for _, id := range []string{"t1", "t2"} {
+		assert.NoError((&Task{Id: id}).Insert())
+	}
+
+	now := time.Now().Round(time.Millisecond)
+	first := Estimate{Start: now, Finish: now.Add(time.Hour), QueuePosition: 3}
+	assert.NoError(SetEstimates(map[string]Estimate{"t1": first, "t2": first}))
+	later := Estimate{Start: now.Add(time.Minute), Finish: now.Add(2 * time.Hour), QueuePosition: 1}
+	assert.NoError(SetEstimates(map[string]Estimate{"t1": later}))
+
+	t1, err := FindOne(ById("t1"))
+	assert.NoError(err)
+	assert.True(later.Start.Equal(t1.EstimatedStart))
+	assert.True(later.Finish.Equal(t1.EstimatedFinish))
+	assert.Equal(1, t1.QueuePosition)
+	assert.True(first.Start.Equal(t1.FirstEstimatedStart))
+	assert.True(first.Finish.Equal(t1.FirstEstimatedFinish))
+
+	assert.NoError(ClearEstimates([]string{"t1"}))
+	t2, err := FindOne(ById("t2"))
+	assert.NoError(err)
+	assert.True(t2.EstimatedFinish.IsZero())
+	assert.True(t2.FirstEstimatedFinish.IsZero())
+	t1, err = FindOne(ById("t1"))
+	assert.NoError(err)
+	assert.True(first.Finish.Equal(t1.FirstEstimatedFinish))
+}
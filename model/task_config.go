@@ -3,6 +3,8 @@ package model
 import (
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/evergreen-ci/evergreen/model/distro"
 	"github.com/evergreen-ci/evergreen/model/task"
@@ -20,6 +22,10 @@ type TaskConfig struct {
 	BuildVariant *BuildVariant
 	Expansions   *util.Expansions
 	WorkDir      string
+
+	// Container is the name of the container that the task's commands run
+	// in, if the distro runs each task in its own container.
+	Container string
 }
 
 func NewTaskConfig(d *distro.Distro, v *version.Version, p *Project, t *task.Task, r *ProjectRef) (*TaskConfig, error) {
@@ -39,7 +45,25 @@ func NewTaskConfig(d *distro.Distro, v *version.Version, p *Project, t *task.Tas
 	}
 
 	e := populateExpansions(d, v, bv, t)
-	return &TaskConfig{d, v, r, p, t, bv, e, d.WorkDir}, nil
+	if pt := p.FindProjectTask(t.DisplayName); pt != nil && pt.Shard != nil {
+		e.Put("shard_task", pt.Shard.Task)
+		e.Put("shard_index", strconv.Itoa(pt.Shard.Index))
+		e.Put("shard_count", strconv.Itoa(pt.Shard.Count))
+		e.Put("shard_tests", strings.Join(pt.Shard.Tests, " "))
+		if pt.Shard.RunsUnlistedTests() {
+			e.Put("shard_exclude_tests", strings.Join(pt.Shard.ExcludeTests, " "))
+		}
+	}
+	return &TaskConfig{
+		Distro:       d,
+		Version:      v,
+		ProjectRef:   r,
+		Project:      p,
+		Task:         t,
+		BuildVariant: bv,
+		Expansions:   e,
+		WorkDir:      d.WorkDir,
+	}, nil
 }
 
 func (c *TaskConfig) GetWorkingDirectory(dir string) (string, error) {
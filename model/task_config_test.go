@@ -4,6 +4,9 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/testutil"
 	"github.com/stretchr/testify/assert"
 )
@@ -37,3 +40,35 @@ func TestTaskConfig(t *testing.T) {
 	assert.Error(err)
 	assert.Equal("", out)
 }
+
+func TestTaskConfigShardExpansions(t *testing.T) {
+	assert := assert.New(t)
+
+	p := &Project{
+		Tasks: []ProjectTask{
+			{Name: "unit_shard_0", Shard: &TestShard{Task: "unit", Index: 0, Count: 2, Tests: []string{"c"}}},
+			{Name: "unit_shard_1", Shard: &TestShard{Task: "unit", Index: 1, Count: 2, Tests: []string{"a", "b"}, ExcludeTests: []string{"c"}}},
+			{Name: "compile"},
+		},
+		BuildVariants: []BuildVariant{{Name: "linux"}},
+	}
+	d := &distro.Distro{Id: "linux"}
+	v := &version.Version{Id: "v"}
+
+	conf, err := NewTaskConfig(d, v, p, &task.Task{DisplayName: "unit_shard_1", BuildVariant: "linux"}, &ProjectRef{})
+	assert.NoError(err)
+	assert.Equal("unit", conf.Expansions.Get("shard_task"))
+	assert.Equal("1", conf.Expansions.Get("shard_index"))
+	assert.Equal("2", conf.Expansions.Get("shard_count"))
+	assert.Equal("a b", conf.Expansions.Get("shard_tests"))
+	assert.Equal("c", conf.Expansions.Get("shard_exclude_tests"))
+
+	conf, err = NewTaskConfig(d, v, p, &task.Task{DisplayName: "unit_shard_0", BuildVariant: "linux"}, &ProjectRef{})
+	assert.NoError(err)
+	assert.Equal("c", conf.Expansions.Get("shard_tests"))
+	assert.False(conf.Expansions.Exists("shard_exclude_tests"))
+
+	conf, err = NewTaskConfig(d, v, p, &task.Task{DisplayName: "compile", BuildVariant: "linux"}, &ProjectRef{})
+	assert.NoError(err)
+	assert.False(conf.Expansions.Exists("shard_tests"))
+}
@@ -381,8 +381,11 @@ func MarkEnd(taskId, caller string, finishTime time.Time, detail *apimodels.Task
 		}
 	}
 
-	// no need to activate/deactivate other task if this is a patch request's task
-	if evergreen.IsPatchRequester(t.Requester) {
+	// no need to activate/deactivate other task if this is a patch request's
+	// task, or a task of a version created by a trigger, a periodic build or
+	// a git tag
+	if evergreen.IsPatchRequester(t.Requester) || t.Requester == evergreen.TriggerRequester ||
+		t.Requester == evergreen.PeriodicBuildRequester || t.Requester == evergreen.GitTagRequester {
 		return errors.Wrap(UpdateBuildAndVersionStatusForTask(t.Id, updates),
 			"Error updating build status (1)")
 	}
@@ -411,19 +414,38 @@ func evalStepback(t *task.Task, p *Project, caller, status string, deactivatePre
 			return errors.WithStack(err)
 		}
 		if shouldStepBack {
-			if err = doStepback(t); err != nil {
+			if p.StepbackMode == StepbackModeBisect {
+				err = doBisectStepback(t)
+			} else {
+				err = doStepback(t)
+			}
+			if err != nil {
 				return errors.Wrap(err, "Error during step back")
 			}
 		} else {
 			grip.Debugln("Not stepping backwards on task failure:", t.Id)
 		}
 
-	} else if deactivatePrevious && status == evergreen.TaskSucceeded {
+	} else if status == evergreen.TaskSucceeded {
 		// if the task was successful, ignore running previous
 		// activated tasks for this buildvariant
+		if deactivatePrevious {
+			if err := DeactivatePreviousTasks(t.Id, caller); err != nil {
+				return errors.Wrap(err, "Error deactivating previous task")
+			}
+		}
 
-		if err := DeactivatePreviousTasks(t.Id, caller); err != nil {
-			return errors.Wrap(err, "Error deactivating previous task")
+		// a pass narrows down the commits to bisect if a later run failed
+		if p != nil && p.StepbackMode == StepbackModeBisect {
+			shouldStepBack, err := getStepback(t.Id, p)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			if shouldStepBack {
+				if err = doBisectStepback(t); err != nil {
+					return errors.Wrap(err, "Error during step back")
+				}
+			}
 		}
 	}
 
@@ -647,6 +669,10 @@ func UpdateBuildAndVersionStatusForTask(taskId string, updates *StatusChanges) e
 			grip.Error(err)
 			return err
 		}
+
+		if b.IsFinished() {
+			event.LogBuildFinished(b.Id, b.Status)
+		}
 	}
 
 	// this is helpful for when we restart a compile task
@@ -725,10 +751,12 @@ func MarkTaskUndispatched(t *task.Task) error {
 
 func MarkTaskDispatched(t *task.Task, hostId, distroId string) error {
 	// record that the task was dispatched on the host
-	if err := t.MarkAsDispatched(hostId, distroId, time.Now()); err != nil {
+	dispatchedAt := time.Now()
+	if err := t.MarkAsDispatched(hostId, distroId, dispatchedAt); err != nil {
 		return errors.Wrapf(err, "error marking task %s as dispatched "+
 			"on host %s", t.Id, hostId)
 	}
+	observeDispatchLatency(t, distroId, dispatchedAt)
 	// the task was successfully dispatched, log the event
 	event.LogTaskDispatched(t.Id, hostId)
 
@@ -32,6 +32,9 @@ type PubKey struct {
 type UserSettings struct {
 	Timezone     string `json:"timezone" bson:"timezone"`
 	NewWaterfall bool   `json:"new_waterfall" bson:"new_waterfall"`
+	// SpawnHostAutoStopHours, if set, stops the user's spawn hosts once
+	// no one has been logged in to them for that many hours.
+	SpawnHostAutoStopHours int `json:"spawn_host_auto_stop_hours" bson:"spawn_host_auto_stop_hours,omitempty"`
 }
 
 func (u *DBUser) Username() string {
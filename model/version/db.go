@@ -2,6 +2,7 @@ package version
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/db"
@@ -16,30 +17,34 @@ const (
 
 var (
 	// bson fields for the version struct
-	IdKey                  = bsonutil.MustHaveTag(Version{}, "Id")
-	CreateTimeKey          = bsonutil.MustHaveTag(Version{}, "CreateTime")
-	StartTimeKey           = bsonutil.MustHaveTag(Version{}, "StartTime")
-	FinishTimeKey          = bsonutil.MustHaveTag(Version{}, "FinishTime")
-	RevisionKey            = bsonutil.MustHaveTag(Version{}, "Revision")
-	AuthorKey              = bsonutil.MustHaveTag(Version{}, "Author")
-	AuthorEmailKey         = bsonutil.MustHaveTag(Version{}, "AuthorEmail")
-	MessageKey             = bsonutil.MustHaveTag(Version{}, "Message")
-	StatusKey              = bsonutil.MustHaveTag(Version{}, "Status")
-	BuildIdsKey            = bsonutil.MustHaveTag(Version{}, "BuildIds")
-	BuildVariantsKey       = bsonutil.MustHaveTag(Version{}, "BuildVariants")
-	RevisionOrderNumberKey = bsonutil.MustHaveTag(Version{}, "RevisionOrderNumber")
-	RequesterKey           = bsonutil.MustHaveTag(Version{}, "Requester")
-	ConfigKey              = bsonutil.MustHaveTag(Version{}, "Config")
-	IgnoredKey             = bsonutil.MustHaveTag(Version{}, "Ignored")
-	OwnerNameKey           = bsonutil.MustHaveTag(Version{}, "Owner")
-	RepoKey                = bsonutil.MustHaveTag(Version{}, "Repo")
-	ProjectNameKey         = bsonutil.MustHaveTag(Version{}, "Branch")
-	RepoKindKey            = bsonutil.MustHaveTag(Version{}, "RepoKind")
-	ErrorsKey              = bsonutil.MustHaveTag(Version{}, "Errors")
-	WarningsKey            = bsonutil.MustHaveTag(Version{}, "Warnings")
-	IdentifierKey          = bsonutil.MustHaveTag(Version{}, "Identifier")
-	RemoteKey              = bsonutil.MustHaveTag(Version{}, "Remote")
-	RemoteURLKey           = bsonutil.MustHaveTag(Version{}, "RemotePath")
+	IdKey                   = bsonutil.MustHaveTag(Version{}, "Id")
+	CreateTimeKey           = bsonutil.MustHaveTag(Version{}, "CreateTime")
+	StartTimeKey            = bsonutil.MustHaveTag(Version{}, "StartTime")
+	FinishTimeKey           = bsonutil.MustHaveTag(Version{}, "FinishTime")
+	RevisionKey             = bsonutil.MustHaveTag(Version{}, "Revision")
+	AuthorKey               = bsonutil.MustHaveTag(Version{}, "Author")
+	AuthorEmailKey          = bsonutil.MustHaveTag(Version{}, "AuthorEmail")
+	MessageKey              = bsonutil.MustHaveTag(Version{}, "Message")
+	StatusKey               = bsonutil.MustHaveTag(Version{}, "Status")
+	BuildIdsKey             = bsonutil.MustHaveTag(Version{}, "BuildIds")
+	BuildVariantsKey        = bsonutil.MustHaveTag(Version{}, "BuildVariants")
+	RevisionOrderNumberKey  = bsonutil.MustHaveTag(Version{}, "RevisionOrderNumber")
+	RequesterKey            = bsonutil.MustHaveTag(Version{}, "Requester")
+	ConfigKey               = bsonutil.MustHaveTag(Version{}, "Config")
+	IgnoredKey              = bsonutil.MustHaveTag(Version{}, "Ignored")
+	OwnerNameKey            = bsonutil.MustHaveTag(Version{}, "Owner")
+	RepoKey                 = bsonutil.MustHaveTag(Version{}, "Repo")
+	ProjectNameKey          = bsonutil.MustHaveTag(Version{}, "Branch")
+	RepoKindKey             = bsonutil.MustHaveTag(Version{}, "RepoKind")
+	ErrorsKey               = bsonutil.MustHaveTag(Version{}, "Errors")
+	WarningsKey             = bsonutil.MustHaveTag(Version{}, "Warnings")
+	IdentifierKey           = bsonutil.MustHaveTag(Version{}, "Identifier")
+	RemoteKey               = bsonutil.MustHaveTag(Version{}, "Remote")
+	RemoteURLKey            = bsonutil.MustHaveTag(Version{}, "RemotePath")
+	TriggeredByKey          = bsonutil.MustHaveTag(Version{}, "TriggeredBy")
+	EstimatedFinishKey      = bsonutil.MustHaveTag(Version{}, "EstimatedFinish")
+	QueuePositionKey        = bsonutil.MustHaveTag(Version{}, "QueuePosition")
+	FirstEstimatedFinishKey = bsonutil.MustHaveTag(Version{}, "FirstEstimatedFinish")
 )
 
 // ById returns a db.Q object which will filter on {_id : <the id param>}
@@ -160,6 +165,33 @@ func BaseVersionFromPatch(projectId, revision string) db.Q {
 		})
 }
 
+// ByProjectIdAndCreateTime finds the versions of any requester within a
+// project that were created at or before the given time, from newest to
+// oldest, or after it, from oldest to newest, if sortAsc is set.
+func ByProjectIdAndCreateTime(projectId string, ts time.Time, limit int, sortAsc bool) db.Q {
+	filter := bson.M{
+		IdentifierKey: projectId,
+	}
+
+	sortSpec := CreateTimeKey
+	if !sortAsc {
+		sortSpec = "-" + sortSpec
+		filter[CreateTimeKey] = bson.M{"$lte": ts}
+	} else {
+		filter[CreateTimeKey] = bson.M{"$gt": ts}
+	}
+	return db.Query(filter).WithoutFields(ConfigKey).Sort([]string{sortSpec}).Limit(limit)
+}
+
+// ByUpstreamVersion finds the versions created by triggers on the given
+// upstream version, ordered from oldest to newest.
+func ByUpstreamVersion(versionId string) db.Q {
+	return db.Query(
+		bson.M{
+			bsonutil.GetDottedKeyName(TriggeredByKey, TriggerInfoVersionKey): versionId,
+		}).Sort([]string{CreateTimeKey})
+}
+
 func FindOne(query db.Q) (*Version, error) {
 	version := &Version{}
 	err := db.FindOneQ(Collection, query, version)
@@ -191,3 +223,62 @@ func UpdateOne(query interface{}, update interface{}) error {
 		update,
 	)
 }
+
+// Estimate is when a version's tasks are expected to finish, and the lowest
+// position of its tasks in the task queues.
+type Estimate struct {
+	Finish        time.Time
+	QueuePosition int
+}
+
+// SetEstimates records the estimates of the versions with the given IDs in
+// bulk. The first estimate of a version is kept until its estimates are
+// cleared.
+func SetEstimates(estimates map[string]Estimate) error {
+	updates := make([]db.BulkUpdate, 0, 2*len(estimates))
+	for id, est := range estimates {
+		updates = append(updates,
+			db.BulkUpdate{
+				Query: bson.M{IdKey: id},
+				Update: bson.M{"$set": bson.M{
+					EstimatedFinishKey: est.Finish,
+					QueuePositionKey:   est.QueuePosition,
+				}},
+			},
+			db.BulkUpdate{
+				Query: bson.M{
+					IdKey:                   id,
+					FirstEstimatedFinishKey: bson.M{"$exists": false},
+				},
+				Update: bson.M{"$set": bson.M{FirstEstimatedFinishKey: est.Finish}},
+			},
+		)
+	}
+	return db.UpdateBulk(Collection, updates)
+}
+
+// FindFinishedWithEstimates returns the versions that finished after the
+// scheduler estimated when they would.
+func FindFinishedWithEstimates() ([]Version, error) {
+	return Find(db.Query(bson.M{
+		EstimatedFinishKey: bson.M{"$exists": true},
+		StatusKey:          bson.M{"$in": []string{evergreen.VersionSucceeded, evergreen.VersionFailed}},
+	}).WithFields(IdKey, RequesterKey, FinishTimeKey, EstimatedFinishKey, FirstEstimatedFinishKey))
+}
+
+// ClearEstimates unsets the estimates of every version but the given ones.
+func ClearEstimates(keep []string) error {
+	_, err := db.UpdateAll(
+		Collection,
+		bson.M{
+			EstimatedFinishKey: bson.M{"$exists": true},
+			IdKey:              bson.M{"$nin": keep},
+		},
+		bson.M{"$unset": bson.M{
+			EstimatedFinishKey:      1,
+			QueuePositionKey:        1,
+			FirstEstimatedFinishKey: 1,
+		}},
+	)
+	return err
+}
@@ -44,6 +44,70 @@ type Version struct {
 	// this field is omitted in the database
 	Errors   []string `bson:"errors,omitempty" json:"errors,omitempty"`
 	Warnings []string `bson:"warnings,omitempty" json:"warnings,omitempty"`
+
+	// TriggeredBy is set on versions created by a trigger on another
+	// project, and describes the upstream version that caused it.
+	TriggeredBy *TriggerInfo `bson:"triggered_by,omitempty" json:"triggered_by,omitempty"`
+
+	// GitTag is set on versions created for a git tag, and is the name of
+	// the tag.
+	GitTag string `bson:"git_tag,omitempty" json:"git_tag,omitempty"`
+
+	// EstimatedFinish is when the scheduler last expected the version's
+	// tasks to finish, and QueuePosition is the lowest position of its
+	// tasks in the task queues at the time.
+	EstimatedFinish time.Time `bson:"est_finish,omitempty" json:"est_finish,omitempty"`
+	QueuePosition   int       `bson:"queue_pos,omitempty" json:"queue_pos,omitempty"`
+	// FirstEstimatedFinish is when the scheduler first expected the
+	// version's tasks to finish, which the version's finish is measured
+	// against.
+	FirstEstimatedFinish time.Time `bson:"first_est_finish,omitempty" json:"first_est_finish,omitempty"`
+}
+
+// TriggerInfo describes the upstream version, build or task that caused a
+// version to be created by a trigger.
+type TriggerInfo struct {
+	Project  string `bson:"project" json:"project"`
+	Level    string `bson:"level" json:"level"`
+	Status   string `bson:"status" json:"status"`
+	Version  string `bson:"version" json:"version"`
+	Build    string `bson:"build,omitempty" json:"build,omitempty"`
+	Task     string `bson:"task,omitempty" json:"task,omitempty"`
+	Revision string `bson:"revision" json:"revision"`
+
+	// Chain lists the projects of every upstream version in the chain of
+	// triggers that led to this version, the nearest one last. It is used
+	// to keep triggers from looping.
+	Chain []string `bson:"chain" json:"chain"`
+
+	Artifacts []TriggerArtifact `bson:"artifacts,omitempty" json:"artifacts,omitempty"`
+}
+
+// TriggerArtifact is a file attached to the upstream tasks of a trigger.
+type TriggerArtifact struct {
+	Name string `bson:"name" json:"name"`
+	Link string `bson:"link" json:"link"`
+}
+
+var (
+	TriggerInfoVersionKey = bsonutil.MustHaveTag(TriggerInfo{}, "Version")
+)
+
+// IsTriggeredBy returns true if the project appears in the chain of triggers
+// that led to this version, including the version's own project.
+func (self *Version) IsTriggeredBy(project string) bool {
+	if self.Identifier == project {
+		return true
+	}
+	if self.TriggeredBy == nil {
+		return false
+	}
+	for _, p := range self.TriggeredBy.Chain {
+		if p == project {
+			return true
+		}
+	}
+	return false
 }
 
 func (self *Version) UpdateBuildVariants() error {
@@ -0,0 +1,310 @@
+package model
+
+import (
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// minDurationChange is the smallest change in a task's duration that is
+	// reported when comparing versions.
+	minDurationChange = time.Minute
+	// minDurationChangeRatio is the smallest change in a task's duration,
+	// relative to its duration in the base version, that is reported when
+	// comparing versions.
+	minDurationChangeRatio = 0.2
+)
+
+// VersionComparison describes how the tasks of a version differ from the
+// tasks of a base version. Either version may be a patch.
+type VersionComparison struct {
+	BaseVersion      string               `json:"base_version"`
+	Version          string               `json:"version"`
+	StatusChanges    []TaskComparison     `json:"status_changes"`
+	DurationChanges  []TaskComparison     `json:"duration_changes"`
+	NewlyFailing     []TestComparison     `json:"newly_failing_tests"`
+	NewlyPassing     []TestComparison     `json:"newly_passing_tests"`
+	AddedArtifacts   []ArtifactComparison `json:"added_artifacts"`
+	RemovedArtifacts []ArtifactComparison `json:"removed_artifacts"`
+}
+
+// TaskComparison pairs a task with the task of the same name and build
+// variant in the base version. The ID and status on either side are empty if
+// the task only exists in one of the versions.
+type TaskComparison struct {
+	BuildVariant string        `json:"build_variant"`
+	DisplayName  string        `json:"display_name"`
+	BaseTaskId   string        `json:"base_task_id"`
+	TaskId       string        `json:"task_id"`
+	BaseStatus   string        `json:"base_status"`
+	Status       string        `json:"status"`
+	BaseDuration time.Duration `json:"base_duration"`
+	Duration     time.Duration `json:"duration"`
+}
+
+// TestComparison pairs the status of a test with its status in the same task
+// of the base version.
+type TestComparison struct {
+	BuildVariant string `json:"build_variant"`
+	TaskName     string `json:"task_name"`
+	TestFile     string `json:"test_file"`
+	BaseStatus   string `json:"base_status"`
+	Status       string `json:"status"`
+}
+
+// ArtifactComparison is a file attached to a task in only one of the versions.
+type ArtifactComparison struct {
+	BuildVariant string `json:"build_variant"`
+	TaskName     string `json:"task_name"`
+	Name         string `json:"name"`
+	Link         string `json:"link"`
+}
+
+// CompareVersions loads the tasks, test results and artifacts of two versions
+// and returns how the version differs from the base version. Only the
+// artifacts that the user may see are compared, with signed links.
+func CompareVersions(baseVersionId, versionId string, u *user.DBUser, settings *evergreen.Settings) (*VersionComparison, error) {
+	baseTasks, baseFiles, err := findComparisonTasks(baseVersionId, u, settings)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem loading tasks for version '%s'", baseVersionId)
+	}
+	tasks, files, err := findComparisonTasks(versionId, u, settings)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem loading tasks for version '%s'", versionId)
+	}
+	return CompareVersionTasks(baseVersionId, versionId, baseTasks, tasks, baseFiles, files), nil
+}
+
+// findComparisonTasks returns the tasks of a version with their test results,
+// and the files attached to each task that the user may see, keyed by task
+// ID.
+func findComparisonTasks(versionId string, u *user.DBUser, settings *evergreen.Settings) ([]task.Task, map[string][]artifact.File, error) {
+	tasks, err := task.Find(task.ByVersion(versionId))
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	if len(tasks) == 0 {
+		return tasks, map[string][]artifact.File{}, nil
+	}
+	tasks, err = task.MergeTestResultsBulk(tasks, nil)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "problem merging test results")
+	}
+
+	taskIds := make([]string, 0, len(tasks))
+	executions := map[string]int{}
+	for _, t := range tasks {
+		taskIds = append(taskIds, t.Id)
+		executions[t.Id] = t.Execution
+	}
+	entries, err := artifact.FindAll(artifact.ByTaskIds(taskIds))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "problem finding artifacts")
+	}
+	files := map[string][]artifact.File{}
+	for _, entry := range entries {
+		if entry.Execution != executions[entry.TaskId] {
+			continue
+		}
+		files[entry.TaskId] = append(files[entry.TaskId], entry.Files...)
+	}
+	if len(files) == 0 {
+		return tasks, files, nil
+	}
+
+	projectRef, err := FindOneProjectRef(tasks[0].Project)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "problem finding project")
+	}
+	for taskId, taskFiles := range files {
+		files[taskId], err = SignArtifactLinks(taskId, artifact.StripHiddenFiles(taskFiles, u != nil),
+			projectRef, u, settings)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "problem signing artifact links")
+		}
+	}
+	return tasks, files, nil
+}
+
+// CompareVersionTasks compares the tasks of a version with the tasks of a base
+// version. Tasks are matched by build variant and display name, and their
+// files by name.
+func CompareVersionTasks(baseVersionId, versionId string, baseTasks, tasks []task.Task,
+	baseFiles, files map[string][]artifact.File) *VersionComparison {
+	comparison := &VersionComparison{
+		BaseVersion:      baseVersionId,
+		Version:          versionId,
+		StatusChanges:    []TaskComparison{},
+		DurationChanges:  []TaskComparison{},
+		NewlyFailing:     []TestComparison{},
+		NewlyPassing:     []TestComparison{},
+		AddedArtifacts:   []ArtifactComparison{},
+		RemovedArtifacts: []ArtifactComparison{},
+	}
+
+	type taskKey struct {
+		variant string
+		name    string
+	}
+	baseByKey := map[taskKey]*task.Task{}
+	for i := range baseTasks {
+		t := &baseTasks[i]
+		baseByKey[taskKey{t.BuildVariant, t.DisplayName}] = t
+	}
+
+	seen := map[taskKey]bool{}
+	for i := range tasks {
+		t := &tasks[i]
+		key := taskKey{t.BuildVariant, t.DisplayName}
+		seen[key] = true
+		baseTask := baseByKey[key]
+
+		pair := TaskComparison{
+			BuildVariant: t.BuildVariant,
+			DisplayName:  t.DisplayName,
+			TaskId:       t.Id,
+			Status:       t.Status,
+			Duration:     t.TimeTaken,
+		}
+		if baseTask == nil {
+			comparison.StatusChanges = append(comparison.StatusChanges, pair)
+			comparison.AddedArtifacts = append(comparison.AddedArtifacts,
+				compareArtifacts(t, files[t.Id], nil)...)
+			continue
+		}
+		pair.BaseTaskId = baseTask.Id
+		pair.BaseStatus = baseTask.Status
+		pair.BaseDuration = baseTask.TimeTaken
+
+		if pair.Status != pair.BaseStatus {
+			comparison.StatusChanges = append(comparison.StatusChanges, pair)
+		}
+		if isSignificantDurationChange(baseTask, t) {
+			comparison.DurationChanges = append(comparison.DurationChanges, pair)
+		}
+
+		failing, passing := compareTests(baseTask, t)
+		comparison.NewlyFailing = append(comparison.NewlyFailing, failing...)
+		comparison.NewlyPassing = append(comparison.NewlyPassing, passing...)
+
+		comparison.AddedArtifacts = append(comparison.AddedArtifacts,
+			compareArtifacts(t, files[t.Id], baseFiles[baseTask.Id])...)
+		comparison.RemovedArtifacts = append(comparison.RemovedArtifacts,
+			compareArtifacts(t, baseFiles[baseTask.Id], files[t.Id])...)
+	}
+
+	for i := range baseTasks {
+		t := &baseTasks[i]
+		if seen[taskKey{t.BuildVariant, t.DisplayName}] {
+			continue
+		}
+		comparison.StatusChanges = append(comparison.StatusChanges, TaskComparison{
+			BuildVariant: t.BuildVariant,
+			DisplayName:  t.DisplayName,
+			BaseTaskId:   t.Id,
+			BaseStatus:   t.Status,
+			BaseDuration: t.TimeTaken,
+		})
+		comparison.RemovedArtifacts = append(comparison.RemovedArtifacts,
+			compareArtifacts(t, baseFiles[t.Id], nil)...)
+	}
+
+	sort.SliceStable(comparison.StatusChanges, func(i, j int) bool {
+		return lessTaskComparison(comparison.StatusChanges[i], comparison.StatusChanges[j])
+	})
+	sort.SliceStable(comparison.DurationChanges, func(i, j int) bool {
+		return lessTaskComparison(comparison.DurationChanges[i], comparison.DurationChanges[j])
+	})
+	return comparison
+}
+
+func lessTaskComparison(a, b TaskComparison) bool {
+	if a.BuildVariant != b.BuildVariant {
+		return a.BuildVariant < b.BuildVariant
+	}
+	return a.DisplayName < b.DisplayName
+}
+
+// isSignificantDurationChange returns true if both tasks finished and their
+// durations differ by at least minDurationChange and minDurationChangeRatio.
+func isSignificantDurationChange(baseTask, t *task.Task) bool {
+	if !util.StringSliceContains(evergreen.CompletedStatuses, baseTask.Status) ||
+		!util.StringSliceContains(evergreen.CompletedStatuses, t.Status) {
+		return false
+	}
+	if baseTask.TimeTaken <= 0 || t.TimeTaken <= 0 {
+		return false
+	}
+	change := t.TimeTaken - baseTask.TimeTaken
+	if change < 0 {
+		change = -change
+	}
+	return change >= minDurationChange &&
+		float64(change)/float64(baseTask.TimeTaken) >= minDurationChangeRatio
+}
+
+// compareTests returns the tests of a task that fail but did not fail in the
+// base task, and the tests that pass but failed in the base task.
+func compareTests(baseTask, t *task.Task) ([]TestComparison, []TestComparison) {
+	baseStatuses := map[string]string{}
+	for _, test := range baseTask.LocalTestResults {
+		baseStatuses[testName(test.TestFile)] = test.Status
+	}
+
+	failing := []TestComparison{}
+	passing := []TestComparison{}
+	for _, test := range t.LocalTestResults {
+		name := testName(test.TestFile)
+		baseStatus := baseStatuses[name]
+		pair := TestComparison{
+			BuildVariant: t.BuildVariant,
+			TaskName:     t.DisplayName,
+			TestFile:     name,
+			BaseStatus:   baseStatus,
+			Status:       test.Status,
+		}
+		switch {
+		case test.Status == evergreen.TestFailedStatus && baseStatus != evergreen.TestFailedStatus:
+			failing = append(failing, pair)
+		case test.Status == evergreen.TestSucceededStatus && baseStatus == evergreen.TestFailedStatus:
+			passing = append(passing, pair)
+		}
+	}
+	return failing, passing
+}
+
+// testName returns the base name of a test file for windows and non-windows
+// paths, so that tests match across distros.
+func testName(testFile string) string {
+	return path.Base(strings.Replace(testFile, "\\", "/", -1))
+}
+
+// compareArtifacts returns the files that are not among the other files.
+func compareArtifacts(t *task.Task, files, otherFiles []artifact.File) []ArtifactComparison {
+	otherNames := map[string]bool{}
+	for _, f := range otherFiles {
+		otherNames[f.Name] = true
+	}
+	diff := []ArtifactComparison{}
+	for _, f := range files {
+		if otherNames[f.Name] {
+			continue
+		}
+		diff = append(diff, ArtifactComparison{
+			BuildVariant: t.BuildVariant,
+			TaskName:     t.DisplayName,
+			Name:         f.Name,
+			Link:         f.Link,
+		})
+	}
+	return diff
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareVersionTasks(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	baseTasks := []task.Task{
+		{
+			Id:           "base_compile",
+			BuildVariant: "linux",
+			DisplayName:  "compile",
+			Status:       evergreen.TaskSucceeded,
+			TimeTaken:    10 * time.Minute,
+		},
+		{
+			Id:           "base_test",
+			BuildVariant: "linux",
+			DisplayName:  "test",
+			Status:       evergreen.TaskFailed,
+			TimeTaken:    10 * time.Minute,
+			LocalTestResults: []task.TestResult{
+				{TestFile: "dir/a.js", Status: evergreen.TestSucceededStatus},
+				{TestFile: "dir/b.js", Status: evergreen.TestFailedStatus},
+			},
+		},
+		{
+			Id:           "base_lint",
+			BuildVariant: "linux",
+			DisplayName:  "lint",
+			Status:       evergreen.TaskSucceeded,
+		},
+	}
+	tasks := []task.Task{
+		{
+			Id:           "compile",
+			BuildVariant: "linux",
+			DisplayName:  "compile",
+			Status:       evergreen.TaskSucceeded,
+			TimeTaken:    20 * time.Minute,
+		},
+		{
+			Id:           "test",
+			BuildVariant: "linux",
+			DisplayName:  "test",
+			Status:       evergreen.TaskFailed,
+			TimeTaken:    10*time.Minute + 30*time.Second,
+			LocalTestResults: []task.TestResult{
+				{TestFile: "dir\\a.js", Status: evergreen.TestFailedStatus},
+				{TestFile: "dir\\b.js", Status: evergreen.TestSucceededStatus},
+				{TestFile: "dir\\c.js", Status: evergreen.TestFailedStatus},
+			},
+		},
+		{
+			Id:           "windows_compile",
+			BuildVariant: "windows",
+			DisplayName:  "compile",
+			Status:       evergreen.TaskFailed,
+		},
+	}
+	baseFiles := map[string][]artifact.File{
+		"base_compile": {{Name: "binary"}, {Name: "debug symbols"}},
+	}
+	files := map[string][]artifact.File{
+		"compile":         {{Name: "binary"}},
+		"windows_compile": {{Name: "installer"}},
+	}
+
+	comparison := CompareVersionTasks("base", "version", baseTasks, tasks, baseFiles, files)
+	assert.Equal("base", comparison.BaseVersion)
+	assert.Equal("version", comparison.Version)
+
+	// tasks that only exist in one of the versions changed status
+	require.Len(comparison.StatusChanges, 2)
+	assert.Equal("lint", comparison.StatusChanges[0].DisplayName)
+	assert.Equal("base_lint", comparison.StatusChanges[0].BaseTaskId)
+	assert.Empty(comparison.StatusChanges[0].TaskId)
+	assert.Equal("windows", comparison.StatusChanges[1].BuildVariant)
+	assert.Empty(comparison.StatusChanges[1].BaseStatus)
+	assert.Equal(evergreen.TaskFailed, comparison.StatusChanges[1].Status)
+
+	// small changes in duration are not reported
+	require.Len(comparison.DurationChanges, 1)
+	assert.Equal("compile", comparison.DurationChanges[0].TaskId)
+	assert.Equal(10*time.Minute, comparison.DurationChanges[0].BaseDuration)
+	assert.Equal(20*time.Minute, comparison.DurationChanges[0].Duration)
+
+	require.Len(comparison.NewlyFailing, 2)
+	assert.Equal("a.js", comparison.NewlyFailing[0].TestFile)
+	assert.Equal(evergreen.TestSucceededStatus, comparison.NewlyFailing[0].BaseStatus)
+	assert.Equal("c.js", comparison.NewlyFailing[1].TestFile)
+	assert.Empty(comparison.NewlyFailing[1].BaseStatus)
+	require.Len(comparison.NewlyPassing, 1)
+	assert.Equal("b.js", comparison.NewlyPassing[0].TestFile)
+
+	require.Len(comparison.AddedArtifacts, 1)
+	assert.Equal("installer", comparison.AddedArtifacts[0].Name)
+	require.Len(comparison.RemovedArtifacts, 1)
+	assert.Equal("debug symbols", comparison.RemovedArtifacts[0].Name)
+	assert.Equal("compile", comparison.RemovedArtifacts[0].TaskName)
+}
+
+func TestCompareVersionTasksIgnoresUnfinishedDurations(t *testing.T) {
+	baseTasks := []task.Task{{Id: "base", DisplayName: "compile", Status: evergreen.TaskSucceeded, TimeTaken: time.Hour}}
+	tasks := []task.Task{{Id: "task", DisplayName: "compile", Status: evergreen.TaskStarted, TimeTaken: time.Minute}}
+
+	comparison := CompareVersionTasks("base", "version", baseTasks, tasks, nil, nil)
+	assert.Empty(t, comparison.DurationChanges)
+	assert.Len(t, comparison.StatusChanges, 1)
+}
@@ -176,14 +176,33 @@ func terminateHost(ctx context.Context, h *host.Host, settings *evergreen.Settin
 			})
 		}
 	}
+	// clear the tasks in the host's slots, for hosts that run several at once.
+	for _, taskId := range h.RunningTasks {
+		grip.Warning(message.Fields{
+			"runner":  RunnerName,
+			"message": "Host has task in slot; clearing before terminating",
+			"host":    h.Id,
+			"task":    taskId,
+		})
+		if err := h.RemoveRunningTask(taskId, time.Now()); err != nil {
+			grip.Error(message.Fields{
+				"runner":  RunnerName,
+				"message": "Error clearing slot task for host",
+				"host":    h.Id,
+				"task":    taskId,
+			})
+		}
+	}
 	// convert the host to a cloud host
 	cloudHost, err := cloud.GetCloudHost(h, settings)
 	if err != nil {
 		return errors.Wrapf(err, "error getting cloud host for %v", h.Id)
 	}
 
-	// run teardown script if we have one, sending notifications if things go awry
-	if h.Distro.Teardown != "" && h.Provisioned {
+	// run teardown script if we have one, sending notifications if things go awry.
+	// stopped hosts can not run it.
+	stopped := h.Status == evergreen.HostStopped || h.Status == evergreen.HostStopping
+	if h.Distro.Teardown != "" && h.Provisioned && !stopped {
 		grip.Info(message.Fields{
 			"runner":  RunnerName,
 			"message": "running teardown script for host",
@@ -0,0 +1,183 @@
+package monitor
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/hostutil"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// countSessionsCommand counts the users logged in to a host, other than the
+// session running the command.
+const countSessionsCommand = `who | grep -cv "$(tty | cut -c6-)" || true`
+
+// countSpawnHostSessions returns the number of users logged in to a spawn
+// host. It is a variable so that tests can replace the ssh check.
+var countSpawnHostSessions = func(h *host.Host, cloudHost *cloud.CloudHost) (int, error) {
+	sshOptions, err := cloudHost.GetSSHOptions()
+	if err != nil {
+		return 0, errors.Wrapf(err, "error getting ssh options for host %s", h.Id)
+	}
+	out, err := hostutil.RunSSHCommand(context.TODO(), countSessionsCommand, sshOptions, *h)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error counting sessions on host %s: %s", h.Id, out)
+	}
+	sessions, err := strconv.Atoi(strings.TrimSpace(out))
+	if err != nil {
+		return 0, errors.Wrapf(err, "unexpected session count '%s' on host %s", out, h.Id)
+	}
+	return sessions, nil
+}
+
+// monitorStoppingHosts is a hostMonitoringFunc that marks hosts being stopped
+// as stopped, once their cloud provider reports that they are.
+func monitorStoppingHosts(settings *evergreen.Settings) []error {
+	hosts, err := host.Find(host.IsStopping)
+	if err != nil {
+		return []error{errors.Wrap(err, "error finding stopping hosts")}
+	}
+
+	var errs []error
+	for i := range hosts {
+		h := &hosts[i]
+		cloudHost, err := cloud.GetCloudHost(h, settings)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "error getting cloud host for host %s", h.Id))
+			continue
+		}
+		cloudStatus, err := cloudHost.GetInstanceStatus()
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "error getting cloud status for host %s", h.Id))
+			continue
+		}
+
+		switch cloudStatus {
+		case cloud.StatusStopped:
+			grip.Info(message.Fields{
+				"runner":    RunnerName,
+				"operation": "monitorStoppingHosts",
+				"message":   "host stopped",
+				"host":      h.Id,
+			})
+			if err = h.SetStopped(); err != nil {
+				errs = append(errs, errors.Wrapf(err, "error setting host %s stopped", h.Id))
+			}
+		case cloud.StatusTerminated:
+			if err = h.SetTerminated(); err != nil {
+				errs = append(errs, errors.Wrapf(err, "error setting host %s terminated", h.Id))
+			}
+		}
+	}
+	return errs
+}
+
+// monitorRestartedHosts is a hostMonitoringFunc that sets the DNS names of
+// spawn hosts that were started again after being stopped.
+func monitorRestartedHosts(settings *evergreen.Settings) []error {
+	hosts, err := host.Find(host.IsRestartedWithoutDNSName)
+	if err != nil {
+		return []error{errors.Wrap(err, "error finding restarted hosts")}
+	}
+
+	var errs []error
+	for i := range hosts {
+		h := &hosts[i]
+		cloudHost, err := cloud.GetCloudHost(h, settings)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "error getting cloud host for host %s", h.Id))
+			continue
+		}
+		dnsName, err := cloudHost.GetDNSName()
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "error getting DNS name for host %s", h.Id))
+			continue
+		}
+		// the provider has not assigned the new name yet
+		if dnsName == "" {
+			continue
+		}
+		if err = h.SetDNSName(dnsName); err != nil {
+			errs = append(errs, errors.Wrapf(err, "error setting DNS name for host %s", h.Id))
+		}
+	}
+	return errs
+}
+
+// stopIdleSpawnHosts is a hostMonitoringFunc that stops the spawn hosts of
+// users who asked for their hosts to be stopped after some hours without
+// anyone logged in to them.
+func stopIdleSpawnHosts(settings *evergreen.Settings) []error {
+	hosts, err := host.Find(host.IsRunningUserHost)
+	if err != nil {
+		return []error{errors.Wrap(err, "error finding running spawn hosts")}
+	}
+
+	var errs []error
+	autoStopHours := map[string]int{}
+	for i := range hosts {
+		h := &hosts[i]
+		hours, ok := autoStopHours[h.StartedBy]
+		if !ok {
+			owner, err := user.FindOne(user.ById(h.StartedBy))
+			if err != nil {
+				errs = append(errs, errors.Wrapf(err, "error finding user %s", h.StartedBy))
+				continue
+			}
+			if owner != nil {
+				hours = owner.Settings.SpawnHostAutoStopHours
+			}
+			autoStopHours[h.StartedBy] = hours
+		}
+		// spot instances can't be stopped
+		if hours <= 0 || h.Distro.IsWindows() || h.Distro.Provider == evergreen.ProviderNameEc2Spot {
+			continue
+		}
+
+		cloudHost, err := cloud.GetCloudHost(h, settings)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "error getting cloud host for host %s", h.Id))
+			continue
+		}
+		if _, ok = cloudHost.CloudMgr.(cloud.CloudStopStartManager); !ok {
+			continue
+		}
+
+		sessions, err := countSpawnHostSessions(h, cloudHost)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if sessions > 0 {
+			if err = h.SetLastActivityTime(time.Now()); err != nil {
+				errs = append(errs, errors.Wrapf(err, "error setting activity time for host %s", h.Id))
+			}
+			continue
+		}
+
+		idle := h.GetElapsedIdleTime()
+		if idle < time.Duration(hours)*time.Hour {
+			continue
+		}
+		grip.Info(message.Fields{
+			"runner":    RunnerName,
+			"operation": "stopIdleSpawnHosts",
+			"message":   "stopping idle spawn host",
+			"host":      h.Id,
+			"user":      h.StartedBy,
+			"idle_secs": idle.Seconds(),
+		})
+		if err = cloudHost.StopInstance(); err != nil {
+			errs = append(errs, errors.Wrapf(err, "error stopping host %s", h.Id))
+		}
+	}
+	return errs
+}
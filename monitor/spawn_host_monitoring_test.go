@@ -0,0 +1,137 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type spawnHostMonitoringSuite struct {
+	mock          cloud.MockProvider
+	sessions      int
+	sessionChecks int
+
+	suite.Suite
+}
+
+func TestSpawnHostMonitoring(t *testing.T) {
+	suite.Run(t, new(spawnHostMonitoringSuite))
+}
+
+func (s *spawnHostMonitoringSuite) SetupSuite() {
+	db.SetGlobalSessionProvider(testutil.TestConfig().SessionFactory())
+	s.mock = cloud.GetMockProvider()
+	countSpawnHostSessions = func(*host.Host, *cloud.CloudHost) (int, error) {
+		s.sessionChecks++
+		return s.sessions, nil
+	}
+}
+
+func (s *spawnHostMonitoringSuite) SetupTest() {
+	s.Require().NoError(db.ClearCollections(host.Collection, user.Collection))
+	s.mock.Reset()
+	s.sessions = 0
+	s.sessionChecks = 0
+}
+
+func (s *spawnHostMonitoringSuite) insertHost(id, status string, created time.Time) *host.Host {
+	h := &host.Host{
+		Id:           id,
+		Host:         id + ".example.com",
+		Status:       status,
+		Provider:     evergreen.ProviderNameMock,
+		StartedBy:    "user",
+		CreationTime: created,
+	}
+	s.Require().NoError(h.Insert())
+	s.mock.Set(id, cloud.MockInstance{Status: cloud.StatusRunning, IsUp: true, DNSName: "new." + id})
+	return h
+}
+
+func (s *spawnHostMonitoringSuite) findHost(id string) *host.Host {
+	h, err := host.FindOne(host.ById(id))
+	s.Require().NoError(err)
+	s.Require().NotNil(h)
+	return h
+}
+
+func (s *spawnHostMonitoringSuite) TestStoppingHostsAreStoppedOnceTheProviderIs() {
+	s.insertHost("h1", evergreen.HostStopping, time.Now())
+	s.insertHost("h2", evergreen.HostStopping, time.Now())
+	instance := s.mock.Get("h1")
+	instance.Status = cloud.StatusStopped
+	s.mock.Set("h1", instance)
+
+	s.Empty(monitorStoppingHosts(nil))
+	s.Equal(evergreen.HostStopped, s.findHost("h1").Status)
+	s.Equal(evergreen.HostStopping, s.findHost("h2").Status)
+}
+
+func (s *spawnHostMonitoringSuite) TestRestartedHostsGetNewDNSNames() {
+	h := s.insertHost("h1", evergreen.HostStopped, time.Now())
+	cloudHost, err := cloud.GetCloudHost(h, nil)
+	s.Require().NoError(err)
+	s.Require().NoError(cloudHost.StartInstance())
+	s.Empty(s.findHost("h1").Host)
+
+	s.Empty(monitorRestartedHosts(nil))
+	s.Equal("new.h1", s.findHost("h1").Host)
+}
+
+func (s *spawnHostMonitoringSuite) TestIdleHostsAreStopped() {
+	s.insertHost("idle", evergreen.HostRunning, time.Now().Add(-3*time.Hour))
+	s.insertHost("new", evergreen.HostRunning, time.Now())
+
+	// hosts are not stopped unless their owner asks for it
+	s.Require().NoError(db.Insert(user.Collection, &user.DBUser{Id: "user"}))
+	s.Empty(stopIdleSpawnHosts(nil))
+	s.Equal(evergreen.HostRunning, s.findHost("idle").Status)
+
+	s.Require().NoError(db.Clear(user.Collection))
+	s.Require().NoError(db.Insert(user.Collection, &user.DBUser{
+		Id:       "user",
+		Settings: user.UserSettings{SpawnHostAutoStopHours: 2},
+	}))
+	s.Empty(stopIdleSpawnHosts(nil))
+	s.Equal(evergreen.HostStopping, s.findHost("idle").Status)
+	s.Equal(evergreen.HostRunning, s.findHost("new").Status)
+}
+
+func (s *spawnHostMonitoringSuite) TestHostsWithUsersLoggedInAreNotStopped() {
+	s.insertHost("h1", evergreen.HostRunning, time.Now().Add(-3*time.Hour))
+	s.Require().NoError(db.Insert(user.Collection, &user.DBUser{
+		Id:       "user",
+		Settings: user.UserSettings{SpawnHostAutoStopHours: 2},
+	}))
+	s.sessions = 1
+
+	s.Empty(stopIdleSpawnHosts(nil))
+	h := s.findHost("h1")
+	s.Equal(evergreen.HostRunning, h.Status)
+	s.True(h.GetElapsedIdleTime() < time.Minute)
+}
+
+func (s *spawnHostMonitoringSuite) TestSpotHostsAreNotStopped() {
+	h := s.insertHost("spot", evergreen.HostRunning, time.Now().Add(-3*time.Hour))
+	s.Require().NoError(host.UpdateOne(
+		bson.M{host.IdKey: h.Id},
+		bson.M{"$set": bson.M{host.DistroKey: distro.Distro{Provider: evergreen.ProviderNameEc2Spot}}},
+	))
+	s.Require().NoError(db.Insert(user.Collection, &user.DBUser{
+		Id:       "user",
+		Settings: user.UserSettings{SpawnHostAutoStopHours: 2},
+	}))
+
+	s.Empty(stopIdleSpawnHosts(nil))
+	s.Equal(evergreen.HostRunning, s.findHost("spot").Status)
+	s.Zero(s.sessionChecks)
+}
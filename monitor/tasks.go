@@ -110,9 +110,9 @@ func cleanUpTask(wrapper doomedTaskWrapper, projects map[string]model.Project) e
 	}
 
 	// if the host still has the task as its running task, clear it.
-	if host.RunningTask == wrapper.task.Id {
+	if host.IsRunningTask(wrapper.task.Id) {
 		// clear out the host's running task
-		if err = host.ClearRunningTask(wrapper.task.Id, time.Now()); err != nil {
+		if err = host.ClearTask(wrapper.task.Id, time.Now()); err != nil {
 			return errors.Wrapf(err, "error clearing running task %v from host %v: %v",
 				wrapper.task.Id, host.Id)
 		}
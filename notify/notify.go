@@ -161,6 +161,13 @@ func Run(settings *evergreen.Settings) error {
 		}))
 		return err
 	}
+	if mciNotification == nil {
+		grip.Debug(message.Fields{
+			"runner":  RunnerName,
+			"message": "no notifications file, only sending subscription notifications",
+		})
+		return nil
+	}
 
 	// validate the notifications
 	err = ValidateNotifications(mciNotification)
@@ -220,7 +227,9 @@ func Run(settings *evergreen.Settings) error {
 	return nil
 }
 
-// This function is responsible for reading the notifications file
+// This function is responsible for reading the notifications file. Users
+// manage their own notifications with subscriptions instead, so it returns
+// nil if there is no notifications file.
 func ParseNotifications(configName string) (*MCINotification, error) {
 	grip.Debug(message.Fields{
 		"runner":  RunnerName,
@@ -243,6 +252,9 @@ func ParseNotifications(configName string) (*MCINotification, error) {
 
 		notificationsFile = fn
 	}
+	if notificationsFile == "" {
+		return nil, nil
+	}
 
 	data, err := ioutil.ReadFile(notificationsFile)
 	if err != nil {
@@ -595,7 +607,7 @@ func constructChangeInfo(v *version.Version, notification *NotificationKey) (cha
 		changeInfo.Revision = v.Revision
 		changeInfo.Email = v.AuthorEmail
 
-	case evergreen.PatchVersionRequester, evergreen.GithubPRRequester:
+	case evergreen.PatchVersionRequester, evergreen.GithubPRRequester, evergreen.GitlabMRRequester:
 		// get the author and description from the patch request
 		patch, err := patch.FindOne(patch.ByVersion(v.Id))
 		if err != nil {
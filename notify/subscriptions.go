@@ -0,0 +1,452 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/send"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// SubscriptionEventTypes are the types of events that are evaluated against
+// the users' notification subscriptions.
+var SubscriptionEventTypes = []string{
+	event.TaskFinished,
+	event.BuildFinished,
+	event.VersionFinished,
+	event.PatchFinished,
+	event.EventHostProvisioned,
+	event.EventHostProvisionFailed,
+}
+
+// subscriptionResource describes the resource an event is about, in the
+// terms that subscriptions are evaluated against.
+type subscriptionResource struct {
+	Type       string
+	Id         string
+	Name       string
+	Project    string
+	Variant    string
+	Tags       []string
+	Owner      string
+	OwnerEmail string
+	Status     string
+	Failed     bool
+	Duration   time.Duration
+	URLPath    string
+
+	// task is set for task resources, whose triggers look at other tasks.
+	task *task.Task
+}
+
+// SubscriptionNotification is the payload posted to webhook subscribers.
+type SubscriptionNotification struct {
+	SubscriptionId string `json:"subscription_id"`
+	ResourceType   string `json:"resource_type"`
+	ResourceId     string `json:"resource_id"`
+	EventType      string `json:"event_type"`
+	Trigger        string `json:"trigger"`
+	Status         string `json:"status"`
+	Subject        string `json:"subject"`
+	URL            string `json:"url"`
+}
+
+// ProcessSubscriptionEvent evaluates an event against the subscriptions to
+// the resource it is about, and sends a notification to the subscriber of
+// each subscription that it triggers.
+func ProcessSubscriptionEvent(settings *evergreen.Settings, e *event.Event) error {
+	resource, err := getSubscriptionResource(e)
+	if err != nil {
+		return errors.Wrapf(err, "error finding resource of event for %s", e.ResourceId)
+	}
+	if resource == nil {
+		return nil
+	}
+
+	subscriptions, err := event.FindSubscriptionsByResourceType(resource.Type)
+	if err != nil {
+		return errors.Wrapf(err, "error finding subscriptions to %s", resource.Type)
+	}
+
+	private, err := resource.private()
+	if err != nil {
+		return errors.Wrapf(err, "error finding project of %s", resource.Id)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	for i := range subscriptions {
+		sub := &subscriptions[i]
+		if private {
+			// private projects are only visible to logged in users
+			visible, err := ownerIsUser(sub)
+			if err != nil {
+				catcher.Add(err)
+				continue
+			}
+			if !visible {
+				continue
+			}
+		}
+		matched, err := resource.matches(sub)
+		if err != nil {
+			catcher.Add(err)
+			continue
+		}
+		if !matched {
+			continue
+		}
+		triggered, err := resource.triggers(sub)
+		if err != nil {
+			catcher.Add(err)
+			continue
+		}
+		if !triggered {
+			continue
+		}
+
+		n := SubscriptionNotification{
+			SubscriptionId: sub.ID.Hex(),
+			ResourceType:   resource.Type,
+			ResourceId:     resource.Id,
+			EventType:      e.EventType,
+			Trigger:        sub.Trigger,
+			Status:         resource.Status,
+			Subject:        resource.subject(sub),
+			URL:            settings.Ui.Url + resource.URLPath,
+		}
+		if err = sendSubscriptionNotification(settings, sub.Subscriber, n); err != nil {
+			catcher.Add(errors.Wrapf(err, "error notifying %s subscriber of subscription %s",
+				sub.Subscriber.Type, n.SubscriptionId))
+			continue
+		}
+		grip.Info(message.Fields{
+			"runner":       RunnerName,
+			"message":      "sent subscription notification",
+			"subscription": n.SubscriptionId,
+			"owner":        sub.Owner,
+			"resource":     resource.Id,
+			"trigger":      sub.Trigger,
+			"subscriber":   sub.Subscriber.Type,
+		})
+	}
+
+	return catcher.Resolve()
+}
+
+// getSubscriptionResource returns the resource that an event is about, or
+// nil if subscriptions are not evaluated for the event.
+func getSubscriptionResource(e *event.Event) (*subscriptionResource, error) {
+	switch e.EventType {
+	case event.TaskFinished:
+		t, err := task.FindOne(task.ById(e.ResourceId))
+		if err != nil || t == nil {
+			return nil, err
+		}
+		r := &subscriptionResource{
+			Type:     event.ResourceTypeTask,
+			Id:       t.Id,
+			Name:     fmt.Sprintf("task '%s' on '%s'", t.DisplayName, t.BuildVariant),
+			Project:  t.Project,
+			Variant:  t.BuildVariant,
+			Tags:     t.Tags,
+			Status:   t.Status,
+			Failed:   t.Status == evergreen.TaskFailed,
+			Duration: t.TimeTaken,
+			URLPath:  "/task/" + t.Id,
+			task:     t,
+		}
+		return r, errors.WithStack(r.setVersionOwner(t.Version))
+
+	case event.BuildFinished:
+		b, err := build.FindOne(build.ById(e.ResourceId))
+		if err != nil || b == nil {
+			return nil, err
+		}
+		r := &subscriptionResource{
+			Type:     event.ResourceTypeBuild,
+			Id:       b.Id,
+			Name:     fmt.Sprintf("build '%s'", b.DisplayName),
+			Project:  b.Project,
+			Variant:  b.BuildVariant,
+			Status:   b.Status,
+			Failed:   b.Status == evergreen.BuildFailed,
+			Duration: b.TimeTaken,
+			URLPath:  "/build/" + b.Id,
+		}
+		return r, errors.WithStack(r.setVersionOwner(b.Version))
+
+	case event.VersionFinished:
+		v, err := version.FindOne(version.ById(e.ResourceId))
+		if err != nil || v == nil {
+			return nil, err
+		}
+		return &subscriptionResource{
+			Type:       event.ResourceTypeVersion,
+			Id:         v.Id,
+			Name:       fmt.Sprintf("version '%s'", v.Revision),
+			Project:    v.Identifier,
+			Owner:      v.Author,
+			OwnerEmail: v.AuthorEmail,
+			Status:     v.Status,
+			Failed:     v.Status == evergreen.VersionFailed,
+			Duration:   v.FinishTime.Sub(v.StartTime),
+			URLPath:    "/version/" + v.Id,
+		}, nil
+
+	case event.PatchFinished:
+		p, err := patch.FindOne(patch.ByVersion(e.ResourceId))
+		if err != nil || p == nil {
+			return nil, err
+		}
+		return &subscriptionResource{
+			Type:     event.ResourceTypePatch,
+			Id:       p.Id.Hex(),
+			Name:     fmt.Sprintf("patch '%s'", p.Description),
+			Project:  p.Project,
+			Owner:    p.Author,
+			Status:   p.Status,
+			Failed:   p.Status == evergreen.PatchFailed,
+			Duration: p.FinishTime.Sub(p.StartTime),
+			URLPath:  "/patch/" + p.Id.Hex(),
+		}, nil
+
+	case event.EventHostProvisioned, event.EventHostProvisionFailed:
+		h, err := host.FindOne(host.ById(e.ResourceId))
+		if err != nil || h == nil {
+			return nil, err
+		}
+		r := &subscriptionResource{
+			Type:    event.ResourceTypeHost,
+			Id:      h.Id,
+			Name:    fmt.Sprintf("host '%s' of distro '%s'", h.Id, h.Distro.Id),
+			Owner:   h.StartedBy,
+			Status:  "provisioned",
+			URLPath: "/host/" + h.Id,
+		}
+		if e.EventType == event.EventHostProvisionFailed {
+			r.Status = "failed to provision"
+			r.Failed = true
+		}
+		return r, nil
+	}
+
+	return nil, nil
+}
+
+// setVersionOwner sets the owner of a task or build to the author of its
+// version.
+func (r *subscriptionResource) setVersionOwner(versionId string) error {
+	v, err := version.FindOne(version.ById(versionId).WithFields(version.AuthorKey, version.AuthorEmailKey))
+	if err != nil {
+		return errors.Wrapf(err, "error finding version %s", versionId)
+	}
+	if v != nil {
+		r.Owner = v.Author
+		r.OwnerEmail = v.AuthorEmail
+	}
+	return nil
+}
+
+// private returns true if the resource is part of a private project, or of a
+// project that doesn't exist anymore.
+func (r *subscriptionResource) private() (bool, error) {
+	if r.Project == "" {
+		return false, nil
+	}
+	ref, err := model.FindOneProjectRef(r.Project)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	return ref == nil || ref.Private, nil
+}
+
+// ownerIsUser returns true if the owner of the subscription is still a user.
+func ownerIsUser(sub *event.Subscription) (bool, error) {
+	u, err := user.FindOne(user.ById(sub.Owner))
+	if err != nil {
+		return false, errors.Wrapf(err, "error finding user %s", sub.Owner)
+	}
+	return u != nil, nil
+}
+
+// matches returns true if all of the subscription's selectors match the
+// resource.
+func (r *subscriptionResource) matches(sub *event.Subscription) (bool, error) {
+	for _, selector := range sub.Selectors {
+		switch selector.Type {
+		case event.SelectorProject:
+			if r.Project != selector.Data {
+				return false, nil
+			}
+		case event.SelectorVariant:
+			if r.Variant != selector.Data {
+				return false, nil
+			}
+		case event.SelectorTaskTag:
+			if !util.StringSliceContains(r.Tags, selector.Data) {
+				return false, nil
+			}
+		case event.SelectorID:
+			if r.Id != selector.Data {
+				return false, nil
+			}
+		case event.SelectorOwner:
+			if r.Owner == selector.Data {
+				continue
+			}
+			if r.OwnerEmail == "" {
+				return false, nil
+			}
+			// commits are authored by email address, not by user
+			u, err := user.FindOne(user.ById(selector.Data))
+			if err != nil {
+				return false, errors.Wrapf(err, "error finding user %s", selector.Data)
+			}
+			if u == nil || u.Email() != r.OwnerEmail {
+				return false, nil
+			}
+		default:
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// triggers returns true if the subscription's trigger fires for the
+// resource.
+func (r *subscriptionResource) triggers(sub *event.Subscription) (bool, error) {
+	switch sub.Trigger {
+	case event.TriggerOutcome:
+		return true, nil
+	case event.TriggerFailure:
+		return r.Failed, nil
+	case event.TriggerExceedsDuration:
+		return r.Duration > time.Duration(sub.DurationSecs)*time.Second, nil
+	case event.TriggerRegression:
+		if !r.Failed || r.task == nil {
+			return false, nil
+		}
+		previous, err := r.task.PreviousCompletedTask(r.task.Project, []string{})
+		if err != nil {
+			return false, errors.Wrapf(err, "error finding previous task of %s", r.task.Id)
+		}
+		return previous != nil && previous.Status == evergreen.TaskSucceeded, nil
+	case event.TriggerFirstFailure:
+		if !r.Failed || r.task == nil {
+			return false, nil
+		}
+		failed, err := task.Count(db.Query(bson.M{
+			task.VersionKey: r.task.Version,
+			task.StatusKey:  evergreen.TaskFailed,
+			task.IdKey:      bson.M{"$ne": r.task.Id},
+		}))
+		if err != nil {
+			return false, errors.Wrapf(err, "error counting failed tasks in version %s", r.task.Version)
+		}
+		return failed == 0, nil
+	}
+	return false, nil
+}
+
+func (r *subscriptionResource) subject(sub *event.Subscription) string {
+	switch sub.Trigger {
+	case event.TriggerExceedsDuration:
+		return fmt.Sprintf("[Evergreen] %s took %s", r.Name, r.Duration.String())
+	case event.TriggerRegression:
+		return fmt.Sprintf("[Evergreen] %s transitioned to failure", r.Name)
+	case event.TriggerFirstFailure:
+		return fmt.Sprintf("[Evergreen] %s is the first failure in its version", r.Name)
+	}
+	if r.Project != "" {
+		return fmt.Sprintf("[Evergreen] %s in %s %s", r.Name, r.Project, r.Status)
+	}
+	return fmt.Sprintf("[Evergreen] %s %s", r.Name, r.Status)
+}
+
+// webhookClient posts notifications to webhook subscribers. It doesn't use
+// a proxy, and only connects to public addresses, which are checked after
+// the webhook's host is resolved, so that webhooks can't reach the services
+// on Evergreen's own network, including through redirects.
+var webhookClient = &http.Client{
+	Timeout: time.Minute,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 30 * time.Second,
+			Control: checkWebhookAddress,
+		}).DialContext,
+		DisableKeepAlives:   true,
+		TLSHandshakeTimeout: 10 * time.Second,
+	},
+}
+
+func checkWebhookAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errors.Wrapf(err, "invalid webhook address '%s'", address)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !event.IsPublicWebhookIP(ip) {
+		return errors.Errorf("webhook address '%s' is not public", address)
+	}
+	return nil
+}
+
+func sendSubscriptionNotification(settings *evergreen.Settings, subscriber event.Subscriber, n SubscriptionNotification) error {
+	switch subscriber.Type {
+	case event.SubscriberEmail:
+		body := fmt.Sprintf("<p>%s</p><p><a href=\"%s\">%s</a></p>", n.Subject, n.URL, n.URL)
+		return ConstructMailer(settings.Notify).SendMail([]string{subscriber.Target}, n.Subject, body)
+
+	case event.SubscriberSlack:
+		if settings.Slack.Token == "" {
+			return errors.New("slack is not configured")
+		}
+		sender, err := send.NewSlackLogger(&send.SlackOptions{
+			Channel: subscriber.Target,
+			Name:    "evergreen",
+		}, settings.Slack.Token, send.LevelInfo{Default: level.Notice, Threshold: level.Notice})
+		if err != nil {
+			return errors.Wrap(err, "error setting up slack")
+		}
+		defer sender.Close()
+		sender.Send(message.NewDefaultMessage(level.Notice, fmt.Sprintf("%s: %s", n.Subject, n.URL)))
+		return nil
+
+	case event.SubscriberWebhook:
+		payload, err := json.Marshal(n)
+		if err != nil {
+			return errors.Wrap(err, "error marshaling notification")
+		}
+		resp, err := webhookClient.Post(subscriber.Target, "application/json", bytes.NewReader(payload))
+		if err != nil {
+			return errors.Wrapf(err, "error posting to webhook '%s'", subscriber.Target)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= http.StatusBadRequest {
+			return errors.Errorf("webhook '%s' returned status %d", subscriber.Target, resp.StatusCode)
+		}
+		return nil
+	}
+
+	return errors.Errorf("'%s' is not a valid subscriber type", subscriber.Type)
+}
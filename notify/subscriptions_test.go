@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptionResourceMatches(t *testing.T) {
+	assert := assert.New(t)
+	r := &subscriptionResource{
+		Type:    event.ResourceTypeTask,
+		Id:      "t1",
+		Project: "mci",
+		Variant: "ubuntu",
+		Tags:    []string{"smoke"},
+		Owner:   "me",
+	}
+
+	sub := &event.Subscription{Selectors: []event.Selector{
+		{Type: event.SelectorProject, Data: "mci"},
+		{Type: event.SelectorVariant, Data: "ubuntu"},
+		{Type: event.SelectorTaskTag, Data: "smoke"},
+		{Type: event.SelectorOwner, Data: "me"},
+	}}
+	matched, err := r.matches(sub)
+	assert.NoError(err)
+	assert.True(matched)
+
+	sub.Selectors = append(sub.Selectors, event.Selector{Type: event.SelectorID, Data: "t2"})
+	matched, err = r.matches(sub)
+	assert.NoError(err)
+	assert.False(matched)
+
+	sub.Selectors = []event.Selector{{Type: event.SelectorOwner, Data: "you"}}
+	matched, err = r.matches(sub)
+	assert.NoError(err)
+	assert.False(matched)
+}
+
+func TestSubscriptionResourceTriggers(t *testing.T) {
+	assert := assert.New(t)
+	r := &subscriptionResource{
+		Type:     event.ResourceTypeBuild,
+		Failed:   true,
+		Duration: 2 * time.Minute,
+	}
+
+	for trigger, expected := range map[string]bool{
+		event.TriggerOutcome:      true,
+		event.TriggerFailure:      true,
+		event.TriggerRegression:   false,
+		event.TriggerFirstFailure: false,
+	} {
+		triggered, err := r.triggers(&event.Subscription{Trigger: trigger})
+		assert.NoError(err)
+		assert.Equal(expected, triggered, trigger)
+	}
+
+	triggered, err := r.triggers(&event.Subscription{Trigger: event.TriggerExceedsDuration, DurationSecs: 60})
+	assert.NoError(err)
+	assert.True(triggered)
+	triggered, err = r.triggers(&event.Subscription{Trigger: event.TriggerExceedsDuration, DurationSecs: 600})
+	assert.NoError(err)
+	assert.False(triggered)
+
+	r.Failed = false
+	triggered, err = r.triggers(&event.Subscription{Trigger: event.TriggerFailure})
+	assert.NoError(err)
+	assert.False(triggered)
+}
+
+func TestSendSubscriptionWebhook(t *testing.T) {
+	assert := assert.New(t)
+	received := SubscriptionNotification{}
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(http.MethodPost, r.Method)
+		assert.NoError(json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	subscriber := event.Subscriber{Type: event.SubscriberWebhook, Target: server.URL}
+	n := SubscriptionNotification{
+		SubscriptionId: "sub",
+		ResourceType:   event.ResourceTypeTask,
+		ResourceId:     "t1",
+		Trigger:        event.TriggerFailure,
+		Status:         evergreen.TaskFailed,
+	}
+	// the test server is on a loopback address
+	assert.Error(sendSubscriptionNotification(&evergreen.Settings{}, subscriber, n))
+
+	defaultClient := webhookClient
+	webhookClient = server.Client()
+	defer func() { webhookClient = defaultClient }()
+
+	assert.NoError(sendSubscriptionNotification(&evergreen.Settings{}, subscriber, n))
+	assert.Equal(n, received)
+
+	status = http.StatusInternalServerError
+	assert.Error(sendSubscriptionNotification(&evergreen.Settings{}, subscriber, n))
+}
+
+func TestCheckWebhookAddress(t *testing.T) {
+	assert := assert.New(t)
+	assert.NoError(checkWebhookAddress("tcp", "203.0.113.7:443", nil))
+	assert.NoError(checkWebhookAddress("tcp6", "[2001:4860:4860::8888]:443", nil))
+
+	assert.Error(checkWebhookAddress("tcp", "127.0.0.1:443", nil))
+	assert.Error(checkWebhookAddress("tcp", "10.0.0.1:443", nil))
+	assert.Error(checkWebhookAddress("tcp", "192.168.1.1:443", nil))
+	assert.Error(checkWebhookAddress("tcp", "169.254.169.254:80", nil))
+	assert.Error(checkWebhookAddress("tcp", "0.0.0.0:443", nil))
+	assert.Error(checkWebhookAddress("tcp6", "[::1]:443", nil))
+	assert.Error(checkWebhookAddress("tcp6", "[fd00::1]:443", nil))
+	assert.Error(checkWebhookAddress("tcp", "example.com", nil))
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/evergreen-ci/evergreen/agent"
 	"github.com/evergreen-ci/evergreen/command"
 	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/evergreen-ci/evergreen/tracing"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
@@ -22,6 +23,8 @@ func Agent() cli.Command {
 		logPrefixFlagName        = "log_prefix"
 		statusPortFlagName       = "status_port"
 		cleanupFlagName          = "cleanup"
+		maxConcurrentFlagName    = "max_concurrent_tasks"
+		traceOutputFlagName      = "trace_output"
 	)
 
 	return cli.Command{
@@ -58,6 +61,15 @@ func Agent() cli.Command {
 				Name:  cleanupFlagName,
 				Usage: "clean up working directory and processes (do not set for smoke tests)",
 			},
+			cli.IntFlag{
+				Name:  maxConcurrentFlagName,
+				Value: 1,
+				Usage: "number of tasks to run at once",
+			},
+			cli.StringFlag{
+				Name:  traceOutputFlagName,
+				Usage: "export trace spans to 'stdout' or to a file at this path",
+			},
 		},
 		Before: mergeBeforeFuncs(
 			func(c *cli.Context) error {
@@ -71,18 +83,25 @@ func Agent() cli.Command {
 		),
 		Action: func(c *cli.Context) error {
 			opts := agent.Options{
-				HostID:           c.String(hostIDFlagName),
-				HostSecret:       c.String(hostSecretFlagName),
-				StatusPort:       c.Int(statusPortFlagName),
-				LogPrefix:        c.String(logPrefixFlagName),
-				WorkingDirectory: c.String(workingDirectoryFlagName),
-				Cleanup:          c.Bool(cleanupFlagName),
+				HostID:             c.String(hostIDFlagName),
+				HostSecret:         c.String(hostSecretFlagName),
+				StatusPort:         c.Int(statusPortFlagName),
+				LogPrefix:          c.String(logPrefixFlagName),
+				WorkingDirectory:   c.String(workingDirectoryFlagName),
+				Cleanup:            c.Bool(cleanupFlagName),
+				MaxConcurrentTasks: c.Int(maxConcurrentFlagName),
 			}
 
 			if err := os.MkdirAll(opts.WorkingDirectory, 0777); err != nil {
 				return errors.Wrapf(err, "problem creating working directory '%s'", opts.WorkingDirectory)
 			}
 
+			traceExporter, err := tracing.NewExporter(c.String(traceOutputFlagName))
+			if err != nil {
+				return errors.Wrap(err, "problem configuring trace exporter")
+			}
+			tracing.SetExporter(traceExporter)
+
 			grip.Info(message.Fields{
 				"message":  "starting agent",
 				"commands": command.RegisteredCommandNames(),
@@ -0,0 +1,106 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/template"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+)
+
+var versionComparisonTemplate = template.Must(template.New("compare_versions").Funcs(template.FuncMap{
+	"duration": func(d model.APIDuration) time.Duration { return d.ToDuration().Round(time.Second) },
+	"status": func(s model.APIString) string {
+		if s == "" {
+			return "(none)"
+		}
+		return string(s)
+	},
+}).Parse(`
+Comparing {{.Version}} against base {{.BaseVersion}}
+
+Tasks that changed status ({{len .StatusChanges}}):
+{{range .StatusChanges}}   {{.BuildVariant}} / {{.DisplayName}}: {{status .BaseStatus}} -> {{status .Status}}
+{{end}}
+Tasks with significant duration changes ({{len .DurationChanges}}):
+{{range .DurationChanges}}   {{.BuildVariant}} / {{.DisplayName}}: {{duration .BaseDuration}} -> {{duration .Duration}}
+{{end}}
+Newly failing tests ({{len .NewlyFailing}}):
+{{range .NewlyFailing}}   {{.BuildVariant}} / {{.TaskName}}: {{.TestFile}}
+{{end}}
+Newly passing tests ({{len .NewlyPassing}}):
+{{range .NewlyPassing}}   {{.BuildVariant}} / {{.TaskName}}: {{.TestFile}}
+{{end}}
+Added artifacts ({{len .AddedArtifacts}}):
+{{range .AddedArtifacts}}   {{.BuildVariant}} / {{.TaskName}}: {{.Name}}
+{{end}}
+Removed artifacts ({{len .RemovedArtifacts}}):
+{{range .RemovedArtifacts}}   {{.BuildVariant}} / {{.TaskName}}: {{.Name}}
+{{end}}
+`))
+
+func CompareVersions() cli.Command {
+	const (
+		baseFlagName    = "base"
+		versionFlagName = "version"
+		jsonFlagName    = "json"
+	)
+
+	return cli.Command{
+		Name:  "compare-versions",
+		Usage: "compare the tasks, tests and artifacts of a version or patch with a base version",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  baseFlagName,
+				Usage: "the ID of the version or patch to compare against",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(versionFlagName, "v"),
+				Usage: "the ID of the version or patch to compare",
+			},
+			cli.BoolFlag{
+				Name:  jsonFlagName,
+				Usage: "write the comparison as json",
+			},
+		},
+		Before: mergeBeforeFuncs(
+			requireStringFlag(baseFlagName),
+			requireStringFlag(versionFlagName)),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().String(confFlagName)
+			baseVersionID := c.String(baseFlagName)
+			versionID := c.String(versionFlagName)
+			asJSON := c.Bool(jsonFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSetttings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+
+			comm := conf.GetRestCommunicator(ctx)
+
+			comparison, err := comm.CompareVersions(ctx, baseVersionID, versionID)
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				out, err := json.MarshalIndent(comparison, "", "  ")
+				if err != nil {
+					return errors.Wrap(err, "problem formatting comparison")
+				}
+				fmt.Println(string(out))
+				return nil
+			}
+			return versionComparisonTemplate.Execute(os.Stdout, comparison)
+		},
+	}
+}
@@ -2,7 +2,6 @@ package operations
 
 import (
 	"fmt"
-	"io/ioutil"
 
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/pkg/errors"
@@ -34,9 +33,9 @@ func Evaluate() cli.Command {
 			showTasks := c.Bool(taskFlagName)
 			showVariants := c.Bool(variantsFlagName)
 
-			configBytes, err := ioutil.ReadFile(path)
+			configBytes, err := readLocalConfig(path)
 			if err != nil {
-				return errors.Wrap(err, "error reading project config")
+				return errors.WithStack(err)
 			}
 
 			p := &model.Project{}
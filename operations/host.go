@@ -10,6 +10,8 @@ func Host() cli.Command {
 			hostCreate(),
 			hostlist(),
 			hostTerminate(),
+			hostStop(),
+			hostStart(),
 			hostStatus(),
 			hostSetup(),
 			hostTeardown(),
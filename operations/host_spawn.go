@@ -172,3 +172,79 @@ func hostTerminate() cli.Command {
 		},
 	}
 }
+
+func hostStop() cli.Command {
+	const hostFlagName = "host"
+
+	return cli.Command{
+		Name:  "stop",
+		Usage: "stop a running spawn host, keeping its disks",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  joinFlagNames(hostFlagName, "h"),
+				Usage: "stop the specified host",
+			},
+		},
+		Before: mergeBeforeFuncs(setPlainLogger, requireStringFlag(hostFlagName)),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().String(confFlagName)
+			hostID := c.String(hostFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSetttings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			if err = client.StopSpawnHost(ctx, hostID); err != nil {
+				return errors.Wrap(err, "problem stopping host")
+			}
+
+			grip.Infof("Stopping host '%s'. Run 'evergreen host start --host %s' to start it again.", hostID, hostID)
+
+			return nil
+		},
+	}
+}
+
+func hostStart() cli.Command {
+	const hostFlagName = "host"
+
+	return cli.Command{
+		Name:  "start",
+		Usage: "start a stopped spawn host",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  joinFlagNames(hostFlagName, "h"),
+				Usage: "start the specified host",
+			},
+		},
+		Before: mergeBeforeFuncs(setPlainLogger, requireStringFlag(hostFlagName)),
+		Action: func(c *cli.Context) error {
+			confPath := c.Parent().String(confFlagName)
+			hostID := c.String(hostFlagName)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conf, err := NewClientSetttings(confPath)
+			if err != nil {
+				return errors.Wrap(err, "problem loading configuration")
+			}
+			client := conf.GetRestCommunicator(ctx)
+			defer client.Close()
+
+			if err = client.StartSpawnHost(ctx, hostID); err != nil {
+				return errors.Wrap(err, "problem starting host")
+			}
+
+			grip.Infof("Started host '%s'. It has a new host name, which the hosts page shows once it is up.", hostID)
+
+			return nil
+		},
+	}
+}
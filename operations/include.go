@@ -0,0 +1,90 @@
+package operations
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/pkg/errors"
+)
+
+// readLocalConfig reads a project configuration file, and merges the files
+// that it includes into it.
+func readLocalConfig(path string) ([]byte, error) {
+	configBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading project config")
+	}
+
+	root, err := localRepoRoot(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	configBytes, err = model.ResolveIncludes(configBytes, localIncludeFetcher(root))
+	if err != nil {
+		return nil, errors.Wrap(err, "error resolving project config includes")
+	}
+	return configBytes, nil
+}
+
+// localRepoRoot returns the root of the git repository that holds the
+// configuration file, or the file's directory if it is not in one.
+func localRepoRoot(path string) (string, error) {
+	dir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return "", errors.Wrap(err, "problem finding config directory")
+	}
+
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return dir, nil
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// localIncludeFetcher returns an IncludeFetcher that reads the files of the
+// project's repository from the local checkout, and fetches the files of
+// modules at the included ref with git.
+func localIncludeFetcher(root string) model.IncludeFetcher {
+	return func(inc model.Include, module *model.Module) ([]byte, error) {
+		if module == nil {
+			return ioutil.ReadFile(filepath.Join(root, inc.FileName))
+		}
+		return fetchModuleFile(module.Repo, inc.Ref, inc.FileName)
+	}
+}
+
+// fetchModuleFile returns the contents of a file of a repository at the
+// given ref, by fetching the ref into a scratch repository.
+func fetchModuleFile(repo, ref, fileName string) ([]byte, error) {
+	dir, err := ioutil.TempDir("", "evg-include-")
+	if err != nil {
+		return nil, errors.Wrap(err, "problem creating scratch repository")
+	}
+	defer os.RemoveAll(dir)
+
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"fetch", "--quiet", "--depth", "1", repo, ref},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return nil, errors.Wrapf(err, "problem fetching '%s' at '%s': %s", repo, ref, out)
+		}
+	}
+
+	cmd := exec.Command("git", "show", "FETCH_HEAD:"+fileName)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem reading '%s' from '%s' at '%s'", fileName, repo, ref)
+	}
+	return out, nil
+}
@@ -3,7 +3,6 @@ package operations
 import (
 	"context"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"sort"
 	"text/tabwriter"
@@ -289,9 +288,9 @@ func listDistros(ctx context.Context, confPath string, onlyUserSpawnable bool) e
 
 // LoadLocalConfig loads the local project config into a project
 func loadLocalConfig(filepath string) (*model.Project, error) {
-	configBytes, err := ioutil.ReadFile(filepath)
+	configBytes, err := readLocalConfig(filepath)
 	if err != nil {
-		return nil, errors.Wrap(err, "error reading project config")
+		return nil, errors.WithStack(err)
 	}
 
 	project := &model.Project{}
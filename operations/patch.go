@@ -83,7 +83,7 @@ func Patch() cli.Command {
 				return err
 			}
 
-			return params.createPatch(ac, conf, diffData)
+			return params.createPatch(ctx, comm, conf, diffData)
 		},
 	}
 }
@@ -151,7 +151,7 @@ func PatchFile() cli.Command {
 
 			diffData := &localDiff{string(fullPatch), "", "", base}
 
-			return params.createPatch(ac, conf, diffData)
+			return params.createPatch(ctx, comm, conf, diffData)
 		},
 	}
 }
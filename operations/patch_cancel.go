@@ -26,7 +26,7 @@ func PatchCancel() cli.Command {
 				return errors.Wrap(err, "problem loading configuration")
 			}
 
-			_ = conf.GetRestCommunicator(ctx)
+			comm := conf.GetRestCommunicator(ctx)
 
 			ac, _, err := conf.getLegacyClients()
 			if err != nil {
@@ -35,7 +35,7 @@ func PatchCancel() cli.Command {
 
 			notifyUserUpdate(ac)
 
-			if err = ac.CancelPatch(patchID); err != nil {
+			if err = comm.AbortPatch(ctx, patchID); err != nil {
 				return err
 			}
 
@@ -26,7 +26,7 @@ func PatchFinalize() cli.Command {
 				return errors.Wrap(err, "problem loading configuration")
 			}
 
-			_ = conf.GetRestCommunicator(ctx)
+			comm := conf.GetRestCommunicator(ctx)
 
 			ac, _, err := conf.getLegacyClients()
 			if err != nil {
@@ -35,7 +35,7 @@ func PatchFinalize() cli.Command {
 
 			notifyUserUpdate(ac)
 
-			if err = ac.FinalizePatch(patchID); err != nil {
+			if err = comm.FinalizePatch(ctx, patchID); err != nil {
 				return err
 			}
 
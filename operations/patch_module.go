@@ -41,7 +41,7 @@ func PatchSetModule() cli.Command {
 				return errors.Wrap(err, "problem loading configuration")
 			}
 
-			_ = conf.GetRestCommunicator(ctx)
+			comm := conf.GetRestCommunicator(ctx)
 
 			ac, rc, err := conf.getLegacyClients()
 			if err != nil {
@@ -90,7 +90,7 @@ func PatchSetModule() cli.Command {
 				}
 			}
 
-			err = ac.UpdatePatchModule(patchID, module, diffData.fullPatch, diffData.base)
+			err = comm.SetPatchModule(ctx, patchID, module, diffData.base, diffData.fullPatch)
 			if err != nil {
 				mods, err := ac.GetPatchModules(patchID, project)
 				var msg string
@@ -135,7 +135,7 @@ func PatchRemoveModule() cli.Command {
 				return errors.Wrap(err, "problem loading configuration")
 			}
 
-			_ = conf.GetRestCommunicator(ctx)
+			comm := conf.GetRestCommunicator(ctx)
 
 			ac, _, err := conf.getLegacyClients()
 			if err != nil {
@@ -144,7 +144,7 @@ func PatchRemoveModule() cli.Command {
 
 			notifyUserUpdate(ac)
 
-			err = ac.DeletePatchModule(patchID, module)
+			err = comm.RemovePatchModule(ctx, patchID, module)
 			if err != nil {
 				return err
 			}
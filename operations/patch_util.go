@@ -13,6 +13,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/rest/client"
+	restmodel "github.com/evergreen-ci/evergreen/rest/model"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/pkg/errors"
 )
@@ -27,7 +28,9 @@ var patchDisplayTemplate = template.Must(template.New("patch").Parse(`
     Description : {{if .Patch.Description}}{{.Patch.Description}}{{else}}<none>{{end}}
 	  Build : {{.Link}}
       Finalized : {{if .Patch.Activated}}Yes{{else}}No{{end}}
-{{if .ShowSummary}}
+{{if .ETA}}	    ETA : {{.ETA}}
+{{end}}{{if .Patch.QueuePosition}} Queue Position : {{.Patch.QueuePosition}}
+{{end}}{{if .ShowSummary}}
 	Summary :
 {{range .Patch.Patches}}{{if not (eq .ModuleName "") }}Module:{{.ModuleName}}{{end}}
 	Base Commit : {{.Githash}}
@@ -67,7 +70,7 @@ type patchSubmission struct {
 	finalize    bool
 }
 
-func (p *patchParams) createPatch(ac *legacyClient, conf *ClientSettings, diffData *localDiff) error {
+func (p *patchParams) createPatch(ctx context.Context, comm client.Communicator, conf *ClientSettings, diffData *localDiff) error {
 	if err := validatePatchSize(diffData, p.Large); err != nil {
 		return err
 	}
@@ -86,23 +89,26 @@ func (p *patchParams) createPatch(ac *legacyClient, conf *ClientSettings, diffDa
 		}
 	}
 
-	variantsStr := strings.Join(p.Variants, ",")
-	patchSub := patchSubmission{
-		projectId:   p.Project,
-		patchData:   diffData.fullPatch,
-		description: p.Description,
-		base:        diffData.base,
-		variants:    variantsStr,
-		tasks:       p.Tasks,
-		finalize:    p.Finalize,
-		alias:       p.Alias,
-	}
-
-	newPatch, err := ac.PutPatch(patchSub)
+	apiPatch, err := comm.CreatePatch(ctx, &restmodel.PatchCreateRequest{
+		Project:     p.Project,
+		Githash:     diffData.base,
+		Patch:       diffData.fullPatch,
+		Description: p.Description,
+		Variants:    p.Variants,
+		Tasks:       p.Tasks,
+		Alias:       p.Alias,
+		Finalize:    p.Finalize,
+	})
 	if err != nil {
 		return err
 	}
-	patchDisp, err := getPatchDisplay(newPatch, p.ShowSummary, conf.UIServerHost)
+	servicePatch, err := apiPatch.ToService()
+	if err != nil {
+		return errors.Wrap(err, "problem reading patch")
+	}
+	newPatch := servicePatch.(patch.Patch)
+
+	patchDisp, err := getPatchDisplay(&newPatch, p.ShowSummary, conf.UIServerHost)
 	if err != nil {
 		return err
 	}
@@ -244,16 +250,27 @@ func getPatchDisplay(p *patch.Patch, summarize bool, uiHost string) (string, err
 		url = uiHost + "/patch/" + p.Id.Hex()
 	}
 
+	now := time.Now()
+	var eta string
+	if !p.EstimatedFinish.IsZero() {
+		eta = p.EstimatedFinish.Local().Format(time.RFC1123)
+		if until := p.EstimatedFinish.Sub(now); until > 0 {
+			eta += fmt.Sprintf(" (in %s)", until.Round(time.Minute))
+		}
+	}
+
 	err := patchDisplayTemplate.Execute(&out, struct {
 		Patch       *patch.Patch
 		ShowSummary bool
 		Link        string
 		Now         time.Time
+		ETA         string
 	}{
 		Patch:       p,
 		ShowSummary: summarize,
 		Link:        url,
-		Now:         time.Now(),
+		Now:         now,
+		ETA:         eta,
 	})
 	if err != nil {
 		return "", err
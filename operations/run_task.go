@@ -0,0 +1,186 @@
+package operations
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/agent"
+	"github.com/evergreen-ci/evergreen/apimodels"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/rest/client"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const localTaskID = "local"
+
+func RunTask() cli.Command {
+	const (
+		variantFlagName    = "variant"
+		taskFlagName       = "task"
+		expansionsFlagName = "expansions"
+		outputFlagName     = "output"
+		keepFlagName       = "keep"
+	)
+
+	return cli.Command{
+		Name:  "run-task",
+		Usage: "run a task from a project configuration on this machine",
+		Flags: addPathFlag(
+			cli.StringFlag{
+				Name:  joinFlagNames(variantFlagName, "v"),
+				Usage: "build variant to run the task on",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(taskFlagName, "t"),
+				Usage: "name of the task to run",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(expansionsFlagName, "e"),
+				Usage: "path to a YAML file of expansions for the task",
+			},
+			cli.StringFlag{
+				Name:  joinFlagNames(outputFlagName, "o"),
+				Value: "evergreen-output",
+				Usage: "directory to save the task's logs, test results and artifacts",
+			},
+			cli.BoolFlag{
+				Name:  keepFlagName,
+				Usage: "keep the scratch directory the task ran in",
+			}),
+		Before: mergeBeforeFuncs(
+			requirePathFlag,
+			requireStringFlag(variantFlagName),
+			requireStringFlag(taskFlagName),
+		),
+		Action: func(c *cli.Context) error {
+			path := c.String(pathFlagName)
+			variant := c.String(variantFlagName)
+			taskName := c.String(taskFlagName)
+			expansionsPath := c.String(expansionsFlagName)
+			outputDir := c.String(outputFlagName)
+
+			comm, err := newLocalTaskCommunicator(path, variant, taskName, expansionsPath, outputDir)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			scratchDir, err := ioutil.TempDir("", "evg-run-task-")
+			if err != nil {
+				return errors.Wrap(err, "problem creating scratch directory")
+			}
+			if c.Bool(keepFlagName) {
+				grip.Infof("running task in scratch directory %s", scratchDir)
+			} else {
+				defer os.RemoveAll(scratchDir)
+			}
+			comm.Distro = &distro.Distro{
+				Id:      localTaskID,
+				WorkDir: scratchDir,
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			agt := agent.New(agent.Options{
+				HostID:           localTaskID,
+				WorkingDirectory: scratchDir,
+				LogPrefix:        evergreen.LocalLoggingOverride,
+			}, comm)
+			if err = agt.RunTask(ctx, client.TaskData{ID: comm.Task.Id, Secret: comm.Task.Secret}); err != nil {
+				return errors.Wrap(err, "problem running task")
+			}
+
+			detail := comm.GetEndTaskDetail()
+			if detail == nil {
+				return errors.New("task did not finish")
+			}
+			fmt.Printf("Task %s on %s finished with status '%s'; output is in %s\n",
+				taskName, variant, detail.Status, outputDir)
+			if detail.Status != evergreen.TaskSucceeded {
+				return errors.Errorf("task failed in command %s", detail.Description)
+			}
+			return nil
+		},
+	}
+}
+
+// newLocalTaskCommunicator loads the project configuration and expansions,
+// and returns a local communicator that serves the task on the variant.
+func newLocalTaskCommunicator(path, variant, taskName, expansionsPath, outputDir string) (*client.Local, error) {
+	configBytes, err := readLocalConfig(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	project := &model.Project{}
+	if err = model.LoadProjectInto(configBytes, "", project); err != nil {
+		return nil, errors.Wrap(err, "error loading project")
+	}
+	if project.FindBuildVariant(variant) == nil {
+		return nil, errors.Errorf("build variant '%s' is not in the project", variant)
+	}
+	if project.FindTaskForVariant(taskName, variant) == nil {
+		return nil, errors.Errorf("task '%s' is not run on build variant '%s'", taskName, variant)
+	}
+
+	expansions := apimodels.ExpansionVars{}
+	if expansionsPath != "" {
+		var data []byte
+		data, err = ioutil.ReadFile(expansionsPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading expansions file")
+		}
+		if err = yaml.Unmarshal(data, &expansions); err != nil {
+			return nil, errors.Wrap(err, "error parsing expansions file")
+		}
+	}
+
+	outputDir, err = filepath.Abs(outputDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem finding output directory")
+	}
+	if err = os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, errors.Wrap(err, "problem creating output directory")
+	}
+
+	comm := client.NewLocal(outputDir)
+	comm.Expansions = expansions
+	comm.Task = &task.Task{
+		Id:           fmt.Sprintf("%s_%s_%s", localTaskID, variant, taskName),
+		Secret:       localTaskID,
+		DisplayName:  taskName,
+		BuildVariant: variant,
+		Project:      project.Identifier,
+		Version:      localTaskID,
+		Requester:    evergreen.RepotrackerVersionRequester,
+	}
+	comm.Version = &version.Version{
+		Id:         localTaskID,
+		Identifier: project.Identifier,
+		Config:     string(configBytes),
+		Branch:     project.Branch,
+		Requester:  evergreen.RepotrackerVersionRequester,
+		CreateTime: time.Now(),
+	}
+	comm.ProjectRef = &model.ProjectRef{
+		Identifier: project.Identifier,
+		Owner:      project.Owner,
+		Repo:       project.Repo,
+		Branch:     project.Branch,
+		RepoKind:   project.RepoKind,
+		RemotePath: project.RemotePath,
+	}
+
+	return comm, nil
+}
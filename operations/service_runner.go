@@ -19,6 +19,7 @@ import (
 	"github.com/evergreen-ci/evergreen/scheduler"
 	"github.com/evergreen-ci/evergreen/service"
 	"github.com/evergreen-ci/evergreen/taskrunner"
+	"github.com/evergreen-ci/evergreen/tracing"
 	"github.com/evergreen-ci/evergreen/units"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/amboy"
@@ -98,6 +99,10 @@ func startRunnerService() cli.Command {
 			defer recovery.LogStackTraceAndExit("evergreen runner")
 			defer cancel()
 
+			traceExporter, err := tracing.NewExporter(settings.Tracing.Output)
+			grip.CatchEmergencyFatal(errors.Wrap(err, "problem configuring trace exporter"))
+			tracing.SetExporter(traceExporter)
+
 			grip.Notice(message.Fields{"build": evergreen.BuildRevision, "process": grip.Name()})
 
 			startCollectorJobs(ctx, env)
@@ -135,6 +140,8 @@ func startCollectorJobs(ctx context.Context, env evergreen.Environment) {
 		catcher.Add(queue.Put(units.NewHostStatsCollector(fmt.Sprintf("host-stats-%d", ts))))
 		catcher.Add(queue.Put(units.NewTaskStatsCollector(fmt.Sprintf("task-stats-%d", ts))))
 		catcher.Add(queue.Put(units.NewLatencyStatsCollector(fmt.Sprintf("latency-stats-%d", ts), time.Minute)))
+		catcher.Add(queue.Put(units.NewPeriodicBuildsJob(time.Now())))
+		catcher.Add(queue.Put(units.NewEventNotifierJob(fmt.Sprintf("%d", ts))))
 
 		return catcher.Resolve()
 	})
@@ -14,6 +14,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/service"
+	"github.com/evergreen-ci/evergreen/tracing"
 	"github.com/evergreen-ci/evergreen/units"
 	"github.com/evergreen-ci/render"
 	"github.com/gorilla/csrf"
@@ -89,6 +90,12 @@ func startWebService() cli.Command {
 				nrgorilla.InstrumentRoutes(router, newRelic)
 			}
 
+			traceExporter, err := tracing.NewExporter(settings.Tracing.Output)
+			if err != nil {
+				return errors.Wrap(err, "problem configuring trace exporter")
+			}
+			tracing.SetExporter(traceExporter)
+
 			catcher := grip.NewBasicCatcher()
 			apiWait := make(chan struct{})
 			go func() {
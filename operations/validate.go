@@ -3,7 +3,6 @@ package operations
 import (
 	"context"
 	"fmt"
-	"io/ioutil"
 
 	"github.com/evergreen-ci/evergreen/validator"
 	"github.com/pkg/errors"
@@ -37,7 +36,7 @@ func Validate() cli.Command {
 
 			notifyUserUpdate(ac)
 
-			confFile, err := ioutil.ReadFile(path)
+			confFile, err := readLocalConfig(path)
 			if err != nil {
 				return err
 			}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/artifact"
 	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/plugin"
@@ -46,18 +47,7 @@ func (self *AttachPlugin) Configure(map[string]interface{}) error {
 
 // stripHiddenFiles is a helper for only showing users the files they are allowed to see.
 func stripHiddenFiles(files []artifact.File, pluginUser *user.DBUser) []artifact.File {
-	publicFiles := []artifact.File{}
-	for _, file := range files {
-		switch {
-		case file.Visibility == artifact.None:
-			continue
-		case file.Visibility == artifact.Private && pluginUser == nil:
-			continue
-		default:
-			publicFiles = append(publicFiles, file)
-		}
-	}
-	return publicFiles
+	return artifact.StripHiddenFiles(files, pluginUser != nil)
 }
 
 // GetPanelConfig returns a plugin.PanelConfig struct representing panels
@@ -93,7 +83,12 @@ func (self *AttachPlugin) GetPanelConfig() (*plugin.PanelConfig, error) {
 							return nil, nil
 						}
 					}
-					return stripHiddenFiles(artifactEntry.Files, context.User), nil
+					files, err := model.SignArtifactLinks(taskId, stripHiddenFiles(artifactEntry.Files, context.User),
+						context.ProjectRef, context.User, &context.Settings)
+					if err != nil {
+						return nil, errors.Wrap(err, "error signing artifact links for task")
+					}
+					return files, nil
 				},
 			},
 			{
@@ -111,7 +106,12 @@ func (self *AttachPlugin) GetPanelConfig() (*plugin.PanelConfig, error) {
 					}
 					for i := range taskArtifactFiles {
 						// remove hidden files if the user isn't logged in
-						taskArtifactFiles[i].Files = stripHiddenFiles(taskArtifactFiles[i].Files, context.User)
+						taskArtifactFiles[i].Files, err = model.SignArtifactLinks(taskArtifactFiles[i].TaskId,
+							stripHiddenFiles(taskArtifactFiles[i].Files, context.User),
+							context.ProjectRef, context.User, &context.Settings)
+						if err != nil {
+							return nil, errors.Wrap(err, "error signing artifact links for build")
+						}
 					}
 					return taskArtifactFiles, nil
 				},
@@ -323,9 +323,11 @@ mciModule.controller('DistrosCtrl', function($scope, $window, $location, mciDist
         'setup': $scope.activeDistro.setup,
         'pool_size': $scope.activeDistro.pool_size,
         'setup_as_sudo' : $scope.activeDistro.setup_as_sudo,
+        'max_concurrent_tasks': $scope.activeDistro.max_concurrent_tasks,
 
       }
       newDistro.settings = _.clone($scope.activeDistro.settings);
+      newDistro.containers = _.clone($scope.activeDistro.containers);
       newDistro.expansions = _.clone($scope.activeDistro.expansions);
 
       $scope.distros.unshift(newDistro);
@@ -337,6 +339,15 @@ mciModule.controller('DistrosCtrl', function($scope, $window, $location, mciDist
     }
 
 
+  $scope.toggleContainers = function() {
+    if ($scope.activeDistro.containers) {
+      // null rather than deleting, so that saving clears the settings
+      $scope.activeDistro.containers = null;
+    } else {
+      $scope.activeDistro.containers = {'image': '', 'slots': 1};
+    }
+  };
+
   $scope.openConfirmationModal = function(option) {
     $scope.confirmationOption = option;
     $scope.modalTitle = 'Configuration';
@@ -112,6 +112,19 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location)
     $scope.isDirty = true;
   }
 
+  // addCommentCommandUser adds a GitHub username to the users who can
+  // control pull request patches with comments
+  $scope.addCommentCommandUser = function(){
+    $scope.settingsFormData.comment_command_users.push($scope.comment_command_user);
+    $scope.comment_command_user = "";
+  }
+
+  // removeCommentCommandUser removes the GitHub username located at index
+  $scope.removeCommentCommandUser = function(index){
+    $scope.settingsFormData.comment_command_users.splice(index, 1);
+    $scope.isDirty = true;
+  }
+
 
   $scope.addProject = function() {
     $scope.modalOpen = false;
@@ -196,6 +209,10 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location)
           alert_config: $scope.projectRef.alert_config || {},
           repotracker_error: $scope.projectRef.repotracker_error || {},
           admins : $scope.projectRef.admins || [],
+          triggers: $scope.projectRef.triggers || [],
+          periodic_builds: $scope.projectRef.periodic_builds || [],
+          git_tag_versions: $scope.projectRef.git_tag_versions || [],
+          comment_command_users: $scope.projectRef.comment_command_users || [],
           setup_github_hook: $scope.githubHookId != 0,
         };
         for (var i = 0; i < $scope.settingsFormData.patch_aliases.length; i++) {
@@ -204,6 +221,11 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location)
             alias.tags_temp = alias.tags.join(',');
           }
         }
+        for (var i = 0; i < $scope.settingsFormData.periodic_builds.length; i++) {
+          var periodicBuild = $scope.settingsFormData.periodic_builds[i];
+          periodicBuild.build_variants_temp = (periodicBuild.build_variants || []).join(',');
+          periodicBuild.tasks_temp = (periodicBuild.tasks || []).join(',');
+        }
 
         $scope.displayName = $scope.projectRef.display_name ? $scope.projectRef.display_name : $scope.projectRef.identifier;
         $location.hash($scope.projectRef.identifier);
@@ -258,6 +280,20 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location)
         return;
       }
     }
+    if ($scope.trigger) {
+      $scope.addTrigger();
+    }
+    if ($scope.periodic_build) {
+      $scope.addPeriodicBuild();
+    }
+    if ($scope.git_tag_version) {
+      $scope.addGitTagVersion();
+    }
+    for (var i = 0; i < $scope.settingsFormData.periodic_builds.length; i++) {
+      var periodicBuild = $scope.settingsFormData.periodic_builds[i];
+      periodicBuild.build_variants = splitNames(periodicBuild.build_variants_temp);
+      periodicBuild.tasks = splitNames(periodicBuild.tasks_temp);
+    }
     if ($scope.patch_alias) {
       $scope.addPatchAlias();
     }
@@ -271,6 +307,9 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location)
     if ($scope.admin_name) {
       $scope.addAdmin();
     }
+    if ($scope.comment_command_user) {
+      $scope.addCommentCommandUser();
+    }
     $http.post('/project/' + $scope.settingsFormData.identifier, $scope.settingsFormData).then(
       function(resp) {
         var data = resp.data;
@@ -345,6 +384,55 @@ mciModule.controller('ProjectCtrl', function($scope, $window, $http, $location)
     $scope.isDirty = true;
   };
 
+  $scope.triggerLevels = ["version", "build", "task"];
+  $scope.triggerStatuses = ["", "success", "failed"];
+
+  $scope.addTrigger = function() {
+    if ($scope.trigger.project && $scope.trigger.level) {
+      $scope.settingsFormData.triggers = $scope.settingsFormData.triggers.concat([$scope.trigger]);
+      $scope.trigger = {};
+      $scope.isDirty = true;
+    }
+  };
+
+  $scope.removeTrigger = function(i) {
+    $scope.settingsFormData.triggers.splice(i, 1);
+    $scope.isDirty = true;
+  };
+
+  $scope.addPeriodicBuild = function() {
+    if ($scope.periodic_build.id && $scope.periodic_build.cron) {
+      $scope.settingsFormData.periodic_builds = $scope.settingsFormData.periodic_builds.concat([$scope.periodic_build]);
+      $scope.periodic_build = {};
+      $scope.isDirty = true;
+    }
+  };
+
+  $scope.removePeriodicBuild = function(i) {
+    $scope.settingsFormData.periodic_builds.splice(i, 1);
+    $scope.isDirty = true;
+  };
+
+  $scope.addGitTagVersion = function() {
+    if ($scope.git_tag_version.pattern && $scope.git_tag_version.alias) {
+      $scope.settingsFormData.git_tag_versions = $scope.settingsFormData.git_tag_versions.concat([$scope.git_tag_version]);
+      $scope.git_tag_version = {};
+      $scope.isDirty = true;
+    }
+  };
+
+  $scope.removeGitTagVersion = function(i) {
+    $scope.settingsFormData.git_tag_versions.splice(i, 1);
+    $scope.isDirty = true;
+  };
+
+  // splitNames turns a comma separated list of names into an array
+  var splitNames = function(names) {
+    return _.filter(_.map((names || '').split(','), function(name) {
+      return name.trim();
+    }));
+  };
+
   $scope.$watch("settingsForm.$dirty", function(dirty) {
     if (dirty){
       $scope.saveMessage = "You have unsaved changes.";
@@ -353,3 +353,26 @@ mciServices.rest.factory('mciAdminRestService', ['mciBaseRestService', function(
 
     return service;
 }]);
+
+mciServices.rest.factory('mciSubscriptionsRestService', ['mciBaseRestService', function(baseSvc) {
+    var resource = mciServices.rest.RestV2Resource("subscriptions");
+
+    var service = {};
+
+    service.getSubscriptions = function(callbacks) {
+      baseSvc.getResource(resource, [], {}, callbacks);
+    }
+
+    service.saveSubscription = function(subscription, callbacks) {
+      var config = {
+          data: subscription
+      };
+      baseSvc.postResource(resource, [], config, callbacks);
+    }
+
+    service.deleteSubscription = function(subscriptionId, callbacks) {
+      baseSvc.deleteResource(resource, [subscriptionId], {}, callbacks);
+    }
+
+    return service;
+}]);
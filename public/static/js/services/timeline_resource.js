@@ -25,10 +25,11 @@ mciModule.factory('$timeline', function($http, $filter) {
     });
   };
 
-  data.loadPage = function(project, limit, skip) {
+  data.loadPage = function(project, limit, skip, requester) {
     var params = {
       limit: limit,
-      skip: skip
+      skip: skip,
+      requester: requester
     };
 
     data.Versions = [];
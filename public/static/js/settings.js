@@ -1,4 +1,4 @@
-mciModule.controller('SettingsCtrl', ['$scope', '$http', '$window', 'notificationService', function($scope, $http, $window, notifier) {
+mciModule.controller('SettingsCtrl', ['$scope', '$http', '$window', 'notificationService', 'mciSubscriptionsRestService', function($scope, $http, $window, notifier, subscriptionsSvc) {
   $scope.timezones = [
     {str: "American Samoa, Niue", value: "Pacific/Niue"},
     {str: "Hawaii", value: "Pacific/Tahiti"},
@@ -45,6 +45,7 @@ mciModule.controller('SettingsCtrl', ['$scope', '$http', '$window', 'notificatio
   $scope.user_tz = $window.user_tz;
   $scope.new_tz = $scope.user_tz || "America/New_York";
   $scope.new_waterfall = $window.new_waterfall;
+  $scope.new_auto_stop_hours = $window.spawn_host_auto_stop_hours || null;
   $scope.userConf = $window.userConf;
   $scope.binaries = $window.binaries;
 
@@ -63,8 +64,8 @@ mciModule.controller('SettingsCtrl', ['$scope', '$http', '$window', 'notificatio
       });
   }
 
-  $scope.updateUserSettings = function(new_tz, new_waterfall) {
-    data = {timezone: new_tz, new_waterfall: new_waterfall};
+  $scope.updateUserSettings = function(new_tz, new_waterfall, new_auto_stop_hours) {
+    data = {timezone: new_tz, new_waterfall: new_waterfall, spawn_host_auto_stop_hours: new_auto_stop_hours || 0};
     $http.put('/settings/', data).then(
       function(resp) {
         window.location.reload()
@@ -73,4 +74,85 @@ mciModule.controller('SettingsCtrl', ['$scope', '$http', '$window', 'notificatio
         notifier.pushNotification("Failed to save changes: " + resp.data.error,'errorHeader');
       });
    };
+
+  $scope.resourceTypes = ["VERSION", "PATCH", "BUILD", "TASK", "HOST"];
+  $scope.triggers = {
+    "VERSION": ["outcome", "failure", "exceeds-duration"],
+    "PATCH": ["outcome", "failure", "exceeds-duration"],
+    "BUILD": ["outcome", "failure", "exceeds-duration"],
+    "TASK": ["outcome", "failure", "regression", "first-failure", "exceeds-duration"],
+    "HOST": ["outcome", "failure"],
+  };
+  $scope.selectorTypes = {
+    "VERSION": ["project", "owner", "id"],
+    "PATCH": ["project", "owner", "id"],
+    "BUILD": ["project", "variant", "owner", "id"],
+    "TASK": ["project", "variant", "task-tag", "owner", "id"],
+    "HOST": ["owner", "id"],
+  };
+  $scope.subscriberTypes = ["email", "slack", "webhook"];
+  $scope.subscriptions = [];
+
+  $scope.resetNewSubscription = function() {
+    $scope.newSubscription = {
+      resource_type: "TASK",
+      trigger: "failure",
+      selectors: [{type: "project", data: ""}, {type: "owner", data: ""}],
+      subscriber: {type: "email", target: ""},
+    };
+  };
+  $scope.resetNewSubscription();
+
+  $scope.describeSelectors = function(subscription) {
+    return _.map(subscription.selectors, function(selector) {
+      return selector.type + "=" + selector.data;
+    }).join(", ");
+  };
+
+  $scope.loadSubscriptions = function() {
+    subscriptionsSvc.getSubscriptions({
+      success: function(resp) {
+        $scope.subscriptions = resp.data;
+      },
+      error: function(resp) {
+        notifier.pushNotification("Failed to load subscriptions: " + resp.data.error, 'errorHeader');
+      }
+    });
+  };
+  $scope.loadSubscriptions();
+
+  $scope.addSelector = function() {
+    $scope.newSubscription.selectors.push({type: $scope.selectorTypes[$scope.newSubscription.resource_type][0], data: ""});
+  };
+
+  $scope.removeSelector = function(index) {
+    $scope.newSubscription.selectors.splice(index, 1);
+  };
+
+  $scope.saveSubscription = function() {
+    var subscription = angular.copy($scope.newSubscription);
+    if (subscription.duration_secs) {
+      subscription.duration_secs = parseInt(subscription.duration_secs);
+    }
+    subscriptionsSvc.saveSubscription(subscription, {
+      success: function(resp) {
+        $scope.resetNewSubscription();
+        $scope.loadSubscriptions();
+      },
+      error: function(resp) {
+        notifier.pushNotification("Failed to save subscription: " + resp.data.error, 'errorHeader');
+      }
+    });
+  };
+
+  $scope.deleteSubscription = function(subscription) {
+    subscriptionsSvc.deleteSubscription(subscription.id, {
+      success: function(resp) {
+        $scope.loadSubscriptions();
+      },
+      error: function(resp) {
+        notifier.pushNotification("Failed to delete subscription: " + resp.data.error, 'errorHeader');
+      }
+    });
+  };
 }]);
@@ -5,9 +5,24 @@ mciModule.controller('TimelineController', function($scope, $timeline, $window,
   $scope.currentPage = $location.search()['page'] || 0;
   $scope.numPerPage = 10;
 
+  // the kinds of versions that the timeline can show
+  $scope.requesters = [
+    {value: 'gitter_request', label: 'Commits'},
+    {value: 'trigger_request', label: 'Triggered versions'},
+    {value: 'periodic_build_request', label: 'Periodic builds'},
+    {value: 'git_tag', label: 'Git tags'},
+  ];
+  $scope.requester = $location.search()['requester'] || 'gitter_request';
+
   $scope.loadCurrentPage = function() {
-    $timeline.loadPage($window.project, $scope.numPerPage, $scope.currentPage);
+    $timeline.loadPage($window.project, $scope.numPerPage, $scope.currentPage, $scope.requester);
     $location.search('page', $scope.currentPage);
+    $location.search('requester', $scope.requester);
+  };
+
+  $scope.setRequester = function(requester) {
+    $scope.requester = requester;
+    $scope.firstPage();
   };
 
   $scope.loadCurrentPage();
@@ -0,0 +1,103 @@
+package repotracker
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// gitTagPollWindow bounds the age of the commits whose tags the repotracker
+// builds when it polls, so that enabling git tag versions does not build
+// every old release.
+const gitTagPollWindow = 7 * 24 * time.Hour
+
+// CreateGitTagVersion creates a version of the project for a tag that one of
+// its git tag definitions builds, using the project's config at the tagged
+// revision. The version runs the tasks of the definition's alias, and is
+// activated immediately. It returns nil if the project does not build the
+// tag, or if it already created the tag's version.
+func (repoTracker *RepoTracker) CreateGitTagVersion(tag string, revision model.Revision) (*version.Version, error) {
+	ref := repoTracker.ProjectRef
+	definition := ref.GitTagDefinitionFor(tag)
+	if definition == nil {
+		return nil, nil
+	}
+
+	id := model.GitTagVersionId(ref.Identifier, tag)
+	existing, err := version.FindOne(version.ById(id).WithFields(version.IdKey))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding version %s", id)
+	}
+	if existing != nil {
+		return nil, nil
+	}
+
+	v, project, err := repoTracker.newRevisionVersion(revision, id, evergreen.GitTagRequester)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	v.GitTag = tag
+
+	tasks, err := definition.SelectTasks(project)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err = createBranchTipVersionItems(v, project, tasks); err != nil {
+		return nil, errors.Wrapf(err, "error creating version items for %s in project %s", v.Id, ref.Identifier)
+	}
+
+	grip.Info(message.Fields{
+		"message":  "created version for git tag",
+		"runner":   RunnerName,
+		"project":  ref.Identifier,
+		"version":  v.Id,
+		"revision": v.Revision,
+		"tag":      tag,
+		"pattern":  definition.Pattern,
+		"alias":    definition.Alias,
+	})
+	return v, nil
+}
+
+// FetchGitTagVersions creates versions for the recent tags of the project's
+// repository that it builds, in case their push events were missed. Only
+// tags of commits that the repotracker stored for the project's branch in
+// the last week are built; other tags are only built from push events.
+func (repoTracker *RepoTracker) FetchGitTagVersions() error {
+	ref := repoTracker.ProjectRef
+	tags, err := repoTracker.GetRecentTags()
+	if err != nil {
+		return errors.Wrapf(err, "error fetching tags for %s", ref.Identifier)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	for _, tag := range tags {
+		if ref.GitTagDefinitionFor(tag.Name) == nil {
+			continue
+		}
+
+		tracked, err := version.FindOne(version.ByProjectIdAndRevision(ref.Identifier, tag.Revision))
+		if err != nil {
+			catcher.Add(errors.Wrapf(err, "error finding version of %s for tag %s", tag.Revision, tag.Name))
+			continue
+		}
+		if tracked == nil || time.Since(tracked.CreateTime) > gitTagPollWindow {
+			continue
+		}
+
+		_, err = repoTracker.CreateGitTagVersion(tag.Name, model.Revision{
+			Author:          tracked.Author,
+			AuthorEmail:     tracked.AuthorEmail,
+			RevisionMessage: tracked.Message,
+			Revision:        tracked.Revision,
+			CreateTime:      time.Now(),
+		})
+		catcher.Add(errors.Wrapf(err, "error creating version for tag %s", tag.Name))
+	}
+	return catcher.Resolve()
+}
@@ -77,6 +77,13 @@ func (gRepoPoller *GithubRepositoryPoller) GetRemoteConfig(projectFileRevision s
 		return nil, thirdparty.FileDecodeError{err.Error()}
 	}
 
+	// merge the files that the configuration includes at the same revision
+	projectFileBytes, err = model.ResolveIncludes(projectFileBytes, model.GithubIncludeFetcher(
+		gRepoPoller.OauthToken, projectRef.Owner, projectRef.Repo, projectFileRevision))
+	if err != nil {
+		return nil, thirdparty.YAMLFormatError{Message: err.Error()}
+	}
+
 	projectConfig = &model.Project{}
 	err = model.LoadProjectInto(projectFileBytes, projectRef.Identifier, projectConfig)
 	if err != nil {
@@ -228,3 +235,18 @@ func (gRepoPoller *GithubRepositoryPoller) GetRecentRevisions(maxRevisions int)
 	}
 	return
 }
+
+// GetRecentTags fetches the most recent page of the repository's tags
+func (gRepoPoller *GithubRepositoryPoller) GetRecentTags() ([]model.GitTag, error) {
+	githubTags, err := thirdparty.GetGithubTags(gRepoPoller.OauthToken,
+		gRepoPoller.ProjectRef.Owner, gRepoPoller.ProjectRef.Repo)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]model.GitTag, 0, len(githubTags))
+	for _, tag := range githubTags {
+		tags = append(tags, model.GitTag{Name: tag.Name, Revision: tag.Commit.Sha})
+	}
+	return tags, nil
+}
@@ -9,6 +9,7 @@ import (
 type mockRepoPoller struct {
 	project   *model.Project
 	revisions []model.Revision
+	tags      []model.GitTag
 
 	ConfigGets uint
 	nextError  error
@@ -66,3 +67,10 @@ func (d *mockRepoPoller) GetRecentRevisions(maxRevisionsToSearch int) ([]model.R
 	}
 	return d.revisions, nil
 }
+
+func (d *mockRepoPoller) GetRecentTags() ([]model.GitTag, error) {
+	if d.nextError != nil {
+		return nil, d.clearError()
+	}
+	return d.tags, nil
+}
@@ -0,0 +1,56 @@
+package repotracker
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// CreatePeriodicVersion creates a version of a project for one of its
+// periodic builds scheduled at the given time, using the project's config at
+// the tip of its branch. The version runs the tasks selected by the periodic
+// build, and is activated immediately. It returns nil if the periodic build
+// already created its version for that time.
+func CreatePeriodicVersion(settings *evergreen.Settings, ref *model.ProjectRef, definition model.PeriodicBuildDefinition,
+	scheduled time.Time) (*version.Version, error) {
+	id := model.PeriodicVersionId(ref.Identifier, definition.ID, scheduled)
+	existing, err := version.FindOne(version.ById(id).WithFields(version.IdKey))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding version %s", id)
+	}
+	if existing != nil {
+		return nil, nil
+	}
+
+	v, project, err := newBranchTipVersion(settings, ref, id, evergreen.PeriodicBuildRequester)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if definition.Message != "" {
+		v.Message = definition.Message
+	}
+
+	tasks, err := definition.SelectTasks(project)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err = createBranchTipVersionItems(v, project, tasks); err != nil {
+		return nil, errors.Wrapf(err, "error creating version items for %s in project %s", v.Id, ref.Identifier)
+	}
+
+	grip.Info(message.Fields{
+		"message":        "created version for periodic build",
+		"runner":         RunnerName,
+		"project":        ref.Identifier,
+		"version":        v.Id,
+		"revision":       v.Revision,
+		"periodic_build": definition.ID,
+		"cron":           definition.Cron,
+	})
+	return v, nil
+}
@@ -1,15 +1,18 @@
 package repotracker
 
 import (
+	"context"
 	"fmt"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/notify"
 	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/tracing"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/evergreen-ci/evergreen/validator"
 	"github.com/mongodb/grip"
@@ -58,6 +61,9 @@ type RepoPoller interface {
 	// project - with the most recent revision appearing as the first element in
 	// the slice.
 	GetRecentRevisions(numNewRepoRevisionsToFetch int) ([]model.Revision, error)
+	// Fetches the most recent tags of the project's repository, and the
+	// revisions they point to.
+	GetRecentTags() ([]model.GitTag, error)
 }
 
 type projectConfigError struct {
@@ -397,6 +403,10 @@ func (repoTracker *RepoTracker) StoreRevisions(revisions []model.Revision) (newe
 			}
 		}
 
+		if err = project.ExpandTestSharding(); err != nil {
+			return nil, errors.Wrap(err, "error sharding tests")
+		}
+
 		// We have a config, so turn it into a usable yaml string to store with the version doc
 		projectYamlBytes, err := yaml.Marshal(project)
 		if err != nil {
@@ -404,11 +414,13 @@ func (repoTracker *RepoTracker) StoreRevisions(revisions []model.Revision) (newe
 		}
 		v.Config = string(projectYamlBytes)
 
-		// "Ignore" a version if all changes are to ignored files
-		if len(project.Ignore) > 0 {
-			filenames, err := repoTracker.GetChangedFiles(revision)
+		// "Ignore" a version if all changes are to ignored files, and
+		// filter its tasks by the changed files
+		var filenames []string
+		if len(project.Ignore) > 0 || project.HasPathFilters() {
+			filenames, err = repoTracker.GetChangedFiles(revision)
 			if err != nil {
-				return nil, errors.Wrap(err, "error checking GitHub for ignored files")
+				return nil, errors.Wrap(err, "error checking GitHub for changed files")
 			}
 			if project.IgnoresAllFiles(filenames) {
 				v.Ignored = true
@@ -416,7 +428,7 @@ func (repoTracker *RepoTracker) StoreRevisions(revisions []model.Revision) (newe
 		}
 
 		// We rebind newestVersion each iteration, so the last binding will be the newest version
-		err = errors.Wrapf(createVersionItems(v, ref, project),
+		err = errors.Wrapf(createVersionItems(v, ref, project, filenames),
 			"Error creating version items for %s in project %s",
 			v.Id, ref.Identifier)
 		if err != nil {
@@ -556,10 +568,17 @@ func NewVersionFromRevision(ref *model.ProjectRef, rev model.Revision) (*version
 
 // createVersionItems populates and stores all the tasks and builds for a version according to
 // the given project config.
-func createVersionItems(v *version.Version, ref *model.ProjectRef, project *model.Project) error {
+func createVersionItems(v *version.Version, ref *model.ProjectRef, project *model.Project, changedFiles []string) (err error) {
+	span := startVersionSpan(v)
+	defer func() { endVersionSpan(span, v, err) }()
+
 	// generate all task Ids so that we can easily reference them for dependencies
 	taskIds := model.NewTaskIdTable(project, v)
 
+	// tasks that the commit's changes don't affect are created, but are not
+	// activated with their build, unless affected tasks depend on them
+	unaffectedByVariant := project.UnaffectedMainlineTasks(changedFiles)
+
 	// create all builds for the version
 	for _, buildvariant := range project.BuildVariants {
 		if buildvariant.Disabled {
@@ -571,6 +590,20 @@ func createVersionItems(v *version.Version, ref *model.ProjectRef, project *mode
 			return errors.WithStack(err)
 		}
 
+		unaffected := unaffectedByVariant[buildvariant.Name]
+		if len(unaffected) > 0 {
+			if err = task.SetPathFilteredForBuild(buildId, unaffected); err != nil {
+				return errors.Wrapf(err, "error filtering tasks of build %s by changed files", buildId)
+			}
+		}
+		allUnaffected := true
+		for _, bvt := range buildvariant.Tasks {
+			if !util.StringSliceContains(unaffected, bvt.Name) {
+				allUnaffected = false
+				break
+			}
+		}
+
 		lastActivated, err := version.FindOne(version.ByLastVariantActivation(ref.Identifier, buildvariant.Name))
 		if err != nil {
 			grip.Error(message.WrapError(err, message.Fields{
@@ -591,12 +624,16 @@ func createVersionItems(v *version.Version, ref *model.ProjectRef, project *mode
 			}
 		}
 
+		// builds without affected tasks are left without an activation
+		// time, so that Evergreen never activates them
 		var activateAt time.Time
-		if lastActivation == nil {
-			// if we don't have a last activation time then prepare to activate it immediately.
-			activateAt = time.Now()
-		} else {
-			activateAt = lastActivation.Add(time.Minute * time.Duration(ref.GetBatchTime(&buildvariant)))
+		if !allUnaffected {
+			if lastActivation == nil {
+				// if we don't have a last activation time then prepare to activate it immediately.
+				activateAt = time.Now()
+			} else {
+				activateAt = lastActivation.Add(time.Minute * time.Duration(ref.GetBatchTime(&buildvariant)))
+			}
 		}
 
 		grip.Info(message.Fields{
@@ -637,3 +674,27 @@ func createVersionItems(v *version.Version, ref *model.ProjectRef, project *mode
 	}
 	return nil
 }
+
+// startVersionSpan starts the span of a version's creation.
+func startVersionSpan(v *version.Version) *tracing.Span {
+	_, span := tracing.StartSpan(context.Background(), "repotracker.CreateVersion")
+	span.SetAttribute("version_id", v.Id)
+	span.SetAttribute("project", v.Identifier)
+	span.SetAttribute("requester", v.Requester)
+	return span
+}
+
+// endVersionSpan ends the span of a version's creation. The span is stored
+// on the tasks of a version that was created, so that the spans recorded for
+// the tasks later on continue its trace.
+func endVersionSpan(span *tracing.Span, v *version.Version, err error) {
+	span.SetError(err)
+	if err == nil {
+		grip.Warning(message.WrapError(task.SetTraceForVersion(v.Id, span.Context()), message.Fields{
+			"message": "problem storing trace of version creation on tasks",
+			"runner":  RunnerName,
+			"version": v.Id,
+		}))
+	}
+	span.End()
+}
@@ -0,0 +1,138 @@
+package repotracker
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// CreateDownstreamVersion creates a version of a project for one of its
+// triggers, using the project's config at the tip of its branch. The
+// version runs the tasks selected by the trigger, and is activated
+// immediately.
+func CreateDownstreamVersion(settings *evergreen.Settings, ref *model.ProjectRef, trigger model.TriggerDefinition,
+	id string, upstream *version.TriggerInfo) (*version.Version, error) {
+	v, project, err := newBranchTipVersion(settings, ref, id, evergreen.TriggerRequester)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	v.TriggeredBy = upstream
+
+	tasks, err := trigger.DownstreamTasks(project)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err = createBranchTipVersionItems(v, project, tasks); err != nil {
+		return nil, errors.Wrapf(err, "error creating version items for %s in project %s", v.Id, ref.Identifier)
+	}
+
+	grip.Info(message.Fields{
+		"message":          "created version for trigger",
+		"runner":           RunnerName,
+		"project":          ref.Identifier,
+		"version":          v.Id,
+		"revision":         v.Revision,
+		"upstream_project": upstream.Project,
+		"upstream_version": upstream.Version,
+		"level":            upstream.Level,
+	})
+	return v, nil
+}
+
+// newBranchTipVersion returns a version with the given id and requester for
+// the commit at the tip of the project's branch, along with the project's
+// config at that commit. The version is not stored.
+func newBranchTipVersion(settings *evergreen.Settings, ref *model.ProjectRef, id, requester string) (*version.Version, *model.Project, error) {
+	token, err := settings.GetGithubOauthToken()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error getting github token")
+	}
+	poller := NewGithubRepositoryPoller(ref, token)
+	revisions, err := poller.GetRecentRevisions(1)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "error finding the tip of %s", ref.Identifier)
+	}
+	if len(revisions) == 0 {
+		return nil, nil, errors.Errorf("no revisions found for %s", ref.Identifier)
+	}
+
+	repoTracker := &RepoTracker{Settings: settings, ProjectRef: ref, RepoPoller: poller}
+	return repoTracker.newRevisionVersion(revisions[0], id, requester)
+}
+
+// newRevisionVersion returns a version with the given id and requester for
+// the revision, along with the project's config at that revision. The
+// version is not stored.
+func (repoTracker *RepoTracker) newRevisionVersion(revision model.Revision, id, requester string) (*version.Version, *model.Project, error) {
+	ref := repoTracker.ProjectRef
+	project, err := repoTracker.GetProjectConfig(revision.Revision)
+	if err != nil {
+		projectError, isProjectError := err.(projectConfigError)
+		if !isProjectError || len(projectError.Errors) > 0 {
+			return nil, nil, errors.Wrapf(err, "error getting config of %s at %s", ref.Identifier, revision.Revision)
+		}
+	}
+
+	v, err := NewVersionFromRevision(ref, revision)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error creating version")
+	}
+	v.Id = id
+	v.CreateTime = time.Now()
+	v.Requester = requester
+	if err = project.ExpandTestSharding(); err != nil {
+		return nil, nil, errors.Wrap(err, "error sharding tests")
+	}
+	projectYamlBytes, err := yaml.Marshal(project)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error marshaling config")
+	}
+	v.Config = string(projectYamlBytes)
+	return v, project, nil
+}
+
+// createBranchTipVersionItems creates and activates the builds and tasks of a
+// version created by a trigger, a periodic build or a git tag, and stores the
+// version.
+func createBranchTipVersionItems(v *version.Version, project *model.Project, tasks model.TaskVariantPairs) (err error) {
+	span := startVersionSpan(v)
+	defer func() { endVersionSpan(span, v, err) }()
+
+	taskIds := model.NewPatchTaskIdTable(project, v, tasks)
+	for _, vt := range tasks.TVPairsToVariantTasks() {
+		displayNames := []string{}
+		for _, dt := range vt.DisplayTasks {
+			displayNames = append(displayNames, dt.Name)
+		}
+		buildId, err := model.CreateBuildFromVersion(project, v, taskIds, vt.Variant, true, vt.Tasks, displayNames)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		v.BuildIds = append(v.BuildIds, buildId)
+		v.BuildVariants = append(v.BuildVariants, version.BuildStatus{
+			BuildVariant: vt.Variant,
+			Activated:    true,
+			ActivateAt:   v.CreateTime,
+			BuildId:      buildId,
+		})
+	}
+
+	if err := v.Insert(); err != nil {
+		for _, buildStatus := range v.BuildVariants {
+			grip.Error(message.WrapError(model.DeleteBuild(buildStatus.BuildId), message.Fields{
+				"runner":     RunnerName,
+				"message":    "issue deleting build",
+				"version_id": v.Id,
+				"build_id":   buildStatus.BuildId,
+			}))
+		}
+		return errors.Wrap(err, "error inserting version")
+	}
+	return nil
+}
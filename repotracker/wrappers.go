@@ -44,6 +44,18 @@ func CollectRevisionsForProject(conf *evergreen.Settings, project model.ProjectR
 		return errors.Wrap(errEncounteredError, err.Error())
 	}
 
+	if len(project.GitTagVersions) > 0 {
+		if err := tracker.FetchGitTagVersions(); err != nil {
+			grip.Warning(message.WrapError(err, message.Fields{
+				"project": project.Identifier,
+				"message": "problem creating versions for git tags",
+				"runner":  RunnerName,
+			}))
+
+			return errors.Wrap(errEncounteredError, err.Error())
+		}
+	}
+
 	return nil
 }
 
@@ -123,10 +123,26 @@ type Communicator interface {
 	//
 	CreateSpawnHost(context.Context, string, string) (*restmodel.APIHost, error)
 	TerminateSpawnHost(context.Context, string) error
+	StopSpawnHost(context.Context, string) error
+	StartSpawnHost(context.Context, string) error
 	ChangeSpawnHostPassword(context.Context, string, string) error
 	ExtendSpawnHostExpiration(context.Context, string, int) error
 	GetHosts(context.Context, func([]*restmodel.APIHost) error) error
 
+	// Patch methods
+	//
+	CreatePatch(context.Context, *restmodel.PatchCreateRequest) (*restmodel.APIPatch, error)
+	ConfigurePatch(context.Context, string, *restmodel.PatchConfigureRequest) (*restmodel.APIPatch, error)
+	FinalizePatch(context.Context, string) error
+	AbortPatch(context.Context, string) error
+	SetPatchPriority(context.Context, string, int64) error
+	SetPatchModule(context.Context, string, string, string, string) error
+	RemovePatchModule(context.Context, string, string) error
+
+	// Version methods
+	//
+	CompareVersions(context.Context, string, string) (*restmodel.APIVersionComparison, error)
+
 	// Fetch list of distributions evergreen can spawn
 	GetDistrosList(context.Context) ([]restmodel.APIDistro, error)
 
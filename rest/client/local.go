@@ -0,0 +1,449 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/admin"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/manifest"
+	patchmodel "github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/logging"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/send"
+	"github.com/pkg/errors"
+)
+
+const (
+	localTestResultsFile = "test_results.json"
+	localArtifactsFile   = "artifacts.json"
+	localTestLogsDir     = "test_logs"
+	localJSONDataDir     = "json"
+)
+
+// localLogFiles maps the log channels of a task to the files the Local
+// communicator writes them to.
+var localLogFiles = map[string]string{
+	apimodels.TaskLogPrefix:   "task.log",
+	apimodels.AgentLogPrefix:  "agent.log",
+	apimodels.SystemLogPrefix: "system.log",
+}
+
+// Local is a Communicator for running a single task on a developer's
+// machine, without an API server. It serves the task's configuration from
+// its fields, and saves the logs, test results, test logs and artifacts of
+// the task as files in its output directory.
+type Local struct {
+	OutputDir string
+
+	// the configuration of the task to run
+	Task       *task.Task
+	Version    *version.Version
+	ProjectRef *serviceModel.ProjectRef
+	Distro     *distro.Distro
+	Expansions apimodels.ExpansionVars
+
+	// data collected from the task
+	EndTaskDetail *apimodels.TaskEndDetail
+	TestResults   []task.TestResult
+	AttachedFiles []*artifact.File
+
+	hostID          string
+	hostSecret      string
+	keyVal          map[string]*serviceModel.KeyVal
+	lastMessageSent time.Time
+
+	mu sync.RWMutex
+}
+
+// NewLocal returns a Communicator that saves the output of a task to the
+// given directory, which must exist.
+func NewLocal(outputDir string) *Local {
+	return &Local{
+		OutputDir:  outputDir,
+		Expansions: apimodels.ExpansionVars{},
+		keyVal:     make(map[string]*serviceModel.KeyVal),
+	}
+}
+
+func (c *Local) Close() {}
+
+func (c *Local) LastMessageAt() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.lastMessageSent
+}
+
+func (c *Local) UpdateLastMessageTime() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastMessageSent = time.Now()
+}
+
+// nolint
+func (c *Local) SetTimeoutStart(timeoutStart time.Duration) {}
+func (c *Local) SetTimeoutMax(timeoutMax time.Duration)     {}
+func (c *Local) SetMaxAttempts(attempts int)                {}
+func (c *Local) SetHostID(hostID string)                    { c.hostID = hostID }
+func (c *Local) SetHostSecret(hostSecret string)            { c.hostSecret = hostSecret }
+func (c *Local) GetHostID() string                          { return c.hostID }
+func (c *Local) GetHostSecret() string                      { return c.hostSecret }
+func (c *Local) SetAPIUser(apiUser string)                  {}
+func (c *Local) SetAPIKey(apiKey string)                    {}
+
+// nolint
+func (c *Local) StartTask(ctx context.Context, td TaskData) error { return nil }
+
+// EndTask saves the final status of the task.
+func (c *Local) EndTask(ctx context.Context, detail *apimodels.TaskEndDetail, td TaskData) (*apimodels.EndTaskResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.EndTaskDetail = detail
+	return &apimodels.EndTaskResponse{}, nil
+}
+
+// GetEndTaskDetail returns the final status of the task, or nil if the task
+// has not finished.
+func (c *Local) GetEndTaskDetail() *apimodels.TaskEndDetail {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.EndTaskDetail
+}
+
+func (c *Local) GetTask(ctx context.Context, td TaskData) (*task.Task, error) {
+	if c.Task == nil {
+		return nil, errors.New("no task to run locally")
+	}
+	return c.Task, nil
+}
+
+func (c *Local) GetProjectRef(ctx context.Context, td TaskData) (*serviceModel.ProjectRef, error) {
+	if c.ProjectRef == nil {
+		return nil, errors.New("no project ref for the local task")
+	}
+	return c.ProjectRef, nil
+}
+
+func (c *Local) GetDistro(ctx context.Context, td TaskData) (*distro.Distro, error) {
+	if c.Distro == nil {
+		return nil, errors.New("no distro for the local task")
+	}
+	return c.Distro, nil
+}
+
+func (c *Local) GetVersion(ctx context.Context, td TaskData) (*version.Version, error) {
+	if c.Version == nil {
+		return nil, errors.New("no version for the local task")
+	}
+	return c.Version, nil
+}
+
+// Heartbeat always succeeds, since nothing can abort a local task.
+func (c *Local) Heartbeat(ctx context.Context, td TaskData) (bool, error) { return false, nil }
+
+func (c *Local) FetchExpansionVars(ctx context.Context, td TaskData) (*apimodels.ExpansionVars, error) {
+	return &c.Expansions, nil
+}
+
+// GetNextTask never returns a task, since the Local communicator only runs
+// the task it was configured with.
+func (c *Local) GetNextTask(ctx context.Context) (*apimodels.NextTaskResponse, error) {
+	return &apimodels.NextTaskResponse{}, nil
+}
+
+// GetLoggerProducer constructs a log producer that writes each channel to
+// its own file in the output directory, as well as to the local logger.
+func (c *Local) GetLoggerProducer(ctx context.Context, td TaskData) LoggerProducer {
+	local := grip.GetSender()
+
+	exec := newLogSender(ctx, c, apimodels.AgentLogPrefix, td)
+	grip.CatchWarning(exec.SetFormatter(send.MakeDefaultFormatter()))
+	exec = send.NewConfiguredMultiSender(local, exec)
+
+	task := newTimeoutLogSender(ctx, c, apimodels.TaskLogPrefix, td)
+	grip.CatchWarning(task.SetFormatter(send.MakeDefaultFormatter()))
+	task = send.NewConfiguredMultiSender(local, task)
+
+	system := newLogSender(ctx, c, apimodels.SystemLogPrefix, td)
+	grip.CatchWarning(system.SetFormatter(send.MakeDefaultFormatter()))
+	system = send.NewConfiguredMultiSender(local, system)
+
+	return &logHarness{
+		execution: logging.MakeGrip(exec),
+		task:      logging.MakeGrip(task),
+		system:    logging.MakeGrip(system),
+	}
+}
+
+// SendLogMessages appends the messages to the log file of their channel.
+func (c *Local) SendLogMessages(ctx context.Context, td TaskData, msgs []apimodels.LogMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	files := map[string]*os.File{}
+	defer func() {
+		for _, f := range files {
+			grip.Warning(f.Close())
+		}
+	}()
+
+	for _, msg := range msgs {
+		name, ok := localLogFiles[msg.Type]
+		if !ok {
+			name = localLogFiles[apimodels.AgentLogPrefix]
+		}
+		f, ok := files[name]
+		if !ok {
+			var err error
+			f, err = os.OpenFile(filepath.Join(c.OutputDir, name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			if err != nil {
+				return errors.Wrapf(err, "problem opening log file %s", name)
+			}
+			files[name] = f
+		}
+
+		line := fmt.Sprintf("[%s] [%s] %s\n", msg.Timestamp.Format("2006/01/02 15:04:05.000"), msg.Severity, msg.Message)
+		if _, err := f.WriteString(line); err != nil {
+			return errors.Wrapf(err, "problem writing to log file %s", name)
+		}
+	}
+
+	return nil
+}
+
+// SendProcessInfo discards the process info, which is only useful on hosts.
+func (c *Local) SendProcessInfo(ctx context.Context, td TaskData, procs []*message.ProcessInfo) error {
+	return nil
+}
+
+// SendSystemInfo discards the system info, which is only useful on hosts.
+func (c *Local) SendSystemInfo(ctx context.Context, td TaskData, sysinfo *message.SystemInfo) error {
+	return nil
+}
+
+// SendTestResults adds the results to the test results file.
+func (c *Local) SendTestResults(ctx context.Context, td TaskData, results *task.LocalTestResults) error {
+	if results == nil || len(results.Results) == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.TestResults = append(c.TestResults, results.Results...)
+	return errors.WithStack(c.writeJSON(localTestResultsFile, c.TestResults))
+}
+
+// SendTestLog saves the test log in the test logs directory, and returns
+// the path to it as the log's id.
+func (c *Local) SendTestLog(ctx context.Context, td TaskData, log *serviceModel.TestLog) (string, error) {
+	if log == nil {
+		return "", nil
+	}
+
+	dir := filepath.Join(c.OutputDir, localTestLogsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", errors.Wrap(err, "problem creating test logs directory")
+	}
+
+	path := filepath.Join(dir, filepath.Base(log.Name)+".log")
+	data := []byte{}
+	for _, line := range log.Lines {
+		data = append(data, line...)
+		data = append(data, '\n')
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return "", errors.Wrapf(err, "problem writing test log %s", log.Name)
+	}
+
+	return path, nil
+}
+
+// AttachFiles adds the files to the artifacts file.
+func (c *Local) AttachFiles(ctx context.Context, td TaskData, taskFiles []*artifact.File) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.AttachedFiles = append(c.AttachedFiles, taskFiles...)
+	return errors.WithStack(c.writeJSON(localArtifactsFile, c.AttachedFiles))
+}
+
+// PostJSONData saves the data in the JSON data directory, under its name.
+func (c *Local) PostJSONData(ctx context.Context, td TaskData, path string, data interface{}) error {
+	dir := filepath.Join(c.OutputDir, localJSONDataDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.Wrap(err, "problem creating json data directory")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return errors.WithStack(c.writeJSON(filepath.Join(localJSONDataDir, filepath.Base(path)+".json"), data))
+}
+
+func (c *Local) KeyValInc(ctx context.Context, td TaskData, kv *serviceModel.KeyVal) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.keyVal[kv.Key]; ok {
+		*kv = *cached
+	} else {
+		c.keyVal[kv.Key] = kv
+	}
+	kv.Value++
+	return nil
+}
+
+func (c *Local) GetManifest(ctx context.Context, td TaskData) (*manifest.Manifest, error) {
+	return &manifest.Manifest{}, nil
+}
+
+// writeJSON writes the data to the named file in the output directory. The
+// caller must hold the lock.
+func (c *Local) writeJSON(name string, data interface{}) error {
+	out, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "problem marshaling %s", name)
+	}
+	return errors.Wrapf(ioutil.WriteFile(filepath.Join(c.OutputDir, name), out, 0644),
+		"problem writing %s", name)
+}
+
+// localNotSupported returns the error for operations that need the API
+// server, and so cannot be run for a local task.
+func localNotSupported(op string) error {
+	return errors.Errorf("%s is not supported when running a task locally", op)
+}
+
+func (c *Local) GetTaskPatch(ctx context.Context, td TaskData) (*patchmodel.Patch, error) {
+	return nil, localNotSupported("getting the task's patch")
+}
+
+func (c *Local) GetPatchFile(ctx context.Context, td TaskData, patchFileID string) (string, error) {
+	return "", localNotSupported("getting a patch file")
+}
+
+func (c *Local) S3Copy(ctx context.Context, td TaskData, req *apimodels.S3CopyRequest) error {
+	return localNotSupported("copying files in s3")
+}
+
+func (c *Local) GetJSONData(ctx context.Context, td TaskData, tn, dn, vn string) ([]byte, error) {
+	return nil, localNotSupported("getting json data")
+}
+
+func (c *Local) GetJSONHistory(ctx context.Context, td TaskData, tags bool, tn, dn string) ([]byte, error) {
+	return nil, localNotSupported("getting json history")
+}
+
+func (c *Local) SetBannerMessage(ctx context.Context, m string, t admin.BannerTheme) error {
+	return localNotSupported("setting the banner message")
+}
+
+func (c *Local) GetBannerMessage(ctx context.Context) (string, error) {
+	return "", localNotSupported("getting the banner message")
+}
+
+func (c *Local) SetServiceFlags(ctx context.Context, f *model.APIServiceFlags) error {
+	return localNotSupported("setting service flags")
+}
+
+func (c *Local) GetServiceFlags(ctx context.Context) (*model.APIServiceFlags, error) {
+	return nil, localNotSupported("getting service flags")
+}
+
+func (c *Local) RestartRecentTasks(ctx context.Context, startAt, endAt time.Time) error {
+	return localNotSupported("restarting tasks")
+}
+
+func (c *Local) GetHostsByUser(ctx context.Context, user string) ([]*model.APIHost, error) {
+	return nil, localNotSupported("getting hosts")
+}
+
+func (c *Local) CreateSpawnHost(ctx context.Context, distroID string, keyName string) (*model.APIHost, error) {
+	return nil, localNotSupported("creating spawn hosts")
+}
+
+func (c *Local) TerminateSpawnHost(ctx context.Context, hostID string) error {
+	return localNotSupported("terminating spawn hosts")
+}
+
+func (c *Local) StopSpawnHost(ctx context.Context, hostID string) error {
+	return localNotSupported("stopping spawn hosts")
+}
+
+func (c *Local) StartSpawnHost(ctx context.Context, hostID string) error {
+	return localNotSupported("starting spawn hosts")
+}
+
+func (c *Local) ChangeSpawnHostPassword(ctx context.Context, hostID, rdpPassword string) error {
+	return localNotSupported("changing spawn host passwords")
+}
+
+func (c *Local) ExtendSpawnHostExpiration(ctx context.Context, hostID string, addHours int) error {
+	return localNotSupported("extending spawn hosts")
+}
+
+func (c *Local) GetHosts(ctx context.Context, f func([]*model.APIHost) error) error {
+	return localNotSupported("getting hosts")
+}
+
+func (c *Local) CreatePatch(ctx context.Context, req *model.PatchCreateRequest) (*model.APIPatch, error) {
+	return nil, localNotSupported("creating patches")
+}
+
+func (c *Local) ConfigurePatch(ctx context.Context, patchID string, req *model.PatchConfigureRequest) (*model.APIPatch, error) {
+	return nil, localNotSupported("configuring patches")
+}
+
+func (c *Local) FinalizePatch(ctx context.Context, patchID string) error {
+	return localNotSupported("finalizing patches")
+}
+
+func (c *Local) AbortPatch(ctx context.Context, patchID string) error {
+	return localNotSupported("aborting patches")
+}
+
+func (c *Local) SetPatchPriority(ctx context.Context, patchID string, priority int64) error {
+	return localNotSupported("setting patch priority")
+}
+
+func (c *Local) SetPatchModule(ctx context.Context, patchID, module, githash, diff string) error {
+	return localNotSupported("setting patch modules")
+}
+
+func (c *Local) RemovePatchModule(ctx context.Context, patchID, module string) error {
+	return localNotSupported("removing patch modules")
+}
+
+func (c *Local) CompareVersions(ctx context.Context, baseVersionID, versionID string) (*model.APIVersionComparison, error) {
+	return nil, localNotSupported("comparing versions")
+}
+
+func (c *Local) GetDistrosList(ctx context.Context) ([]model.APIDistro, error) {
+	return nil, localNotSupported("listing distros")
+}
+
+func (c *Local) GetCurrentUsersKeys(ctx context.Context) ([]model.APIPubKey, error) {
+	return nil, localNotSupported("listing keys")
+}
+
+func (c *Local) AddPublicKey(ctx context.Context, keyName, keyValue string) error {
+	return localNotSupported("adding keys")
+}
+
+func (c *Local) DeletePublicKey(ctx context.Context, keyName string) error {
+	return localNotSupported("deleting keys")
+}
+
+func (c *Local) ListAliases(ctx context.Context, project string) ([]serviceModel.PatchDefinition, error) {
+	return nil, localNotSupported("listing aliases")
+}
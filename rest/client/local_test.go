@@ -0,0 +1,70 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/apimodels"
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalCommunicatorSavesOutput(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+	td := TaskData{ID: "task", Secret: "secret"}
+
+	dir, err := ioutil.TempDir("", "local-comm")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	comm := NewLocal(dir)
+
+	// log messages go to the file of their channel
+	assert.NoError(comm.SendLogMessages(ctx, td, []apimodels.LogMessage{
+		{Type: apimodels.TaskLogPrefix, Severity: "I", Message: "task message", Timestamp: time.Now()},
+		{Type: apimodels.SystemLogPrefix, Severity: "I", Message: "system message", Timestamp: time.Now()},
+	}))
+	data, err := ioutil.ReadFile(filepath.Join(dir, "task.log"))
+	assert.NoError(err)
+	assert.Contains(string(data), "task message")
+	assert.NotContains(string(data), "system message")
+	data, err = ioutil.ReadFile(filepath.Join(dir, "system.log"))
+	assert.NoError(err)
+	assert.Contains(string(data), "system message")
+
+	// test results accumulate across calls
+	for _, name := range []string{"test1", "test2"} {
+		assert.NoError(comm.SendTestResults(ctx, td, &task.LocalTestResults{
+			Results: []task.TestResult{{TestFile: name, Status: "pass"}},
+		}))
+	}
+	data, err = ioutil.ReadFile(filepath.Join(dir, localTestResultsFile))
+	assert.NoError(err)
+	results := []task.TestResult{}
+	assert.NoError(json.Unmarshal(data, &results))
+	assert.Len(results, 2)
+
+	path, err := comm.SendTestLog(ctx, td, &serviceModel.TestLog{Name: "test1", Lines: []string{"line1", "line2"}})
+	assert.NoError(err)
+	data, err = ioutil.ReadFile(path)
+	assert.NoError(err)
+	assert.Equal("line1\nline2\n", string(data))
+
+	assert.NoError(comm.AttachFiles(ctx, td, []*artifact.File{{Name: "file", Link: "link"}}))
+	data, err = ioutil.ReadFile(filepath.Join(dir, localArtifactsFile))
+	assert.NoError(err)
+	assert.Contains(string(data), "link")
+
+	_, err = comm.EndTask(ctx, &apimodels.TaskEndDetail{Status: "success"}, td)
+	assert.NoError(err)
+	assert.Equal("success", comm.GetEndTaskDetail().Status)
+}
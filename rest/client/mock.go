@@ -328,6 +328,14 @@ func (*Mock) TerminateSpawnHost(ctx context.Context, hostID string) error {
 	return errors.New("(*Mock) TerminateSpawnHost is not implemented")
 }
 
+func (*Mock) StopSpawnHost(ctx context.Context, hostID string) error {
+	return errors.New("(*Mock) StopSpawnHost is not implemented")
+}
+
+func (*Mock) StartSpawnHost(ctx context.Context, hostID string) error {
+	return errors.New("(*Mock) StartSpawnHost is not implemented")
+}
+
 func (*Mock) ChangeSpawnHostPassword(context.Context, string, string) error {
 	return errors.New("(*Mock) ChangeSpawnHostPassword is not implemented")
 }
@@ -433,6 +441,13 @@ func (c *Mock) GetSystemInfoLength() int {
 	return length
 }
 
+func (c *Mock) CompareVersions(ctx context.Context, baseVersionID, versionID string) (*model.APIVersionComparison, error) {
+	return &model.APIVersionComparison{
+		BaseVersion: model.APIString(baseVersionID),
+		Version:     model.APIString(versionID),
+	}, nil
+}
+
 func (c *Mock) GetDistrosList(ctx context.Context) ([]model.APIDistro, error) {
 	mockDistros := []model.APIDistro{
 		{
@@ -471,3 +486,22 @@ func (c *Mock) DeletePublicKey(ctx context.Context, keyName string) error {
 func (c *Mock) ListAliases(ctx context.Context, keyName string) ([]serviceModel.PatchDefinition, error) {
 	return nil, errors.New("(c *Mock) ListAliases not implemented")
 }
+
+func (c *Mock) CreatePatch(ctx context.Context, req *model.PatchCreateRequest) (*model.APIPatch, error) {
+	return &model.APIPatch{
+		ProjectId:   model.APIString(req.Project),
+		Githash:     model.APIString(req.Githash),
+		Description: model.APIString(req.Description),
+		Activated:   req.Finalize,
+	}, nil
+}
+
+func (c *Mock) ConfigurePatch(ctx context.Context, patchID string, req *model.PatchConfigureRequest) (*model.APIPatch, error) {
+	return &model.APIPatch{Id: model.APIString(patchID), Activated: req.Finalize}, nil
+}
+
+func (c *Mock) FinalizePatch(ctx context.Context, patchID string) error              { return nil }
+func (c *Mock) AbortPatch(ctx context.Context, patchID string) error                 { return nil }
+func (c *Mock) SetPatchPriority(ctx context.Context, patchID string, p int64) error  { return nil }
+func (c *Mock) RemovePatchModule(ctx context.Context, patchID, module string) error  { return nil }
+func (c *Mock) SetPatchModule(ctx context.Context, patchID, m, h, diff string) error { return nil }
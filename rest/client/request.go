@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/tracing"
 	"github.com/jpillora/backoff"
 	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
@@ -109,6 +110,7 @@ func (c *communicatorImpl) createRequest(info requestInfo, data interface{}) (*h
 
 func (c *communicatorImpl) doRequest(ctx context.Context, data interface{}, r *http.Request) (*http.Response, error) {
 	r = r.WithContext(ctx)
+	tracing.Inject(ctx, r.Header)
 	response, err := c.httpClient.Do(r)
 	if err != nil {
 		return nil, err
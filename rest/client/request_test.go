@@ -1,10 +1,14 @@
 package client
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/tracing"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -81,3 +85,20 @@ func (s *RequestTestSuite) TestSetTaskPathSuffix() {
 	info.setTaskPathSuffix("foo")
 	s.Equal("task/bar/foo", info.path)
 }
+
+func (s *RequestTestSuite) TestDoRequestSendsTraceParent() {
+	var traceParent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParent = r.Header.Get(tracing.TraceParentHeader)
+	}))
+	defer server.Close()
+	s.evergreenREST.httpClient = server.Client()
+
+	ctx, span := tracing.StartSpan(context.Background(), "agent.Command")
+	r, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	s.Require().NoError(err)
+	resp, err := s.evergreenREST.doRequest(ctx, nil, r)
+	s.Require().NoError(err)
+	s.NoError(resp.Body.Close())
+	s.Equal(tracing.FormatTraceParent(span.Context()), traceParent)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"time"
 
 	serviceModel "github.com/evergreen-ci/evergreen/model"
@@ -107,6 +108,52 @@ func (c *communicatorImpl) TerminateSpawnHost(ctx context.Context, hostID string
 	return nil
 }
 
+func (c *communicatorImpl) StopSpawnHost(ctx context.Context, hostID string) error {
+	info := requestInfo{
+		method:  post,
+		path:    fmt.Sprintf("hosts/%s/stop", hostID),
+		version: apiVersion2,
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return errors.Wrapf(err, "error sending request to stop host")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := rest.APIError{}
+		if err := util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return errors.Wrap(err, "problem stopping host and parsing error message")
+		}
+		return errors.Wrap(errMsg, "problem stopping host")
+	}
+
+	return nil
+}
+
+func (c *communicatorImpl) StartSpawnHost(ctx context.Context, hostID string) error {
+	info := requestInfo{
+		method:  post,
+		path:    fmt.Sprintf("hosts/%s/start", hostID),
+		version: apiVersion2,
+	}
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return errors.Wrapf(err, "error sending request to start host")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := rest.APIError{}
+		if err := util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return errors.Wrap(err, "problem starting host and parsing error message")
+		}
+		return errors.Wrap(errMsg, "problem starting host")
+	}
+
+	return nil
+}
+
 func (c *communicatorImpl) ChangeSpawnHostPassword(ctx context.Context, hostID, rdpPassword string) error {
 	info := requestInfo{
 		method:  post,
@@ -293,6 +340,34 @@ func (c *communicatorImpl) RestartRecentTasks(ctx context.Context, startAt, endA
 	return nil
 }
 
+func (c *communicatorImpl) CompareVersions(ctx context.Context, baseVersionID, versionID string) (*model.APIVersionComparison, error) {
+	info := requestInfo{
+		method:  get,
+		version: apiVersion2,
+		path:    fmt.Sprintf("versions/%s/compare?base=%s", versionID, url.QueryEscape(baseVersionID)),
+	}
+
+	resp, err := c.request(ctx, info, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "problem comparing versions")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := rest.APIError{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Wrap(err, "problem comparing versions and parsing error message")
+		}
+		return nil, errors.Wrap(errMsg, "problem comparing versions")
+	}
+
+	comparison := &model.APIVersionComparison{}
+	if err = util.ReadJSONInto(resp.Body, comparison); err != nil {
+		return nil, errors.Wrap(err, "error parsing version comparison")
+	}
+	return comparison, nil
+}
+
 func (c *communicatorImpl) GetDistrosList(ctx context.Context) ([]model.APIDistro, error) {
 	info := requestInfo{
 		method:  get,
@@ -431,3 +506,103 @@ func (c *communicatorImpl) ListAliases(ctx context.Context, project string) ([]s
 	}
 	return patchAliases, nil
 }
+
+// CreatePatch submits a new patch and returns it. The patch is finalized
+// once any module changes in the request have been added, if requested.
+func (c *communicatorImpl) CreatePatch(ctx context.Context, req *model.PatchCreateRequest) (*model.APIPatch, error) {
+	info := requestInfo{
+		method:  post,
+		path:    "patches",
+		version: apiVersion2,
+	}
+	return c.patchRequest(ctx, info, req, "problem creating patch")
+}
+
+// ConfigurePatch sets the description, variants and tasks of a patch, and
+// finalizes it if requested.
+func (c *communicatorImpl) ConfigurePatch(ctx context.Context, patchID string, req *model.PatchConfigureRequest) (*model.APIPatch, error) {
+	info := requestInfo{
+		method:  post,
+		path:    fmt.Sprintf("patches/%s/configure", patchID),
+		version: apiVersion2,
+	}
+	return c.patchRequest(ctx, info, req, "problem configuring patch")
+}
+
+func (c *communicatorImpl) FinalizePatch(ctx context.Context, patchID string) error {
+	_, err := c.ConfigurePatch(ctx, patchID, &model.PatchConfigureRequest{Finalize: true})
+	return errors.Wrapf(err, "problem finalizing patch %s", patchID)
+}
+
+func (c *communicatorImpl) AbortPatch(ctx context.Context, patchID string) error {
+	info := requestInfo{
+		method:  post,
+		path:    fmt.Sprintf("patches/%s/abort", patchID),
+		version: apiVersion2,
+	}
+	_, err := c.patchRequest(ctx, info, "", "problem aborting patch")
+	return err
+}
+
+func (c *communicatorImpl) SetPatchPriority(ctx context.Context, patchID string, priority int64) error {
+	info := requestInfo{
+		method:  patch,
+		path:    fmt.Sprintf("patches/%s", patchID),
+		version: apiVersion2,
+	}
+	body := struct {
+		Priority int64 `json:"priority"`
+	}{Priority: priority}
+	_, err := c.patchRequest(ctx, info, body, "problem setting patch priority")
+	return err
+}
+
+// SetPatchModule adds or replaces the changes to a module in an unfinalized
+// patch.
+func (c *communicatorImpl) SetPatchModule(ctx context.Context, patchID, module, githash, diff string) error {
+	info := requestInfo{
+		method:  put,
+		path:    fmt.Sprintf("patches/%s/modules/%s", patchID, module),
+		version: apiVersion2,
+	}
+	body := model.PatchModuleRequest{
+		Githash: githash,
+		Patch:   diff,
+	}
+	_, err := c.patchRequest(ctx, info, body, "problem updating patch module")
+	return err
+}
+
+func (c *communicatorImpl) RemovePatchModule(ctx context.Context, patchID, module string) error {
+	info := requestInfo{
+		method:  delete,
+		path:    fmt.Sprintf("patches/%s/modules/%s", patchID, module),
+		version: apiVersion2,
+	}
+	_, err := c.patchRequest(ctx, info, "", "problem removing patch module")
+	return err
+}
+
+// patchRequest sends a request to one of the patch routes and reads the
+// patch returned by it.
+func (c *communicatorImpl) patchRequest(ctx context.Context, info requestInfo, body interface{}, msg string) (*model.APIPatch, error) {
+	resp, err := c.request(ctx, info, body)
+	if err != nil {
+		return nil, errors.Wrap(err, msg)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg := rest.APIError{}
+		if err = util.ReadJSONInto(resp.Body, &errMsg); err != nil {
+			return nil, errors.Wrapf(err, "%s and parsing error message", msg)
+		}
+		return nil, errors.Wrap(errMsg, msg)
+	}
+
+	p := &model.APIPatch{}
+	if err = util.ReadJSONInto(resp.Body, p); err != nil {
+		return nil, errors.Wrap(err, "problem reading patch from response")
+	}
+	return p, nil
+}
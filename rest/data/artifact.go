@@ -0,0 +1,45 @@
+package data
+
+import (
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/pkg/errors"
+)
+
+// DBArtifactConnector is a struct that implements the Artifact related methods
+// from the Connector through interactions with the backing database.
+type DBArtifactConnector struct{}
+
+// FindTaskArtifacts returns the files of the task's execution that the user
+// may see, with signed links for the files stored in S3.
+func (ac *DBArtifactConnector) FindTaskArtifacts(t *task.Task, projectRef *model.ProjectRef, u *user.DBUser) ([]artifact.File, error) {
+	taskID := t.Id
+	if t.OldTaskId != "" {
+		taskID = t.OldTaskId
+	}
+	entry, err := artifact.FindOne(artifact.ByTaskIdAndExecution(taskID, t.Execution))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding artifacts for task '%s'", taskID)
+	}
+	if entry == nil {
+		return []artifact.File{}, nil
+	}
+
+	return model.SignArtifactLinks(taskID, artifact.StripHiddenFiles(entry.Files, u != nil),
+		projectRef, u, evergreen.GetEnvironment().Settings())
+}
+
+// MockArtifactConnector stores a cached set of artifact files, by task id,
+// that are queried against by the implementations of the Connector
+// interface's Artifact related functions. It does not sign links.
+type MockArtifactConnector struct {
+	CachedFiles map[string][]artifact.File
+}
+
+func (mac *MockArtifactConnector) FindTaskArtifacts(t *task.Task, projectRef *model.ProjectRef, u *user.DBUser) ([]artifact.File, error) {
+	return model.SignArtifactLinks(t.Id, artifact.StripHiddenFiles(mac.CachedFiles[t.Id], u != nil),
+		projectRef, u, nil)
+}
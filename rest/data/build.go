@@ -5,9 +5,11 @@ import (
 
 	"fmt"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/rest"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/pkg/errors"
 )
 
@@ -31,6 +33,15 @@ func (bc *DBBuildConnector) FindBuildById(buildId string) (*build.Build, error)
 	return b, nil
 }
 
+// FindBuildsByIds queries the backing database for all of the builds with
+// the given ids.
+func (bc *DBBuildConnector) FindBuildsByIds(buildIds []string) ([]build.Build, error) {
+	if len(buildIds) == 0 {
+		return []build.Build{}, nil
+	}
+	return build.Find(build.ByIds(buildIds))
+}
+
 // FindProjectByBranch queries the project_refs database to find the name of the
 // project a given branch falls under.
 func (bc *DBBuildConnector) FindProjectByBranch(branch string) (*model.ProjectRef, error) {
@@ -57,12 +68,39 @@ func (bc *DBBuildConnector) RestartBuild(buildId string, user string) error {
 	return model.RestartBuildTasks(buildId, user)
 }
 
+// RestartFailedBuildTasks restarts the tasks of the build that failed.
+func (bc *DBBuildConnector) RestartFailedBuildTasks(buildId string, user string) error {
+	b, err := build.FindOne(build.ById(buildId))
+	if err != nil {
+		return errors.Wrapf(err, "error finding build %s", buildId)
+	}
+	if b == nil {
+		return &rest.APIError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("build with id %s not found", buildId),
+		}
+	}
+
+	taskIds := []string{}
+	for _, t := range b.Tasks {
+		if t.Status == evergreen.TaskFailed {
+			taskIds = append(taskIds, t.Id)
+		}
+	}
+	if len(taskIds) == 0 {
+		return nil
+	}
+
+	return model.RestartBuild(buildId, taskIds, false, user)
+}
+
 // MockBuildConnector is a struct that implements the Build related methods
 // from the Connector through interactions with the backing database.
 type MockBuildConnector struct {
 	CachedBuilds         []build.Build
 	CachedProjects       map[string]*model.ProjectRef
 	CachedAborted        map[string]string
+	CachedRestarted      map[string]string
 	FailOnChangePriority bool
 	FailOnAbort          bool
 	FailOnRestart        bool
@@ -82,6 +120,18 @@ func (bc *MockBuildConnector) FindBuildById(buildId string) (*build.Build, error
 	}
 }
 
+// FindBuildsByIds returns the members of the CachedBuilds slice with
+// matching id fields.
+func (bc *MockBuildConnector) FindBuildsByIds(buildIds []string) ([]build.Build, error) {
+	builds := []build.Build{}
+	for _, b := range bc.CachedBuilds {
+		if util.StringSliceContains(buildIds, b.Id) {
+			builds = append(builds, b)
+		}
+	}
+	return builds, nil
+}
+
 // FindProjectByBranch accesses the map of branch names to project names to find
 // the project corresponding to a given branch.
 func (bc *MockBuildConnector) FindProjectByBranch(branch string) (*model.ProjectRef, error) {
@@ -127,3 +177,13 @@ func (bc *MockBuildConnector) RestartBuild(buildId string, user string) error {
 	}
 	return nil
 }
+
+// RestartFailedBuildTasks sets the value of the input build Id in
+// CachedRestarted to the user.
+func (bc *MockBuildConnector) RestartFailedBuildTasks(buildId string, user string) error {
+	if bc.FailOnRestart {
+		return errors.New("manufactured error")
+	}
+	bc.CachedRestarted[buildId] = user
+	return nil
+}
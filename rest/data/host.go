@@ -101,6 +101,14 @@ func (hc *DBHostConnector) TerminateHost(host *host.Host) error {
 	return errors.WithStack(spawn.TerminateHost(host, evergreen.GetEnvironment().Settings()))
 }
 
+func (hc *DBHostConnector) StopHost(host *host.Host) error {
+	return errors.WithStack(spawn.StopHost(host, evergreen.GetEnvironment().Settings()))
+}
+
+func (hc *DBHostConnector) StartHost(host *host.Host) error {
+	return errors.WithStack(spawn.StartHost(host, evergreen.GetEnvironment().Settings()))
+}
+
 // MockHostConnector is a struct that implements the Host related methods
 // from the Connector through interactions with he backing database.
 type MockHostConnector struct {
@@ -253,6 +261,30 @@ func (hc *MockHostConnector) TerminateHost(host *host.Host) error {
 	return errors.New("can't find host")
 }
 
+func (hc *MockHostConnector) StopHost(host *host.Host) error {
+	for i := range hc.CachedHosts {
+		if hc.CachedHosts[i].Id == host.Id {
+			hc.CachedHosts[i].Status = evergreen.HostStopping
+			host.Status = evergreen.HostStopping
+			return nil
+		}
+	}
+
+	return errors.New("can't find host")
+}
+
+func (hc *MockHostConnector) StartHost(host *host.Host) error {
+	for i := range hc.CachedHosts {
+		if hc.CachedHosts[i].Id == host.Id {
+			hc.CachedHosts[i].Status = evergreen.HostRunning
+			host.Status = evergreen.HostRunning
+			return nil
+		}
+	}
+
+	return errors.New("can't find host")
+}
+
 func (dbc *MockConnector) FindHostByIdWithOwner(hostID string, user auth.User) (*host.Host, error) {
 	return findHostByIdWithOwner(dbc, hostID, user)
 }
@@ -24,6 +24,8 @@ type DBConnector struct {
 	DBAdminConnector
 	DBStatusConnector
 	DBAliasConnector
+	DBArtifactConnector
+	DBSubscriptionConnector
 	RepoTrackerConnector
 }
 
@@ -54,6 +56,8 @@ type MockConnector struct {
 	MockAdminConnector
 	MockStatusConnector
 	MockAliasConnector
+	MockArtifactConnector
+	MockSubscriptionConnector
 	MockRepoTrackerConnector
 }
 
@@ -6,8 +6,10 @@ import (
 	"github.com/evergreen-ci/evergreen/auth"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/admin"
+	"github.com/evergreen-ci/evergreen/model/artifact"
 	"github.com/evergreen-ci/evergreen/model/build"
 	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/task"
@@ -40,6 +42,10 @@ type Connector interface {
 
 	// FindTaskById is a method to find a specific task given its ID.
 	FindTaskById(string) (*task.Task, error)
+
+	// FindTaskArtifacts returns the files of a task that the user may see,
+	// with signed links for the files stored in S3.
+	FindTaskArtifacts(*task.Task, *model.ProjectRef, *user.DBUser) ([]artifact.File, error)
 	FindTasksByIds([]string) ([]task.Task, error)
 	SetTaskPriority(*task.Task, string, int64) error
 	SetTaskActivated(string, string, bool) error
@@ -54,6 +60,9 @@ type Connector interface {
 
 	// FindBuildById is a method to find the build matching the same BuildId.
 	FindBuildById(string) (*build.Build, error)
+	// FindBuildsByIds is a method to find all of the builds matching the
+	// given BuildIds, in no particular order.
+	FindBuildsByIds([]string) ([]build.Build, error)
 	// SetBuildPriority and SetBuildActivated change the status of the input build
 	SetBuildPriority(string, int64) error
 	SetBuildActivated(string, string, bool) error
@@ -62,6 +71,9 @@ type Connector interface {
 	AbortBuild(string, string) error
 	// RestartBuild is a method to restart the build matching the same BuildId.
 	RestartBuild(string, string) error
+	// RestartFailedBuildTasks is a method to restart the failed tasks of the
+	// build matching the same BuildId.
+	RestartFailedBuildTasks(string, string) error
 
 	// FindProjects is a method to find projects as ordered by name
 	FindProjects(string, int, int, bool) ([]model.ProjectRef, error)
@@ -117,6 +129,9 @@ type Connector interface {
 
 	// FindVersionById returns version given its ID.
 	FindVersionById(string) (*version.Version, error)
+	// FindVersionsByIds returns all of the versions matching the given IDs,
+	// in no particular order.
+	FindVersionsByIds([]string) ([]version.Version, error)
 
 	// FindPatchesByProject provides access to the patches corresponding to the input project ID
 	// as ordered by creation time.
@@ -127,6 +142,16 @@ type Connector interface {
 	// FindPatchById fetches the patch corresponding to the input patch ID.
 	FindPatchById(string) (*patch.Patch, error)
 
+	// CompareVersions returns how the tasks of a version differ from the
+	// tasks of a base version, given their IDs, with the artifacts that the
+	// user may see.
+	CompareVersions(string, string, *user.DBUser) (*model.VersionComparison, error)
+	// FindDownstreamVersions returns the versions created by triggers on a
+	// version given its ID.
+	FindDownstreamVersions(string) ([]version.Version, error)
+	// FindVersionsByProject returns the versions of every requester of the
+	// project with the given ID, starting from the given creation time.
+	FindVersionsByProject(string, time.Time, int, bool) ([]version.Version, error)
 	// AbortVersion aborts all tasks of a version given its ID.
 	AbortVersion(string) error
 
@@ -135,6 +160,9 @@ type Connector interface {
 	// AbortPatchesFromPullRequest aborts patches with the same PR Number,
 	// in the same repository, at the pull request's close time
 	AbortPatchesFromPullRequest(*github.PullRequestEvent) error
+	// AddGithubCommentCommand queues the Evergreen command in a pull
+	// request comment. Comments that are not commands are ignored.
+	AddGithubCommentCommand(amboy.Queue, string, *github.IssueCommentEvent) error
 
 	// RestartVersion restarts all completed tasks of a version given its ID and the caller.
 	RestartVersion(string, string) error
@@ -142,6 +170,17 @@ type Connector interface {
 	SetPatchPriority(string, int64) error
 	SetPatchActivated(string, string, bool) error
 
+	// CreatePatch processes a patch intent into a new patch.
+	CreatePatch(patch.Intent) (*patch.Patch, error)
+	// ConfigurePatch changes the description, variants and tasks of the
+	// patch with the given ID, and finalizes it if requested.
+	ConfigurePatch(string, *PatchConfiguration) (*patch.Patch, error)
+	// SetPatchModule adds or replaces the changes to a module of a patch,
+	// given the patch ID, module name, base githash and diff.
+	SetPatchModule(string, string, string, string) error
+	// RemovePatchModule removes the changes to a module from a patch.
+	RemovePatchModule(string, string) error
+
 	// GetAdminSettings/SetAdminSettings retrieves/sets the system-wide settings document
 	GetAdminSettings() (*admin.AdminSettings, error)
 	SetAdminSettings(*admin.AdminSettings, *user.DBUser) error
@@ -163,6 +202,13 @@ type Connector interface {
 	AddPublicKey(*user.DBUser, string, string) error
 	DeletePublicKey(*user.DBUser, string) error
 
+	// GetSubscriptions returns the notification subscriptions of a user.
+	GetSubscriptions(string) ([]event.Subscription, error)
+	// SaveSubscription creates or updates a notification subscription.
+	SaveSubscription(*event.Subscription) error
+	// DeleteSubscription removes a user's notification subscription.
+	DeleteSubscription(string, string) error
+
 	AddPatchIntent(patch.Intent, amboy.Queue) error
 
 	SetHostStatus(*host.Host, string) error
@@ -171,6 +217,12 @@ type Connector interface {
 	// TerminateHost terminates the given host via the cloud provider's API
 	TerminateHost(*host.Host) error
 
+	// StopHost stops the given host via the cloud provider's API
+	StopHost(*host.Host) error
+
+	// StartHost starts the given stopped host via the cloud provider's API
+	StartHost(*host.Host) error
+
 	// FindProjectAliases queries the database to find all aliases.
 	FindProjectAliases(string) ([]model.PatchDefinition, error)
 
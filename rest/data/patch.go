@@ -6,16 +6,33 @@ import (
 	"strings"
 	"time"
 
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/rest"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/units"
 	"github.com/google/go-github/github"
+	"github.com/mongodb/amboy"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
 	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/yaml.v2"
 )
 
+// PatchConfiguration holds the changes that can be made to an existing
+// patch through the REST API.
+type PatchConfiguration struct {
+	Description *string
+	Variants    []string
+	Tasks       []string
+	Alias       string
+	Finalize    bool
+}
+
 // DBPatchConnector is a struct that implements the Patch related methods
 // from the Connector through interactions with the backing database.
 type DBPatchConnector struct{}
@@ -113,6 +130,210 @@ func (p *DBPatchConnector) AbortPatchesFromPullRequest(event *github.PullRequest
 	return nil
 }
 
+// AddGithubCommentCommand queues a job to run the Evergreen command that a
+// user commented on a pull request.
+func (p *DBPatchConnector) AddGithubCommentCommand(q amboy.Queue, msgID string, event *github.IssueCommentEvent) error {
+	command, ok, err := verifyIssueCommentEventForCommand(event)
+	if err != nil || !ok {
+		return err
+	}
+
+	j := units.NewGithubCommentCommandJob(msgID, event.Repo.Owner.GetLogin(), event.Repo.GetName(),
+		event.Issue.GetNumber(), event.Comment.GetID(), event.Comment.User.GetLogin(), command)
+	if err = q.Put(j); err != nil {
+		return &rest.APIError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    "failed to add comment command job to queue",
+		}
+	}
+
+	grip.Info(message.Fields{
+		"message":   "github comment command queued",
+		"job":       j.ID(),
+		"repo":      event.Repo.GetFullName(),
+		"pr_number": event.Issue.GetNumber(),
+		"user":      event.Comment.User.GetLogin(),
+		"command":   command.String(),
+	})
+	return nil
+}
+
+// CreatePatch stores the intent and processes it into a new patch, in the
+// same way as patches submitted through the legacy API.
+func (pc *DBPatchConnector) CreatePatch(intent patch.Intent) (*patch.Patch, error) {
+	if err := intent.Insert(); err != nil {
+		return nil, errors.Wrap(err, "couldn't insert patch intent")
+	}
+
+	patchId := bson.NewObjectId()
+	job := units.NewPatchIntentProcessor(patchId, intent)
+	job.Run()
+	if err := job.Error(); err != nil {
+		return nil, &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("error processing patch: %s", err.Error()),
+		}
+	}
+
+	return pc.FindPatchById(patchId.Hex())
+}
+
+// ConfigurePatch changes the description and the variants and tasks of a
+// patch, finalizing it if requested. Tasks added to a patch that has
+// already been finalized are added to its version.
+func (pc *DBPatchConnector) ConfigurePatch(patchId string, config *PatchConfiguration) (*patch.Patch, error) {
+	p, err := pc.FindPatchById(patchId)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Description != nil {
+		if err = p.SetDescription(*config.Description); err != nil {
+			return nil, errors.Wrap(err, "error setting description")
+		}
+	}
+
+	project := &model.Project{}
+	if err = yaml.Unmarshal([]byte(p.PatchedConfig), project); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling project config")
+	}
+
+	var tasks model.TaskVariantPairs
+	if len(config.Variants) > 0 || len(config.Tasks) > 0 || config.Alias != "" {
+		p.BuildVariants = config.Variants
+		p.Tasks = config.Tasks
+		project.BuildProjectTVPairs(p, config.Alias)
+
+		tasks = model.VariantTasksToTVPairs(p.VariantsTasks)
+		if err = model.ValidateTVPairs(project, tasks.ExecTasks); err != nil {
+			return nil, &rest.APIError{
+				StatusCode: http.StatusBadRequest,
+				Message:    err.Error(),
+			}
+		}
+		if err = p.SetVariantsTasks(p.VariantsTasks); err != nil {
+			return nil, errors.Wrap(err, "error setting patch variants and tasks")
+		}
+	}
+
+	if p.Version != "" {
+		if len(tasks.ExecTasks) == 0 {
+			return p, nil
+		}
+		patchVersion, err := version.FindOne(version.ById(p.Version))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error finding version %s", p.Version)
+		}
+		if patchVersion == nil {
+			return nil, errors.Errorf("couldn't find version %s", p.Version)
+		}
+		if err = model.AddNewTasksForPatch(p, patchVersion, project, tasks); err != nil {
+			return nil, errors.Wrapf(err, "error creating new tasks for version %s", patchVersion.Id)
+		}
+		if err = model.AddNewBuildsForPatch(p, patchVersion, project, tasks); err != nil {
+			return nil, errors.Wrapf(err, "error creating new builds for version %s", patchVersion.Id)
+		}
+	} else if config.Finalize {
+		if len(p.VariantsTasks) == 0 {
+			return nil, &rest.APIError{
+				StatusCode: http.StatusBadRequest,
+				Message:    "patch has no tasks to schedule",
+			}
+		}
+		githubOauthToken, err := evergreen.GetEnvironment().Settings().GetGithubOauthToken()
+		if err != nil {
+			return nil, err
+		}
+		p.Activated = true
+		if _, err = model.FinalizePatch(p, evergreen.PatchVersionRequester, githubOauthToken); err != nil {
+			return nil, errors.Wrap(err, "error finalizing patch")
+		}
+	}
+
+	return pc.FindPatchById(patchId)
+}
+
+// SetPatchModule adds or replaces the changes to a module of an unfinalized
+// patch.
+func (pc *DBPatchConnector) SetPatchModule(patchId, module, githash, patchContent string) error {
+	p, err := pc.FindPatchById(patchId)
+	if err != nil {
+		return err
+	}
+	if p.Activated {
+		return &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "can't change the modules of a finalized patch",
+		}
+	}
+
+	project := &model.Project{}
+	if err = yaml.Unmarshal([]byte(p.PatchedConfig), project); err != nil {
+		return errors.Wrap(err, "error unmarshaling project config")
+	}
+	mod, err := project.GetModuleByName(module)
+	if err != nil || mod == nil {
+		return &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("no such module: %s", module),
+		}
+	}
+
+	githubOauthToken, err := evergreen.GetEnvironment().Settings().GetGithubOauthToken()
+	if err != nil {
+		return err
+	}
+	repoOwner, repo := mod.GetRepoOwnerAndName()
+	commitInfo, err := thirdparty.GetCommitEvent(githubOauthToken, repoOwner, repo, githash)
+	if err != nil {
+		return errors.Wrapf(err, "error finding commit %s", githash)
+	}
+	if commitInfo == nil {
+		return &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("commit hash %s doesn't seem to exist", githash),
+		}
+	}
+
+	summaries, err := thirdparty.GetPatchSummaries(patchContent)
+	if err != nil {
+		return &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("problem reading patch: %s", err.Error()),
+		}
+	}
+
+	patchFileId := bson.NewObjectId().Hex()
+	if err = db.WriteGridFile(patch.GridFSPrefix, patchFileId, strings.NewReader(patchContent)); err != nil {
+		return errors.Wrap(err, "failed to write patch file to db")
+	}
+
+	return p.UpdateModulePatch(patch.ModulePatch{
+		ModuleName: module,
+		Githash:    githash,
+		PatchSet: patch.PatchSet{
+			PatchFileId: patchFileId,
+			Summary:     summaries,
+		},
+	})
+}
+
+// RemovePatchModule removes the changes to a module from an unfinalized
+// patch.
+func (pc *DBPatchConnector) RemovePatchModule(patchId, module string) error {
+	p, err := pc.FindPatchById(patchId)
+	if err != nil {
+		return err
+	}
+	if p.Activated {
+		return &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "can't change the modules of a finalized patch",
+		}
+	}
+	return p.RemoveModulePatch(module)
+}
+
 // MockPatchConnector is a struct that implements the Patch related methods
 // from the Connector through interactions with he backing database.
 type MockPatchConnector struct {
@@ -240,6 +461,92 @@ func (c *MockPatchConnector) AbortPatchesFromPullRequest(event *github.PullReque
 	return err
 }
 
+func (c *MockPatchConnector) AddGithubCommentCommand(_ amboy.Queue, _ string, event *github.IssueCommentEvent) error {
+	_, _, err := verifyIssueCommentEventForCommand(event)
+	return err
+}
+
+// CreatePatch adds a patch built from the intent to CachedPatches.
+func (pc *MockPatchConnector) CreatePatch(intent patch.Intent) (*patch.Patch, error) {
+	p := intent.NewPatch()
+	p.Id = bson.NewObjectId()
+	p.CreateTime = time.Now()
+	p.Activated = intent.ShouldFinalizePatch()
+	pc.CachedPatches = append(pc.CachedPatches, *p)
+	return pc.FindPatchById(p.Id.Hex())
+}
+
+// ConfigurePatch sets the description, variants and tasks of the cached
+// patch, activating it if it is finalized.
+func (pc *MockPatchConnector) ConfigurePatch(patchId string, config *PatchConfiguration) (*patch.Patch, error) {
+	p, err := pc.FindPatchById(patchId)
+	if err != nil {
+		return nil, err
+	}
+	if config.Description != nil {
+		p.Description = *config.Description
+	}
+	if len(config.Variants) > 0 || len(config.Tasks) > 0 {
+		vts := []patch.VariantTasks{}
+		for _, v := range config.Variants {
+			vts = append(vts, patch.VariantTasks{Variant: v, Tasks: config.Tasks})
+		}
+		p.SyncVariantsTasks(vts)
+	}
+	if config.Finalize {
+		p.Activated = true
+	}
+	return p, nil
+}
+
+// SetPatchModule adds or replaces the module patch on the cached patch.
+func (pc *MockPatchConnector) SetPatchModule(patchId, module, githash, patchContent string) error {
+	p, err := pc.FindPatchById(patchId)
+	if err != nil {
+		return err
+	}
+	if p.Activated {
+		return &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "can't change the modules of a finalized patch",
+		}
+	}
+	modulePatch := patch.ModulePatch{
+		ModuleName: module,
+		Githash:    githash,
+		PatchSet:   patch.PatchSet{Patch: patchContent},
+	}
+	for i := range p.Patches {
+		if p.Patches[i].ModuleName == module {
+			p.Patches[i] = modulePatch
+			return nil
+		}
+	}
+	p.Patches = append(p.Patches, modulePatch)
+	return nil
+}
+
+// RemovePatchModule removes the module patch from the cached patch.
+func (pc *MockPatchConnector) RemovePatchModule(patchId, module string) error {
+	p, err := pc.FindPatchById(patchId)
+	if err != nil {
+		return err
+	}
+	if p.Activated {
+		return &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "can't change the modules of a finalized patch",
+		}
+	}
+	for i := range p.Patches {
+		if p.Patches[i].ModuleName == module {
+			p.Patches = append(p.Patches[:i], p.Patches[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
 func verifyPullRequestEventForAbort(event *github.PullRequestEvent) (string, string, error) {
 	if event.Number == nil || event.Repo == nil ||
 		event.Repo.FullName == nil || event.PullRequest == nil ||
@@ -260,3 +567,27 @@ func verifyPullRequestEventForAbort(event *github.PullRequestEvent) (string, str
 
 	return baseRepo[0], baseRepo[1], nil
 }
+
+// verifyIssueCommentEventForCommand returns the command in a new comment on
+// a pull request, and false if the event is not for one.
+func verifyIssueCommentEventForCommand(event *github.IssueCommentEvent) (units.GithubCommentCommand, bool, error) {
+	if event == nil || event.GetAction() != "created" || event.Issue == nil || event.Issue.PullRequestLinks == nil {
+		return units.GithubCommentCommand{}, false, nil
+	}
+	command, ok := units.ParseGithubCommentCommand(event.GetComment().GetBody())
+	if !ok {
+		return units.GithubCommentCommand{}, false, nil
+	}
+
+	if event.Issue.GetNumber() == 0 || event.Comment.GetID() == 0 ||
+		event.Comment.User == nil || event.Comment.User.GetLogin() == "" ||
+		event.Repo == nil || event.Repo.GetName() == "" ||
+		event.Repo.Owner == nil || event.Repo.Owner.GetLogin() == "" {
+		return units.GithubCommentCommand{}, false, &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "pull request comment event is malformed",
+		}
+	}
+
+	return command, true, nil
+}
@@ -2,19 +2,37 @@ package data
 
 import (
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/rest"
 	"github.com/evergreen-ci/evergreen/units"
 	"github.com/google/go-github/github"
 	"github.com/mongodb/amboy"
 )
 
+const gitTagRefPrefix = "refs/tags/"
+
 type RepoTrackerConnector struct{}
 
 func (c *RepoTrackerConnector) TriggerRepotracker(q amboy.Queue, msgID string, event *github.PushEvent) error {
 	if err := validatePushEvent(event); err != nil {
 		return err
 	}
+	if tag, ok := pushedGitTag(event); ok {
+		if event.GetDeleted() {
+			return nil
+		}
+		if err := q.Put(units.NewGitTagVersionJob(msgID, *event.Repo.Owner.Name,
+			*event.Repo.Name, tag, gitTagRevision(event))); err != nil {
+			return &rest.APIError{
+				StatusCode: http.StatusInternalServerError,
+				Message:    "failed to add git tag version job to queue",
+			}
+		}
+		return nil
+	}
 	if err := q.Put(units.NewRepotrackerJob(msgID, *event.Repo.Owner.Name,
 		*event.Repo.Name)); err != nil {
 		return &rest.APIError{
@@ -43,3 +61,31 @@ func validatePushEvent(event *github.PushEvent) error {
 	}
 	return nil
 }
+
+// pushedGitTag returns the name of the tag that the push event is for, and
+// false if it is not for a tag.
+func pushedGitTag(event *github.PushEvent) (string, bool) {
+	if !strings.HasPrefix(event.GetRef(), gitTagRefPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(event.GetRef(), gitTagRefPrefix), true
+}
+
+// gitTagRevision returns the commit that a pushed tag points to. The
+// revision is empty if the push event did not include the commit.
+func gitTagRevision(event *github.PushEvent) model.Revision {
+	commit := event.GetHeadCommit()
+	if commit == nil || commit.GetID() == "" {
+		return model.Revision{}
+	}
+	revision := model.Revision{
+		RevisionMessage: commit.GetMessage(),
+		Revision:        commit.GetID(),
+		CreateTime:      time.Now(),
+	}
+	if commit.Author != nil {
+		revision.Author = commit.Author.GetName()
+		revision.AuthorEmail = commit.Author.GetEmail()
+	}
+	return revision
+}
@@ -30,3 +30,31 @@ func TestValidatePushEvent(t *testing.T) {
 	err = validatePushEvent(&event)
 	assert.Nil(err)
 }
+
+func TestPushedGitTag(t *testing.T) {
+	assert := assert.New(t) //nolint
+
+	event := github.PushEvent{Ref: github.String("refs/heads/master")}
+	_, ok := pushedGitTag(&event)
+	assert.False(ok)
+	assert.Empty(gitTagRevision(&event).Revision)
+
+	event.Ref = github.String("refs/tags/v1.2.3")
+	tag, ok := pushedGitTag(&event)
+	assert.True(ok)
+	assert.Equal("v1.2.3", tag)
+
+	event.HeadCommit = &github.PushEventCommit{
+		ID:      github.String("abcdef"),
+		Message: github.String("release 1.2.3"),
+		Author: &github.CommitAuthor{
+			Name:  github.String("baxterthehacker"),
+			Email: github.String("baxterthehacker@users.noreply.github.com"),
+		},
+	}
+	revision := gitTagRevision(&event)
+	assert.Equal("abcdef", revision.Revision)
+	assert.Equal("release 1.2.3", revision.RevisionMessage)
+	assert.Equal("baxterthehacker", revision.Author)
+	assert.Equal("baxterthehacker@users.noreply.github.com", revision.AuthorEmail)
+}
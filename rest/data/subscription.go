@@ -0,0 +1,116 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/rest"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// DBSubscriptionConnector is a struct that implements the Subscription
+// related methods from the Connector through interactions with the backing
+// database.
+type DBSubscriptionConnector struct{}
+
+// GetSubscriptions returns the subscriptions of a user.
+func (sc *DBSubscriptionConnector) GetSubscriptions(owner string) ([]event.Subscription, error) {
+	return event.FindSubscriptionsByOwner(owner)
+}
+
+// SaveSubscription creates the subscription, or updates it if it already
+// exists. Users can only update their own subscriptions, and can only
+// subscribe to projects that exist.
+func (sc *DBSubscriptionConnector) SaveSubscription(s *event.Subscription) error {
+	for _, identifier := range s.SelectorData(event.SelectorProject) {
+		ref, err := model.FindOneProjectRef(identifier)
+		if err != nil {
+			return errors.Wrapf(err, "error finding project '%s'", identifier)
+		}
+		if ref == nil {
+			return &rest.APIError{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("project '%s' not found", identifier),
+			}
+		}
+	}
+	if s.ID != "" {
+		existing, err := event.FindSubscriptionById(s.ID.Hex())
+		if err != nil {
+			return errors.Wrapf(err, "error finding subscription '%s'", s.ID.Hex())
+		}
+		if existing == nil || existing.Owner != s.Owner {
+			return &rest.APIError{
+				StatusCode: http.StatusNotFound,
+				Message:    fmt.Sprintf("subscription '%s' not found", s.ID.Hex()),
+			}
+		}
+	}
+	return errors.Wrap(s.Upsert(), "error saving subscription")
+}
+
+// DeleteSubscription removes a user's subscription.
+func (sc *DBSubscriptionConnector) DeleteSubscription(owner, id string) error {
+	existing, err := event.FindSubscriptionById(id)
+	if err != nil {
+		return errors.Wrapf(err, "error finding subscription '%s'", id)
+	}
+	if existing == nil || existing.Owner != owner {
+		return &rest.APIError{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("subscription '%s' not found", id),
+		}
+	}
+	return errors.Wrap(event.RemoveSubscription(id), "error removing subscription")
+}
+
+// MockSubscriptionConnector stores a cached set of subscriptions that are
+// queried against by the implementations of the Connector interface's
+// Subscription related functions.
+type MockSubscriptionConnector struct {
+	CachedSubscriptions []event.Subscription
+}
+
+func (msc *MockSubscriptionConnector) GetSubscriptions(owner string) ([]event.Subscription, error) {
+	subscriptions := []event.Subscription{}
+	for _, s := range msc.CachedSubscriptions {
+		if s.Owner == owner {
+			subscriptions = append(subscriptions, s)
+		}
+	}
+	return subscriptions, nil
+}
+
+func (msc *MockSubscriptionConnector) SaveSubscription(s *event.Subscription) error {
+	if s.ID == "" {
+		s.ID = bson.NewObjectId()
+		msc.CachedSubscriptions = append(msc.CachedSubscriptions, *s)
+		return nil
+	}
+	for i, existing := range msc.CachedSubscriptions {
+		if existing.ID == s.ID && existing.Owner == s.Owner {
+			msc.CachedSubscriptions[i] = *s
+			return nil
+		}
+	}
+	return &rest.APIError{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("subscription '%s' not found", s.ID.Hex()),
+	}
+}
+
+func (msc *MockSubscriptionConnector) DeleteSubscription(owner, id string) error {
+	for i, existing := range msc.CachedSubscriptions {
+		if existing.ID.Hex() == id && existing.Owner == owner {
+			msc.CachedSubscriptions = append(msc.CachedSubscriptions[:i], msc.CachedSubscriptions[i+1:]...)
+			return nil
+		}
+	}
+	return &rest.APIError{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("subscription '%s' not found", id),
+	}
+}
@@ -3,12 +3,15 @@ package data
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/user"
 	"github.com/evergreen-ci/evergreen/model/version"
 	"github.com/evergreen-ci/evergreen/rest"
+	"github.com/evergreen-ci/evergreen/util"
 )
 
 // DBVersionConnector is a struct that implements Version related methods
@@ -54,6 +57,15 @@ func (vc *DBVersionConnector) FindVersionById(versionId string) (*version.Versio
 	return v, nil
 }
 
+// FindVersionsByIds queries the backing database for all of the versions
+// with the given ids.
+func (vc *DBVersionConnector) FindVersionsByIds(versionIds []string) ([]version.Version, error) {
+	if len(versionIds) == 0 {
+		return []version.Version{}, nil
+	}
+	return version.Find(version.ByIds(versionIds))
+}
+
 // AbortVersion aborts all tasks of a version given its ID.
 // It wraps the service level AbortVersion.
 func (vc *DBVersionConnector) AbortVersion(versionId string) error {
@@ -83,6 +95,33 @@ func (vc *DBVersionConnector) RestartVersion(versionId string, caller string) er
 	return model.RestartVersion(versionId, taskIds, true, caller)
 }
 
+// CompareVersions returns how the tasks of a version differ from the tasks
+// of a base version, with the artifacts that the user may see. Either
+// version may be a patch.
+func (vc *DBVersionConnector) CompareVersions(baseVersionId, versionId string, u *user.DBUser) (*model.VersionComparison, error) {
+	for _, id := range []string{baseVersionId, versionId} {
+		if _, err := vc.FindVersionById(id); err != nil {
+			return nil, err
+		}
+	}
+	return model.CompareVersions(baseVersionId, versionId, u, evergreen.GetEnvironment().Settings())
+}
+
+// FindDownstreamVersions queries the backing database for the versions
+// created by triggers on the version with the given versionId.
+func (vc *DBVersionConnector) FindDownstreamVersions(versionId string) ([]version.Version, error) {
+	if _, err := vc.FindVersionById(versionId); err != nil {
+		return nil, err
+	}
+	return version.Find(version.ByUpstreamVersion(versionId))
+}
+
+// FindVersionsByProject queries the backing database for the versions of a
+// project created before the given time, or after it if sortAsc is set.
+func (vc *DBVersionConnector) FindVersionsByProject(projectId string, ts time.Time, limit int, sortAsc bool) ([]version.Version, error) {
+	return version.Find(version.ByProjectIdAndCreateTime(projectId, ts, limit, sortAsc))
+}
+
 // MockVersionConnector stores a cached set of tasks that are queried against by the
 // implementations of the Connector interface's Version related functions.
 type MockVersionConnector struct {
@@ -130,6 +169,18 @@ func (mvc *MockVersionConnector) FindVersionById(versionId string) (*version.Ver
 	}
 }
 
+// FindVersionsByIds is the mock implementation of the function for the Connector interface
+// without needing to use a database. It returns the cached versions with matching ids.
+func (mvc *MockVersionConnector) FindVersionsByIds(versionIds []string) ([]version.Version, error) {
+	versions := []version.Version{}
+	for _, v := range mvc.CachedVersions {
+		if util.StringSliceContains(versionIds, v.Id) {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
 // AbortVersion aborts all tasks of a version given its ID. Specifically, it sets the
 // Aborted key of the tasks to true if they are currently in abortable statuses.
 func (mvc *MockVersionConnector) AbortVersion(versionId string) error {
@@ -151,3 +202,68 @@ func (mvc *MockVersionConnector) RestartVersion(versionId string, caller string)
 	mvc.CachedRestartedVersions[versionId] = caller
 	return nil
 }
+
+// CompareVersions is the mock implementation of the function for the Connector
+// interface without needing to use a database. It compares the cached tasks of
+// the versions, which have no artifacts.
+func (mvc *MockVersionConnector) CompareVersions(baseVersionId, versionId string, u *user.DBUser) (*model.VersionComparison, error) {
+	tasks := map[string][]task.Task{}
+	for _, id := range []string{baseVersionId, versionId} {
+		if _, err := mvc.FindVersionById(id); err != nil {
+			return nil, err
+		}
+		for _, t := range mvc.CachedTasks {
+			if t.Version == id {
+				tasks[id] = append(tasks[id], t)
+			}
+		}
+	}
+	return model.CompareVersionTasks(baseVersionId, versionId, tasks[baseVersionId], tasks[versionId], nil, nil), nil
+}
+
+// FindDownstreamVersions is the mock implementation of the function for the
+// Connector interface without needing to use a database. It returns the cached
+// versions triggered by the given version.
+func (mvc *MockVersionConnector) FindDownstreamVersions(versionId string) ([]version.Version, error) {
+	if _, err := mvc.FindVersionById(versionId); err != nil {
+		return nil, err
+	}
+	versions := []version.Version{}
+	for _, v := range mvc.CachedVersions {
+		if v.TriggeredBy != nil && v.TriggeredBy.Version == versionId {
+			versions = append(versions, v)
+		}
+	}
+	return versions, nil
+}
+
+// FindVersionsByProject queries the cached versions for the versions of a
+// project. Assumes CachedVersions is sorted by increasing creation time.
+func (mvc *MockVersionConnector) FindVersionsByProject(projectId string, ts time.Time, limit int, sortAsc bool) ([]version.Version, error) {
+	versions := []version.Version{}
+	if limit <= 0 {
+		return versions, nil
+	}
+	if sortAsc {
+		for i := 0; i < len(mvc.CachedVersions); i++ {
+			v := mvc.CachedVersions[i]
+			if v.Identifier == projectId && v.CreateTime.After(ts) {
+				versions = append(versions, v)
+				if len(versions) == limit {
+					break
+				}
+			}
+		}
+	} else {
+		for i := len(mvc.CachedVersions) - 1; i >= 0; i-- {
+			v := mvc.CachedVersions[i]
+			if v.Identifier == projectId && !v.CreateTime.After(ts) {
+				versions = append(versions, v)
+				if len(versions) == limit {
+					break
+				}
+			}
+		}
+	}
+	return versions, nil
+}
@@ -0,0 +1,98 @@
+package graphql
+
+// Document is the parsed representation of a GraphQL request body. It
+// holds every operation and fragment definition found in the query text.
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// Operation is a single named or anonymous operation in a Document.
+// Only "query" operations are supported, since the API is read-only.
+type Operation struct {
+	Type         string
+	Name         string
+	Variables    []VariableDefinition
+	SelectionSet []Selection
+}
+
+// VariableDefinition describes a variable declared by an operation,
+// including its optional default value.
+type VariableDefinition struct {
+	Name     string
+	Type     string
+	Default  Value
+	Required bool
+}
+
+// Fragment is a named, reusable selection set.
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	SelectionSet  []Selection
+}
+
+// Selection is implemented by every member of a selection set: fields,
+// fragment spreads and inline fragments.
+type Selection interface {
+	directives() []Directive
+}
+
+// FieldSelection is a request for a single field of an object, optionally aliased
+// and with its own arguments and sub-selections.
+type FieldSelection struct {
+	Alias        string
+	Name         string
+	Arguments    []Argument
+	Directives   []Directive
+	SelectionSet []Selection
+}
+
+// ResponseKey returns the key the field's value is written under in the
+// response, which is the alias if one was given.
+func (f *FieldSelection) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// FragmentSpread is a "...Name" reference to a named fragment.
+type FragmentSpread struct {
+	Name       string
+	Directives []Directive
+}
+
+// InlineFragment is an anonymous "... on Type { }" selection.
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []Directive
+	SelectionSet  []Selection
+}
+
+func (f *FieldSelection) directives() []Directive { return f.Directives }
+func (f *FragmentSpread) directives() []Directive { return f.Directives }
+func (f *InlineFragment) directives() []Directive { return f.Directives }
+
+// Argument is a name/value pair passed to a field or directive.
+type Argument struct {
+	Name  string
+	Value Value
+}
+
+// Directive is an "@name(args)" annotation. The executor understands the
+// standard @include and @skip directives.
+type Directive struct {
+	Name      string
+	Arguments []Argument
+}
+
+// Value is a literal in the query text. It is one of: nil, bool, int64,
+// float64, string, EnumValue, Variable, []Value or map[string]Value.
+type Value interface{}
+
+// Variable is a reference to an operation variable.
+type Variable string
+
+// EnumValue is a bare identifier used as a value.
+type EnumValue string
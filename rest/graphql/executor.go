@@ -0,0 +1,575 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// Request is the standard GraphQL-over-HTTP request body.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Response is the standard GraphQL-over-HTTP response body. Data is nil
+// when the request could not be executed at all.
+type Response struct {
+	Data   interface{} `json:"data"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error describes a problem with a request or with resolving a field.
+// Path is set for field errors and identifies the value that was nulled.
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Execute parses, validates and runs a request against the schema. Parse
+// and validation errors, including exceeding the complexity or depth
+// limits, are returned without executing any resolvers. Errors from
+// individual resolvers null the affected field and are collected
+// alongside the partial data.
+func (s *Schema) Execute(ctx context.Context, req Request) *Response {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	op, err := doc.operation(req.OperationName)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	e := &executor{schema: s, doc: doc, op: op}
+	if e.vars, err = coerceVariables(op, req.Variables); err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	if _, err = e.complexity(op); err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	results := e.executeSelectionSet(ctx, s.Query, []interface{}{nil}, [][]interface{}{{}}, op.SelectionSet)
+	return &Response{Data: results[0], Errors: e.errors}
+}
+
+// Complexity validates a request against the schema and returns its
+// estimated cost without executing it.
+func (s *Schema) Complexity(req Request) (int, error) {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return 0, err
+	}
+	op, err := doc.operation(req.OperationName)
+	if err != nil {
+		return 0, err
+	}
+	e := &executor{schema: s, doc: doc, op: op}
+	if e.vars, err = coerceVariables(op, req.Variables); err != nil {
+		return 0, err
+	}
+	return e.complexity(op)
+}
+
+func (d *Document) operation(name string) (*Operation, error) {
+	if name == "" {
+		if len(d.Operations) != 1 {
+			return nil, errors.New("operationName is required when the document contains multiple operations")
+		}
+		return d.Operations[0], nil
+	}
+	for _, op := range d.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, errors.Errorf("operation '%s' is not defined", name)
+}
+
+func coerceVariables(op *Operation, provided map[string]interface{}) (map[string]interface{}, error) {
+	vars := map[string]interface{}{}
+	for _, def := range op.Variables {
+		v, ok := provided[def.Name]
+		switch {
+		case ok:
+			vars[def.Name] = v
+		case def.Default != nil:
+			vars[def.Name] = valueToGo(def.Default, nil)
+		case def.Required:
+			return nil, errors.Errorf("variable '$%s' of type '%s' is required", def.Name, def.Type)
+		}
+	}
+	return vars, nil
+}
+
+type executor struct {
+	schema *Schema
+	doc    *Document
+	op     *Operation
+	vars   map[string]interface{}
+	errors []*Error
+}
+
+// collectedField is a field in a selection set after fragments have been
+// expanded and fields sharing a response key have been merged.
+type collectedField struct {
+	key        string
+	ast        *FieldSelection
+	def        *Field
+	selections []Selection
+}
+
+func (e *executor) collectFields(obj *Object, sels []Selection) []*collectedField {
+	out := []*collectedField{}
+	index := map[string]*collectedField{}
+	e.collectInto(obj, sels, &out, index)
+	return out
+}
+
+func (e *executor) collectInto(obj *Object, sels []Selection, out *[]*collectedField, index map[string]*collectedField) {
+	for _, sel := range sels {
+		if !e.included(sel.directives()) {
+			continue
+		}
+		switch s := sel.(type) {
+		case *FieldSelection:
+			key := s.ResponseKey()
+			if cf, ok := index[key]; ok {
+				cf.selections = append(cf.selections, s.SelectionSet...)
+				continue
+			}
+			cf := &collectedField{
+				key:        key,
+				ast:        s,
+				def:        obj.Fields[s.Name],
+				selections: append([]Selection{}, s.SelectionSet...),
+			}
+			index[key] = cf
+			*out = append(*out, cf)
+		case *FragmentSpread:
+			e.collectInto(obj, e.doc.Fragments[s.Name].SelectionSet, out, index)
+		case *InlineFragment:
+			e.collectInto(obj, s.SelectionSet, out, index)
+		}
+	}
+}
+
+// executeSelectionSet resolves a selection set against every source at
+// once, so that resolvers for sibling objects run before any of their
+// deferred values are forced. It returns one result per source.
+func (e *executor) executeSelectionSet(ctx context.Context, obj *Object, sources []interface{}, paths [][]interface{}, sels []Selection) []interface{} {
+	fields := e.collectFields(obj, sels)
+	results := make([]*orderedMap, len(sources))
+	for i := range sources {
+		results[i] = newOrderedMap()
+	}
+
+	values := make([][]interface{}, len(fields))
+	for fi, f := range fields {
+		values[fi] = make([]interface{}, len(sources))
+		if f.ast.Name == "__typename" {
+			for si := range sources {
+				values[fi][si] = obj.Name
+			}
+			continue
+		}
+
+		args := e.arguments(f.def, f.ast.Arguments)
+		for si, source := range sources {
+			v, err := f.def.Resolve(ctx, ResolveParams{Source: source, Args: args})
+			if err != nil {
+				e.fieldError(err, paths[si], f.key)
+				continue
+			}
+			values[fi][si] = v
+		}
+	}
+
+	for fi, f := range fields {
+		for si, v := range values[fi] {
+			thunk, ok := v.(Thunk)
+			if !ok {
+				continue
+			}
+			resolved, err := thunk()
+			if err != nil {
+				e.fieldError(err, paths[si], f.key)
+				resolved = nil
+			}
+			values[fi][si] = resolved
+		}
+	}
+
+	for fi, f := range fields {
+		if f.def == nil || f.def.Type == nil {
+			for si, v := range values[fi] {
+				if isNil(v) {
+					v = nil
+				}
+				results[si].set(f.key, v)
+			}
+			continue
+		}
+
+		children := []interface{}{}
+		childPaths := [][]interface{}{}
+		assign := []func(interface{}){}
+		for si, v := range values[fi] {
+			if isNil(v) {
+				results[si].set(f.key, nil)
+				continue
+			}
+			path := appendPath(paths[si], f.key)
+
+			if !f.def.List {
+				result := results[si]
+				key := f.key
+				children = append(children, v)
+				childPaths = append(childPaths, path)
+				assign = append(assign, func(r interface{}) { result.set(key, r) })
+				continue
+			}
+
+			rv := reflect.ValueOf(v)
+			if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+				e.fieldError(errors.Errorf("expected a list but resolved %T", v), paths[si], f.key)
+				results[si].set(f.key, nil)
+				continue
+			}
+			list := make([]interface{}, rv.Len())
+			results[si].set(f.key, list)
+			for j := 0; j < rv.Len(); j++ {
+				idx := j
+				elem := rv.Index(j).Interface()
+				if isNil(elem) {
+					continue
+				}
+				children = append(children, elem)
+				childPaths = append(childPaths, appendPath(path, idx))
+				assign = append(assign, func(r interface{}) { list[idx] = r })
+			}
+		}
+
+		if len(children) == 0 {
+			continue
+		}
+		for i, r := range e.executeSelectionSet(ctx, f.def.Type, children, childPaths, f.selections) {
+			assign[i](r)
+		}
+	}
+
+	out := make([]interface{}, len(results))
+	for i, r := range results {
+		out[i] = r
+	}
+	return out
+}
+
+func (e *executor) fieldError(err error, path []interface{}, key string) {
+	e.errors = append(e.errors, &Error{
+		Message: err.Error(),
+		Path:    appendPath(path, key),
+	})
+}
+
+func appendPath(path []interface{}, elem interface{}) []interface{} {
+	out := make([]interface{}, len(path), len(path)+1)
+	copy(out, path)
+	return append(out, elem)
+}
+
+// arguments builds the argument map for a field, substituting variables
+// and filling in defaults for arguments that were not given.
+func (e *executor) arguments(def *Field, args []Argument) map[string]interface{} {
+	out := map[string]interface{}{}
+	for name, v := range def.Args {
+		if v != nil {
+			out[name] = v
+		}
+	}
+	for _, arg := range args {
+		v := valueToGo(arg.Value, e.vars)
+		if v == nil {
+			if _, ok := out[arg.Name]; ok {
+				continue
+			}
+		}
+		out[arg.Name] = v
+	}
+	return out
+}
+
+func (e *executor) included(dirs []Directive) bool {
+	for _, dir := range dirs {
+		var cond bool
+		for _, arg := range dir.Arguments {
+			if arg.Name == "if" {
+				cond, _ = valueToGo(arg.Value, e.vars).(bool)
+			}
+		}
+		switch dir.Name {
+		case "include":
+			if !cond {
+				return false
+			}
+		case "skip":
+			if cond {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// complexity validates the operation against the schema and computes its
+// estimated cost, enforcing the schema's complexity and depth limits.
+func (e *executor) complexity(op *Operation) (int, error) {
+	maxComplexity := e.schema.MaxComplexity
+	if maxComplexity <= 0 {
+		maxComplexity = DefaultMaxComplexity
+	}
+
+	cost, err := e.selectionCost(e.schema.Query, op.SelectionSet, 1, map[string]bool{})
+	if err != nil {
+		return 0, err
+	}
+	if cost > maxComplexity {
+		return cost, errors.Errorf("query complexity of %d exceeds the limit of %d", cost, maxComplexity)
+	}
+	return cost, nil
+}
+
+func (e *executor) selectionCost(obj *Object, sels []Selection, depth int, fragments map[string]bool) (int, error) {
+	maxDepth := e.schema.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	if depth > maxDepth {
+		return 0, errors.Errorf("query depth exceeds the limit of %d", maxDepth)
+	}
+
+	total := 0
+	for _, sel := range sels {
+		if !e.included(sel.directives()) {
+			continue
+		}
+
+		switch s := sel.(type) {
+		case *FieldSelection:
+			cost, err := e.fieldCost(obj, s, depth, fragments)
+			if err != nil {
+				return 0, err
+			}
+			total += cost
+		case *FragmentSpread:
+			frag, ok := e.doc.Fragments[s.Name]
+			if !ok {
+				return 0, errors.Errorf("fragment '%s' is not defined", s.Name)
+			}
+			if fragments[s.Name] {
+				return 0, errors.Errorf("fragment '%s' spreads itself", s.Name)
+			}
+			if frag.TypeCondition != obj.Name {
+				return 0, errors.Errorf("fragment '%s' on '%s' cannot be spread within '%s'",
+					s.Name, frag.TypeCondition, obj.Name)
+			}
+			fragments[s.Name] = true
+			cost, err := e.selectionCost(obj, frag.SelectionSet, depth, fragments)
+			delete(fragments, s.Name)
+			if err != nil {
+				return 0, err
+			}
+			total += cost
+		case *InlineFragment:
+			if s.TypeCondition != "" && s.TypeCondition != obj.Name {
+				return 0, errors.Errorf("inline fragment on '%s' cannot be used within '%s'",
+					s.TypeCondition, obj.Name)
+			}
+			cost, err := e.selectionCost(obj, s.SelectionSet, depth, fragments)
+			if err != nil {
+				return 0, err
+			}
+			total += cost
+		}
+	}
+	return total, nil
+}
+
+func (e *executor) fieldCost(obj *Object, f *FieldSelection, depth int, fragments map[string]bool) (int, error) {
+	if f.Name == "__typename" {
+		if len(f.SelectionSet) > 0 {
+			return 0, errors.New("field '__typename' cannot have a selection")
+		}
+		return 0, nil
+	}
+
+	def, ok := obj.Fields[f.Name]
+	if !ok {
+		return 0, errors.Errorf("field '%s' is not defined on type '%s'", f.Name, obj.Name)
+	}
+	for _, arg := range f.Arguments {
+		if _, ok := def.Args[arg.Name]; !ok {
+			return 0, errors.Errorf("field '%s' on type '%s' does not accept argument '%s'",
+				f.Name, obj.Name, arg.Name)
+		}
+		if v, ok := arg.Value.(Variable); ok {
+			if _, ok := e.vars[string(v)]; !ok && !e.declared(string(v)) {
+				return 0, errors.Errorf("variable '$%s' is not defined", v)
+			}
+		}
+	}
+
+	cost := def.Cost
+	if cost == 0 {
+		cost = 1
+	}
+	if def.Type == nil {
+		if len(f.SelectionSet) > 0 {
+			return 0, errors.Errorf("field '%s' on type '%s' is a scalar and cannot have a selection",
+				f.Name, obj.Name)
+		}
+		return cost, nil
+	}
+	if len(f.SelectionSet) == 0 {
+		return 0, errors.Errorf("field '%s' on type '%s' must have a selection of subfields",
+			f.Name, obj.Name)
+	}
+
+	childCost, err := e.selectionCost(def.Type, f.SelectionSet, depth+1, fragments)
+	if err != nil {
+		return 0, err
+	}
+	multiplier := 1
+	if def.Multiplier != nil {
+		multiplier = def.Multiplier(e.arguments(def, f.Arguments))
+	}
+	return cost + multiplier*childCost, nil
+}
+
+func (e *executor) declared(name string) bool {
+	for _, def := range e.op.Variables {
+		if def.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// valueToGo converts a literal from the query into a plain Go value,
+// substituting variables from vars.
+func valueToGo(v Value, vars map[string]interface{}) interface{} {
+	switch val := v.(type) {
+	case Variable:
+		return vars[string(val)]
+	case EnumValue:
+		return string(val)
+	case []Value:
+		out := make([]interface{}, len(val))
+		for i := range val {
+			out[i] = valueToGo(val[i], vars)
+		}
+		return out
+	case map[string]Value:
+		out := make(map[string]interface{}, len(val))
+		for k := range val {
+			out[k] = valueToGo(val[k], vars)
+		}
+		return out
+	default:
+		return val
+	}
+}
+
+func isNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// orderedMap is a JSON object that preserves the order in which keys
+// were set, since GraphQL responses follow the order of the selection.
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedMap() *orderedMap {
+	return &orderedMap{values: map[string]interface{}{}}
+}
+
+func (m *orderedMap) set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	buf := bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem marshaling field '%s'", key)
+		}
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// IntArg returns the named argument as an int, accepting the integer
+// types produced by the parser and by JSON-decoded variables.
+func IntArg(args map[string]interface{}, name string) (int, error) {
+	switch v := args[name].(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v != float64(int(v)) {
+			return 0, errors.Errorf("argument '%s' must be an integer", name)
+		}
+		return int(v), nil
+	default:
+		return 0, errors.Errorf("argument '%s' must be an integer", name)
+	}
+}
+
+// StringArg returns the named argument as a string.
+func StringArg(args map[string]interface{}, name string) (string, error) {
+	switch v := args[name].(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	default:
+		return "", errors.Errorf("argument '%s' must be a string, not %s", name, fmt.Sprintf("%T", v))
+	}
+}
@@ -0,0 +1,200 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/suite"
+)
+
+type testParent struct {
+	Name     string   `json:"name"`
+	ChildIds []string `json:"child_ids"`
+}
+
+type testChild struct {
+	Id    string `json:"id"`
+	Value int    `json:"value"`
+}
+
+type ExecutorSuite struct {
+	schema     *Schema
+	batchCalls [][]string
+	ctx        context.Context
+
+	suite.Suite
+}
+
+func TestExecutorSuite(t *testing.T) {
+	suite.Run(t, new(ExecutorSuite))
+}
+
+func (s *ExecutorSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.batchCalls = nil
+
+	children := map[string]interface{}{
+		"c1": &testChild{Id: "c1", Value: 1},
+		"c2": &testChild{Id: "c2", Value: 2},
+		"c3": &testChild{Id: "c3", Value: 3},
+	}
+	loader := NewLoader(func(_ context.Context, keys []string) (map[string]interface{}, error) {
+		s.batchCalls = append(s.batchCalls, keys)
+		return children, nil
+	})
+
+	child := &Object{Name: "Child", Fields: ModelFields(&testChild{})}
+	parent := &Object{Name: "Parent", Fields: ModelFields(&testParent{})}
+	parent.AddField("children", &Field{
+		Type:       child,
+		List:       true,
+		Multiplier: func(map[string]interface{}) int { return 10 },
+		Resolve: func(ctx context.Context, p ResolveParams) (interface{}, error) {
+			return loader.LoadMany(ctx, p.Source.(*testParent).ChildIds), nil
+		},
+	})
+	parent.AddField("broken", &Field{
+		Resolve: func(context.Context, ResolveParams) (interface{}, error) {
+			return nil, errors.New("resolver failed")
+		},
+	})
+
+	parents := []*testParent{
+		{Name: "one", ChildIds: []string{"c1", "c2"}},
+		{Name: "two", ChildIds: []string{"c2", "c3", "missing"}},
+	}
+	query := NewObject("Query").
+		AddField("parents", &Field{
+			Type:       parent,
+			List:       true,
+			Multiplier: func(map[string]interface{}) int { return 10 },
+			Resolve: func(context.Context, ResolveParams) (interface{}, error) {
+				return parents, nil
+			},
+		}).
+		AddField("parent", &Field{
+			Type: parent,
+			Args: map[string]interface{}{"name": nil},
+			Resolve: func(_ context.Context, p ResolveParams) (interface{}, error) {
+				name, err := StringArg(p.Args, "name")
+				if err != nil {
+					return nil, err
+				}
+				for _, parent := range parents {
+					if parent.Name == name {
+						return parent, nil
+					}
+				}
+				return nil, nil
+			},
+		})
+
+	s.schema = &Schema{Query: query}
+}
+
+func (s *ExecutorSuite) execute(req Request) string {
+	resp := s.schema.Execute(s.ctx, req)
+	out, err := json.Marshal(resp)
+	s.Require().NoError(err)
+	return string(out)
+}
+
+func (s *ExecutorSuite) TestNestedListsAreBatched() {
+	out := s.execute(Request{Query: `{ parents { name children { id value } } }`})
+	s.JSONEq(`{"data": {"parents": [
+		{"name": "one", "children": [{"id": "c1", "value": 1}, {"id": "c2", "value": 2}]},
+		{"name": "two", "children": [{"id": "c2", "value": 2}, {"id": "c3", "value": 3}]}
+	]}}`, out)
+
+	s.Require().Len(s.batchCalls, 1)
+	sort.Strings(s.batchCalls[0])
+	s.Equal([]string{"c1", "c2", "c3", "missing"}, s.batchCalls[0])
+}
+
+func (s *ExecutorSuite) TestResponseFollowsSelectionOrder() {
+	out := s.execute(Request{Query: `{ parent(name: "one") { child_ids name } }`})
+	s.Equal(`{"data":{"parent":{"child_ids":["c1","c2"],"name":"one"}}}`, out)
+}
+
+func (s *ExecutorSuite) TestAliasesFragmentsAndDirectives() {
+	out := s.execute(Request{
+		Query: `query Q($skip: Boolean!, $which: String) {
+			first: parent(name: "one") { ...names }
+			second: parent(name: $which) { __typename name @skip(if: $skip) }
+		}
+		query Other { parents { name } }
+		fragment names on Parent { name ... on Parent { child_ids } }`,
+		OperationName: "Q",
+		Variables:     map[string]interface{}{"skip": true, "which": "two"},
+	})
+	s.JSONEq(`{"data": {
+		"first": {"name": "one", "child_ids": ["c1", "c2"]},
+		"second": {"__typename": "Parent"}
+	}}`, out)
+}
+
+func (s *ExecutorSuite) TestMissingValuesAreNull() {
+	out := s.execute(Request{Query: `{ parent(name: "three") { name } }`})
+	s.JSONEq(`{"data": {"parent": null}}`, out)
+}
+
+func (s *ExecutorSuite) TestFieldErrorsIncludePath() {
+	out := s.execute(Request{Query: `{ parents { name broken } }`})
+	s.JSONEq(`{
+		"data": {"parents": [{"name": "one", "broken": null}, {"name": "two", "broken": null}]},
+		"errors": [
+			{"message": "resolver failed", "path": ["parents", 0, "broken"]},
+			{"message": "resolver failed", "path": ["parents", 1, "broken"]}
+		]
+	}`, out)
+}
+
+func (s *ExecutorSuite) TestValidationErrors() {
+	for name, query := range map[string]string{
+		"UnknownField":      `{ parents { nope } }`,
+		"UnknownArgument":   `{ parents(limit: 1) { name } }`,
+		"LeafSelection":     `{ parents { name { id } } }`,
+		"MissingSelection":  `{ parents }`,
+		"UnknownFragment":   `{ parents { ...nope } }`,
+		"WrongFragmentType": `{ parents { ...c } } fragment c on Child { id }`,
+		"FragmentCycle":     `{ parents { ...a } } fragment a on Parent { ...b } fragment b on Parent { ...a }`,
+		"UnknownVariable":   `{ parent(name: $name) { name } }`,
+	} {
+		resp := s.schema.Execute(s.ctx, Request{Query: query})
+		s.Nil(resp.Data, name)
+		s.Len(resp.Errors, 1, name)
+	}
+	s.Empty(s.batchCalls)
+}
+
+func (s *ExecutorSuite) TestRequiredVariables() {
+	resp := s.schema.Execute(s.ctx, Request{Query: `query ($name: String!) { parent(name: $name) { name } }`})
+	s.Nil(resp.Data)
+	s.Require().Len(resp.Errors, 1)
+	s.Contains(resp.Errors[0].Message, "required")
+}
+
+func (s *ExecutorSuite) TestComplexity() {
+	cost, err := s.schema.Complexity(Request{Query: `{ parents { name children { id value } } }`})
+	s.NoError(err)
+	// parents(1) + 10 * (name(1) + children(1) + 10 * (id(1) + value(1)))
+	s.Equal(1+10*(1+1+10*2), cost)
+
+	s.schema.MaxComplexity = 100
+	resp := s.schema.Execute(s.ctx, Request{Query: `{ parents { name children { id value } } }`})
+	s.Nil(resp.Data)
+	s.Require().Len(resp.Errors, 1)
+	s.Contains(resp.Errors[0].Message, "exceeds the limit of 100")
+	s.Empty(s.batchCalls)
+}
+
+func (s *ExecutorSuite) TestDepthLimit() {
+	s.schema.MaxDepth = 2
+	resp := s.schema.Execute(s.ctx, Request{Query: `{ parents { children { id } } }`})
+	s.Nil(resp.Data)
+	s.Require().Len(resp.Errors, 1)
+	s.Contains(resp.Errors[0].Message, "depth")
+}
@@ -0,0 +1,98 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchFunc fetches the values for a set of keys in a single operation.
+// Keys that are absent from the returned map resolve to null.
+type BatchFunc func(context.Context, []string) (map[string]interface{}, error)
+
+// Loader coalesces individual key lookups into batched calls to a
+// BatchFunc. Because the executor resolves every sibling value in a
+// selection before it forces any Thunk, all of the keys requested at
+// one level of a query are fetched together, avoiding one database
+// query per parent object. Results are cached for the lifetime of the
+// Loader, which should therefore be scoped to a single request.
+type Loader struct {
+	fetch BatchFunc
+
+	mu      sync.Mutex
+	pending []string
+	cache   map[string]*loaderResult
+}
+
+type loaderResult struct {
+	value interface{}
+	err   error
+}
+
+// NewLoader constructs a Loader around the given batch function.
+func NewLoader(fetch BatchFunc) *Loader {
+	return &Loader{
+		fetch: fetch,
+		cache: map[string]*loaderResult{},
+	}
+}
+
+// Load registers interest in a key and returns a Thunk that yields its
+// value. The underlying fetch happens when the first Thunk is forced.
+func (l *Loader) Load(ctx context.Context, key string) Thunk {
+	l.mu.Lock()
+	res, ok := l.cache[key]
+	if !ok {
+		res = &loaderResult{}
+		l.cache[key] = res
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.dispatch(ctx)
+		return res.value, res.err
+	}
+}
+
+// LoadMany is the same as Load for a list of keys; the thunk yields the
+// non-null values in key order.
+func (l *Loader) LoadMany(ctx context.Context, keys []string) Thunk {
+	thunks := make([]Thunk, 0, len(keys))
+	for _, key := range keys {
+		thunks = append(thunks, l.Load(ctx, key))
+	}
+
+	return func() (interface{}, error) {
+		out := make([]interface{}, 0, len(thunks))
+		for _, thunk := range thunks {
+			v, err := thunk()
+			if err != nil {
+				return nil, err
+			}
+			if !isNil(v) {
+				out = append(out, v)
+			}
+		}
+		return out, nil
+	}
+}
+
+func (l *Loader) dispatch(ctx context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.pending) == 0 {
+		return
+	}
+	keys := l.pending
+	l.pending = nil
+
+	values, err := l.fetch(ctx, keys)
+	for _, key := range keys {
+		res := l.cache[key]
+		res.err = err
+		if err == nil {
+			res.value = values[key]
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseShorthandQuery(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := Parse(`{ version(id: "v1") { version_id builds { _id } } }`)
+	require.NoError(err)
+	require.Len(doc.Operations, 1)
+	op := doc.Operations[0]
+	assert.Equal("query", op.Type)
+	require.Len(op.SelectionSet, 1)
+
+	field, ok := op.SelectionSet[0].(*FieldSelection)
+	require.True(ok)
+	assert.Equal("version", field.Name)
+	require.Len(field.Arguments, 1)
+	assert.Equal("id", field.Arguments[0].Name)
+	assert.Equal("v1", field.Arguments[0].Value)
+	require.Len(field.SelectionSet, 2)
+	assert.Equal("builds", field.SelectionSet[1].(*FieldSelection).Name)
+}
+
+func TestParseOperationWithVariablesAndFragments(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := Parse(`
+		# leading comment
+		query Dashboard($id: String!, $limit: Int = 10, $tags: [String]) {
+			b: build(id: $id) {
+				...buildFields
+				... on Build @include(if: true) { status }
+				task_connection(limit: $limit, status: FAILED) { nodes { task_id } }
+			}
+		}
+
+		fragment buildFields on Build { _id, display_name }
+	`)
+	require.NoError(err)
+	require.Len(doc.Operations, 1)
+	op := doc.Operations[0]
+	assert.Equal("Dashboard", op.Name)
+	require.Len(op.Variables, 3)
+	assert.Equal(VariableDefinition{Name: "id", Type: "String!", Required: true}, op.Variables[0])
+	assert.Equal(int64(10), op.Variables[1].Default)
+	assert.Equal("[String]", op.Variables[2].Type)
+
+	build := op.SelectionSet[0].(*FieldSelection)
+	assert.Equal("b", build.ResponseKey())
+	assert.Equal("build", build.Name)
+	assert.Equal(Variable("id"), build.Arguments[0].Value)
+	require.Len(build.SelectionSet, 3)
+
+	spread, ok := build.SelectionSet[0].(*FragmentSpread)
+	require.True(ok)
+	assert.Equal("buildFields", spread.Name)
+
+	inline, ok := build.SelectionSet[1].(*InlineFragment)
+	require.True(ok)
+	assert.Equal("Build", inline.TypeCondition)
+	require.Len(inline.Directives, 1)
+	assert.Equal("include", inline.Directives[0].Name)
+
+	tasks := build.SelectionSet[2].(*FieldSelection)
+	assert.Equal(EnumValue("FAILED"), tasks.Arguments[1].Value)
+
+	frag, ok := doc.Fragments["buildFields"]
+	require.True(ok)
+	assert.Equal("Build", frag.TypeCondition)
+	assert.Len(frag.SelectionSet, 2)
+}
+
+func TestParseValues(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	doc, err := Parse(`{ f(a: -1, b: 2.5e1, c: "x\ny", d: null, e: false, f: [1, 2], g: {h: "i"}) }`)
+	require.NoError(err)
+	args := doc.Operations[0].SelectionSet[0].(*FieldSelection).Arguments
+	require.Len(args, 7)
+	assert.Equal(int64(-1), args[0].Value)
+	assert.Equal(25.0, args[1].Value)
+	assert.Equal("x\ny", args[2].Value)
+	assert.Nil(args[3].Value)
+	assert.Equal(false, args[4].Value)
+	assert.Equal([]Value{int64(1), int64(2)}, args[5].Value)
+	assert.Equal(map[string]Value{"h": "i"}, args[6].Value)
+}
+
+func TestParseErrors(t *testing.T) {
+	for name, query := range map[string]string{
+		"Empty":             "",
+		"Unterminated":      "{ version(id: \"v1) { _id } }",
+		"UnclosedSelection": "{ version { _id }",
+		"EmptySelection":    "{ version { } }",
+		"Mutation":          "mutation { abort }",
+		"BadCharacter":      "{ version % }",
+		"DuplicateFragment": "{ a } fragment f on Query { a } fragment f on Query { b }",
+		"VariableInDefault": "query ($a: Int = $b) { a }",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(query)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package graphql
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+const (
+	// DefaultMaxComplexity is the query cost that a Schema will permit
+	// when no other limit has been set.
+	DefaultMaxComplexity = 5000
+	// DefaultMaxDepth is the selection depth that a Schema will permit
+	// when no other limit has been set.
+	DefaultMaxDepth = 10
+)
+
+// Schema describes the types that can be queried and the limits that
+// are applied to each request.
+type Schema struct {
+	// Query is the root type for all query operations.
+	Query *Object
+
+	// MaxComplexity caps the estimated cost of a request, as computed
+	// by Complexity, and MaxDepth caps how deeply selection sets may be
+	// nested. Zero values use the package defaults.
+	MaxComplexity int
+	MaxDepth      int
+}
+
+// Object is a GraphQL object type: a named collection of fields.
+type Object struct {
+	Name   string
+	Fields map[string]*Field
+}
+
+// NewObject constructs an empty object type with the given name.
+func NewObject(name string) *Object {
+	return &Object{Name: name, Fields: map[string]*Field{}}
+}
+
+// AddField registers a field on the object, replacing any existing field
+// with the same name, and returns the object to allow chaining.
+func (o *Object) AddField(name string, f *Field) *Object {
+	o.Fields[name] = f
+	return o
+}
+
+// Field describes a field of an Object. A field with a nil Type is a leaf
+// whose resolved value is serialized as JSON; otherwise the resolved value
+// is used as the source for resolving the sub-selection against Type.
+type Field struct {
+	Type *Object
+	List bool
+
+	// Args lists the arguments the field accepts, mapped to their
+	// default values. Arguments not listed here are rejected.
+	Args map[string]interface{}
+
+	// Resolve produces the value of the field. It may return a Thunk to
+	// defer work until every sibling value has been requested, which
+	// allows a Loader to batch lookups.
+	Resolve ResolveFunc
+
+	// Cost is the complexity charged for the field itself; it defaults
+	// to one. For list fields, Multiplier estimates how many elements
+	// will be returned so that the cost of the sub-selection can be
+	// scaled accordingly.
+	Cost       int
+	Multiplier func(args map[string]interface{}) int
+}
+
+// ResolveParams holds the inputs to a ResolveFunc.
+type ResolveParams struct {
+	// Source is the value of the parent object.
+	Source interface{}
+	// Args holds the field's arguments with defaults applied and
+	// variables substituted.
+	Args map[string]interface{}
+}
+
+// ResolveFunc computes the value of a field.
+type ResolveFunc func(context.Context, ResolveParams) (interface{}, error)
+
+// Thunk is a deferred field value.
+type Thunk func() (interface{}, error)
+
+// ModelFields returns leaf fields for every JSON-visible field of the
+// given struct, named after the JSON tag. This allows the REST models to
+// be exposed without duplicating their field lists. Sources resolved
+// against these fields must be pointers to the same struct type.
+func ModelFields(model interface{}) map[string]*Field {
+	fields := map[string]*Field{}
+	t := reflect.TypeOf(model)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		idx := sf.Index
+		fields[name] = &Field{
+			Resolve: func(_ context.Context, p ResolveParams) (interface{}, error) {
+				v := reflect.ValueOf(p.Source)
+				for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+					if v.IsNil() {
+						return nil, nil
+					}
+					v = v.Elem()
+				}
+				fv := v.FieldByIndex(idx)
+				if fv.CanAddr() {
+					// the REST models define some of their
+					// marshalers on pointer receivers
+					return fv.Addr().Interface(), nil
+				}
+				return fv.Interface(), nil
+			},
+		}
+	}
+	return fields
+}
@@ -32,6 +32,7 @@ type APIServiceFlags struct {
 	SchedulerDisabled            bool `json:"scheduler_disabled"`
 	GithubPRTestingDisabled      bool `json:"github_pr_testing_disabled"`
 	RepotrackerPushEventDisabled bool `json:"repotracker_push_event_disabled"`
+	GitlabMRTestingDisabled      bool `json:"gitlab_mr_testing_disabled"`
 }
 
 // RestartTasksResponse is the response model returned from the /admin/restart route
@@ -105,6 +106,7 @@ func (as *APIServiceFlags) BuildFromService(h interface{}) error {
 		as.SchedulerDisabled = v.SchedulerDisabled
 		as.GithubPRTestingDisabled = v.GithubPRTestingDisabled
 		as.RepotrackerPushEventDisabled = v.RepotrackerPushEventDisabled
+		as.GitlabMRTestingDisabled = v.GitlabMRTestingDisabled
 	default:
 		return errors.Errorf("%T is not a supported service flags type", h)
 	}
@@ -124,6 +126,7 @@ func (as *APIServiceFlags) ToService() (interface{}, error) {
 		SchedulerDisabled:            as.SchedulerDisabled,
 		GithubPRTestingDisabled:      as.GithubPRTestingDisabled,
 		RepotrackerPushEventDisabled: as.RepotrackerPushEventDisabled,
+		GitlabMRTestingDisabled:      as.GitlabMRTestingDisabled,
 	}
 	return serviceFlags, nil
 }
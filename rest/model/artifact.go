@@ -0,0 +1,43 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/pkg/errors"
+)
+
+// APIFile is the model to be returned by the API whenever artifact files are
+// fetched. The URLs of files stored in S3 are signed and expire.
+type APIFile struct {
+	Name           APIString `json:"name"`
+	URL            APIString `json:"url"`
+	Visibility     APIString `json:"visibility"`
+	IgnoreForFetch bool      `json:"ignore_for_fetch"`
+}
+
+// BuildFromService converts from a service level artifact.File to an
+// APIFile.
+func (f *APIFile) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case artifact.File:
+		f.Name = APIString(v.Name)
+		f.URL = APIString(v.Link)
+		f.Visibility = APIString(v.Visibility)
+		f.IgnoreForFetch = v.IgnoreForFetch
+	case *artifact.File:
+		return f.BuildFromService(*v)
+	default:
+		return errors.Errorf("incorrect type '%T' when creating APIFile", h)
+	}
+	return nil
+}
+
+// ToService returns a service layer artifact.File using the data from the
+// APIFile.
+func (f *APIFile) ToService() (interface{}, error) {
+	return artifact.File{
+		Name:           string(f.Name),
+		Link:           string(f.URL),
+		Visibility:     string(f.Visibility),
+		IgnoreForFetch: f.IgnoreForFetch,
+	}, nil
+}
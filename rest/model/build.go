@@ -9,8 +9,11 @@ import (
 )
 
 var (
-	commitOrigin = "commit"
-	patchOrigin  = "patch"
+	commitOrigin   = "commit"
+	patchOrigin    = "patch"
+	triggerOrigin  = "trigger"
+	periodicOrigin = "periodic"
+	gitTagOrigin   = "git_tag"
 )
 
 // APIBuild is the model to be returned by the API whenever builds are fetched.
@@ -35,6 +38,7 @@ type APIBuild struct {
 	PredictedMakespan   APIDuration `json:"predicted_makespan_ms"`
 	ActualMakespan      APIDuration `json:"actual_makespan_ms"`
 	Origin              APIString   `json:"origin"`
+	Requester           APIString   `json:"requester"`
 }
 
 // BuildFromService converts from service level structs to an APIBuild.
@@ -69,8 +73,15 @@ func (apiBuild *APIBuild) BuildFromService(h interface{}) error {
 		origin = commitOrigin
 	} else if evergreen.IsPatchRequester(v.Requester) {
 		origin = patchOrigin
+	} else if v.Requester == evergreen.TriggerRequester {
+		origin = triggerOrigin
+	} else if v.Requester == evergreen.PeriodicBuildRequester {
+		origin = periodicOrigin
+	} else if v.Requester == evergreen.GitTagRequester {
+		origin = gitTagOrigin
 	}
 	apiBuild.Origin = APIString(origin)
+	apiBuild.Requester = APIString(v.Requester)
 	return nil
 }
 
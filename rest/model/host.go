@@ -18,7 +18,10 @@ type APIHost struct {
 	User        APIString  `json:"user"`
 	Status      APIString  `json:"status"`
 	RunningTask taskInfo   `json:"running_task"`
-	UserHost    bool       `json:"user_host"`
+	// RunningTasks has all of the tasks running on the host, which can be
+	// more than one on hosts with task slots.
+	RunningTasks []taskInfo `json:"running_tasks"`
+	UserHost     bool       `json:"user_host"`
 }
 
 // HostPostRequest is a struct that holds the format of a POST request to /hosts
@@ -42,21 +45,29 @@ type taskInfo struct {
 
 // BuildFromService converts from service level structs to an APIHost. It can
 // be called multiple times with different data types, a service layer host and
-// a service layer task, which are each loaded into the data structure.
+// a service layer task, which are each loaded into the data structure. Each
+// task is added to the running tasks, and the first is also the running task.
 func (apiHost *APIHost) BuildFromService(h interface{}) error {
 	switch v := h.(type) {
 	case host.Host, *host.Host:
 		return apiHost.buildFromHostStruct(h)
 	case *task.Task:
-		apiHost.RunningTask = getTaskInfo(v)
+		apiHost.addRunningTask(getTaskInfo(v))
 	case task.Task:
-		apiHost.RunningTask = getTaskInfo(&v)
+		apiHost.addRunningTask(getTaskInfo(&v))
 	default:
 		return fmt.Errorf("incorrect type when fetching converting host type")
 	}
 	return nil
 }
 
+func (apiHost *APIHost) addRunningTask(info taskInfo) {
+	if len(apiHost.RunningTasks) == 0 {
+		apiHost.RunningTask = info
+	}
+	apiHost.RunningTasks = append(apiHost.RunningTasks, info)
+}
+
 func getTaskInfo(t *task.Task) taskInfo {
 	return taskInfo{
 		Id:           APIString(t.Id),
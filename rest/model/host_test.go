@@ -41,6 +41,13 @@ func TestHostBuildFromService(t *testing.T) {
 						VersionId:    APIString("testVersionId"),
 						BuildId:      APIString("testBuildId"),
 					},
+					RunningTasks: []taskInfo{{
+						Id:           APIString("testRunningTaskId"),
+						Name:         APIString("testRTName"),
+						DispatchTime: NewTime(timeNow),
+						VersionId:    APIString("testVersionId"),
+						BuildId:      APIString("testBuildId"),
+					}},
 				},
 				sh: host.Host{
 					Id: "testId",
@@ -68,6 +75,9 @@ func TestHostBuildFromService(t *testing.T) {
 					RunningTask: taskInfo{
 						DispatchTime: NewTime(time.Time{}),
 					},
+					RunningTasks: []taskInfo{{
+						DispatchTime: NewTime(time.Time{}),
+					}},
 				},
 				sh: host.Host{},
 				st: task.Task{},
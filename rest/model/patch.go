@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/pkg/errors"
@@ -24,6 +25,13 @@ type APIPatch struct {
 	Tasks         []APIString   `json:"tasks"`
 	VariantsTasks []variantTask `json:"variants_tasks"`
 	Activated     bool          `json:"activated"`
+	ModulePatches []modulePatch `json:"module_code_changes"`
+
+	// EstimatedFinish is when the scheduler expects the patch's tasks to
+	// finish, and QueuePosition is the lowest position of its tasks in the
+	// task queues.
+	EstimatedFinish APITime `json:"estimated_finish"`
+	QueuePosition   int     `json:"queue_position"`
 }
 
 type variantTask struct {
@@ -31,6 +39,52 @@ type variantTask struct {
 	Tasks []APIString `json:"tasks"`
 }
 
+type modulePatch struct {
+	Module  APIString     `json:"module"`
+	Githash APIString     `json:"githash"`
+	Files   []fileSummary `json:"files"`
+}
+
+type fileSummary struct {
+	Name      APIString `json:"name"`
+	Additions int       `json:"additions"`
+	Deletions int       `json:"deletions"`
+}
+
+// PatchCreateRequest is the format of a POST request to /patches. A patch
+// is made of a diff against the base githash of the project, plus any
+// changes to the project's modules.
+type PatchCreateRequest struct {
+	Project     string               `json:"project"`
+	Githash     string               `json:"githash"`
+	Patch       string               `json:"patch"`
+	Description string               `json:"description"`
+	Variants    []string             `json:"variants"`
+	Tasks       []string             `json:"tasks"`
+	Alias       string               `json:"alias"`
+	Finalize    bool                 `json:"finalize"`
+	Modules     []PatchModuleRequest `json:"modules"`
+}
+
+// PatchModuleRequest is the format of a PUT request to
+// /patches/{patch_id}/modules/{module}, and of the modules of a
+// PatchCreateRequest.
+type PatchModuleRequest struct {
+	Module  string `json:"module,omitempty"`
+	Githash string `json:"githash"`
+	Patch   string `json:"patch"`
+}
+
+// PatchConfigureRequest is the format of a POST request to
+// /patches/{patch_id}/configure.
+type PatchConfigureRequest struct {
+	Description *string  `json:"description,omitempty"`
+	Variants    []string `json:"variants,omitempty"`
+	Tasks       []string `json:"tasks,omitempty"`
+	Alias       string   `json:"alias,omitempty"`
+	Finalize    bool     `json:"finalize"`
+}
+
 // BuildFromService converts from service level structs to an APIPatch
 func (apiPatch *APIPatch) BuildFromService(h interface{}) error {
 	v, ok := h.(patch.Patch)
@@ -61,7 +115,7 @@ func (apiPatch *APIPatch) BuildFromService(h interface{}) error {
 	variantTasks := []variantTask{}
 	for _, vt := range v.VariantsTasks {
 		vtasks := make([]APIString, 0)
-		for _, task := range v.Tasks {
+		for _, task := range vt.Tasks {
 			vtasks = append(vtasks, APIString(task))
 		}
 		variantTasks = append(variantTasks, variantTask{
@@ -71,10 +125,72 @@ func (apiPatch *APIPatch) BuildFromService(h interface{}) error {
 	}
 	apiPatch.VariantsTasks = variantTasks
 	apiPatch.Activated = v.Activated
+	apiPatch.EstimatedFinish = NewTime(v.EstimatedFinish)
+	apiPatch.QueuePosition = v.QueuePosition
+	apiPatch.ModulePatches = []modulePatch{}
+	for _, mp := range v.Patches {
+		files := []fileSummary{}
+		for _, summary := range mp.PatchSet.Summary {
+			files = append(files, fileSummary{
+				Name:      APIString(summary.Name),
+				Additions: summary.Additions,
+				Deletions: summary.Deletions,
+			})
+		}
+		apiPatch.ModulePatches = append(apiPatch.ModulePatches, modulePatch{
+			Module:  APIString(mp.ModuleName),
+			Githash: APIString(mp.Githash),
+			Files:   files,
+		})
+	}
 	return nil
 }
 
 // ToService converts a service layer patch using the data from APIPatch
 func (apiPatch *APIPatch) ToService() (interface{}, error) {
-	return nil, errors.New("not implemented for read-only route")
+	if !patch.IsValidId(string(apiPatch.Id)) {
+		return nil, errors.Errorf("patch id '%s' is not a valid object id", apiPatch.Id)
+	}
+	p := patch.Patch{
+		Id:          patch.NewId(string(apiPatch.Id)),
+		Description: string(apiPatch.Description),
+		Project:     string(apiPatch.ProjectId),
+		Githash:     string(apiPatch.Githash),
+		PatchNumber: apiPatch.PatchNumber,
+		Author:      string(apiPatch.Author),
+		Status:      string(apiPatch.Status),
+		CreateTime:  time.Time(apiPatch.CreateTime),
+		Activated:   apiPatch.Activated,
+
+		EstimatedFinish: time.Time(apiPatch.EstimatedFinish),
+		QueuePosition:   apiPatch.QueuePosition,
+	}
+	for _, v := range apiPatch.Variants {
+		p.BuildVariants = append(p.BuildVariants, string(v))
+	}
+	for _, t := range apiPatch.Tasks {
+		p.Tasks = append(p.Tasks, string(t))
+	}
+	for _, vt := range apiPatch.VariantsTasks {
+		variantTasks := patch.VariantTasks{Variant: string(vt.Name)}
+		for _, t := range vt.Tasks {
+			variantTasks.Tasks = append(variantTasks.Tasks, string(t))
+		}
+		p.VariantsTasks = append(p.VariantsTasks, variantTasks)
+	}
+	for _, mp := range apiPatch.ModulePatches {
+		modulePatch := patch.ModulePatch{
+			ModuleName: string(mp.Module),
+			Githash:    string(mp.Githash),
+		}
+		for _, f := range mp.Files {
+			modulePatch.PatchSet.Summary = append(modulePatch.PatchSet.Summary, patch.Summary{
+				Name:      string(f.Name),
+				Additions: f.Additions,
+				Deletions: f.Deletions,
+			})
+		}
+		p.Patches = append(p.Patches, modulePatch)
+	}
+	return p, nil
 }
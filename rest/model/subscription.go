@@ -0,0 +1,84 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// APISubscription is the model to be returned by the API whenever
+// notification subscriptions are fetched.
+type APISubscription struct {
+	ID           APIString     `json:"id"`
+	Owner        APIString     `json:"owner"`
+	ResourceType APIString     `json:"resource_type"`
+	Trigger      APIString     `json:"trigger"`
+	Selectors    []APISelector `json:"selectors"`
+	Subscriber   APISubscriber `json:"subscriber"`
+	DurationSecs int           `json:"duration_secs,omitempty"`
+}
+
+type APISelector struct {
+	Type APIString `json:"type"`
+	Data APIString `json:"data"`
+}
+
+type APISubscriber struct {
+	Type   APIString `json:"type"`
+	Target APIString `json:"target"`
+}
+
+// BuildFromService converts from service level structs to an
+// APISubscription.
+func (s *APISubscription) BuildFromService(h interface{}) error {
+	switch v := h.(type) {
+	case event.Subscription:
+		s.ID = APIString(v.ID.Hex())
+		s.Owner = APIString(v.Owner)
+		s.ResourceType = APIString(v.ResourceType)
+		s.Trigger = APIString(v.Trigger)
+		s.Selectors = []APISelector{}
+		for _, selector := range v.Selectors {
+			s.Selectors = append(s.Selectors, APISelector{
+				Type: APIString(selector.Type),
+				Data: APIString(selector.Data),
+			})
+		}
+		s.Subscriber = APISubscriber{
+			Type:   APIString(v.Subscriber.Type),
+			Target: APIString(v.Subscriber.Target),
+		}
+		s.DurationSecs = v.DurationSecs
+	default:
+		return errors.Errorf("incorrect type when converting subscription type")
+	}
+	return nil
+}
+
+// ToService returns a service layer subscription using the data from
+// APISubscription.
+func (s *APISubscription) ToService() (interface{}, error) {
+	out := event.Subscription{
+		Owner:        string(s.Owner),
+		ResourceType: string(s.ResourceType),
+		Trigger:      string(s.Trigger),
+		Subscriber: event.Subscriber{
+			Type:   string(s.Subscriber.Type),
+			Target: string(s.Subscriber.Target),
+		},
+		DurationSecs: s.DurationSecs,
+	}
+	if s.ID != "" {
+		if !bson.IsObjectIdHex(string(s.ID)) {
+			return nil, errors.Errorf("'%s' is not a valid subscription id", s.ID)
+		}
+		out.ID = bson.ObjectIdHex(string(s.ID))
+	}
+	for _, selector := range s.Selectors {
+		out.Selectors = append(out.Selectors, event.Selector{
+			Type: string(selector.Type),
+			Data: string(selector.Data),
+		})
+	}
+	return out, nil
+}
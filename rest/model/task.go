@@ -38,11 +38,15 @@ type APITask struct {
 	Execution        int              `json:"execution"`
 	Order            int              `json:"order"`
 	Status           APIString        `json:"status"`
+	Requester        APIString        `json:"requester"`
 	Details          apiTaskEndDetail `json:"status_details"`
 	Logs             logLinks         `json:"logs"`
 	TimeTaken        APIDuration      `json:"time_taken_ms"`
 	ExpectedDuration APIDuration      `json:"expected_duration_ms"`
 	EstimatedCost    float64          `json:"estimated_cost"`
+	EstimatedStart   APITime          `json:"estimated_start"`
+	EstimatedFinish  APITime          `json:"estimated_finish"`
+	QueuePosition    int              `json:"queue_position"`
 }
 
 type logLinks struct {
@@ -93,9 +97,13 @@ func (at *APITask) BuildFromService(t interface{}) error {
 				TimedOut:    v.Details.TimedOut,
 			},
 			Status:           APIString(v.Status),
+			Requester:        APIString(v.Requester),
 			TimeTaken:        NewAPIDuration(v.TimeTaken),
 			ExpectedDuration: NewAPIDuration(v.ExpectedDuration),
 			EstimatedCost:    v.Cost,
+			EstimatedStart:   NewTime(v.EstimatedStart),
+			EstimatedFinish:  NewTime(v.EstimatedFinish),
+			QueuePosition:    v.QueuePosition,
 		}
 
 		if len(v.DependsOn) > 0 {
@@ -151,9 +159,13 @@ func (ad *APITask) ToService() (interface{}, error) {
 			TimedOut:    ad.Details.TimedOut,
 		},
 		Status:           string(ad.Status),
+		Requester:        string(ad.Requester),
 		TimeTaken:        ad.TimeTaken.ToDuration(),
 		ExpectedDuration: ad.ExpectedDuration.ToDuration(),
 		Cost:             ad.EstimatedCost,
+		EstimatedStart:   time.Time(ad.EstimatedStart),
+		EstimatedFinish:  time.Time(ad.EstimatedFinish),
+		QueuePosition:    ad.QueuePosition,
 	}
 	dependsOn := make([]task.Dependency, len(ad.DependsOn))
 
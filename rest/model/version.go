@@ -20,6 +20,29 @@ type APIVersion struct {
 	Repo          APIString     `json:"repo"`
 	Branch        APIString     `json:"branch"`
 	BuildVariants []buildDetail `json:"build_variants_status"`
+	Requester     APIString     `json:"requester"`
+
+	// EstimatedFinish is when the scheduler expects the version's tasks to
+	// finish, and QueuePosition is the lowest position of its tasks in the
+	// task queues.
+	EstimatedFinish APITime `json:"estimated_finish"`
+	QueuePosition   int     `json:"queue_position"`
+
+	// TriggeredBy links a version created by a trigger to the upstream
+	// version that caused it.
+	TriggeredBy *APITriggerInfo `json:"triggered_by,omitempty"`
+}
+
+// APITriggerInfo describes the upstream version, build or task that caused a
+// version to be created by a trigger.
+type APITriggerInfo struct {
+	Project  APIString `json:"project"`
+	Level    APIString `json:"level"`
+	Status   APIString `json:"status"`
+	Version  APIString `json:"version_id"`
+	Build    APIString `json:"build_id"`
+	Task     APIString `json:"task_id"`
+	Revision APIString `json:"revision"`
 }
 
 type buildDetail struct {
@@ -45,6 +68,20 @@ func (apiVersion *APIVersion) BuildFromService(h interface{}) error {
 	apiVersion.Status = APIString(v.Status)
 	apiVersion.Repo = APIString(v.Repo)
 	apiVersion.Branch = APIString(v.Branch)
+	apiVersion.Requester = APIString(v.Requester)
+	apiVersion.EstimatedFinish = NewTime(v.EstimatedFinish)
+	apiVersion.QueuePosition = v.QueuePosition
+	if v.TriggeredBy != nil {
+		apiVersion.TriggeredBy = &APITriggerInfo{
+			Project:  APIString(v.TriggeredBy.Project),
+			Level:    APIString(v.TriggeredBy.Level),
+			Status:   APIString(v.TriggeredBy.Status),
+			Version:  APIString(v.TriggeredBy.Version),
+			Build:    APIString(v.TriggeredBy.Build),
+			Task:     APIString(v.TriggeredBy.Task),
+			Revision: APIString(v.TriggeredBy.Revision),
+		}
+	}
 
 	var bd buildDetail
 	for _, t := range v.BuildVariants {
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/pkg/errors"
+)
+
+// APIVersionComparison is the model to be returned by the API when two
+// versions are compared.
+type APIVersionComparison struct {
+	BaseVersion      APIString               `json:"base_version_id"`
+	Version          APIString               `json:"version_id"`
+	StatusChanges    []APITaskComparison     `json:"status_changes"`
+	DurationChanges  []APITaskComparison     `json:"duration_changes"`
+	NewlyFailing     []APITestComparison     `json:"newly_failing_tests"`
+	NewlyPassing     []APITestComparison     `json:"newly_passing_tests"`
+	AddedArtifacts   []APIArtifactComparison `json:"added_artifacts"`
+	RemovedArtifacts []APIArtifactComparison `json:"removed_artifacts"`
+}
+
+// APITaskComparison pairs a task with the same task in the base version.
+type APITaskComparison struct {
+	BuildVariant APIString   `json:"build_variant"`
+	DisplayName  APIString   `json:"display_name"`
+	BaseTaskId   APIString   `json:"base_task_id"`
+	TaskId       APIString   `json:"task_id"`
+	BaseStatus   APIString   `json:"base_status"`
+	Status       APIString   `json:"status"`
+	BaseDuration APIDuration `json:"base_time_taken_ms"`
+	Duration     APIDuration `json:"time_taken_ms"`
+}
+
+// APITestComparison pairs the status of a test with its status in the base
+// version.
+type APITestComparison struct {
+	BuildVariant APIString `json:"build_variant"`
+	TaskName     APIString `json:"task_name"`
+	TestFile     APIString `json:"test_file"`
+	BaseStatus   APIString `json:"base_status"`
+	Status       APIString `json:"status"`
+}
+
+// APIArtifactComparison is a file attached to a task in only one of the
+// compared versions.
+type APIArtifactComparison struct {
+	BuildVariant APIString `json:"build_variant"`
+	TaskName     APIString `json:"task_name"`
+	Name         APIString `json:"name"`
+	URL          APIString `json:"url"`
+}
+
+// BuildFromService converts from a service level version comparison to an
+// APIVersionComparison.
+func (c *APIVersionComparison) BuildFromService(h interface{}) error {
+	v, ok := h.(*model.VersionComparison)
+	if !ok {
+		return errors.Errorf("incorrect type when converting version comparison type")
+	}
+
+	c.BaseVersion = APIString(v.BaseVersion)
+	c.Version = APIString(v.Version)
+	c.StatusChanges = buildTaskComparisons(v.StatusChanges)
+	c.DurationChanges = buildTaskComparisons(v.DurationChanges)
+	c.NewlyFailing = buildTestComparisons(v.NewlyFailing)
+	c.NewlyPassing = buildTestComparisons(v.NewlyPassing)
+	c.AddedArtifacts = buildArtifactComparisons(v.AddedArtifacts)
+	c.RemovedArtifacts = buildArtifactComparisons(v.RemovedArtifacts)
+	return nil
+}
+
+// ToService is not implemented for APIVersionComparison.
+func (c *APIVersionComparison) ToService() (interface{}, error) {
+	return nil, errors.New("ToService() is not implemented for APIVersionComparison")
+}
+
+func buildTaskComparisons(tasks []model.TaskComparison) []APITaskComparison {
+	out := []APITaskComparison{}
+	for _, t := range tasks {
+		out = append(out, APITaskComparison{
+			BuildVariant: APIString(t.BuildVariant),
+			DisplayName:  APIString(t.DisplayName),
+			BaseTaskId:   APIString(t.BaseTaskId),
+			TaskId:       APIString(t.TaskId),
+			BaseStatus:   APIString(t.BaseStatus),
+			Status:       APIString(t.Status),
+			BaseDuration: NewAPIDuration(t.BaseDuration),
+			Duration:     NewAPIDuration(t.Duration),
+		})
+	}
+	return out
+}
+
+func buildTestComparisons(tests []model.TestComparison) []APITestComparison {
+	out := []APITestComparison{}
+	for _, t := range tests {
+		out = append(out, APITestComparison{
+			BuildVariant: APIString(t.BuildVariant),
+			TaskName:     APIString(t.TaskName),
+			TestFile:     APIString(t.TestFile),
+			BaseStatus:   APIString(t.BaseStatus),
+			Status:       APIString(t.Status),
+		})
+	}
+	return out
+}
+
+func buildArtifactComparisons(files []model.ArtifactComparison) []APIArtifactComparison {
+	out := []APIArtifactComparison{}
+	for _, f := range files {
+		out = append(out, APIArtifactComparison{
+			BuildVariant: APIString(f.BuildVariant),
+			TaskName:     APIString(f.TaskName),
+			Name:         APIString(f.Name),
+			URL:          APIString(f.Link),
+		})
+	}
+	return out
+}
@@ -51,6 +51,9 @@ func TestVersionBuildFromService(t *testing.T) {
 		Repo:          repo,
 		Branch:        branch,
 		BuildVariants: buildVariants,
+
+		EstimatedFinish: time,
+		QueuePosition:   3,
 	}
 
 	apiVersion := &APIVersion{}
@@ -69,6 +72,8 @@ func TestVersionBuildFromService(t *testing.T) {
 	assert.Equal(apiVersion.Status, APIString(status))
 	assert.Equal(apiVersion.Repo, APIString(repo))
 	assert.Equal(apiVersion.Branch, APIString(branch))
+	assert.Equal(apiVersion.EstimatedFinish, NewTime(time))
+	assert.Equal(apiVersion.QueuePosition, 3)
 
 	bvs := apiVersion.BuildVariants
 	assert.Equal(bvs[0].BuildVariant, APIString(bv1))
@@ -0,0 +1,71 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/rest"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/pkg/errors"
+)
+
+// getTaskArtifactsRouteManager gets the route manager for the
+// GET /tasks/{task_id}/artifacts route.
+func getTaskArtifactsRouteManager(route string, version int) *RouteManager {
+	return &RouteManager{
+		Route:   route,
+		Version: version,
+		Methods: []MethodHandler{
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser, PrefetchProjectContext},
+				MethodType:        http.MethodGet,
+				Authenticator:     &RequireUserAuthenticator{},
+				RequestHandler:    &taskArtifactsGetHandler{},
+			},
+		},
+	}
+}
+
+// taskArtifactsGetHandler returns the artifact files of a task, with signed
+// links for the files stored in S3.
+type taskArtifactsGetHandler struct{}
+
+func (h *taskArtifactsGetHandler) Handler() RequestHandler {
+	return &taskArtifactsGetHandler{}
+}
+
+func (h *taskArtifactsGetHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	projCtx := MustHaveProjectContext(ctx)
+	if projCtx.Task == nil {
+		return rest.APIError{
+			Message:    "Task not found",
+			StatusCode: http.StatusNotFound,
+		}
+	}
+	return nil
+}
+
+func (h *taskArtifactsGetHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	projCtx := MustHaveProjectContext(ctx)
+	files, err := sc.FindTaskArtifacts(projCtx.Task, projCtx.ProjectRef, GetUser(ctx))
+	if err != nil {
+		if _, ok := err.(*rest.APIError); !ok {
+			err = errors.Wrap(err, "Database error")
+		}
+		return ResponseData{}, err
+	}
+
+	models := make([]model.Model, 0, len(files))
+	for _, f := range files {
+		fileModel := &model.APIFile{}
+		if err = fileModel.BuildFromService(f); err != nil {
+			return ResponseData{}, errors.Wrap(err, "API model error")
+		}
+		models = append(models, fileModel)
+	}
+
+	return ResponseData{
+		Result: models,
+	}, nil
+}
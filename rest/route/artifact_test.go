@@ -0,0 +1,53 @@
+package route
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskArtifactsGetHandler(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	sc := &data.MockConnector{
+		MockArtifactConnector: data.MockArtifactConnector{
+			CachedFiles: map[string][]artifact.File{
+				"task1": {
+					{Name: "public", Link: "https://s3.amazonaws.com/bucket/public.tgz", Visibility: artifact.Public},
+					{Name: "private", Link: "https://s3.amazonaws.com/bucket/private.tgz", Visibility: artifact.Private},
+					{Name: "hidden", Link: "https://s3.amazonaws.com/bucket/hidden.tgz", Visibility: artifact.None},
+				},
+			},
+		},
+	}
+	projCtx := &serviceModel.Context{
+		Task:       &task.Task{Id: "task1"},
+		ProjectRef: &serviceModel.ProjectRef{Identifier: "project", Private: true},
+	}
+
+	rm := getTaskArtifactsRouteManager("/tasks/{task_id}/artifacts", 2)
+	handler := rm.Methods[0].Handler()
+
+	ctx := context.WithValue(context.Background(), RequestContext, projCtx)
+	ctx = context.WithValue(ctx, evergreen.RequestUser, &user.DBUser{Id: "user"})
+	res, err := handler.Execute(ctx, sc)
+	require.NoError(err)
+	require.Len(res.Result, 2)
+	assert.Equal(model.APIString("public"), res.Result[0].(*model.APIFile).Name)
+	assert.Equal(model.APIString("private"), res.Result[1].(*model.APIFile).Name)
+
+	// the artifacts of private projects are not returned without a user
+	ctx = context.WithValue(context.Background(), RequestContext, projCtx)
+	_, err = handler.Execute(ctx, sc)
+	assert.Error(err)
+}
@@ -18,6 +18,7 @@ import (
 // types and functions for Version Cost Route
 type costByVersionHandler struct {
 	versionId string
+	query     *listQuery
 }
 
 func getCostByVersionIdRouteManager(route string, version int) *RouteManager {
@@ -45,7 +46,9 @@ func (cbvh *costByVersionHandler) ParseAndValidate(ctx context.Context, r *http.
 		return errors.New("request data incomplete")
 	}
 
-	return nil
+	var err error
+	cbvh.query, err = parseListQuery(r.URL.Query(), &ListQuerySpec{Model: &model.APIVersionCost{}})
+	return err
 }
 
 func (cbvh *costByVersionHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
@@ -65,9 +68,7 @@ func (cbvh *costByVersionHandler) Execute(ctx context.Context, sc data.Connector
 		}
 		return ResponseData{}, err
 	}
-	return ResponseData{
-		Result: []model.Model{versionCostModel},
-	}, nil
+	return selectFields(cbvh.query, versionCostModel)
 }
 
 // types and functions for Distro Cost Route
@@ -75,6 +76,7 @@ type costByDistroHandler struct {
 	distroId  string
 	startTime time.Time
 	duration  time.Duration
+	query     *listQuery
 }
 
 func getCostByDistroIdRouteManager(route string, version int) *RouteManager {
@@ -140,7 +142,8 @@ func (cbvh *costByDistroHandler) ParseAndValidate(ctx context.Context, r *http.R
 	cbvh.startTime = st
 	cbvh.duration = d
 
-	return nil
+	cbvh.query, err = parseListQuery(r.URL.Query(), &ListQuerySpec{Model: &model.APIDistroCost{}})
+	return err
 }
 
 func (cbvh *costByDistroHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
@@ -160,9 +163,7 @@ func (cbvh *costByDistroHandler) Execute(ctx context.Context, sc data.Connector)
 		}
 		return ResponseData{}, err
 	}
-	return ResponseData{
-		Result: []model.Model{distroCostModel},
-	}, nil
+	return selectFields(cbvh.query, distroCostModel)
 }
 
 type costTasksByProjectHandler struct {
@@ -197,6 +198,11 @@ func (h *costTasksByProjectHandler) Handler() RequestHandler {
 		KeyQueryParam:   "start_at",
 		LimitQueryParam: "limit",
 		Paginator:       costTasksByProjectPaginator,
+		ListQuery: &ListQuerySpec{
+			Model:    &model.APITaskCost{},
+			Filters:  map[string]string{"variant": "build_variant"},
+			KeyField: "task_id",
+		},
 	}}
 }
 
@@ -2,14 +2,18 @@ package route
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/rest"
 	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/google/go-github/github"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
 )
 
 const (
@@ -78,6 +82,20 @@ func (gh *githubHookApi) ParseAndValidate(ctx context.Context, r *http.Request)
 		}
 	}
 
+	// the vendored client predates the checks API, so check run events
+	// are parsed here
+	if eventType == thirdparty.GithubCheckRunEvent {
+		checkRun := &thirdparty.GithubCheckRunWebhook{}
+		if err = json.Unmarshal(body, checkRun); err != nil {
+			return rest.APIError{
+				StatusCode: http.StatusBadRequest,
+				Message:    err.Error(),
+			}
+		}
+		gh.event = checkRun
+		return nil
+	}
+
 	gh.event, err = github.ParseWebHook(eventType, body)
 	if err != nil {
 		return rest.APIError{
@@ -132,6 +150,28 @@ func (gh *githubHookApi) Execute(ctx context.Context, sc data.Connector) (Respon
 
 	case *github.PushEvent:
 		return ResponseData{}, sc.TriggerRepotracker(gh.queue, gh.msgID, event)
+
+	case *github.IssueCommentEvent:
+		return ResponseData{}, sc.AddGithubCommentCommand(gh.queue, gh.msgID, event)
+
+	case *thirdparty.GithubCheckRunWebhook:
+		if event.Action != thirdparty.GithubCheckRunRequestedAction || event.RequestedAction == nil ||
+			event.RequestedAction.Identifier != thirdparty.GithubCheckRunRerunFailed {
+			break
+		}
+		if event.CheckRun.ExternalID == "" {
+			return ResponseData{}, rest.APIError{
+				StatusCode: http.StatusBadRequest,
+				Message:    "check run has no build",
+			}
+		}
+		grip.Info(message.Fields{
+			"message":   "restarting failed tasks from github check run",
+			"build":     event.CheckRun.ExternalID,
+			"check_run": event.CheckRun.ID,
+			"msg_id":    gh.msgID,
+		})
+		return ResponseData{}, sc.RestartFailedBuildTasks(event.CheckRun.ExternalID, evergreen.GithubPatchUser)
 	}
 
 	return ResponseData{}, nil
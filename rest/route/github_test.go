@@ -15,6 +15,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/evergreen/thirdparty"
 	"github.com/google/go-github/github"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/grip"
@@ -57,9 +58,14 @@ func (s *GithubWebhookRouteSuite) SetupTest() {
 
 	s.queue = evergreen.GetEnvironment().LocalQueue()
 	s.rm = getGithubHooksRouteManager(s.queue, []byte(s.conf.Api.GithubWebhookSecret))("", 2)
-	s.sc = &data.MockConnector{MockPatchIntentConnector: data.MockPatchIntentConnector{
-		CachedIntents: map[data.MockPatchIntentKey]patch.Intent{},
-	}}
+	s.sc = &data.MockConnector{
+		MockPatchIntentConnector: data.MockPatchIntentConnector{
+			CachedIntents: map[data.MockPatchIntentKey]patch.Intent{},
+		},
+		MockBuildConnector: data.MockBuildConnector{
+			CachedRestarted: map[string]string{},
+		},
+	}
 
 	var err error
 	s.prBody, err = ioutil.ReadFile(filepath.Join(testutil.GetDirectoryOfFile(), "testdata", "pull_request.json"))
@@ -166,3 +172,70 @@ func (s *GithubWebhookRouteSuite) TestPushEventTriggersRepoTracker() {
 	s.NoError(err)
 	s.Empty(resp.Result)
 }
+
+func (s *GithubWebhookRouteSuite) TestCheckRunRerunRestartsFailedTasks() {
+	body := []byte(`{
+		"action": "requested_action",
+		"check_run": {"id": 7, "name": "evergreen/ubuntu", "head_sha": "abcdef", "external_id": "build-1"},
+		"requested_action": {"identifier": "rerun-failed"}
+	}`)
+	req, err := makeRequest("1", body, []byte(s.conf.Api.GithubWebhookSecret))
+	s.NoError(err)
+	req.Header.Set("X-Github-Event", thirdparty.GithubCheckRunEvent)
+
+	ctx := context.Background()
+	s.NoError(s.h.ParseAndValidate(ctx, req))
+	s.IsType(&thirdparty.GithubCheckRunWebhook{}, s.h.event)
+
+	resp, err := s.h.Execute(ctx, s.sc)
+	s.NoError(err)
+	s.Empty(resp.Result)
+	s.Equal(evergreen.GithubPatchUser, s.sc.MockBuildConnector.CachedRestarted["build-1"])
+}
+
+func (s *GithubWebhookRouteSuite) TestCheckRunIgnoresOtherActions() {
+	s.h.event = &thirdparty.GithubCheckRunWebhook{
+		Action:   "created",
+		CheckRun: thirdparty.GithubCheckRun{ExternalID: "build-1"},
+	}
+
+	resp, err := s.h.Execute(context.Background(), s.sc)
+	s.NoError(err)
+	s.Empty(resp.Result)
+	s.Empty(s.sc.MockBuildConnector.CachedRestarted)
+}
+
+func (s *GithubWebhookRouteSuite) TestIssueCommentCommand() {
+	body := []byte(`{
+		"action": "created",
+		"issue": {"number": 5, "pull_request": {"url": "https://api.github.com/repos/evergreen-ci/evergreen/pulls/5"}},
+		"comment": {"id": 9, "body": "evergreen retry", "user": {"login": "octocat"}},
+		"repository": {"name": "evergreen", "full_name": "evergreen-ci/evergreen", "owner": {"login": "evergreen-ci"}}
+	}`)
+	req, err := makeRequest("1", body, []byte(s.conf.Api.GithubWebhookSecret))
+	s.NoError(err)
+	req.Header.Set("X-Github-Event", thirdparty.GithubIssueCommentEvent)
+
+	ctx := context.Background()
+	s.NoError(s.h.ParseAndValidate(ctx, req))
+	s.Require().IsType(&github.IssueCommentEvent{}, s.h.event)
+
+	resp, err := s.h.Execute(ctx, s.sc)
+	s.NoError(err)
+	s.Empty(resp.Result)
+
+	event := s.h.event.(*github.IssueCommentEvent)
+	event.Repo.Owner = nil
+	_, err = s.h.Execute(ctx, s.sc)
+	s.Error(err)
+
+	// comments that aren't commands, or aren't on pull requests, are ignored
+	event.Comment.Body = github.String("LGTM")
+	_, err = s.h.Execute(ctx, s.sc)
+	s.NoError(err)
+
+	event.Comment.Body = github.String("evergreen retry")
+	event.Issue.PullRequestLinks = nil
+	_, err = s.h.Execute(ctx, s.sc)
+	s.NoError(err)
+}
@@ -0,0 +1,122 @@
+package route
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/rest"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+)
+
+type gitlabHookApi struct {
+	queue  amboy.Queue
+	secret []byte
+
+	event *patch.GitlabMergeRequestEvent
+}
+
+func getGitlabHooksRouteManager(queue amboy.Queue, secret []byte) routeManagerFactory {
+	return func(route string, version int) *RouteManager {
+		methods := []MethodHandler{}
+		if len(secret) > 0 {
+			methods = append(methods, MethodHandler{
+				Authenticator: &NoAuthAuthenticator{},
+				RequestHandler: &gitlabHookApi{
+					queue:  queue,
+					secret: secret,
+				},
+				MethodType: http.MethodPost,
+			})
+		}
+
+		return &RouteManager{
+			Route:   route,
+			Methods: methods,
+			Version: version,
+		}
+	}
+}
+
+func (gh *gitlabHookApi) Handler() RequestHandler {
+	return &gitlabHookApi{
+		queue:  gh.queue,
+		secret: gh.secret,
+	}
+}
+
+func (gh *gitlabHookApi) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	if len(gh.secret) == 0 || gh.queue == nil {
+		return rest.APIError{
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	// gitlab sends the webhook's secret token rather than signing the payload
+	token := []byte(r.Header.Get(thirdparty.GitlabTokenHeader))
+	if subtle.ConstantTimeCompare(token, gh.secret) != 1 {
+		grip.Error("Rejecting GitLab webhook POST with an invalid token")
+		return rest.APIError{
+			StatusCode: http.StatusUnauthorized,
+			Message:    "invalid webhook token",
+		}
+	}
+
+	// only merge request events create patches
+	if r.Header.Get(thirdparty.GitlabEventHeader) != thirdparty.GitlabMergeRequestHook {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "failed to read request body",
+		}
+	}
+	gh.event = &patch.GitlabMergeRequestEvent{}
+	if err = json.Unmarshal(body, gh.event); err != nil {
+		return rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	return nil
+}
+
+func (gh *gitlabHookApi) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	if gh.event == nil || !gh.event.ChangesCommits() {
+		return ResponseData{}, nil
+	}
+
+	intent, err := patch.NewGitlabIntent(gh.event)
+	if err != nil {
+		return ResponseData{}, rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	if err = sc.AddPatchIntent(intent, gh.queue); err != nil {
+		return ResponseData{}, rest.APIError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}
+	}
+	grip.Info(message.Fields{
+		"message":   "gitlab merge request queued",
+		"project":   gh.event.Project.PathWithNamespace,
+		"mr_number": gh.event.ObjectAttributes.IID,
+		"intent_id": intent.ID(),
+	})
+
+	return ResponseData{}, nil
+}
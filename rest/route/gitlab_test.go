@@ -0,0 +1,137 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/rest"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/mongodb/amboy/queue"
+	"github.com/stretchr/testify/suite"
+)
+
+type GitlabWebhookRouteSuite struct {
+	sc     *data.MockConnector
+	rm     *RouteManager
+	mrBody []byte
+	h      *gitlabHookApi
+	suite.Suite
+}
+
+func TestGitlabWebhookRouteSuite(t *testing.T) {
+	suite.Run(t, new(GitlabWebhookRouteSuite))
+}
+
+func (s *GitlabWebhookRouteSuite) SetupTest() {
+	s.rm = getGitlabHooksRouteManager(queue.NewLocalUnordered(1), []byte("gitlab-secret"))("", 2)
+	s.sc = &data.MockConnector{
+		MockPatchIntentConnector: data.MockPatchIntentConnector{
+			CachedIntents: map[data.MockPatchIntentKey]patch.Intent{},
+		},
+	}
+
+	var err error
+	s.mrBody, err = ioutil.ReadFile(filepath.Join(testutil.GetDirectoryOfFile(), "testdata", "gitlab_merge_request.json"))
+	s.Require().NoError(err)
+
+	s.Require().Len(s.rm.Methods, 1)
+	var ok bool
+	s.h, ok = s.rm.Methods[0].Handler().(*gitlabHookApi)
+	s.Require().True(ok)
+}
+
+func (s *GitlabWebhookRouteSuite) request(event, token string, body []byte) *http.Request {
+	req, err := http.NewRequest(http.MethodPost, "/hooks/gitlab", bytes.NewReader(body))
+	s.Require().NoError(err)
+	req.Header.Add(thirdparty.GitlabEventHeader, event)
+	req.Header.Add(thirdparty.GitlabTokenHeader, token)
+	return req
+}
+
+func (s *GitlabWebhookRouteSuite) TestNoSecretDisablesRoute() {
+	rm := getGitlabHooksRouteManager(queue.NewLocalUnordered(1), nil)("", 2)
+	s.Empty(rm.Methods)
+}
+
+func (s *GitlabWebhookRouteSuite) TestInvalidToken() {
+	ctx := context.Background()
+	for _, token := range []string{"", "wrong-secret"} {
+		err := s.h.ParseAndValidate(ctx, s.request(thirdparty.GitlabMergeRequestHook, token, s.mrBody))
+		s.Require().Error(err)
+		apiErr, ok := err.(rest.APIError)
+		s.Require().True(ok)
+		s.Equal(http.StatusUnauthorized, apiErr.StatusCode)
+	}
+}
+
+func (s *GitlabWebhookRouteSuite) TestAddIntent() {
+	ctx := context.Background()
+	s.Require().NoError(s.h.ParseAndValidate(ctx, s.request(thirdparty.GitlabMergeRequestHook, "gitlab-secret", s.mrBody)))
+	resp, err := s.h.Execute(ctx, s.sc)
+	s.NoError(err)
+	s.Empty(resp.Result)
+
+	s.Require().Len(s.sc.MockPatchIntentConnector.CachedIntents, 1)
+	for _, intent := range s.sc.MockPatchIntentConnector.CachedIntents {
+		s.Equal(patch.GitlabIntentType, intent.GetType())
+		s.Equal("3-12-da1560886d4f094c3e6c9ef40349f7d38b5d27d7", intent.ID())
+		p := intent.NewPatch()
+		s.Equal(3, p.GitlabPatchData.ProjectID)
+		s.Equal(12, p.GitlabPatchData.MRNumber)
+		s.Equal("evergreen-ci/evergreen", p.GitlabPatchData.Project)
+		s.Equal("master", p.GitlabPatchData.TargetBranch)
+	}
+
+	// a redelivered webhook is the same intent
+	s.Require().NoError(s.h.ParseAndValidate(ctx, s.request(thirdparty.GitlabMergeRequestHook, "gitlab-secret", s.mrBody)))
+	_, err = s.h.Execute(ctx, s.sc)
+	s.Error(err)
+	s.Len(s.sc.MockPatchIntentConnector.CachedIntents, 1)
+}
+
+func (s *GitlabWebhookRouteSuite) TestIgnoredEvents() {
+	ctx := context.Background()
+
+	// events other than merge requests
+	s.Require().NoError(s.h.ParseAndValidate(ctx, s.request("Push Hook", "gitlab-secret", []byte(`{"object_kind": "push"}`))))
+	_, err := s.h.Execute(ctx, s.sc)
+	s.NoError(err)
+
+	// merge request updates that don't push commits
+	event := map[string]interface{}{}
+	s.Require().NoError(json.Unmarshal(s.mrBody, &event))
+	event["object_attributes"].(map[string]interface{})["action"] = "update"
+	body, err := json.Marshal(event)
+	s.Require().NoError(err)
+	s.Require().NoError(s.h.ParseAndValidate(ctx, s.request(thirdparty.GitlabMergeRequestHook, "gitlab-secret", body)))
+	_, err = s.h.Execute(ctx, s.sc)
+	s.NoError(err)
+
+	event["object_attributes"].(map[string]interface{})["action"] = "close"
+	body, err = json.Marshal(event)
+	s.Require().NoError(err)
+	s.Require().NoError(s.h.ParseAndValidate(ctx, s.request(thirdparty.GitlabMergeRequestHook, "gitlab-secret", body)))
+	_, err = s.h.Execute(ctx, s.sc)
+	s.NoError(err)
+
+	s.Empty(s.sc.MockPatchIntentConnector.CachedIntents)
+}
+
+func (s *GitlabWebhookRouteSuite) TestMalformedMergeRequest() {
+	ctx := context.Background()
+	s.Error(s.h.ParseAndValidate(ctx, s.request(thirdparty.GitlabMergeRequestHook, "gitlab-secret", []byte("{"))))
+
+	body := []byte(`{"object_kind": "merge_request", "object_attributes": {"action": "open"}}`)
+	s.Require().NoError(s.h.ParseAndValidate(ctx, s.request(thirdparty.GitlabMergeRequestHook, "gitlab-secret", body)))
+	_, err := s.h.Execute(ctx, s.sc)
+	s.Error(err)
+	s.Empty(s.sc.MockPatchIntentConnector.CachedIntents)
+}
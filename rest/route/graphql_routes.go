@@ -0,0 +1,115 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/rest"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/graphql"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+////////////////////////////////////////////////////////////////////////
+//
+// Handler for read-only GraphQL queries
+//
+//    /graphql
+
+func getGraphQLRouteManager(route string, version int) *RouteManager {
+	h := &graphQLHandler{}
+	return &RouteManager{
+		Route:   route,
+		Version: version,
+		Methods: []MethodHandler{
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				Authenticator:     &RequireUserAuthenticator{},
+				RequestHandler:    h.Handler(),
+				MethodType:        http.MethodGet,
+			},
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				Authenticator:     &RequireUserAuthenticator{},
+				RequestHandler:    h.Handler(),
+				MethodType:        http.MethodPost,
+			},
+		},
+	}
+}
+
+type graphQLHandler struct {
+	request graphql.Request
+}
+
+func (h *graphQLHandler) Handler() RequestHandler {
+	return &graphQLHandler{}
+}
+
+// ParseAndValidate reads the query, operation name and variables from the
+// JSON body of a POST, or from the query string of a GET.
+func (h *graphQLHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	if r.Method == http.MethodGet {
+		vals := r.URL.Query()
+		h.request.Query = vals.Get("query")
+		h.request.OperationName = vals.Get("operationName")
+		if vars := vals.Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &h.request.Variables); err != nil {
+				return rest.APIError{
+					StatusCode: http.StatusBadRequest,
+					Message:    "variables must be a JSON object",
+				}
+			}
+		}
+	} else {
+		body := util.NewRequestReader(r)
+		defer body.Close()
+		if err := util.ReadJSONInto(body, &h.request); err != nil {
+			return errors.Wrap(err, "Argument read error")
+		}
+	}
+
+	if h.request.Query == "" {
+		return rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must specify a query",
+		}
+	}
+	return nil
+}
+
+// Execute runs the query against a schema built for this request. Query
+// errors are reported in the body of the response, as is conventional
+// for GraphQL, rather than as API errors.
+func (h *graphQLHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	resp := &graphQLResponse{}
+	if err := resp.BuildFromService(newGraphQLSchema(sc).Execute(ctx, h.request)); err != nil {
+		return ResponseData{}, errors.Wrap(err, "API model error")
+	}
+
+	return ResponseData{
+		Result: []model.Model{resp},
+	}, nil
+}
+
+// graphQLResponse adapts a GraphQL response to the Model interface so it
+// can be written out by the standard handler.
+type graphQLResponse struct {
+	*graphql.Response
+}
+
+func (r *graphQLResponse) BuildFromService(h interface{}) error {
+	resp, ok := h.(*graphql.Response)
+	if !ok {
+		return errors.New("incorrect type when converting graphql response")
+	}
+	r.Response = resp
+	return nil
+}
+
+func (r *graphQLResponse) ToService() (interface{}, error) {
+	return nil, errors.New("not implemented for read-only route")
+}
@@ -0,0 +1,492 @@
+package route
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/rest"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/graphql"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/pkg/errors"
+)
+
+// graphQLListEstimate is the number of elements assumed for list fields
+// whose length is not bounded by a limit argument, such as the builds of
+// a version, when computing query complexity.
+const graphQLListEstimate = 25
+
+// graphQLConnection is the value of a paginated field. It carries a page
+// of results as returned by a PaginatorFunc, so that GraphQL clients page
+// through results with the same keys as the REST routes.
+type graphQLConnection struct {
+	nodes []model.Model
+	pages *PageResult
+}
+
+// graphQLLoaders holds the per-request batched loaders used to resolve
+// references between resources without issuing one query per parent.
+type graphQLLoaders struct {
+	builds   *graphql.Loader
+	tasks    *graphql.Loader
+	versions *graphql.Loader
+}
+
+func newGraphQLLoaders(sc data.Connector) *graphQLLoaders {
+	return &graphQLLoaders{
+		builds: graphql.NewLoader(func(_ context.Context, ids []string) (map[string]interface{}, error) {
+			builds, err := sc.FindBuildsByIds(ids)
+			if err != nil {
+				return nil, errors.Wrap(err, "Database error")
+			}
+			projects := map[string]string{}
+			out := make(map[string]interface{}, len(builds))
+			for _, b := range builds {
+				if _, ok := projects[b.Project]; !ok {
+					ref, err := sc.FindProjectByBranch(b.Project)
+					if err != nil {
+						return nil, errors.Wrap(err, "Database error")
+					}
+					if ref != nil {
+						projects[b.Project] = ref.Repo
+					}
+				}
+				buildModel := &model.APIBuild{ProjectId: model.APIString(projects[b.Project])}
+				if err = buildModel.BuildFromService(b); err != nil {
+					return nil, errors.Wrap(err, "API model error")
+				}
+				out[b.Id] = buildModel
+			}
+			return out, nil
+		}),
+		tasks: graphql.NewLoader(func(_ context.Context, ids []string) (map[string]interface{}, error) {
+			tasks, err := sc.FindTasksByIds(ids)
+			if err != nil {
+				if apiErr, ok := err.(*rest.APIError); !ok || apiErr.StatusCode != http.StatusNotFound {
+					return nil, errors.Wrap(err, "Database error")
+				}
+			}
+			out := make(map[string]interface{}, len(tasks))
+			for i := range tasks {
+				taskModel := &model.APITask{}
+				if err = taskModel.BuildFromService(&tasks[i]); err != nil {
+					return nil, errors.Wrap(err, "API model error")
+				}
+				if err = taskModel.BuildFromService(sc.GetURL()); err != nil {
+					return nil, errors.Wrap(err, "API model error")
+				}
+				out[tasks[i].Id] = taskModel
+			}
+			return out, nil
+		}),
+		versions: graphql.NewLoader(func(_ context.Context, ids []string) (map[string]interface{}, error) {
+			versions, err := sc.FindVersionsByIds(ids)
+			if err != nil {
+				return nil, errors.Wrap(err, "Database error")
+			}
+			out := make(map[string]interface{}, len(versions))
+			for i := range versions {
+				versionModel := &model.APIVersion{}
+				if err = versionModel.BuildFromService(&versions[i]); err != nil {
+					return nil, errors.Wrap(err, "API model error")
+				}
+				out[versions[i].Id] = versionModel
+			}
+			return out, nil
+		}),
+	}
+}
+
+// newGraphQLSchema builds the read-only GraphQL schema over the REST v2
+// models. A schema holds request-scoped loaders, so a new one must be
+// built for every request.
+func newGraphQLSchema(sc data.Connector) *graphql.Schema {
+	loaders := newGraphQLLoaders(sc)
+
+	pageType := graphql.NewObject("Page").
+		AddField("key", &graphql.Field{Resolve: func(_ context.Context, p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(*Page).Key, nil
+		}}).
+		AddField("limit", &graphql.Field{Resolve: func(_ context.Context, p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(*Page).Limit, nil
+		}})
+	pageInfoType := graphql.NewObject("PageInfo").
+		AddField("next", &graphql.Field{Type: pageType, Resolve: func(_ context.Context, p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(*PageResult).Next, nil
+		}}).
+		AddField("prev", &graphql.Field{Type: pageType, Resolve: func(_ context.Context, p graphql.ResolveParams) (interface{}, error) {
+			return p.Source.(*PageResult).Prev, nil
+		}})
+
+	versionType := modelObject("Version", &model.APIVersion{})
+	buildType := modelObject("Build", &model.APIBuild{})
+	taskType := modelObject("Task", &model.APITask{})
+	testType := modelObject("Test", &model.APITest{})
+	hostType := modelObject("Host", &model.APIHost{})
+	distroType := modelObject("Distro", &model.APIDistro{})
+	patchType := modelObject("Patch", &model.APIPatch{})
+
+	taskConnection := connectionObject("TaskConnection", taskType, pageInfoType)
+	testConnection := connectionObject("TestConnection", testType, pageInfoType)
+	hostConnection := connectionObject("HostConnection", hostType, pageInfoType)
+	patchConnection := connectionObject("PatchConnection", patchType, pageInfoType)
+
+	versionType.
+		AddField("builds", &graphql.Field{
+			Type:       buildType,
+			List:       true,
+			Multiplier: fixedMultiplier(graphQLListEstimate),
+			Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+				v := p.Source.(*model.APIVersion)
+				ids := make([]string, 0, len(v.BuildVariants))
+				for _, bv := range v.BuildVariants {
+					ids = append(ids, string(bv.BuildId))
+				}
+				return loaders.builds.LoadMany(ctx, ids), nil
+			},
+		})
+
+	buildType.
+		AddField("version", &graphql.Field{
+			Type: versionType,
+			Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+				return loaders.versions.Load(ctx, string(p.Source.(*model.APIBuild).Version)), nil
+			},
+		}).
+		AddField("tasks", &graphql.Field{
+			Type:       taskType,
+			List:       true,
+			Multiplier: fixedMultiplier(graphQLListEstimate),
+			Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+				return loaders.tasks.LoadMany(ctx, p.Source.(*model.APIBuild).Tasks), nil
+			},
+		}).
+		AddField("task_connection", connectionField(taskConnection, tasksByBuildPaginator, sc,
+			map[string]interface{}{"status": nil},
+			func(p graphql.ResolveParams) (interface{}, error) {
+				status, err := graphql.StringArg(p.Args, "status")
+				return tasksByBuildArgs{
+					buildId: string(p.Source.(*model.APIBuild).Id),
+					status:  status,
+				}, err
+			}))
+
+	taskType.
+		AddField("build", &graphql.Field{
+			Type: buildType,
+			Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+				return loaders.builds.Load(ctx, string(p.Source.(*model.APITask).BuildId)), nil
+			},
+		}).
+		AddField("version", &graphql.Field{
+			Type: versionType,
+			Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+				return loaders.versions.Load(ctx, string(p.Source.(*model.APITask).Version)), nil
+			},
+		}).
+		AddField("tests", connectionField(testConnection, testPaginator, sc,
+			map[string]interface{}{"status": nil, "execution": nil},
+			func(p graphql.ResolveParams) (interface{}, error) {
+				status, err := graphql.StringArg(p.Args, "status")
+				if err != nil {
+					return nil, err
+				}
+				execution, err := graphql.IntArg(p.Args, "execution")
+				return testGetHandlerArgs{
+					taskId:        string(p.Source.(*model.APITask).Id),
+					testStatus:    status,
+					testExecution: execution,
+				}, err
+			}))
+
+	hostType.
+		AddField("task", &graphql.Field{
+			Type: taskType,
+			Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+				taskId := string(p.Source.(*model.APIHost).RunningTask.Id)
+				if taskId == "" {
+					return nil, nil
+				}
+				return loaders.tasks.Load(ctx, taskId), nil
+			},
+		}).
+		AddField("running_tasks", &graphql.Field{
+			Type:       taskType,
+			List:       true,
+			Multiplier: fixedMultiplier(graphQLListEstimate),
+			Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+				running := p.Source.(*model.APIHost).RunningTasks
+				taskIds := make([]string, 0, len(running))
+				for _, info := range running {
+					taskIds = append(taskIds, string(info.Id))
+				}
+				return loaders.tasks.LoadMany(ctx, taskIds), nil
+			},
+		})
+
+	patchType.
+		AddField("version", &graphql.Field{
+			Type: versionType,
+			Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+				// finalized patches share their id with their version
+				return loaders.versions.Load(ctx, string(p.Source.(*model.APIPatch).Id)), nil
+			},
+		})
+
+	idArgs := map[string]interface{}{"id": nil}
+	query := graphql.NewObject("Query").
+		AddField("version", &graphql.Field{
+			Type: versionType,
+			Args: idArgs,
+			Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+				id, err := requiredStringArg(p.Args, "id")
+				if err != nil {
+					return nil, err
+				}
+				return loaders.versions.Load(ctx, id), nil
+			},
+		}).
+		AddField("build", &graphql.Field{
+			Type: buildType,
+			Args: idArgs,
+			Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+				id, err := requiredStringArg(p.Args, "id")
+				if err != nil {
+					return nil, err
+				}
+				return loaders.builds.Load(ctx, id), nil
+			},
+		}).
+		AddField("task", &graphql.Field{
+			Type: taskType,
+			Args: idArgs,
+			Resolve: func(ctx context.Context, p graphql.ResolveParams) (interface{}, error) {
+				id, err := requiredStringArg(p.Args, "id")
+				if err != nil {
+					return nil, err
+				}
+				return loaders.tasks.Load(ctx, id), nil
+			},
+		}).
+		AddField("host", &graphql.Field{
+			Type: hostType,
+			Args: idArgs,
+			Resolve: func(_ context.Context, p graphql.ResolveParams) (interface{}, error) {
+				id, err := requiredStringArg(p.Args, "id")
+				if err != nil {
+					return nil, err
+				}
+				h, err := sc.FindHostById(id)
+				if err != nil {
+					return nil, ignoreNotFound(err)
+				}
+				hostModel := &model.APIHost{}
+				if err = hostModel.BuildFromService(h); err != nil {
+					return nil, errors.Wrap(err, "API model error")
+				}
+				tasks, err := sc.FindTasksByIds(h.RunningTaskIds())
+				if err != nil {
+					if apiErr, ok := err.(*rest.APIError); !ok || apiErr.StatusCode != http.StatusNotFound {
+						return nil, errors.Wrap(err, "Database error")
+					}
+				}
+				tasksById := make(map[string]task.Task, len(tasks))
+				for _, t := range tasks {
+					tasksById[t.Id] = t
+				}
+				return hostModel, errors.Wrap(addHostRunningTasks(hostModel, *h, tasksById), "API model error")
+			},
+		}).
+		AddField("hosts", connectionField(hostConnection, hostPaginator, sc,
+			map[string]interface{}{"status": nil},
+			func(p graphql.ResolveParams) (interface{}, error) {
+				status, err := graphql.StringArg(p.Args, "status")
+				return hostGetArgs{status: status}, err
+			})).
+		AddField("distros", &graphql.Field{
+			Type:       distroType,
+			List:       true,
+			Multiplier: fixedMultiplier(graphQLListEstimate),
+			Resolve: func(_ context.Context, p graphql.ResolveParams) (interface{}, error) {
+				distros, err := sc.FindAllDistros()
+				if err != nil {
+					return nil, errors.Wrap(err, "Database error")
+				}
+				models := make([]*model.APIDistro, len(distros))
+				for i, d := range distros {
+					models[i] = &model.APIDistro{}
+					if err = models[i].BuildFromService(d); err != nil {
+						return nil, errors.Wrap(err, "API model error")
+					}
+				}
+				return models, nil
+			},
+		}).
+		AddField("patch", &graphql.Field{
+			Type: patchType,
+			Args: idArgs,
+			Resolve: func(_ context.Context, p graphql.ResolveParams) (interface{}, error) {
+				id, err := requiredStringArg(p.Args, "id")
+				if err != nil {
+					return nil, err
+				}
+				foundPatch, err := sc.FindPatchById(id)
+				if err != nil {
+					return nil, ignoreNotFound(err)
+				}
+				patchModel := &model.APIPatch{}
+				return patchModel, errors.Wrap(patchModel.BuildFromService(*foundPatch), "API model error")
+			},
+		}).
+		AddField("patches", connectionField(patchConnection, graphQLPatchPaginator, sc,
+			map[string]interface{}{"project_id": nil, "user_id": nil},
+			func(p graphql.ResolveParams) (interface{}, error) {
+				project, err := graphql.StringArg(p.Args, "project_id")
+				if err != nil {
+					return nil, err
+				}
+				user, err := graphql.StringArg(p.Args, "user_id")
+				if err != nil {
+					return nil, err
+				}
+				if (project == "") == (user == "") {
+					return nil, errors.New("exactly one of 'project_id' or 'user_id' must be set")
+				}
+				if project != "" {
+					return patchesByProjectArgs{projectId: project}, nil
+				}
+				return patchesByUserArgs{user: user}, nil
+			}))
+
+	return &graphql.Schema{Query: query}
+}
+
+// modelObject builds an object type exposing every field of a REST model
+// under its JSON name.
+func modelObject(name string, apiModel model.Model) *graphql.Object {
+	obj := graphql.NewObject(name)
+	for fieldName, field := range graphql.ModelFields(apiModel) {
+		obj.AddField(fieldName, field)
+	}
+	return obj
+}
+
+func connectionObject(name string, node, pageInfo *graphql.Object) *graphql.Object {
+	return graphql.NewObject(name).
+		AddField("nodes", &graphql.Field{
+			Type: node,
+			List: true,
+			Resolve: func(_ context.Context, p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(*graphQLConnection).nodes, nil
+			},
+		}).
+		AddField("page_info", &graphql.Field{
+			Type: pageInfo,
+			Resolve: func(_ context.Context, p graphql.ResolveParams) (interface{}, error) {
+				pages := p.Source.(*graphQLConnection).pages
+				if pages == nil {
+					pages = &PageResult{}
+				}
+				return pages, nil
+			},
+		})
+}
+
+// connectionField exposes a PaginatorFunc as a field accepting the same
+// start_at and limit arguments as the corresponding REST route. The
+// makeArgs function builds the paginator's arguments from the field's
+// arguments and its parent.
+func connectionField(conn *graphql.Object, paginator PaginatorFunc, sc data.Connector,
+	args map[string]interface{}, makeArgs func(graphql.ResolveParams) (interface{}, error)) *graphql.Field {
+
+	fieldArgs := map[string]interface{}{
+		"start_at": nil,
+		"limit":    defaultLimit,
+	}
+	for name, def := range args {
+		fieldArgs[name] = def
+	}
+
+	return &graphql.Field{
+		Type: conn,
+		Args: fieldArgs,
+		Multiplier: func(args map[string]interface{}) int {
+			limit, err := graphql.IntArg(args, "limit")
+			if err != nil || limit <= 0 {
+				return defaultLimit
+			}
+			return limit
+		},
+		Resolve: func(_ context.Context, p graphql.ResolveParams) (interface{}, error) {
+			key, err := graphql.StringArg(p.Args, "start_at")
+			if err != nil {
+				return nil, err
+			}
+			limit, err := graphql.IntArg(p.Args, "limit")
+			if err != nil {
+				return nil, err
+			}
+			if limit <= 0 {
+				return nil, errors.Errorf("limit must be a positive integer, not %d", limit)
+			}
+			paginatorArgs, err := makeArgs(p)
+			if err != nil {
+				return nil, err
+			}
+
+			models, pages, err := paginator(key, limit, paginatorArgs, sc)
+			if err != nil {
+				if err = ignoreNotFound(err); err != nil {
+					return nil, err
+				}
+				return &graphQLConnection{nodes: []model.Model{}}, nil
+			}
+			return &graphQLConnection{nodes: models, pages: pages}, nil
+		},
+	}
+}
+
+// graphQLPatchPaginator dispatches to the project or user patch paginator
+// depending on the type of its arguments.
+func graphQLPatchPaginator(key string, limit int, args interface{}, sc data.Connector) ([]model.Model, *PageResult, error) {
+	switch args.(type) {
+	case patchesByProjectArgs:
+		return patchesByProjectPaginator(key, limit, args, sc)
+	case patchesByUserArgs:
+		return patchesByUserPaginator(key, limit, args, sc)
+	default:
+		panic(incorrectArgsTypeErrorMessage)
+	}
+}
+
+func fixedMultiplier(n int) func(map[string]interface{}) int {
+	return func(map[string]interface{}) int { return n }
+}
+
+func requiredStringArg(args map[string]interface{}, name string) (string, error) {
+	val, err := graphql.StringArg(args, name)
+	if err != nil {
+		return "", err
+	}
+	if val == "" {
+		return "", errors.Errorf("argument '%s' is required", name)
+	}
+	return val, nil
+}
+
+// ignoreNotFound turns not-found API errors into a nil error, since
+// missing resources are represented by null in GraphQL responses.
+func ignoreNotFound(err error) error {
+	switch apiErr := errors.Cause(err).(type) {
+	case *rest.APIError:
+		if apiErr.StatusCode == http.StatusNotFound {
+			return nil
+		}
+	case rest.APIError:
+		if apiErr.StatusCode == http.StatusNotFound {
+			return nil
+		}
+	}
+	return err
+}
@@ -0,0 +1,209 @@
+package route
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	serviceModel "github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/stretchr/testify/suite"
+)
+
+// countingConnector records the number of calls made to the batch
+// lookups used by the GraphQL loaders.
+type countingConnector struct {
+	buildCalls   int
+	versionCalls int
+	taskCalls    int
+
+	*data.MockConnector
+}
+
+func (c *countingConnector) FindBuildsByIds(ids []string) ([]build.Build, error) {
+	c.buildCalls++
+	return c.MockConnector.FindBuildsByIds(ids)
+}
+
+func (c *countingConnector) FindVersionsByIds(ids []string) ([]version.Version, error) {
+	c.versionCalls++
+	return c.MockConnector.FindVersionsByIds(ids)
+}
+
+func (c *countingConnector) FindTasksByIds(ids []string) ([]task.Task, error) {
+	c.taskCalls++
+	return c.MockConnector.FindTasksByIds(ids)
+}
+
+type GraphQLSuite struct {
+	sc *countingConnector
+	suite.Suite
+}
+
+func TestGraphQLSuite(t *testing.T) {
+	suite.Run(t, new(GraphQLSuite))
+}
+
+func (s *GraphQLSuite) SetupTest() {
+	s.sc = &countingConnector{MockConnector: &data.MockConnector{
+		URL: "http://evergreen.example.com",
+		MockVersionConnector: data.MockVersionConnector{
+			CachedVersions: []version.Version{
+				{
+					Id:       "v1",
+					Revision: "abc",
+					BuildVariants: []version.BuildStatus{
+						{BuildVariant: "linux", BuildId: "b1"},
+						{BuildVariant: "windows", BuildId: "b2"},
+					},
+				},
+			},
+		},
+		MockBuildConnector: data.MockBuildConnector{
+			CachedBuilds: []build.Build{
+				{Id: "b1", Version: "v1", Project: "branch", BuildVariant: "linux",
+					Tasks: []build.TaskCache{{Id: "t1"}, {Id: "t2"}}},
+				{Id: "b2", Version: "v1", Project: "branch", BuildVariant: "windows",
+					Tasks: []build.TaskCache{{Id: "t3"}}},
+			},
+			CachedProjects: map[string]*serviceModel.ProjectRef{
+				"branch": {Repo: "project", Identifier: "branch"},
+			},
+		},
+		MockHostConnector: data.MockHostConnector{
+			CachedHosts: []host.Host{
+				{Id: "h1", Status: "running", RunningTask: "t1", RunningTasks: []string{"t3"}},
+			},
+		},
+		MockTaskConnector: data.MockTaskConnector{
+			CachedTasks: []task.Task{
+				{Id: "t1", BuildId: "b1", Version: "v1", Status: "success"},
+				{Id: "t2", BuildId: "b1", Version: "v1", Status: "failed"},
+				{Id: "t3", BuildId: "b2", Version: "v1", Status: "failed"},
+			},
+		},
+	}}
+}
+
+func (s *GraphQLSuite) execute(query string, vars map[string]interface{}) map[string]interface{} {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": vars})
+	s.Require().NoError(err)
+	req, err := http.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	s.Require().NoError(err)
+
+	rm := getGraphQLRouteManager("/graphql", 2)
+	handler := rm.Methods[1].RequestHandler.Handler()
+	s.Require().NoError(handler.ParseAndValidate(context.Background(), req))
+	res, err := handler.Execute(context.Background(), s.sc)
+	s.Require().NoError(err)
+	s.Require().Len(res.Result, 1)
+
+	out, err := json.Marshal(res.Result[0])
+	s.Require().NoError(err)
+	result := map[string]interface{}{}
+	s.Require().NoError(json.Unmarshal(out, &result))
+	return result
+}
+
+func (s *GraphQLSuite) TestVersionBuildsAndTasksAreBatched() {
+	result := s.execute(`query ($id: String!) {
+		version(id: $id) {
+			version_id
+			builds { _id project_id build_variant tasks { task_id status build { _id } } }
+		}
+	}`, map[string]interface{}{"id": "v1"})
+	s.Nil(result["errors"])
+
+	v := result["data"].(map[string]interface{})["version"].(map[string]interface{})
+	s.Equal("v1", v["version_id"])
+	builds := v["builds"].([]interface{})
+	s.Require().Len(builds, 2)
+
+	b1 := builds[0].(map[string]interface{})
+	s.Equal("b1", b1["_id"])
+	s.Equal("project", b1["project_id"])
+	tasks := b1["tasks"].([]interface{})
+	s.Require().Len(tasks, 2)
+	s.Equal("t2", tasks[1].(map[string]interface{})["task_id"])
+	s.Equal("failed", tasks[1].(map[string]interface{})["status"])
+	s.Equal("b1", tasks[1].(map[string]interface{})["build"].(map[string]interface{})["_id"])
+
+	b2 := builds[1].(map[string]interface{})
+	s.Len(b2["tasks"].([]interface{}), 1)
+
+	// one lookup per level of the query, regardless of how many
+	// builds and tasks were returned
+	s.Equal(1, s.sc.versionCalls)
+	s.Equal(1, s.sc.buildCalls)
+	s.Equal(1, s.sc.taskCalls)
+}
+
+func (s *GraphQLSuite) TestTaskConnectionUsesPaginator() {
+	result := s.execute(`{
+		build(id: "b1") {
+			task_connection(start_at: "t1", limit: 1) {
+				nodes { task_id }
+				page_info { next { key limit } prev { key } }
+			}
+		}
+	}`, nil)
+	s.Nil(result["errors"])
+
+	conn := result["data"].(map[string]interface{})["build"].(map[string]interface{})["task_connection"].(map[string]interface{})
+	nodes := conn["nodes"].([]interface{})
+	s.Require().Len(nodes, 1)
+	s.Equal("t1", nodes[0].(map[string]interface{})["task_id"])
+
+	next := conn["page_info"].(map[string]interface{})["next"].(map[string]interface{})
+	s.Equal("t2", next["key"])
+	s.EqualValues(1, next["limit"])
+}
+
+func (s *GraphQLSuite) TestHostRunningTasks() {
+	result := s.execute(`{
+		host(id: "h1") {
+			task { task_id }
+			running_tasks { task_id status }
+		}
+	}`, nil)
+	s.Nil(result["errors"])
+
+	h := result["data"].(map[string]interface{})["host"].(map[string]interface{})
+	s.Equal("t1", h["task"].(map[string]interface{})["task_id"])
+	tasks := h["running_tasks"].([]interface{})
+	s.Require().Len(tasks, 2)
+	s.Equal("t1", tasks[0].(map[string]interface{})["task_id"])
+	s.Equal("t3", tasks[1].(map[string]interface{})["task_id"])
+	s.Equal("failed", tasks[1].(map[string]interface{})["status"])
+}
+
+func (s *GraphQLSuite) TestMissingResourceIsNull() {
+	result := s.execute(`{ version(id: "nope") { version_id } }`, nil)
+	s.Nil(result["errors"])
+	s.Nil(result["data"].(map[string]interface{})["version"])
+}
+
+func (s *GraphQLSuite) TestComplexityLimit() {
+	result := s.execute(`{
+		build(id: "b1") {
+			task_connection(limit: 100) {
+				nodes { tests(limit: 100) { nodes { test_file status } } }
+			}
+		}
+	}`, nil)
+	s.Nil(result["data"])
+	s.Len(result["errors"], 1)
+}
+
+func (s *GraphQLSuite) TestParseRequiresQuery() {
+	req, err := http.NewRequest(http.MethodGet, "/graphql?variables=%7B%7D", nil)
+	s.Require().NoError(err)
+	handler := getGraphQLRouteManager("/graphql", 2).Methods[0].RequestHandler.Handler()
+	s.Error(handler.ParseAndValidate(context.Background(), req))
+}
@@ -123,15 +123,19 @@ func (high *hostIDGetHandler) Execute(ctx context.Context, sc data.Connector) (R
 		return ResponseData{}, err
 	}
 
-	if foundHost.RunningTask != "" {
-		runningTask, err := sc.FindTaskById(foundHost.RunningTask)
+	if taskIds := foundHost.RunningTaskIds(); len(taskIds) > 0 {
+		tasks, err := sc.FindTasksByIds(taskIds)
 		if err != nil {
 			if apiErr, ok := err.(*rest.APIError); !ok || (ok && apiErr.StatusCode != http.StatusNotFound) {
 				return ResponseData{}, errors.Wrap(err, "Database error")
 			}
 		}
 
-		if err = hostModel.BuildFromService(runningTask); err != nil {
+		tasksById := make(map[string]task.Task, len(tasks))
+		for _, t := range tasks {
+			tasksById[t.Id] = t
+		}
+		if err = addHostRunningTasks(hostModel, *foundHost, tasksById); err != nil {
 			return ResponseData{}, errors.Wrap(err, "problem adding task data to host response")
 		}
 	}
@@ -147,10 +151,19 @@ func (hgh *hostGetHandler) Handler() RequestHandler {
 		LimitQueryParam: "limit",
 		Paginator:       hostPaginator,
 		Args:            hostGetArgs{},
+		ListQuery:       hostListQuery,
 	}
 	return &hostGetHandler{hostPaginationExecutor}
 }
 
+// hostListQuery describes the filters and fields supported by the host
+// list routes.
+var hostListQuery = &ListQuerySpec{
+	Model:    &model.APIHost{},
+	Filters:  map[string]string{"status": "status"},
+	KeyField: "host_id",
+}
+
 type hostGetArgs struct {
 	status string
 	user   string
@@ -158,7 +171,7 @@ type hostGetArgs struct {
 
 func (hgh *hostGetHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
 	hgh.Args = hostGetArgs{
-		status: r.URL.Query().Get("status"),
+		status: singleFilterValue(r.URL.Query(), "status"),
 	}
 	return hgh.PaginationExecutor.ParseAndValidate(ctx, r)
 }
@@ -208,9 +221,7 @@ func hostPaginator(key string, limit int, args interface{}, sc data.Connector) (
 	// Grab the taskIds associated as running on the hosts.
 	taskIds := []string{}
 	for _, h := range hosts {
-		if h.RunningTask != "" {
-			taskIds = append(taskIds, h.RunningTask)
-		}
+		taskIds = append(taskIds, h.RunningTaskIds()...)
 	}
 
 	tasks, err := sc.FindTasksByIds(taskIds)
@@ -241,16 +252,9 @@ func makeHostModelsWithTasks(hosts []host.Host, tasks []task.Task) ([]model.Mode
 		if err != nil {
 			return []model.Model{}, err
 		}
-		if h.RunningTask != "" {
-			runningTask, ok := tasksById[h.RunningTask]
-			if !ok {
-				continue
-			}
-			// Add the task information to the host document.
-			err := apiHost.BuildFromService(runningTask)
-			if err != nil {
-				return []model.Model{}, err
-			}
+		// Add the task information to the host document.
+		if err = addHostRunningTasks(&apiHost, h, tasksById); err != nil {
+			return []model.Model{}, err
 		}
 		// Put the model into the array
 		models[ix] = &apiHost
@@ -259,6 +263,21 @@ func makeHostModelsWithTasks(hosts []host.Host, tasks []task.Task) ([]model.Mode
 
 }
 
+// addHostRunningTasks adds the tasks that the host is running to its model,
+// in the order the host has them.
+func addHostRunningTasks(apiHost *model.APIHost, h host.Host, tasksById map[string]task.Task) error {
+	for _, id := range h.RunningTaskIds() {
+		runningTask, ok := tasksById[id]
+		if !ok {
+			continue
+		}
+		if err := apiHost.BuildFromService(runningTask); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func makeNextHostsPage(hosts []host.Host, limit int) *Page {
 	var nextPage *Page
 	if len(hosts) > limit {
@@ -291,12 +310,13 @@ func (h *hostsByUserHandler) Handler() RequestHandler {
 		LimitQueryParam: "limit",
 		Paginator:       hostPaginator,
 		Args:            hostGetArgs{},
+		ListQuery:       hostListQuery,
 	}}
 }
 
 func (h *hostsByUserHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
 	h.Args = hostGetArgs{
-		status: r.URL.Query().Get("status"),
+		status: singleFilterValue(r.URL.Query(), "status"),
 		user:   mux.Vars(r)["user_id"],
 	}
 	return h.PaginationExecutor.ParseAndValidate(ctx, r)
@@ -399,6 +419,122 @@ func (h *hostTerminateHandler) Execute(ctx context.Context, sc data.Connector) (
 	return ResponseData{}, nil
 }
 
+func getHostStopRouteManager(route string, version int) *RouteManager {
+	return &RouteManager{
+		Route:   route,
+		Version: version,
+		Methods: []MethodHandler{
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				MethodType:        http.MethodPost,
+				Authenticator:     &RequireUserAuthenticator{},
+				RequestHandler:    &hostStopHandler{},
+			},
+		},
+	}
+}
+
+type hostStopHandler struct {
+	hostID string
+}
+
+func (h *hostStopHandler) Handler() RequestHandler {
+	return &hostStopHandler{}
+}
+
+func (h *hostStopHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	var err error
+	h.hostID, err = validateHostID(mux.Vars(r)["host_id"])
+
+	return err
+}
+
+func (h *hostStopHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	u := MustHaveUser(ctx)
+
+	host, err := sc.FindHostByIdWithOwner(h.hostID, u)
+	if err != nil {
+		return ResponseData{}, err
+	}
+
+	if host.Status == evergreen.HostStopped || host.Status == evergreen.HostStopping {
+		return ResponseData{}, &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("Host %s is already stopped", host.Id),
+		}
+	}
+	if host.Status != evergreen.HostRunning {
+		return ResponseData{}, &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("Host %s is %s, only running hosts can be stopped", host.Id, host.Status),
+		}
+	}
+
+	if err := sc.StopHost(host); err != nil {
+		return ResponseData{}, &rest.APIError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}
+	}
+
+	return ResponseData{}, nil
+}
+
+func getHostStartRouteManager(route string, version int) *RouteManager {
+	return &RouteManager{
+		Route:   route,
+		Version: version,
+		Methods: []MethodHandler{
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				MethodType:        http.MethodPost,
+				Authenticator:     &RequireUserAuthenticator{},
+				RequestHandler:    &hostStartHandler{},
+			},
+		},
+	}
+}
+
+type hostStartHandler struct {
+	hostID string
+}
+
+func (h *hostStartHandler) Handler() RequestHandler {
+	return &hostStartHandler{}
+}
+
+func (h *hostStartHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	var err error
+	h.hostID, err = validateHostID(mux.Vars(r)["host_id"])
+
+	return err
+}
+
+func (h *hostStartHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	u := MustHaveUser(ctx)
+
+	host, err := sc.FindHostByIdWithOwner(h.hostID, u)
+	if err != nil {
+		return ResponseData{}, err
+	}
+
+	if host.Status != evergreen.HostStopped {
+		return ResponseData{}, &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("Host %s is %s, only stopped hosts can be started", host.Id, host.Status),
+		}
+	}
+
+	if err := sc.StartHost(host); err != nil {
+		return ResponseData{}, &rest.APIError{
+			StatusCode: http.StatusInternalServerError,
+			Message:    err.Error(),
+		}
+	}
+
+	return ResponseData{}, nil
+}
+
 func getHostChangeRDPPasswordRouteManager(route string, version int) *RouteManager {
 	return &RouteManager{
 		Route:   route,
@@ -176,6 +176,74 @@ func (s *hostTerminateHostHandlerSuite) TestRegularUserCannotTerminateAnyHost()
 	s.Equal(evergreen.HostRunning, s.sc.CachedHosts[1].Status)
 }
 
+type hostStopStartHandlerSuite struct {
+	sc *data.MockConnector
+
+	suite.Suite
+}
+
+func TestHostStopStartHandlers(t *testing.T) {
+	suite.Run(t, &hostStopStartHandlerSuite{})
+}
+
+func (s *hostStopStartHandlerSuite) SetupTest() {
+	s.sc = getMockHostsConnector()
+}
+
+func (s *hostStopStartHandlerSuite) execute(rm *RouteManager, hostID, userID string) error {
+	ctx := context.WithValue(context.Background(), evergreen.RequestUser, s.sc.MockUserConnector.CachedUsers[userID])
+	var data ResponseData
+	var err error
+	switch h := rm.Methods[0].Handler().(type) {
+	case *hostStopHandler:
+		h.hostID = hostID
+		data, err = h.Execute(ctx, s.sc)
+	case *hostStartHandler:
+		h.hostID = hostID
+		data, err = h.Execute(ctx, s.sc)
+	}
+	s.Empty(data.Result)
+	return err
+}
+
+func (s *hostStopStartHandlerSuite) TestStopAndStartRunningHost() {
+	s.NoError(s.execute(getHostStopRouteManager("", 2), "host2", "user0"))
+	s.Equal(evergreen.HostStopping, s.sc.CachedHosts[1].Status)
+
+	// the host can not be started until it has finished stopping
+	err := s.execute(getHostStartRouteManager("", 2), "host2", "user0")
+	s.IsType(new(rest.APIError), err)
+	s.Equal(http.StatusBadRequest, err.(*rest.APIError).StatusCode)
+
+	s.sc.CachedHosts[1].Status = evergreen.HostStopped
+	s.NoError(s.execute(getHostStartRouteManager("", 2), "host2", "user0"))
+	s.Equal(evergreen.HostRunning, s.sc.CachedHosts[1].Status)
+}
+
+func (s *hostStopStartHandlerSuite) TestStopHostThatIsNotRunning() {
+	for _, id := range []string{"host1", "host3"} {
+		err := s.execute(getHostStopRouteManager("", 2), id, "user0")
+		s.IsType(new(rest.APIError), err)
+		s.Equal(http.StatusBadRequest, err.(*rest.APIError).StatusCode)
+	}
+	s.Equal(evergreen.HostTerminated, s.sc.CachedHosts[0].Status)
+	s.Equal(evergreen.HostUninitialized, s.sc.CachedHosts[2].Status)
+}
+
+func (s *hostStopStartHandlerSuite) TestStartRunningHost() {
+	err := s.execute(getHostStartRouteManager("", 2), "host2", "user0")
+	s.IsType(new(rest.APIError), err)
+	s.Equal(http.StatusBadRequest, err.(*rest.APIError).StatusCode)
+}
+
+func (s *hostStopStartHandlerSuite) TestOnlyOwnerOrSuperUserCanStopHost() {
+	s.Error(s.execute(getHostStopRouteManager("", 2), "host2", "user1"))
+	s.Equal(evergreen.HostRunning, s.sc.CachedHosts[1].Status)
+
+	s.NoError(s.execute(getHostStopRouteManager("", 2), "host2", "root"))
+	s.Equal(evergreen.HostStopping, s.sc.CachedHosts[1].Status)
+}
+
 type hostChangeRDPPasswordHandlerSuite struct {
 	rm *RouteManager
 	sc *data.MockConnector
@@ -0,0 +1,365 @@
+package route
+
+import (
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen/rest"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	cursorQueryParam = "cursor"
+	fieldsQueryParam = "fields"
+	sinceQueryParam  = "since"
+	untilQueryParam  = "until"
+
+	// maxFilterPages bounds the number of pages read from the service
+	// layer while filling a single filtered page. When it is reached the
+	// page is returned short, with a cursor to resume from.
+	maxFilterPages = 10
+
+	listTimeFormat = "2006-01-02T15:04:05.000Z"
+)
+
+// listFilterParams are the filter expressions shared by list routes. Each
+// accepts a comma separated list of values, any of which may match.
+var listFilterParams = []string{"status", "variant", "requester"}
+
+// ListQuerySpec describes the shared query parameters that a list route
+// supports. Filters are evaluated against the JSON form of the route's
+// models, so a spec only has to name the fields that each filter uses.
+type ListQuerySpec struct {
+	// Model is an instance of the type the route returns. It is used to
+	// validate the fields requested with the 'fields' parameter.
+	Model model.Model
+
+	// Filters maps each supported filter parameter (status, variant,
+	// requester) to the JSON field it is matched against. Filters that
+	// are not in the map are rejected.
+	Filters map[string]string
+
+	// TimeField is the JSON field that the 'since' and 'until' parameters
+	// are compared against. Time ranges are rejected when it is empty.
+	TimeField string
+
+	// KeyField is the JSON field holding the key that the route's
+	// paginator starts from.
+	KeyField string
+}
+
+// listQuery holds the filters, field selection and cursor fingerprint
+// parsed from a request to a list route.
+type listQuery struct {
+	spec    *ListQuerySpec
+	filters map[string][]string
+	since   time.Time
+	until   time.Time
+	fields  []string
+
+	// fingerprint identifies the filters that a cursor was issued
+	// for, so that a cursor cannot be replayed against a different
+	// result set.
+	fingerprint string
+
+	// params holds the query parameters that are carried over into the
+	// links to other pages.
+	params url.Values
+}
+
+type listCursor struct {
+	Key    string `json:"key"`
+	Filter string `json:"filter,omitempty"`
+}
+
+func listQueryError(format string, args ...interface{}) error {
+	return rest.APIError{
+		StatusCode: http.StatusBadRequest,
+		Message:    fmt.Sprintf(format, args...),
+	}
+}
+
+// parseListQuery reads the filter and field selection parameters from the
+// request and validates them against the spec.
+func parseListQuery(vals url.Values, spec *ListQuerySpec) (*listQuery, error) {
+	q := &listQuery{
+		spec:    spec,
+		filters: map[string][]string{},
+		params:  url.Values{},
+	}
+	canonical := []string{}
+
+	for _, param := range listFilterParams {
+		values := splitListParam(vals.Get(param))
+		if len(values) == 0 {
+			continue
+		}
+		field, ok := spec.Filters[param]
+		if !ok {
+			return nil, listQueryError("filtering by '%s' is not supported for this route", param)
+		}
+		q.filters[field] = values
+		q.params.Set(param, strings.Join(values, ","))
+
+		sorted := append([]string{}, values...)
+		sort.Strings(sorted)
+		canonical = append(canonical, param+"="+strings.Join(sorted, ","))
+	}
+
+	for _, param := range []string{sinceQueryParam, untilQueryParam} {
+		value := vals.Get(param)
+		if value == "" {
+			continue
+		}
+		if spec.TimeField == "" {
+			return nil, listQueryError("filtering by time is not supported for this route")
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, listQueryError("value '%s' provided for '%s' must be an RFC 3339 time", value, param)
+		}
+		if param == sinceQueryParam {
+			q.since = t
+		} else {
+			q.until = t
+		}
+		q.params.Set(param, value)
+		canonical = append(canonical, param+"="+t.UTC().Format(time.RFC3339Nano))
+	}
+	if !q.since.IsZero() && !q.until.IsZero() && q.until.Before(q.since) {
+		return nil, listQueryError("'%s' must not be before '%s'", untilQueryParam, sinceQueryParam)
+	}
+
+	if fields := splitListParam(vals.Get(fieldsQueryParam)); len(fields) > 0 {
+		modelType := reflect.TypeOf(spec.Model)
+		for _, f := range fields {
+			if !hasJSONPath(modelType, strings.Split(f, ".")) {
+				return nil, listQueryError("'%s' is not a field of this resource", f)
+			}
+		}
+		q.fields = fields
+		q.params.Set(fieldsQueryParam, strings.Join(fields, ","))
+	}
+
+	if len(canonical) > 0 {
+		sum := sha1.Sum([]byte(strings.Join(canonical, "&")))
+		q.fingerprint = fmt.Sprintf("%x", sum[:6])
+	}
+
+	return q, nil
+}
+
+// filtered returns true if the query excludes any results.
+func (q *listQuery) filtered() bool {
+	return q.fingerprint != ""
+}
+
+// encodeCursor returns an opaque cursor for the given paginator key.
+func (q *listQuery) encodeCursor(key string) string {
+	out, _ := json.Marshal(listCursor{Key: key, Filter: q.fingerprint})
+	return base64.RawURLEncoding.EncodeToString(out)
+}
+
+// decodeCursor returns the paginator key held by the cursor, checking
+// that it was issued for the same filters as the current request.
+func (q *listQuery) decodeCursor(cursor string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", listQueryError("invalid cursor '%s'", cursor)
+	}
+	c := listCursor{}
+	if err = json.Unmarshal(raw, &c); err != nil {
+		return "", listQueryError("invalid cursor '%s'", cursor)
+	}
+	if c.Filter != q.fingerprint {
+		return "", listQueryError("cursor was issued for a different set of filters")
+	}
+	return c.Key, nil
+}
+
+// matches returns true if the JSON document of a model satisfies all of
+// the query's filters.
+func (q *listQuery) matches(doc map[string]interface{}) bool {
+	for field, values := range q.filters {
+		value, ok := lookupJSONPath(doc, field).(string)
+		if !ok || !util.StringSliceContains(values, value) {
+			return false
+		}
+	}
+
+	if q.since.IsZero() && q.until.IsZero() {
+		return true
+	}
+	value, ok := lookupJSONPath(doc, q.spec.TimeField).(string)
+	if !ok {
+		return false
+	}
+	t, err := time.Parse(listTimeFormat, value)
+	if err != nil {
+		return false
+	}
+	if !q.since.IsZero() && t.Before(q.since) {
+		return false
+	}
+	if !q.until.IsZero() && t.After(q.until) {
+		return false
+	}
+	return true
+}
+
+// key returns the paginator key of a model's JSON document.
+func (q *listQuery) key(doc map[string]interface{}) string {
+	if value := lookupJSONPath(doc, q.spec.KeyField); value != nil {
+		return fmt.Sprint(value)
+	}
+	return ""
+}
+
+// project returns the model restricted to the selected fields, or the
+// model itself if no fields were selected.
+func (q *listQuery) project(m model.Model, doc map[string]interface{}) model.Model {
+	if len(q.fields) == 0 {
+		return m
+	}
+	out := sparseModel{}
+	for _, f := range q.fields {
+		value := lookupJSONPath(doc, f)
+		parts := strings.Split(f, ".")
+		cur := map[string]interface{}(out)
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := cur[part].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				cur[part] = next
+			}
+			cur = next
+		}
+		cur[parts[len(parts)-1]] = value
+	}
+	return out
+}
+
+// apply filters and projects a complete list of models. It is used by
+// routes whose results are not paginated.
+func (q *listQuery) apply(models []model.Model) ([]model.Model, error) {
+	out := []model.Model{}
+	for _, m := range models {
+		doc, err := modelDocument(m)
+		if err != nil {
+			return nil, err
+		}
+		if q.matches(doc) {
+			out = append(out, q.project(m, doc))
+		}
+	}
+	return out, nil
+}
+
+// selectFields returns a response holding the single model, restricted to
+// the fields selected by the query, if any.
+func selectFields(q *listQuery, m model.Model) (ResponseData, error) {
+	if q == nil {
+		return ResponseData{Result: []model.Model{m}}, nil
+	}
+	models, err := q.apply([]model.Model{m})
+	if err != nil {
+		return ResponseData{}, err
+	}
+	return ResponseData{Result: models}, nil
+}
+
+// sparseModel is a model restricted to the fields selected with the
+// 'fields' query parameter.
+type sparseModel map[string]interface{}
+
+func (m sparseModel) BuildFromService(h interface{}) error {
+	return errors.New("sparse models are built by field selection")
+}
+
+func (m sparseModel) ToService() (interface{}, error) {
+	return nil, errors.New("not implemented for read-only route")
+}
+
+// modelDocument returns the JSON form of a model as a generic document.
+func modelDocument(m model.Model) (map[string]interface{}, error) {
+	out, err := json.Marshal(m)
+	if err != nil {
+		return nil, errors.Wrap(err, "API model error")
+	}
+	doc := map[string]interface{}{}
+	if err = json.Unmarshal(out, &doc); err != nil {
+		return nil, errors.Wrap(err, "API model error")
+	}
+	return doc, nil
+}
+
+func lookupJSONPath(doc map[string]interface{}, path string) interface{} {
+	var cur interface{} = doc
+	for _, part := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		cur = m[part]
+	}
+	return cur
+}
+
+// hasJSONPath returns true if the dotted path names a field, by its JSON
+// tag, of the given type.
+func hasJSONPath(t reflect.Type, path []string) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if len(path) == 0 {
+		return true
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if name == path[0] {
+			return hasJSONPath(f.Type, path[1:])
+		}
+	}
+	return false
+}
+
+func splitListParam(value string) []string {
+	out := []string{}
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// singleFilterValue returns the value of a filter parameter when it names
+// exactly one value, so that it can be passed to the service layer. Lists
+// of values are matched by the list query instead.
+func singleFilterValue(vals url.Values, param string) string {
+	values := splitListParam(vals.Get(param))
+	if len(values) != 1 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,218 @@
+package route
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/rest"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/suite"
+)
+
+type ListQuerySuite struct {
+	tasks []model.Model
+	calls int
+
+	suite.Suite
+}
+
+func TestListQuerySuite(t *testing.T) {
+	suite.Run(t, new(ListQuerySuite))
+}
+
+func (s *ListQuerySuite) SetupTest() {
+	s.calls = 0
+	s.tasks = []model.Model{}
+	for i := 0; i < 10; i++ {
+		status := evergreen.TaskSucceeded
+		if i%3 == 0 {
+			status = evergreen.TaskFailed
+		}
+		s.tasks = append(s.tasks, &model.APITask{
+			Id:           model.APIString(fmt.Sprintf("t%d", i)),
+			Status:       model.APIString(status),
+			BuildVariant: "linux",
+			Requester:    model.APIString(evergreen.RepotrackerVersionRequester),
+		})
+	}
+}
+
+// paginator pages through the suite's tasks by index, in the same way as
+// the service layer paginators.
+func (s *ListQuerySuite) paginator(key string, limit int, _ interface{}, _ data.Connector) ([]model.Model, *PageResult, error) {
+	s.calls++
+	start := 0
+	for i, t := range s.tasks {
+		if string(t.(*model.APITask).Id) == key {
+			start = i
+		}
+	}
+	end := start + limit
+	pages := &PageResult{}
+	if end < len(s.tasks) {
+		pages.Next = &Page{Relation: "next", Key: string(s.tasks[end].(*model.APITask).Id), Limit: limit}
+	} else {
+		end = len(s.tasks)
+	}
+	if start > 0 {
+		pages.Prev = &Page{Relation: "prev", Key: "t0", Limit: limit}
+	}
+	return s.tasks[start:end], pages, nil
+}
+
+func (s *ListQuerySuite) executor() *PaginationExecutor {
+	return &PaginationExecutor{
+		KeyQueryParam:   "start_at",
+		LimitQueryParam: "limit",
+		Paginator:       s.paginator,
+		ListQuery: &ListQuerySpec{
+			Model:     &model.APITask{},
+			Filters:   map[string]string{"status": "status", "variant": "build_variant"},
+			TimeField: "create_time",
+			KeyField:  "task_id",
+		},
+	}
+}
+
+func (s *ListQuerySuite) execute(query string) (*PaginationExecutor, ResponseData, error) {
+	pe := s.executor()
+	r := httptest.NewRequest(http.MethodGet, "/tasks?"+query, nil)
+	if err := pe.ParseAndValidate(context.Background(), r); err != nil {
+		return pe, ResponseData{}, err
+	}
+	res, err := pe.Execute(context.Background(), &data.MockConnector{})
+	return pe, res, err
+}
+
+func (s *ListQuerySuite) ids(models []model.Model) []string {
+	ids := []string{}
+	for _, m := range models {
+		ids = append(ids, string(m.(*model.APITask).Id))
+	}
+	return ids
+}
+
+func (s *ListQuerySuite) nextQuery(res ResponseData) url.Values {
+	w := httptest.NewRecorder()
+	s.Require().NoError(res.Metadata.(*PaginationMetadata).MakeHeader(w, "http://evergreen.example.com/rest/v2", "/rest/v2/tasks"))
+	link := w.Header().Get(evergreen.RoutePaginatorNextPageHeaderKey)
+	matches := linkMatcher.FindStringSubmatch(link)
+	s.Require().Len(matches, 3, link)
+	s.Equal("next", matches[2])
+	u, err := url.Parse(matches[1])
+	s.Require().NoError(err)
+	s.Equal("/rest/v2/tasks", u.Path)
+	return u.Query()
+}
+
+func (s *ListQuerySuite) TestUnfilteredPagesUseCursors() {
+	_, res, err := s.execute("limit=4")
+	s.Require().NoError(err)
+	s.Equal([]string{"t0", "t1", "t2", "t3"}, s.ids(res.Result))
+	s.Equal(1, s.calls)
+
+	next := s.nextQuery(res)
+	s.Empty(next.Get("start_at"))
+	s.Equal("4", next.Get("limit"))
+
+	_, res, err = s.execute(next.Encode())
+	s.Require().NoError(err)
+	s.Equal([]string{"t4", "t5", "t6", "t7"}, s.ids(res.Result))
+	s.NotNil(res.Metadata.(*PaginationMetadata).Pages.Prev)
+}
+
+func (s *ListQuerySuite) TestLegacyKeyIsAccepted() {
+	_, res, err := s.execute("start_at=t8")
+	s.Require().NoError(err)
+	s.Equal([]string{"t8", "t9"}, s.ids(res.Result))
+	s.Nil(res.Metadata.(*PaginationMetadata).Pages.Next)
+}
+
+func (s *ListQuerySuite) TestFiltersFillPagesAcrossServicePages() {
+	_, res, err := s.execute("status=failed&limit=2")
+	s.Require().NoError(err)
+	s.Equal([]string{"t0", "t3"}, s.ids(res.Result))
+	s.Nil(res.Metadata.(*PaginationMetadata).Pages.Prev)
+
+	next := s.nextQuery(res)
+	s.Equal("failed", next.Get("status"))
+
+	_, res, err = s.execute(next.Encode())
+	s.Require().NoError(err)
+	s.Equal([]string{"t6", "t9"}, s.ids(res.Result))
+	s.Nil(res.Metadata.(*PaginationMetadata).Pages.Next)
+}
+
+func (s *ListQuerySuite) TestMultipleFilterValues() {
+	_, res, err := s.execute("status=failed,success&variant=linux")
+	s.Require().NoError(err)
+	s.Len(res.Result, 10)
+
+	_, res, err = s.execute("variant=windows")
+	s.Require().NoError(err)
+	s.Len(res.Result, 0)
+}
+
+func (s *ListQuerySuite) TestCursorIsBoundToFilters() {
+	_, res, err := s.execute("status=failed&limit=1")
+	s.Require().NoError(err)
+	cursor := s.nextQuery(res).Get(cursorQueryParam)
+
+	_, _, err = s.execute("status=failed,success&limit=1&cursor=" + cursor)
+	s.Require().Error(err)
+	s.Equal(http.StatusBadRequest, err.(rest.APIError).StatusCode)
+
+	_, _, err = s.execute("cursor=not-a-cursor")
+	s.Error(err)
+}
+
+func (s *ListQuerySuite) TestFieldSelection() {
+	_, res, err := s.execute("limit=1&fields=task_id,status_details.status")
+	s.Require().NoError(err)
+	s.Require().Len(res.Result, 1)
+
+	out, err := json.Marshal(res.Result[0])
+	s.Require().NoError(err)
+	s.JSONEq(`{"task_id": "t0", "status_details": {"status": null}}`, string(out))
+	s.Equal("task_id,status_details.status", s.nextQuery(res).Get(fieldsQueryParam))
+}
+
+func (s *ListQuerySuite) TestInvalidQueries() {
+	for name, query := range map[string]string{
+		"UnsupportedFilter": "requester=gitter_request",
+		"UnknownField":      "fields=task_id,nope",
+		"UnknownNested":     "fields=status_details.nope",
+		"BadTime":           "since=yesterday",
+		"InvertedRange":     "since=2017-01-02T00:00:00Z&until=2017-01-01T00:00:00Z",
+	} {
+		_, _, err := s.execute(query)
+		s.Require().Error(err, name)
+		s.Equal(http.StatusBadRequest, err.(rest.APIError).StatusCode, name)
+	}
+	s.Equal(0, s.calls)
+}
+
+func (s *ListQuerySuite) TestTimeRange() {
+	q, err := parseListQuery(url.Values{
+		"since": []string{"2017-01-01T00:00:00Z"},
+		"until": []string{"2017-01-31T00:00:00Z"},
+	}, s.executor().ListQuery)
+	s.Require().NoError(err)
+
+	s.True(q.matches(map[string]interface{}{"create_time": "2017-01-15T10:00:00.000Z"}))
+	s.False(q.matches(map[string]interface{}{"create_time": "2017-02-15T10:00:00.000Z"}))
+	s.False(q.matches(map[string]interface{}{"create_time": nil}))
+}
+
+func (s *ListQuerySuite) TestSingleFilterValue() {
+	s.Equal("failed", singleFilterValue(url.Values{"status": []string{"failed"}}, "status"))
+	s.Equal("", singleFilterValue(url.Values{"status": []string{"failed,success"}}, "status"))
+	s.Equal("", singleFilterValue(url.Values{}, "status"))
+}
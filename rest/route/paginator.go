@@ -44,8 +44,13 @@ type PaginationExecutor struct {
 	// function.
 	Args interface{}
 
+	// ListQuery, if set, enables the shared filter, field selection and
+	// cursor parameters for the route.
+	ListQuery *ListQuerySpec
+
 	limit int
 	key   string
+	query *listQuery
 }
 
 // PaginationMetadata is a struct that contains all of the information for
@@ -55,6 +60,10 @@ type PaginationMetadata struct {
 
 	KeyQueryParam   string
 	LimitQueryParam string
+
+	// Query holds additional parameters, such as filters, that are
+	// carried over into the links to other pages.
+	Query url.Values
 }
 
 // Page contains the information about a single page of the resource.
@@ -78,6 +87,10 @@ type PaginatorFunc func(string, int, interface{}, data.Connector) ([]model.Model
 // Execute serves as an implementation of the RequestHandler's 'Execute' method.
 // It calls the embedded PaginationFunc and then processes and returns the results.
 func (pe *PaginationExecutor) Execute(_ context.Context, sc data.Connector) (ResponseData, error) {
+	if pe.query != nil {
+		return pe.executeListQuery(sc)
+	}
+
 	models, pages, err := pe.Paginator(pe.key, pe.limit, pe.Args, sc)
 	if err != nil {
 		return ResponseData{}, err
@@ -96,14 +109,94 @@ func (pe *PaginationExecutor) Execute(_ context.Context, sc data.Connector) (Res
 	return rd, nil
 }
 
+// executeListQuery pages through the results of the paginator, keeping
+// only those that match the request's filters, until it has filled a
+// page. The keys of the resulting pages are returned as opaque cursors.
+// Filtered results only link to the next page, since the paginators'
+// previous pages are not aware of the filters.
+func (pe *PaginationExecutor) executeListQuery(sc data.Connector) (ResponseData, error) {
+	q := pe.query
+	results := []model.Model{}
+	pages := &PageResult{}
+	key := pe.key
+
+	for i := 0; i < maxFilterPages; i++ {
+		models, found, err := pe.Paginator(key, pe.limit, pe.Args, sc)
+		if err != nil {
+			return ResponseData{}, err
+		}
+		if i == 0 && found != nil && !q.filtered() {
+			pages.Prev = found.Prev
+		}
+
+		for _, m := range models {
+			doc, err := modelDocument(m)
+			if err != nil {
+				return ResponseData{}, err
+			}
+			if !q.matches(doc) {
+				continue
+			}
+			if len(results) == pe.limit {
+				pages.Next = &Page{Relation: "next", Key: q.key(doc)}
+				break
+			}
+			results = append(results, q.project(m, doc))
+		}
+
+		if pages.Next != nil || found == nil || found.Next == nil {
+			break
+		}
+		key = found.Next.Key
+		if !q.filtered() || i == maxFilterPages-1 {
+			pages.Next = &Page{Relation: "next", Key: key}
+		}
+		if !q.filtered() {
+			break
+		}
+	}
+
+	for _, p := range []*Page{pages.Next, pages.Prev} {
+		if p != nil {
+			p.Key = q.encodeCursor(p.Key)
+			p.Limit = pe.limit
+		}
+	}
+
+	return ResponseData{
+		Result: results,
+		Metadata: &PaginationMetadata{
+			Pages:           pages,
+			KeyQueryParam:   cursorQueryParam,
+			LimitQueryParam: pe.LimitQueryParam,
+			Query:           q.params,
+		},
+	}, nil
+}
+
 // ParseAndValidate gets the key and limit from the request
-// and sets them on the PaginationExecutor.
+// and sets them on the PaginationExecutor. Routes with a ListQuery also
+// accept an opaque cursor in place of the key.
 func (pe *PaginationExecutor) ParseAndValidate(_ context.Context, r *http.Request) error {
 	vals := r.URL.Query()
 	if k, ok := vals[pe.KeyQueryParam]; ok && len(k) > 0 {
 		pe.key = k[0]
 	}
 
+	if pe.ListQuery != nil {
+		var err error
+		pe.query, err = parseListQuery(vals, pe.ListQuery)
+		if err != nil {
+			return err
+		}
+		if cursor := vals.Get(cursorQueryParam); cursor != "" {
+			pe.key, err = pe.query.decodeCursor(cursor)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	pe.limit = defaultLimit
 	limit := ""
 	if l, ok := vals[pe.LimitQueryParam]; ok && len(l) > 0 {
@@ -207,6 +300,7 @@ func (pm *PaginationMetadata) MakeHeader(w http.ResponseWriter, apiURL, route st
 		return err
 	}
 	baseURL.Path = path.Clean(fmt.Sprintf("/%s", route))
+	baseURL.RawQuery = pm.Query.Encode()
 
 	b := bytes.Buffer{}
 	if pm.Pages.Next != nil {
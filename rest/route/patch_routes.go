@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/rest"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/evergreen-ci/evergreen/rest/model"
@@ -525,3 +526,305 @@ func (p *patchRestartHandler) Execute(ctx context.Context, sc data.Connector) (R
 		Result: []model.Model{patchModel},
 	}, nil
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// Handler for creating patches
+//
+//    /patches
+
+func getPatchCreateManager(route string, version int) *RouteManager {
+	p := &patchCreateHandler{}
+	return &RouteManager{
+		Route:   route,
+		Version: version,
+		Methods: []MethodHandler{
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				MethodType:        http.MethodPost,
+				Authenticator:     &RequireUserAuthenticator{},
+				RequestHandler:    p.Handler(),
+			},
+		},
+	}
+}
+
+type patchCreateHandler struct {
+	request model.PatchCreateRequest
+}
+
+func (p *patchCreateHandler) Handler() RequestHandler {
+	return &patchCreateHandler{}
+}
+
+func (p *patchCreateHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	body := util.NewRequestReader(r)
+	defer body.Close()
+
+	if err := util.ReadJSONInto(body, &p.request); err != nil {
+		return errors.Wrap(err, "Argument read error")
+	}
+
+	size := len(p.request.Patch)
+	for _, m := range p.request.Modules {
+		if m.Module == "" {
+			return rest.APIError{
+				Message:    "modules must be named",
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+		size += len(m.Patch)
+	}
+	if size > patch.SizeLimit {
+		return rest.APIError{
+			Message:    "patch is too large",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+	return nil
+}
+
+// Execute creates the patch from the base diff and then adds any module
+// changes. A patch with module changes is only finalized once all of the
+// modules have been added.
+func (p *patchCreateHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	req := p.request
+	finalize := req.Finalize && len(req.Modules) == 0
+
+	intent, err := patch.NewCliIntent(MustHaveUser(ctx).Id, req.Project, req.Githash, "",
+		req.Patch, req.Description, finalize, req.Variants, req.Tasks, req.Alias)
+	if err != nil {
+		return ResponseData{}, rest.APIError{
+			Message:    err.Error(),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	newPatch, err := sc.CreatePatch(intent)
+	if err != nil {
+		if _, ok := err.(*rest.APIError); !ok {
+			err = errors.Wrap(err, "Database error")
+		}
+		return ResponseData{}, err
+	}
+	patchId := newPatch.Id.Hex()
+
+	for _, m := range req.Modules {
+		if err = sc.SetPatchModule(patchId, m.Module, m.Githash, m.Patch); err != nil {
+			if _, ok := err.(*rest.APIError); !ok {
+				err = errors.Wrapf(err, "problem adding module '%s' to patch %s", m.Module, patchId)
+			}
+			return ResponseData{}, err
+		}
+	}
+	if req.Finalize && !finalize {
+		newPatch, err = sc.ConfigurePatch(patchId, &data.PatchConfiguration{Finalize: true})
+		if err != nil {
+			if _, ok := err.(*rest.APIError); !ok {
+				err = errors.Wrapf(err, "problem finalizing patch %s", patchId)
+			}
+			return ResponseData{}, err
+		}
+	} else if len(req.Modules) > 0 {
+		if newPatch, err = sc.FindPatchById(patchId); err != nil {
+			return ResponseData{}, errors.Wrap(err, "Database error")
+		}
+	}
+
+	return patchResponse(newPatch)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// Handler for setting the variants and tasks of a patch and scheduling it
+//
+//    /patches/{patch_id}/configure
+
+func getPatchConfigureManager(route string, version int) *RouteManager {
+	p := &patchConfigureHandler{}
+	return &RouteManager{
+		Route:   route,
+		Version: version,
+		Methods: []MethodHandler{
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				MethodType:        http.MethodPost,
+				Authenticator:     &RequireUserAuthenticator{},
+				RequestHandler:    p.Handler(),
+			},
+		},
+	}
+}
+
+type patchConfigureHandler struct {
+	request model.PatchConfigureRequest
+	patchId string
+}
+
+func (p *patchConfigureHandler) Handler() RequestHandler {
+	return &patchConfigureHandler{}
+}
+
+func (p *patchConfigureHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	p.patchId = mux.Vars(r)["patch_id"]
+	if !patch.IsValidId(p.patchId) {
+		return rest.APIError{
+			Message:    fmt.Sprintf("patch id '%s' is not valid", p.patchId),
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+
+	body := util.NewRequestReader(r)
+	defer body.Close()
+	if err := util.ReadJSONInto(body, &p.request); err != nil {
+		return errors.Wrap(err, "Argument read error")
+	}
+
+	if len(p.request.Variants) > 0 && len(p.request.Tasks) == 0 ||
+		len(p.request.Tasks) > 0 && len(p.request.Variants) == 0 {
+		return rest.APIError{
+			Message:    "variants and tasks must be set together",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+	return nil
+}
+
+func (p *patchConfigureHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	foundPatch, err := sc.ConfigurePatch(p.patchId, &data.PatchConfiguration{
+		Description: p.request.Description,
+		Variants:    p.request.Variants,
+		Tasks:       p.request.Tasks,
+		Alias:       p.request.Alias,
+		Finalize:    p.request.Finalize,
+	})
+	if err != nil {
+		if _, ok := err.(*rest.APIError); !ok {
+			err = errors.Wrap(err, "Database error")
+		}
+		return ResponseData{}, err
+	}
+
+	return patchResponse(foundPatch)
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// Handler for changing the modules of a patch
+//
+//    /patches/{patch_id}/modules/{module}
+
+func getPatchModuleManager(route string, version int) *RouteManager {
+	return &RouteManager{
+		Route:   route,
+		Version: version,
+		Methods: []MethodHandler{
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				MethodType:        http.MethodPut,
+				Authenticator:     &RequireUserAuthenticator{},
+				RequestHandler:    &patchModuleSetHandler{},
+			},
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				MethodType:        http.MethodDelete,
+				Authenticator:     &RequireUserAuthenticator{},
+				RequestHandler:    &patchModuleRemoveHandler{},
+			},
+		},
+	}
+}
+
+type patchModuleSetHandler struct {
+	request model.PatchModuleRequest
+	patchId string
+}
+
+func (p *patchModuleSetHandler) Handler() RequestHandler {
+	return &patchModuleSetHandler{}
+}
+
+func (p *patchModuleSetHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	vars := mux.Vars(r)
+	p.patchId = vars["patch_id"]
+
+	body := util.NewRequestReader(r)
+	defer body.Close()
+	if err := util.ReadJSONInto(body, &p.request); err != nil {
+		return errors.Wrap(err, "Argument read error")
+	}
+	p.request.Module = vars["module"]
+
+	if p.request.Githash == "" {
+		return rest.APIError{
+			Message:    "must specify the githash of the module",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+	if len(p.request.Patch) > patch.SizeLimit {
+		return rest.APIError{
+			Message:    "patch is too large",
+			StatusCode: http.StatusBadRequest,
+		}
+	}
+	return nil
+}
+
+func (p *patchModuleSetHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	err := sc.SetPatchModule(p.patchId, p.request.Module, p.request.Githash, p.request.Patch)
+	if err != nil {
+		if _, ok := err.(*rest.APIError); !ok {
+			err = errors.Wrap(err, "Database error")
+		}
+		return ResponseData{}, err
+	}
+
+	foundPatch, err := sc.FindPatchById(p.patchId)
+	if err != nil {
+		return ResponseData{}, errors.Wrap(err, "Database error")
+	}
+	return patchResponse(foundPatch)
+}
+
+type patchModuleRemoveHandler struct {
+	patchId string
+	module  string
+}
+
+func (p *patchModuleRemoveHandler) Handler() RequestHandler {
+	return &patchModuleRemoveHandler{}
+}
+
+func (p *patchModuleRemoveHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	vars := mux.Vars(r)
+	p.patchId = vars["patch_id"]
+	p.module = vars["module"]
+	return nil
+}
+
+func (p *patchModuleRemoveHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	if err := sc.RemovePatchModule(p.patchId, p.module); err != nil {
+		if _, ok := err.(*rest.APIError); !ok {
+			err = errors.Wrap(err, "Database error")
+		}
+		return ResponseData{}, err
+	}
+
+	foundPatch, err := sc.FindPatchById(p.patchId)
+	if err != nil {
+		return ResponseData{}, errors.Wrap(err, "Database error")
+	}
+	return patchResponse(foundPatch)
+}
+
+// patchResponse returns the API model of the patch as the result of a
+// request.
+func patchResponse(p *patch.Patch) (ResponseData, error) {
+	patchModel := &model.APIPatch{}
+	if err := patchModel.BuildFromService(*p); err != nil {
+		return ResponseData{}, errors.Wrap(err, "API model error")
+	}
+	return ResponseData{
+		Result: []model.Model{patchModel},
+	}, nil
+}
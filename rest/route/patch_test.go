@@ -2,6 +2,7 @@ package route
 
 import (
 	"context"
+	"net/http"
 	"testing"
 	"time"
 
@@ -468,3 +469,135 @@ func executePatchesByUserRequest(user string, ts time.Time, limit int, sc *data.
 
 	return pe.Execute(context.TODO(), sc)
 }
+
+////////////////////////////////////////////////////////////////////////
+//
+// Tests for create and configure patch routes
+
+type PatchCreateSuite struct {
+	sc   *data.MockConnector
+	data data.MockPatchConnector
+	ctx  context.Context
+
+	suite.Suite
+}
+
+func TestPatchCreateSuite(t *testing.T) {
+	suite.Run(t, new(PatchCreateSuite))
+}
+
+func (s *PatchCreateSuite) SetupTest() {
+	s.data = data.MockPatchConnector{}
+	s.sc = &data.MockConnector{
+		MockPatchConnector: s.data,
+	}
+	s.ctx = context.WithValue(context.Background(), evergreen.RequestUser, &user.DBUser{Id: "user1"})
+}
+
+func (s *PatchCreateSuite) create(req model.PatchCreateRequest) (*model.APIPatch, error) {
+	rm := getPatchCreateManager("", 2)
+	rm.Methods[0].RequestHandler.(*patchCreateHandler).request = req
+	res, err := rm.Methods[0].Execute(s.ctx, s.sc)
+	if err != nil {
+		return nil, err
+	}
+	s.Require().Len(res.Result, 1)
+	return res.Result[0].(*model.APIPatch), nil
+}
+
+func (s *PatchCreateSuite) TestCreate() {
+	p, err := s.create(model.PatchCreateRequest{
+		Project:     "proj",
+		Githash:     "abcdef",
+		Description: "my patch",
+		Variants:    []string{"linux"},
+		Tasks:       []string{"compile"},
+		Finalize:    true,
+	})
+	s.Require().NoError(err)
+	s.Equal(model.APIString("proj"), p.ProjectId)
+	s.Equal(model.APIString("user1"), p.Author)
+	s.Equal(model.APIString("my patch"), p.Description)
+	s.True(p.Activated)
+	s.Len(s.sc.MockPatchConnector.CachedPatches, 1)
+}
+
+func (s *PatchCreateSuite) TestCreateWithModulesFinalizesLast() {
+	p, err := s.create(model.PatchCreateRequest{
+		Project:  "proj",
+		Githash:  "abcdef",
+		Variants: []string{"linux"},
+		Tasks:    []string{"compile"},
+		Finalize: true,
+		Modules: []model.PatchModuleRequest{
+			{Module: "enterprise", Githash: "123456", Patch: ""},
+		},
+	})
+	s.Require().NoError(err)
+	s.True(p.Activated)
+	s.Require().Len(s.sc.MockPatchConnector.CachedPatches, 1)
+	cached := s.sc.MockPatchConnector.CachedPatches[0]
+	s.Require().Len(cached.Patches, 2)
+	s.Equal("enterprise", cached.Patches[1].ModuleName)
+	s.Equal("123456", cached.Patches[1].Githash)
+}
+
+func (s *PatchCreateSuite) TestCreateRejectsInvalidIntents() {
+	_, err := s.create(model.PatchCreateRequest{Project: "proj"})
+	s.Require().Error(err)
+	s.Equal(http.StatusBadRequest, err.(rest.APIError).StatusCode)
+
+	_, err = s.create(model.PatchCreateRequest{Project: "proj", Githash: "abcdef", Finalize: true})
+	s.Require().Error(err)
+	s.Equal(http.StatusBadRequest, err.(rest.APIError).StatusCode)
+	s.Len(s.sc.MockPatchConnector.CachedPatches, 0)
+}
+
+func (s *PatchCreateSuite) TestConfigure() {
+	p, err := s.create(model.PatchCreateRequest{Project: "proj", Githash: "abcdef"})
+	s.Require().NoError(err)
+	s.False(p.Activated)
+
+	rm := getPatchConfigureManager("", 2)
+	handler := rm.Methods[0].RequestHandler.(*patchConfigureHandler)
+	description := "configured"
+	handler.patchId = string(p.Id)
+	handler.request = model.PatchConfigureRequest{
+		Description: &description,
+		Variants:    []string{"linux", "windows"},
+		Tasks:       []string{"compile"},
+		Finalize:    true,
+	}
+	res, err := rm.Methods[0].Execute(s.ctx, s.sc)
+	s.Require().NoError(err)
+	s.Require().Len(res.Result, 1)
+	configured := res.Result[0].(*model.APIPatch)
+	s.True(configured.Activated)
+	s.Equal(model.APIString("configured"), configured.Description)
+	s.Len(configured.VariantsTasks, 2)
+}
+
+func (s *PatchCreateSuite) TestModules() {
+	p, err := s.create(model.PatchCreateRequest{Project: "proj", Githash: "abcdef"})
+	s.Require().NoError(err)
+
+	rm := getPatchModuleManager("", 2)
+	setHandler := rm.Methods[0].RequestHandler.(*patchModuleSetHandler)
+	setHandler.patchId = string(p.Id)
+	setHandler.request = model.PatchModuleRequest{Module: "enterprise", Githash: "123456"}
+	_, err = rm.Methods[0].Execute(s.ctx, s.sc)
+	s.Require().NoError(err)
+	s.Len(s.sc.MockPatchConnector.CachedPatches[0].Patches, 2)
+
+	removeHandler := rm.Methods[1].RequestHandler.(*patchModuleRemoveHandler)
+	removeHandler.patchId = string(p.Id)
+	removeHandler.module = "enterprise"
+	_, err = rm.Methods[1].Execute(s.ctx, s.sc)
+	s.Require().NoError(err)
+	s.Len(s.sc.MockPatchConnector.CachedPatches[0].Patches, 1)
+
+	s.sc.MockPatchConnector.CachedPatches[0].Activated = true
+	_, err = rm.Methods[1].Execute(s.ctx, s.sc)
+	s.Require().Error(err)
+	s.Equal(http.StatusBadRequest, err.(*rest.APIError).StatusCode)
+}
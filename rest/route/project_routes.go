@@ -41,6 +41,10 @@ func (p *projectGetHandler) Handler() RequestHandler {
 		KeyQueryParam:   "start_at",
 		LimitQueryParam: "limit",
 		Paginator:       projectPaginator,
+		ListQuery: &ListQuerySpec{
+			Model:    &model.APIProject{},
+			KeyField: "identifier",
+		},
 	}}
 }
 
@@ -3,10 +3,16 @@ package route
 import (
 	"fmt"
 
+	"github.com/evergreen-ci/evergreen/metrics"
 	"github.com/evergreen-ci/evergreen/rest/data"
 	"github.com/gorilla/mux"
 )
 
+// routeLatency records the duration of the requests to each REST route.
+var routeLatency = metrics.NewHistogramVec("evergreen_rest_request_duration_seconds",
+	"Duration of REST API requests by route, method and status code.",
+	metrics.DefaultLatencyBuckets, "route", "method", "code")
+
 // routeManagerFactory is a function type used to create RouteManagers and used to register handlders.
 type routeManagerFactory func(string, int) *RouteManager
 
@@ -28,7 +34,8 @@ type RouteManager struct {
 // these to the given router.
 func (rm *RouteManager) Register(r *mux.Router, sc data.Connector) {
 	for _, method := range rm.Methods {
-		routeHandlerFunc := makeHandler(method, sc)
+		routeName := fmt.Sprintf("/%s/v%d%s", sc.GetPrefix(), rm.Version, rm.Route)
+		routeHandlerFunc := metrics.InstrumentHandler(routeLatency, routeName, makeHandler(method, sc))
 		sr := r.PathPrefix(fmt.Sprintf("/%s/v%d/", sc.GetPrefix(), rm.Version)).Subrouter().StrictSlash(true)
 
 		sr.HandleFunc(rm.Route, routeHandlerFunc).Methods(method.MethodType)
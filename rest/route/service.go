@@ -11,19 +11,19 @@ import (
 // AttachHandler attaches the api's request handlers to the given mux router.
 // It builds a Connector then attaches each of the main functions for
 // the api to the router.
-func AttachHandler(root *mux.Router, queue amboy.Queue, URL, prefix string, superUsers []string, githubSecret []byte) http.Handler {
+func AttachHandler(root *mux.Router, queue amboy.Queue, URL, prefix string, superUsers []string, githubSecret, gitlabSecret []byte) http.Handler {
 	sc := &data.DBConnector{}
 
 	sc.SetURL(URL)
 	sc.SetPrefix(prefix)
 	sc.SetSuperUsers(superUsers)
-	return GetHandler(root, sc, queue, githubSecret)
+	return GetHandler(root, sc, queue, githubSecret, gitlabSecret)
 }
 
 // GetHandler builds each of the functions that this api implements and then
 // registers them on the given router. It then returns the given router as an
 // http handler which can be given more functions.
-func GetHandler(r *mux.Router, sc data.Connector, queue amboy.Queue, githubSecret []byte) http.Handler {
+func GetHandler(r *mux.Router, sc data.Connector, queue amboy.Queue, githubSecret, gitlabSecret []byte) http.Handler {
 	routes := map[string]routeManagerFactory{
 		"/":                                                    getPlaceHolderManger,
 		"/admin":                                               getAdminSettingsManager,
@@ -35,12 +35,18 @@ func GetHandler(r *mux.Router, sc data.Connector, queue amboy.Queue, githubSecre
 		"/builds/{build_id}/restart":                           getBuildRestartManager,
 		"/builds/{build_id}/tasks":                             getTasksByBuildRouteManager,
 		"/distros":                                             getDistroRouteManager,
+		"/graphql":                                             getGraphQLRouteManager,
 		"/hosts":                                               getHostRouteManager,
 		"/hosts/{host_id}":                                     getHostIDRouteManager,
 		"/hosts/{host_id}/change_password":                     getHostChangeRDPPasswordRouteManager,
 		"/hosts/{host_id}/extend_expiration":                   getHostExtendExpirationRouteManager,
 		"/hosts/{host_id}/terminate":                           getHostTerminateRouteManager,
+		"/hosts/{host_id}/stop":                                getHostStopRouteManager,
+		"/hosts/{host_id}/start":                               getHostStartRouteManager,
+		"/patches":                                             getPatchCreateManager,
 		"/patches/{patch_id}":                                  getPatchByIdManager,
+		"/patches/{patch_id}/configure":                        getPatchConfigureManager,
+		"/patches/{patch_id}/modules/{module}":                 getPatchModuleManager,
 		"/users/{user_id}/patches":                             getPatchesByUserManager,
 		"/users/{user_id}/hosts":                               getHostsByUserManager,
 		"/patches/{patch_id}/abort":                            getPatchAbortManager,
@@ -48,8 +54,10 @@ func GetHandler(r *mux.Router, sc data.Connector, queue amboy.Queue, githubSecre
 		"/projects":                                            getProjectRouteManager,
 		"/projects/{project_id}/patches":                       getPatchesByProjectManager,
 		"/projects/{project_id}/revisions/{commit_hash}/tasks": getTasksByProjectAndCommitRouteManager,
+		"/projects/{project_id}/versions":                      getVersionsByProjectRouteManager,
 		"/tasks/{task_id}":                                     getTaskRouteManager,
 		"/tasks/{task_id}/abort":                               getTaskAbortManager,
+		"/tasks/{task_id}/artifacts":                           getTaskArtifactsRouteManager,
 		"/tasks/{task_id}/restart":                             getTaskRestartRouteManager,
 		"/tasks/{task_id}/tests":                               getTestRouteManager,
 		"/tasks/{task_id}/metrics/process":                     getTaskProcessMetricsManager,
@@ -59,13 +67,18 @@ func GetHandler(r *mux.Router, sc data.Connector, queue amboy.Queue, githubSecre
 		"/cost/project/{project_id}/tasks":                     getCostTaskByProjectRouteManager,
 		"/versions/{version_id}":                               getVersionIdRouteManager,
 		"/versions/{version_id}/builds":                        getBuildsForVersionRouteManager,
+		"/versions/{version_id}/compare":                       getCompareVersionRouteManager,
+		"/versions/{version_id}/downstream":                    getDownstreamVersionsRouteManager,
 		"/versions/{version_id}/abort":                         getAbortVersionRouteManager,
 		"/versions/{version_id}/restart":                       getRestartVersionRouteManager,
 		"/status/hosts/distros":                                getHostStatsByDistroManager,
+		"/subscriptions":                                       getSubscriptionsRouteManager,
+		"/subscriptions/{subscription_id}":                     getSubscriptionDeleteRouteManager,
 		"/status/recent_tasks":                                 getRecentTasksRouteManager,
 		"/keys":                                                getKeysRouteManager,
 		"/keys/{key_name}":                                     getKeysDeleteRouteManager,
 		"/hooks/github":                                        getGithubHooksRouteManager(queue, githubSecret),
+		"/hooks/gitlab":                                        getGitlabHooksRouteManager(queue, gitlabSecret),
 		"/alias/{name}":                                        getAliasRouteManager,
 	}
 
@@ -0,0 +1,178 @@
+package route
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/rest"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+func getSubscriptionsRouteManager(route string, version int) *RouteManager {
+	return &RouteManager{
+		Route: route,
+		Methods: []MethodHandler{
+			MethodHandler{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				Authenticator:     &RequireUserAuthenticator{},
+				RequestHandler:    &subscriptionsGetHandler{},
+				MethodType:        http.MethodGet,
+			},
+			MethodHandler{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				Authenticator:     &RequireUserAuthenticator{},
+				RequestHandler:    &subscriptionPostHandler{},
+				MethodType:        http.MethodPost,
+			},
+		},
+		Version: version,
+	}
+}
+
+type subscriptionsGetHandler struct{}
+
+func (h *subscriptionsGetHandler) Handler() RequestHandler {
+	return &subscriptionsGetHandler{}
+}
+
+func (h *subscriptionsGetHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	return nil
+}
+
+func (h *subscriptionsGetHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	u := MustHaveUser(ctx)
+
+	subscriptions, err := sc.GetSubscriptions(u.Id)
+	if err != nil {
+		return ResponseData{}, errors.Wrap(err, "error finding subscriptions")
+	}
+
+	models := make([]model.Model, len(subscriptions))
+	for i, s := range subscriptions {
+		apiSubscription := &model.APISubscription{}
+		if err = apiSubscription.BuildFromService(s); err != nil {
+			return ResponseData{}, errors.Wrap(err, "error marshalling subscription to api")
+		}
+		models[i] = apiSubscription
+	}
+
+	return ResponseData{
+		Result: models,
+	}, nil
+}
+
+// subscriptionPostHandler creates a subscription, or updates one of the
+// user's subscriptions if the body has the subscription's id. Owner
+// selectors without data select the resources of the user.
+type subscriptionPostHandler struct {
+	subscription *model.APISubscription
+}
+
+func (h *subscriptionPostHandler) Handler() RequestHandler {
+	return &subscriptionPostHandler{}
+}
+
+func (h *subscriptionPostHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	body := util.NewRequestReader(r)
+	defer body.Close()
+
+	h.subscription = &model.APISubscription{}
+	if err := util.ReadJSONInto(body, h.subscription); err != nil {
+		return &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("failed to unmarshal subscription: %s", err),
+		}
+	}
+
+	return nil
+}
+
+func (h *subscriptionPostHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	u := MustHaveUser(ctx)
+
+	h.subscription.Owner = model.APIString(u.Id)
+	for i := range h.subscription.Selectors {
+		if h.subscription.Selectors[i].Type == event.SelectorOwner && h.subscription.Selectors[i].Data == "" {
+			h.subscription.Selectors[i].Data = model.APIString(u.Id)
+		}
+	}
+
+	i, err := h.subscription.ToService()
+	if err != nil {
+		return ResponseData{}, &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+	subscription := i.(event.Subscription)
+	if err = subscription.Validate(); err != nil {
+		return ResponseData{}, &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid subscription: %s", err),
+		}
+	}
+
+	if err = sc.SaveSubscription(&subscription); err != nil {
+		return ResponseData{}, err
+	}
+
+	saved := &model.APISubscription{}
+	if err = saved.BuildFromService(subscription); err != nil {
+		return ResponseData{}, errors.Wrap(err, "error marshalling subscription to api")
+	}
+
+	return ResponseData{
+		Result: []model.Model{saved},
+	}, nil
+}
+
+func getSubscriptionDeleteRouteManager(route string, version int) *RouteManager {
+	return &RouteManager{
+		Route: route,
+		Methods: []MethodHandler{
+			MethodHandler{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				Authenticator:     &RequireUserAuthenticator{},
+				RequestHandler:    &subscriptionDeleteHandler{},
+				MethodType:        http.MethodDelete,
+			},
+		},
+		Version: version,
+	}
+}
+
+type subscriptionDeleteHandler struct {
+	id string
+}
+
+func (h *subscriptionDeleteHandler) Handler() RequestHandler {
+	return &subscriptionDeleteHandler{}
+}
+
+func (h *subscriptionDeleteHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	h.id = mux.Vars(r)["subscription_id"]
+	if h.id == "" {
+		return &rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "empty subscription id",
+		}
+	}
+
+	return nil
+}
+
+func (h *subscriptionDeleteHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	u := MustHaveUser(ctx)
+
+	if err := sc.DeleteSubscription(u.Id, h.id); err != nil {
+		return ResponseData{}, err
+	}
+
+	return ResponseData{}, nil
+}
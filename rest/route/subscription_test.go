@@ -0,0 +1,127 @@
+package route
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/model/user"
+	"github.com/evergreen-ci/evergreen/rest/data"
+	"github.com/evergreen-ci/evergreen/rest/model"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type SubscriptionRouteSuite struct {
+	sc  *data.MockConnector
+	ctx context.Context
+	suite.Suite
+}
+
+func TestSubscriptionRouteSuite(t *testing.T) {
+	suite.Run(t, new(SubscriptionRouteSuite))
+}
+
+func (s *SubscriptionRouteSuite) SetupTest() {
+	s.ctx = context.WithValue(context.Background(), evergreen.RequestUser, &user.DBUser{Id: "user0"})
+	s.sc = &data.MockConnector{MockSubscriptionConnector: data.MockSubscriptionConnector{
+		CachedSubscriptions: []event.Subscription{
+			{
+				ID:           bson.NewObjectId(),
+				Owner:        "user0",
+				ResourceType: event.ResourceTypeTask,
+				Trigger:      event.TriggerFailure,
+				Subscriber:   event.Subscriber{Type: event.SubscriberSlack, Target: "#user0"},
+			},
+			{
+				ID:           bson.NewObjectId(),
+				Owner:        "user1",
+				ResourceType: event.ResourceTypeBuild,
+				Trigger:      event.TriggerOutcome,
+				Subscriber:   event.Subscriber{Type: event.SubscriberSlack, Target: "#user1"},
+			},
+		},
+	}}
+}
+
+func (s *SubscriptionRouteSuite) TestGetOnlyReturnsOwnSubscriptions() {
+	rm := getSubscriptionsRouteManager("", 2)
+
+	resp, err := rm.Methods[0].Execute(s.ctx, s.sc)
+	s.NoError(err)
+	s.Require().Len(resp.Result, 1)
+	subscription := resp.Result[0].(*model.APISubscription)
+	s.Equal(model.APIString("user0"), subscription.Owner)
+	s.Equal(model.APIString("#user0"), subscription.Subscriber.Target)
+}
+
+func (s *SubscriptionRouteSuite) TestPostSetsOwner() {
+	rm := getSubscriptionsRouteManager("", 2)
+	rm.Methods[1].RequestHandler.(*subscriptionPostHandler).subscription = &model.APISubscription{
+		Owner:        "user1",
+		ResourceType: event.ResourceTypeVersion,
+		Trigger:      event.TriggerFailure,
+		Selectors: []model.APISelector{
+			{Type: event.SelectorOwner},
+			{Type: event.SelectorProject, Data: "mci"},
+		},
+		Subscriber: model.APISubscriber{Type: event.SubscriberEmail, Target: "user0@example.com"},
+	}
+
+	resp, err := rm.Methods[1].Execute(s.ctx, s.sc)
+	s.NoError(err)
+	s.Require().Len(resp.Result, 1)
+	subscription := resp.Result[0].(*model.APISubscription)
+	s.NotEmpty(subscription.ID)
+	s.Equal(model.APIString("user0"), subscription.Owner)
+	s.Equal(model.APIString("user0"), subscription.Selectors[0].Data)
+	s.Equal(model.APIString("mci"), subscription.Selectors[1].Data)
+
+	s.Len(s.sc.MockSubscriptionConnector.CachedSubscriptions, 3)
+}
+
+func (s *SubscriptionRouteSuite) TestPostInvalidSubscriptionFails() {
+	rm := getSubscriptionsRouteManager("", 2)
+	rm.Methods[1].RequestHandler.(*subscriptionPostHandler).subscription = &model.APISubscription{
+		ResourceType: event.ResourceTypeHost,
+		Trigger:      event.TriggerRegression,
+		Subscriber:   model.APISubscriber{Type: event.SubscriberWebhook, Target: "not a url"},
+	}
+
+	_, err := rm.Methods[1].Execute(s.ctx, s.sc)
+	s.Error(err)
+	s.Len(s.sc.MockSubscriptionConnector.CachedSubscriptions, 2)
+}
+
+func (s *SubscriptionRouteSuite) TestPostCannotUpdateOtherUsersSubscription() {
+	rm := getSubscriptionsRouteManager("", 2)
+	other := s.sc.MockSubscriptionConnector.CachedSubscriptions[1]
+	rm.Methods[1].RequestHandler.(*subscriptionPostHandler).subscription = &model.APISubscription{
+		ID:           model.APIString(other.ID.Hex()),
+		ResourceType: event.ResourceTypeBuild,
+		Trigger:      event.TriggerFailure,
+		Subscriber:   model.APISubscriber{Type: event.SubscriberSlack, Target: "#user0"},
+	}
+
+	_, err := rm.Methods[1].Execute(s.ctx, s.sc)
+	s.Error(err)
+	s.Equal("user1", s.sc.MockSubscriptionConnector.CachedSubscriptions[1].Owner)
+	s.Equal(event.TriggerOutcome, s.sc.MockSubscriptionConnector.CachedSubscriptions[1].Trigger)
+}
+
+func (s *SubscriptionRouteSuite) TestDelete() {
+	rm := getSubscriptionDeleteRouteManager("", 2)
+	handler := rm.Methods[0].RequestHandler.(*subscriptionDeleteHandler)
+
+	handler.id = s.sc.MockSubscriptionConnector.CachedSubscriptions[1].ID.Hex()
+	_, err := rm.Methods[0].Execute(s.ctx, s.sc)
+	s.Error(err)
+	s.Len(s.sc.MockSubscriptionConnector.CachedSubscriptions, 2)
+
+	handler.id = s.sc.MockSubscriptionConnector.CachedSubscriptions[0].ID.Hex()
+	_, err = rm.Methods[0].Execute(s.ctx, s.sc)
+	s.NoError(err)
+	s.Require().Len(s.sc.MockSubscriptionConnector.CachedSubscriptions, 1)
+	s.Equal("user1", s.sc.MockSubscriptionConnector.CachedSubscriptions[0].Owner)
+}
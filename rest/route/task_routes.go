@@ -304,7 +304,7 @@ type tasksByBuildArgs struct {
 func (tbh *tasksByBuildHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
 	args := tasksByBuildArgs{
 		buildId: mux.Vars(r)["build_id"],
-		status:  r.URL.Query().Get("status"),
+		status:  singleFilterValue(r.URL.Query(), "status"),
 	}
 	if args.buildId == "" {
 		return rest.APIError{
@@ -381,6 +381,16 @@ func (hgh *tasksByBuildHandler) Handler() RequestHandler {
 		Paginator:       tasksByBuildPaginator,
 
 		Args: tasksByBuildArgs{},
+		ListQuery: &ListQuerySpec{
+			Model: &model.APITask{},
+			Filters: map[string]string{
+				"status":    "status",
+				"variant":   "build_variant",
+				"requester": "requester",
+			},
+			TimeField: "create_time",
+			KeyField:  "task_id",
+		},
 	}
 
 	return &tasksByBuildHandler{taskPaginationExecutor}
@@ -49,6 +49,12 @@ func (hgh *testGetHandler) Handler() RequestHandler {
 		LimitQueryParam: "limit",
 		Paginator:       testPaginator,
 		Args:            testGetHandlerArgs{},
+		ListQuery: &ListQuerySpec{
+			Model:     &model.APITest{},
+			Filters:   map[string]string{"status": "status"},
+			TimeField: "start_time",
+			KeyField:  "test_file",
+		},
 	}
 
 	return &testGetHandler{testPaginationExecutor}
@@ -66,7 +72,7 @@ func (tgh *testGetHandler) ParseAndValidate(ctx context.Context, r *http.Request
 	}
 	tgh.Args = testGetHandlerArgs{
 		taskId:     projCtx.Task.Id,
-		testStatus: r.URL.Query().Get("status"),
+		testStatus: singleFilterValue(r.URL.Query(), "status"),
 	}
 	return tgh.PaginationExecutor.ParseAndValidate(ctx, r)
 }
@@ -2,7 +2,10 @@ package route
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/evergreen-ci/evergreen/rest"
 	"github.com/evergreen-ci/evergreen/rest/data"
@@ -78,6 +81,21 @@ func (vh *versionHandler) Execute(ctx context.Context, sc data.Connector) (Respo
 // buildsForVersionHandler is a RequestHandler for fetching all builds for a version
 type buildsForVersionHandler struct {
 	versionId string
+	query     *listQuery
+}
+
+// buildListQuery describes the filters and fields supported when listing
+// the builds of a version. A version has a single build per variant, so
+// the builds are returned in one page and need no cursor.
+var buildListQuery = &ListQuerySpec{
+	Model: &model.APIBuild{},
+	Filters: map[string]string{
+		"status":    "status",
+		"variant":   "build_variant",
+		"requester": "requester",
+	},
+	TimeField: "create_time",
+	KeyField:  "_id",
 }
 
 func getBuildsForVersionRouteManager(route string, version int) *RouteManager {
@@ -107,7 +125,9 @@ func (h *buildsForVersionHandler) ParseAndValidate(ctx context.Context, r *http.
 		return errors.New("request data incomplete")
 	}
 
-	return nil
+	var err error
+	h.query, err = parseListQuery(r.URL.Query(), buildListQuery)
+	return err
 }
 
 // Execute calls the FindVersionById function to find the version by its ID, calls FindBuildById for each
@@ -143,6 +163,13 @@ func (h *buildsForVersionHandler) Execute(ctx context.Context, sc data.Connector
 
 		buildModels = append(buildModels, buildModel)
 	}
+
+	if h.query != nil {
+		buildModels, err = h.query.apply(buildModels)
+		if err != nil {
+			return ResponseData{}, err
+		}
+	}
 	return ResponseData{
 		Result: buildModels,
 	}, nil
@@ -286,3 +313,253 @@ func (h *versionRestartHandler) Execute(ctx context.Context, sc data.Connector)
 		Result: []model.Model{versionModel},
 	}, err
 }
+
+// versionCompareHandler is a RequestHandler for comparing a version with a
+// base version.
+type versionCompareHandler struct {
+	versionId     string
+	baseVersionId string
+}
+
+func getCompareVersionRouteManager(route string, version int) *RouteManager {
+	return &RouteManager{
+		Route: route,
+		Methods: []MethodHandler{
+			{
+				PrefetchFunctions: []PrefetchFunc{PrefetchUser},
+				Authenticator:     &NoAuthAuthenticator{},
+				RequestHandler:    &versionCompareHandler{},
+				MethodType:        http.MethodGet,
+			},
+		},
+		Version: version,
+	}
+}
+
+// Handler returns a pointer to a new versionCompareHandler.
+func (h *versionCompareHandler) Handler() RequestHandler {
+	return &versionCompareHandler{}
+}
+
+// ParseAndValidate fetches the versionId from the http request, and the ID
+// of the version to compare it with from the "base" query parameter.
+func (h *versionCompareHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	h.versionId = getVersionIdFromRequest(r)
+	h.baseVersionId = r.URL.Query().Get("base")
+
+	if h.versionId == "" {
+		return errors.New("request data incomplete")
+	}
+	if h.baseVersionId == "" {
+		return rest.APIError{
+			StatusCode: http.StatusBadRequest,
+			Message:    "a base version to compare with is required",
+		}
+	}
+
+	return nil
+}
+
+// Execute calls the data CompareVersions function and returns the
+// differences between the versions.
+func (h *versionCompareHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	comparison, err := sc.CompareVersions(h.baseVersionId, h.versionId, GetUser(ctx))
+	if err != nil {
+		if _, ok := err.(*rest.APIError); !ok {
+			err = errors.Wrap(err, "Database error in comparing versions")
+		}
+		return ResponseData{}, err
+	}
+
+	comparisonModel := &model.APIVersionComparison{}
+	if err = comparisonModel.BuildFromService(comparison); err != nil {
+		return ResponseData{}, errors.Wrap(err, "API model error")
+	}
+
+	return ResponseData{
+		Result: []model.Model{comparisonModel},
+	}, nil
+}
+
+// downstreamVersionsHandler is a RequestHandler for fetching the versions
+// created by triggers on a version.
+type downstreamVersionsHandler struct {
+	versionId string
+}
+
+func getDownstreamVersionsRouteManager(route string, version int) *RouteManager {
+	return &RouteManager{
+		Route: route,
+		Methods: []MethodHandler{
+			{
+				Authenticator:  &NoAuthAuthenticator{},
+				RequestHandler: &downstreamVersionsHandler{},
+				MethodType:     http.MethodGet,
+			},
+		},
+		Version: version,
+	}
+}
+
+// Handler returns a pointer to a new downstreamVersionsHandler.
+func (h *downstreamVersionsHandler) Handler() RequestHandler {
+	return &downstreamVersionsHandler{}
+}
+
+// ParseAndValidate fetches the versionId from the http request.
+func (h *downstreamVersionsHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	h.versionId = getVersionIdFromRequest(r)
+
+	if h.versionId == "" {
+		return errors.New("request data incomplete")
+	}
+
+	return nil
+}
+
+// Execute calls the data FindDownstreamVersions function and returns the
+// versions triggered by the version.
+func (h *downstreamVersionsHandler) Execute(ctx context.Context, sc data.Connector) (ResponseData, error) {
+	versions, err := sc.FindDownstreamVersions(h.versionId)
+	if err != nil {
+		if _, ok := err.(*rest.APIError); !ok {
+			err = errors.Wrap(err, "Database error")
+		}
+		return ResponseData{}, err
+	}
+
+	models := make([]model.Model, len(versions))
+	for i := range versions {
+		versionModel := &model.APIVersion{}
+		if err = versionModel.BuildFromService(&versions[i]); err != nil {
+			return ResponseData{}, errors.Wrap(err, "API model error")
+		}
+		models[i] = versionModel
+	}
+	return ResponseData{
+		Result: models,
+	}, nil
+}
+
+////////////////////////////////////////////////////////////////////////
+//
+// Handler for the versions of a project
+//
+//    /projects/{project_id}/versions
+
+type versionsByProjectArgs struct {
+	projectId string
+}
+
+func getVersionsByProjectRouteManager(route string, version int) *RouteManager {
+	h := &versionsByProjectHandler{}
+	return &RouteManager{
+		Route:   route,
+		Version: version,
+		Methods: []MethodHandler{
+			{
+				MethodType:     http.MethodGet,
+				Authenticator:  &NoAuthAuthenticator{},
+				RequestHandler: h.Handler(),
+			},
+		},
+	}
+}
+
+// versionsByProjectHandler pages through the versions of a project from
+// newest to oldest. The versions can be filtered by requester, e.g. to the
+// versions created by periodic builds.
+type versionsByProjectHandler struct {
+	PaginationExecutor
+}
+
+func (h *versionsByProjectHandler) Handler() RequestHandler {
+	return &versionsByProjectHandler{PaginationExecutor{
+		KeyQueryParam:   "start_at",
+		LimitQueryParam: "limit",
+		Paginator:       versionsByProjectPaginator,
+		Args:            versionsByProjectArgs{},
+		ListQuery: &ListQuerySpec{
+			Model: &model.APIVersion{},
+			Filters: map[string]string{
+				"status":    "status",
+				"requester": "requester",
+			},
+			TimeField: "create_time",
+			KeyField:  "create_time",
+		},
+	}}
+}
+
+func (h *versionsByProjectHandler) ParseAndValidate(ctx context.Context, r *http.Request) error {
+	h.Args = versionsByProjectArgs{projectId: mux.Vars(r)["project_id"]}
+
+	return h.PaginationExecutor.ParseAndValidate(ctx, r)
+}
+
+func versionsByProjectPaginator(key string, limit int, args interface{}, sc data.Connector) ([]model.Model, *PageResult, error) {
+	projectId := args.(versionsByProjectArgs).projectId
+	ts := time.Now()
+	if key != "" {
+		// keys of filtered pages are read from the JSON of the versions,
+		// without the quotes of the API time format
+		var err error
+		ts, err = time.ParseInLocation(model.APITimeFormat, fmt.Sprintf("%q", strings.Trim(key, `"`)), time.UTC)
+		if err != nil {
+			return []model.Model{}, nil, &rest.APIError{
+				Message:    fmt.Sprintf("problem parsing time from '%s' (%s)", key, err.Error()),
+				StatusCode: http.StatusBadRequest,
+			}
+		}
+	}
+
+	versions, err := sc.FindVersionsByProject(projectId, ts, limit*2, false)
+	if err != nil {
+		if _, ok := err.(*rest.APIError); !ok {
+			err = errors.Wrap(err, "Database error")
+		}
+		return []model.Model{}, nil, err
+	}
+	if len(versions) <= 0 {
+		return []model.Model{}, nil, rest.APIError{
+			Message:    "no versions found",
+			StatusCode: http.StatusNotFound,
+		}
+	}
+
+	// Make the previous page
+	prevVersions, err := sc.FindVersionsByProject(projectId, ts, limit, true)
+	if err != nil {
+		if _, ok := err.(*rest.APIError); !ok {
+			err = errors.Wrap(err, "Database error")
+		}
+		return []model.Model{}, nil, err
+	}
+
+	pages := &PageResult{}
+	if len(versions) > limit {
+		pages.Next = &Page{
+			Relation: "next",
+			Key:      model.NewTime(versions[limit].CreateTime).String(),
+			Limit:    len(versions) - limit,
+		}
+		versions = versions[:limit]
+	}
+	if len(prevVersions) >= 1 {
+		pages.Prev = &Page{
+			Relation: "prev",
+			Key:      model.NewTime(prevVersions[len(prevVersions)-1].CreateTime).String(),
+			Limit:    len(prevVersions),
+		}
+	}
+
+	models := []model.Model{}
+	for i := range versions {
+		versionModel := &model.APIVersion{}
+		if err = versionModel.BuildFromService(&versions[i]); err != nil {
+			return []model.Model{}, nil, errors.Wrap(err, "API model error")
+		}
+		models = append(models, versionModel)
+	}
+	return models, pages, nil
+}
@@ -2,6 +2,8 @@ package route
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -193,3 +195,125 @@ func (s *VersionSuite) TestRestartVersion() {
 	s.Equal(model.APIString(versionId), h.Id)
 	s.Equal("caller1", s.versionData.CachedRestartedVersions["versionId"])
 }
+
+// TestCompareVersions tests the route for comparing a version with a base
+// version.
+func (s *VersionSuite) TestCompareVersions() {
+	sc := &data.MockConnector{
+		MockVersionConnector: data.MockVersionConnector{
+			CachedVersions: []version.Version{{Id: "base"}, {Id: "candidate"}},
+			CachedTasks: []task.Task{
+				{Id: "t1", Version: "base", BuildVariant: "bv", DisplayName: "compile", Status: evergreen.TaskSucceeded},
+				{Id: "t2", Version: "candidate", BuildVariant: "bv", DisplayName: "compile", Status: evergreen.TaskFailed},
+			},
+		},
+	}
+
+	handler := &versionCompareHandler{versionId: "candidate", baseVersionId: "base"}
+	res, err := handler.Execute(context.TODO(), sc)
+	s.NoError(err)
+	s.Require().Len(res.Result, 1)
+	comparison, ok := res.Result[0].(*model.APIVersionComparison)
+	s.Require().True(ok)
+	s.Equal(model.APIString("base"), comparison.BaseVersion)
+	s.Equal(model.APIString("candidate"), comparison.Version)
+	s.Require().Len(comparison.StatusChanges, 1)
+	s.Equal(model.APIString("t1"), comparison.StatusChanges[0].BaseTaskId)
+	s.Equal(model.APIString("t2"), comparison.StatusChanges[0].TaskId)
+	s.Equal(model.APIString(evergreen.TaskFailed), comparison.StatusChanges[0].Status)
+
+	handler = &versionCompareHandler{versionId: "candidate", baseVersionId: "missing"}
+	_, err = handler.Execute(context.TODO(), sc)
+	s.Error(err)
+}
+
+// TestFindDownstreamVersions tests the route for finding the versions
+// created by triggers on a version.
+func (s *VersionSuite) TestFindDownstreamVersions() {
+	sc := &data.MockConnector{
+		MockVersionConnector: data.MockVersionConnector{
+			CachedVersions: []version.Version{
+				{Id: "upstream", Identifier: "server"},
+				{
+					Id:         "downstream",
+					Identifier: "driver",
+					Requester:  evergreen.TriggerRequester,
+					TriggeredBy: &version.TriggerInfo{
+						Project:  "server",
+						Level:    "version",
+						Status:   evergreen.VersionSucceeded,
+						Version:  "upstream",
+						Revision: "abcdef",
+						Chain:    []string{"server"},
+					},
+				},
+			},
+		},
+	}
+
+	handler := &downstreamVersionsHandler{versionId: "upstream"}
+	res, err := handler.Execute(context.TODO(), sc)
+	s.NoError(err)
+	s.Require().Len(res.Result, 1)
+	downstream, ok := res.Result[0].(*model.APIVersion)
+	s.Require().True(ok)
+	s.Equal(model.APIString("downstream"), downstream.Id)
+	s.Equal(model.APIString(evergreen.TriggerRequester), downstream.Requester)
+	s.Require().NotNil(downstream.TriggeredBy)
+	s.Equal(model.APIString("upstream"), downstream.TriggeredBy.Version)
+	s.Equal(model.APIString("abcdef"), downstream.TriggeredBy.Revision)
+
+	handler = &downstreamVersionsHandler{versionId: "downstream"}
+	res, err = handler.Execute(context.TODO(), sc)
+	s.NoError(err)
+	s.Empty(res.Result)
+
+	handler = &downstreamVersionsHandler{versionId: "missing"}
+	_, err = handler.Execute(context.TODO(), sc)
+	s.Error(err)
+}
+
+// TestFindVersionsByProject tests the route for paging through the versions
+// of a project, filtered by requester.
+func (s *VersionSuite) TestFindVersionsByProject() {
+	base := time.Date(2018, time.March, 5, 2, 0, 0, 0, time.UTC)
+	sc := &data.MockConnector{
+		MockVersionConnector: data.MockVersionConnector{
+			CachedVersions: []version.Version{
+				{Id: "commit1", Identifier: "server", Requester: evergreen.RepotrackerVersionRequester, CreateTime: base},
+				{Id: "nightly1", Identifier: "server", Requester: evergreen.PeriodicBuildRequester, CreateTime: base.Add(time.Hour)},
+				{Id: "other", Identifier: "driver", Requester: evergreen.PeriodicBuildRequester, CreateTime: base.Add(2 * time.Hour)},
+				{Id: "commit2", Identifier: "server", Requester: evergreen.RepotrackerVersionRequester, CreateTime: base.Add(3 * time.Hour)},
+				{Id: "nightly2", Identifier: "server", Requester: evergreen.PeriodicBuildRequester, CreateTime: base.Add(4 * time.Hour)},
+			},
+		},
+	}
+
+	execute := func(query string) ([]string, *PageResult) {
+		handler := (&versionsByProjectHandler{}).Handler().(*versionsByProjectHandler)
+		handler.Args = versionsByProjectArgs{projectId: "server"}
+		r := httptest.NewRequest(http.MethodGet, "/projects/server/versions?"+query, nil)
+		s.Require().NoError(handler.PaginationExecutor.ParseAndValidate(context.TODO(), r))
+		res, err := handler.Execute(context.TODO(), sc)
+		s.Require().NoError(err)
+		ids := []string{}
+		for _, m := range res.Result {
+			ids = append(ids, string(m.(*model.APIVersion).Id))
+		}
+		return ids, res.Metadata.(*PaginationMetadata).Pages
+	}
+
+	ids, _ := execute("")
+	s.Equal([]string{"nightly2", "commit2", "nightly1", "commit1"}, ids)
+
+	ids, pages := execute("requester=" + evergreen.PeriodicBuildRequester + "&limit=1")
+	s.Equal([]string{"nightly2"}, ids)
+	s.Require().NotNil(pages.Next)
+
+	ids, pages = execute("requester=" + evergreen.PeriodicBuildRequester + "&limit=1&cursor=" + pages.Next.Key)
+	s.Equal([]string{"nightly1"}, ids)
+	s.Nil(pages.Next)
+
+	ids, _ = execute("start_at=" + model.NewTime(base.Add(3*time.Hour)).String() + "&limit=2")
+	s.Equal([]string{"commit2", "nightly1"}, ids)
+}
@@ -4,7 +4,6 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/cloud"
 	"github.com/evergreen-ci/evergreen/model/distro"
-	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
@@ -78,16 +77,24 @@ func (self *DeficitBasedHostAllocator) numNewHostsForDistro(
 	existingDistroHosts := hostAllocatorData.existingDistroHosts[distro.Id]
 	runnableDistroTasks := hostAllocatorData.taskQueueItems[distro.Id]
 
-	freeHosts := make([]host.Host, 0, len(existingDistroHosts))
+	freeSlots := 0
 	for _, existingDistroHost := range existingDistroHosts {
-		if existingDistroHost.RunningTask == "" {
-			freeHosts = append(freeHosts, existingDistroHost)
-		}
+		freeSlots += existingDistroHost.FreeSlots()
+	}
+
+	// each new host can run as many tasks as it has slots
+	slotsPerHost := 1
+	if distro.HasTaskSlots() {
+		slotsPerHost = distro.TaskSlots()
+	}
+	deficit := len(runnableDistroTasks) - freeSlots
+	if deficit > 0 {
+		deficit = (deficit + slotsPerHost - 1) / slotsPerHost
 	}
 
 	numNewHosts := util.Min(
-		// the deficit of available hosts vs. tasks to be run
-		len(runnableDistroTasks)-len(freeHosts),
+		// the deficit of available task slots vs. tasks to be run
+		deficit,
 		// the maximum number of new hosts we're allowed to spin up
 		distro.PoolSize-len(existingDistroHosts),
 	)
@@ -208,10 +208,8 @@ func computeRunningTasksDuration(existingDistroHosts []host.Host,
 	runningTaskIds := []string{}
 
 	for _, existingDistroHost := range existingDistroHosts {
-		if existingDistroHost.RunningTask != "" {
-			runningTaskIds = append(runningTaskIds,
-				existingDistroHost.RunningTask)
-		}
+		runningTaskIds = append(runningTaskIds,
+			existingDistroHost.RunningTaskIds()...)
 	}
 
 	// if this distro's hosts are all free, return immediately
@@ -445,7 +443,7 @@ func (self *DurationBasedHostAllocator) numNewHostsForDistro(
 	// determine how many free hosts we have
 	numFreeHosts := 0
 	for _, existingDistroHost := range existingDistroHosts {
-		if existingDistroHost.RunningTask == "" {
+		if existingDistroHost.HasFreeSlot() {
 			numFreeHosts += 1
 		}
 	}
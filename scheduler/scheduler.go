@@ -14,6 +14,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/tracing"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
@@ -43,7 +44,13 @@ type versionBuildVariant struct {
 // are ready to be run, splitting them by distro, prioritizing them, and saving
 // the per-distro queues.  Then determines the number of new hosts to spin up
 // for each distro, and spins them up.
-func (s *Scheduler) Schedule(ctx context.Context) error {
+func (s *Scheduler) Schedule(ctx context.Context) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "scheduler.Schedule")
+	defer func() {
+		span.SetError(err)
+		span.End()
+	}()
+
 	if err := model.UpdateStaticHosts(); err != nil {
 		return errors.Wrap(err, "error updating static hosts")
 	}
@@ -132,7 +139,7 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 			for d := range distroInputChan {
 				distroStartTime := time.Now()
 				// schedule the distro
-				res := s.scheduleDistro(d.distroId, d.runnableTasksForDistro, taskExpectedDuration)
+				res := s.scheduleDistro(ctx, d.distroId, d.runnableTasksForDistro, taskExpectedDuration)
 				if res.err != nil {
 					grip.Error(message.Fields{
 						"operation": "scheduling distro",
@@ -307,6 +314,19 @@ func (s *Scheduler) Schedule(ctx context.Context) error {
 		})
 	}
 
+	estimatesStart := time.Now()
+	err = updateEstimates(runnableTasks, taskQueueItems, hostsByDistro, hostsSpawned, estimatesStart)
+	grip.Error(message.WrapError(err, message.Fields{
+		"runner":    RunnerName,
+		"operation": "estimating task and version start and finish times",
+	}))
+	grip.Info(message.Fields{
+		"runner":    RunnerName,
+		"operation": "estimating task and version start and finish times",
+		"span":      time.Since(estimatesStart).String(),
+		"duration":  time.Since(estimatesStart),
+	})
+
 	for d, t := range schedulerEvents {
 		event.LogSchedulerEvent(event.SchedulerEventData{
 			ResourceType:  event.ResourceTypeScheduler,
@@ -337,12 +357,20 @@ type distroSchedulerResult struct {
 	err            error
 }
 
-func (s *Scheduler) scheduleDistro(distroId string, runnableTasksForDistro []task.Task,
+func (s *Scheduler) scheduleDistro(ctx context.Context, distroId string, runnableTasksForDistro []task.Task,
 	taskExpectedDuration model.ProjectTaskDurations) distroSchedulerResult {
 
 	res := distroSchedulerResult{
 		distroId: distroId,
 	}
+	_, span := tracing.StartSpan(ctx, "scheduler.scheduleDistro")
+	span.SetAttribute("distro_id", distroId)
+	span.SetAttribute("num_tasks", len(runnableTasksForDistro))
+	defer func() {
+		span.SetError(res.err)
+		span.End()
+	}()
+
 	grip.Info(message.Fields{
 		"runner":    RunnerName,
 		"distro":    distroId,
@@ -515,6 +543,8 @@ func (s *Scheduler) splitTasksByDistro(tasksToSplit []task.Task) (
 // Returns a map of distro -> hosts spawned, and an error if one occurs.
 func (s *Scheduler) spawnHosts(ctx context.Context, newHostsNeeded map[string]int) (map[string][]host.Host, error) {
 	startTime := time.Now()
+	_, span := tracing.StartSpan(ctx, "scheduler.spawnHosts")
+	defer span.End()
 
 	// loop over the distros, spawning up the appropriate number of hosts
 	// for each distro
@@ -529,6 +559,7 @@ func (s *Scheduler) spawnHosts(ctx context.Context, newHostsNeeded map[string]in
 		hostsSpawnedPerDistro[distroId] = make([]host.Host, 0, numHostsToSpawn)
 		for i := 0; i < numHostsToSpawn; i++ {
 			if ctx.Err() != nil {
+				span.SetError(ctx.Err())
 				return nil, errors.New("scheduling run canceled.")
 			}
 
@@ -590,6 +621,7 @@ func (s *Scheduler) spawnHosts(ctx context.Context, newHostsNeeded map[string]in
 					"provider": d.Provider,
 				}))
 
+				span.SetError(err)
 				return nil, err
 			}
 
@@ -619,6 +651,12 @@ func (s *Scheduler) spawnHosts(ctx context.Context, newHostsNeeded map[string]in
 		"duration":  time.Since(startTime),
 	})
 
+	numSpawned := 0
+	for _, hosts := range hostsSpawnedPerDistro {
+		numSpawned += len(hosts)
+	}
+	span.SetAttribute("num_hosts", numSpawned)
+
 	return hostsSpawnedPerDistro, nil
 }
 
@@ -0,0 +1,347 @@
+package scheduler
+
+import (
+	"math"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// hostStartupEstimate is how long a host that isn't running yet is expected
+// to take before it can run tasks.
+const hostStartupEstimate = 5 * time.Minute
+
+// taskEstimate is when a task is expected to start and finish, and its
+// lowest position in the task queues, or 0 if it isn't queued.
+type taskEstimate struct {
+	start         time.Time
+	finish        time.Time
+	queuePosition int
+}
+
+// updateEstimates estimates when every queued, running and waiting task
+// starts and finishes, and when each of their versions finishes, which is
+// when the version's critical path of dependencies does, and stores the
+// estimates. It first logs how far off the estimates of the tasks and
+// versions that finished since the last pass were.
+func updateEstimates(runnableTasks []task.Task, taskQueueItems map[string][]model.TaskQueueItem,
+	hostsByDistro, hostsSpawned map[string][]host.Host, now time.Time) error {
+	if err := logEstimateAccuracy(); err != nil {
+		return errors.Wrap(err, "error checking the accuracy of estimates")
+	}
+
+	runningIds := []string{}
+	for _, hosts := range hostsByDistro {
+		for _, h := range hosts {
+			runningIds = append(runningIds, h.RunningTaskIds()...)
+		}
+	}
+	running := []task.Task{}
+	if len(runningIds) > 0 {
+		var err error
+		running, err = task.Find(task.ByIds(runningIds))
+		if err != nil {
+			return errors.Wrap(err, "error finding running tasks")
+		}
+	}
+	estimates := estimateRunningTasks(running, now)
+
+	for distroId, queue := range taskQueueItems {
+		hostsFree := []time.Time{}
+		for _, h := range hostsByDistro[distroId] {
+			hostsFree = append(hostsFree, hostSlotsFree(h, estimates, now)...)
+		}
+		for _, h := range hostsSpawned[distroId] {
+			hostsFree = append(hostsFree, hostSlotsFree(h, estimates, now)...)
+		}
+		for id, est := range estimateQueue(queue, hostsFree, now) {
+			if prev, ok := estimates[id]; ok {
+				if prev.queuePosition > 0 && prev.queuePosition < est.queuePosition {
+					est.queuePosition = prev.queuePosition
+				}
+				if !est.start.Before(prev.start) {
+					est.start, est.finish = prev.start, prev.finish
+				}
+			}
+			estimates[id] = est
+		}
+	}
+
+	requesters := map[string]string{}
+	for _, t := range runnableTasks {
+		requesters[t.Version] = t.Requester
+	}
+	for _, t := range running {
+		requesters[t.Version] = t.Requester
+	}
+	versionEstimates := map[string]version.Estimate{}
+	patchEstimates := map[string]patch.Estimate{}
+	for versionId, requester := range requesters {
+		tasks, err := task.Find(db.Query(bson.M{
+			task.VersionKey:   versionId,
+			task.ActivatedKey: true,
+			task.StatusKey: bson.M{"$in": []string{
+				evergreen.TaskUndispatched, evergreen.TaskDispatched, evergreen.TaskStarted,
+			}},
+		}).WithFields(task.IdKey, task.DependsOnKey, task.ExpectedDurationKey))
+		if err != nil {
+			return errors.Wrapf(err, "error finding tasks of version '%s'", versionId)
+		}
+		finish, queuePosition := estimateVersion(tasks, estimates, now)
+		if finish.IsZero() {
+			continue
+		}
+		versionEstimates[versionId] = version.Estimate{Finish: finish, QueuePosition: queuePosition}
+		if evergreen.IsPatchRequester(requester) {
+			patchEstimates[versionId] = patch.Estimate{Finish: finish, QueuePosition: queuePosition}
+		}
+	}
+
+	taskEstimates := make(map[string]task.Estimate, len(estimates))
+	taskIds := make([]string, 0, len(estimates))
+	for id, est := range estimates {
+		taskEstimates[id] = task.Estimate{Start: est.start, Finish: est.finish, QueuePosition: est.queuePosition}
+		taskIds = append(taskIds, id)
+	}
+	versionIds := make([]string, 0, len(versionEstimates))
+	for versionId := range versionEstimates {
+		versionIds = append(versionIds, versionId)
+	}
+
+	if err := task.SetEstimates(taskEstimates); err != nil {
+		return errors.Wrap(err, "error storing estimates of tasks")
+	}
+	if err := version.SetEstimates(versionEstimates); err != nil {
+		return errors.Wrap(err, "error storing estimates of versions")
+	}
+	if err := patch.SetEstimatesForVersions(patchEstimates); err != nil {
+		return errors.Wrap(err, "error storing estimates of patches")
+	}
+
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(task.ClearEstimates(taskIds))
+	catcher.Add(version.ClearEstimates(versionIds))
+	catcher.Add(patch.ClearEstimates(versionIds))
+	return errors.Wrap(catcher.Resolve(), "error clearing old estimates")
+}
+
+// hostSlotsFree returns when each of the host's task slots is expected to
+// be free: once its running task finishes, or once the host starts.
+func hostSlotsFree(h host.Host, estimates map[string]taskEstimate, now time.Time) []time.Time {
+	startup := now
+	if h.Status != evergreen.HostRunning {
+		startup = now.Add(hostStartupEstimate)
+	}
+
+	slots := 1
+	if h.Distro.HasTaskSlots() {
+		slots = h.Distro.TaskSlots()
+	}
+	free := []time.Time{}
+	for _, id := range h.RunningTaskIds() {
+		t := startup
+		if est, ok := estimates[id]; ok && est.finish.After(t) {
+			t = est.finish
+		}
+		free = append(free, t)
+	}
+	for len(free) < slots {
+		free = append(free, startup)
+	}
+	return free
+}
+
+// estimateRunningTasks expects the running tasks to take their expected
+// durations, and to still be running now if they have taken longer.
+func estimateRunningTasks(tasks []task.Task, now time.Time) map[string]taskEstimate {
+	estimates := map[string]taskEstimate{}
+	for _, t := range tasks {
+		start := t.StartTime
+		if start.Before(t.DispatchTime) {
+			start = t.DispatchTime
+		}
+		if !start.After(util.ZeroTime) {
+			start = now
+		}
+		finish := start.Add(expectedDuration(t.ExpectedDuration))
+		if finish.Before(now) {
+			finish = now
+		}
+		estimates[t.Id] = taskEstimate{start: start, finish: finish}
+	}
+	return estimates
+}
+
+// estimateQueue expects the queue to run in order, each task on the slot
+// that is free first, given when each slot of the distro's hosts is free.
+// Without any hosts, it expects one to start.
+func estimateQueue(queue []model.TaskQueueItem, hostsFree []time.Time, now time.Time) map[string]taskEstimate {
+	free := append([]time.Time{}, hostsFree...)
+	if len(free) == 0 {
+		free = append(free, now.Add(hostStartupEstimate))
+	}
+
+	estimates := map[string]taskEstimate{}
+	for idx, item := range queue {
+		next := 0
+		for i := range free {
+			if free[i].Before(free[next]) {
+				next = i
+			}
+		}
+		start := free[next]
+		if start.Before(now) {
+			start = now
+		}
+		finish := start.Add(expectedDuration(item.ExpectedDuration))
+		free[next] = finish
+		estimates[item.Id] = taskEstimate{start: start, finish: finish, queuePosition: idx + 1}
+	}
+	return estimates
+}
+
+// estimateVersion estimates when the version's unfinished tasks that are
+// neither queued nor running start, which is once the tasks they depend on
+// finish, adding them to the estimates. It returns when the last of the
+// tasks is expected to finish, and the lowest queue position of the tasks.
+func estimateVersion(tasks []task.Task, estimates map[string]taskEstimate, now time.Time) (time.Time, int) {
+	byId := map[string]task.Task{}
+	for _, t := range tasks {
+		byId[t.Id] = t
+	}
+
+	visiting := map[string]bool{}
+	var estimate func(id string) (taskEstimate, bool)
+	estimate = func(id string) (taskEstimate, bool) {
+		if est, ok := estimates[id]; ok {
+			return est, true
+		}
+		t, ok := byId[id]
+		if !ok || visiting[id] {
+			return taskEstimate{}, false
+		}
+		visiting[id] = true
+		defer delete(visiting, id)
+
+		start := now
+		for _, dep := range t.DependsOn {
+			if depEst, ok := estimate(dep.TaskId); ok && depEst.finish.After(start) {
+				start = depEst.finish
+			}
+		}
+		est := taskEstimate{start: start, finish: start.Add(expectedDuration(t.ExpectedDuration))}
+		estimates[id] = est
+		return est, true
+	}
+
+	var finish time.Time
+	queuePosition := 0
+	for _, t := range tasks {
+		est, ok := estimate(t.Id)
+		if !ok {
+			continue
+		}
+		if est.finish.After(finish) {
+			finish = est.finish
+		}
+		if est.queuePosition > 0 && (queuePosition == 0 || est.queuePosition < queuePosition) {
+			queuePosition = est.queuePosition
+		}
+	}
+	return finish, queuePosition
+}
+
+func expectedDuration(d time.Duration) time.Duration {
+	if d <= 0 {
+		return model.DefaultTaskDuration
+	}
+	return d
+}
+
+// logEstimateAccuracy logs how far the first estimates of the tasks and
+// versions that finished since they were estimated were off, since later
+// estimates converge on the actual times as the tasks run.
+func logEstimateAccuracy() error {
+	tasks, err := task.FindFinishedWithEstimates()
+	if err != nil {
+		return errors.Wrap(err, "error finding finished tasks")
+	}
+	versions, err := version.FindFinishedWithEstimates()
+	if err != nil {
+		return errors.Wrap(err, "error finding finished versions")
+	}
+	if len(tasks) == 0 && len(versions) == 0 {
+		return nil
+	}
+
+	startErrs := []time.Duration{}
+	finishErrs := []time.Duration{}
+	for _, t := range tasks {
+		start := firstEstimate(t.FirstEstimatedStart, t.EstimatedStart)
+		if !start.IsZero() && !t.StartTime.IsZero() {
+			startErrs = append(startErrs, t.StartTime.Sub(start))
+		}
+		finishErrs = append(finishErrs, t.FinishTime.Sub(firstEstimate(t.FirstEstimatedFinish, t.EstimatedFinish)))
+	}
+	versionErrs := []time.Duration{}
+	patchErrs := []time.Duration{}
+	for _, v := range versions {
+		finishErr := v.FinishTime.Sub(firstEstimate(v.FirstEstimatedFinish, v.EstimatedFinish))
+		if evergreen.IsPatchRequester(v.Requester) {
+			patchErrs = append(patchErrs, finishErr)
+		} else {
+			versionErrs = append(versionErrs, finishErr)
+		}
+	}
+
+	grip.Info(message.Fields{
+		"runner":         RunnerName,
+		"message":        "accuracy of estimates of finished tasks and versions",
+		"task_start":     estimateErrorStats(startErrs),
+		"task_finish":    estimateErrorStats(finishErrs),
+		"version_finish": estimateErrorStats(versionErrs),
+		"patch_finish":   estimateErrorStats(patchErrs),
+	})
+	return nil
+}
+
+// firstEstimate returns the first estimate, or the last one for documents
+// estimated before first estimates were stored.
+func firstEstimate(first, last time.Time) time.Time {
+	if first.IsZero() {
+		return last
+	}
+	return first
+}
+
+// estimateErrorStats summarizes how late, or early if negative, things
+// happened compared to their estimates.
+func estimateErrorStats(errs []time.Duration) message.Fields {
+	if len(errs) == 0 {
+		return message.Fields{"count": 0}
+	}
+	var total, totalAbs, maxAbs float64
+	for _, e := range errs {
+		secs := e.Seconds()
+		total += secs
+		totalAbs += math.Abs(secs)
+		maxAbs = math.Max(maxAbs, math.Abs(secs))
+	}
+	return message.Fields{
+		"count":               len(errs),
+		"mean_error_secs":     total / float64(len(errs)),
+		"mean_abs_error_secs": totalAbs / float64(len(errs)),
+		"max_abs_error_secs":  maxAbs,
+	}
+}
@@ -0,0 +1,103 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/distro"
+	"github.com/evergreen-ci/evergreen/model/host"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateQueue(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+
+	queue := []model.TaskQueueItem{
+		{Id: "t1", ExpectedDuration: 10 * time.Minute},
+		{Id: "t2", ExpectedDuration: 20 * time.Minute},
+		{Id: "t3", ExpectedDuration: 5 * time.Minute},
+		{Id: "t4"},
+	}
+	estimates := estimateQueue(queue, []time.Time{now.Add(-time.Minute), now.Add(15 * time.Minute)}, now)
+	assert.Equal(taskEstimate{start: now, finish: now.Add(10 * time.Minute), queuePosition: 1}, estimates["t1"])
+	assert.Equal(taskEstimate{start: now.Add(10 * time.Minute), finish: now.Add(30 * time.Minute), queuePosition: 2}, estimates["t2"])
+	assert.Equal(taskEstimate{start: now.Add(15 * time.Minute), finish: now.Add(20 * time.Minute), queuePosition: 3}, estimates["t3"])
+	assert.Equal(taskEstimate{start: now.Add(20 * time.Minute), finish: now.Add(20*time.Minute + model.DefaultTaskDuration), queuePosition: 4}, estimates["t4"])
+
+	// without any hosts, one is expected to start
+	estimates = estimateQueue(queue[:1], nil, now)
+	assert.Equal(now.Add(hostStartupEstimate), estimates["t1"].start)
+}
+
+func TestHostSlotsFree(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+
+	estimates := estimateRunningTasks([]task.Task{
+		{Id: "running", StartTime: now.Add(-5 * time.Minute), ExpectedDuration: 20 * time.Minute},
+		{Id: "overdue", StartTime: now.Add(-time.Hour), ExpectedDuration: 20 * time.Minute},
+	}, now)
+	assert.Equal(now.Add(15*time.Minute), estimates["running"].finish)
+	assert.Equal(now, estimates["overdue"].finish)
+
+	assert.Equal([]time.Time{now.Add(15 * time.Minute)},
+		hostSlotsFree(host.Host{Status: evergreen.HostRunning, RunningTask: "running"}, estimates, now))
+	assert.Equal([]time.Time{now},
+		hostSlotsFree(host.Host{Status: evergreen.HostRunning}, estimates, now))
+	assert.Equal([]time.Time{now.Add(hostStartupEstimate)},
+		hostSlotsFree(host.Host{Status: evergreen.HostStarting}, estimates, now))
+	assert.Equal([]time.Time{now.Add(15 * time.Minute), now, now},
+		hostSlotsFree(host.Host{
+			Status:       evergreen.HostRunning,
+			Distro:       distro.Distro{MaxConcurrentTasks: 3},
+			RunningTasks: []string{"running", "overdue"},
+		}, estimates, now))
+}
+
+func TestEstimateVersion(t *testing.T) {
+	assert := assert.New(t)
+	now := time.Now()
+
+	estimates := map[string]taskEstimate{
+		"compile": {start: now, finish: now.Add(10 * time.Minute), queuePosition: 4},
+		"lint":    {start: now, finish: now.Add(5 * time.Minute), queuePosition: 2},
+	}
+	tasks := []task.Task{
+		{Id: "compile"},
+		{Id: "lint"},
+		{Id: "test", ExpectedDuration: 30 * time.Minute, DependsOn: []task.Dependency{{TaskId: "compile"}}},
+		{Id: "package", ExpectedDuration: time.Minute, DependsOn: []task.Dependency{{TaskId: "test"}, {TaskId: "lint"}}},
+		{Id: "cross_version", ExpectedDuration: time.Minute, DependsOn: []task.Dependency{{TaskId: "other"}}},
+	}
+
+	finish, queuePosition := estimateVersion(tasks, estimates, now)
+	assert.Equal(now.Add(41*time.Minute), finish)
+	assert.Equal(2, queuePosition)
+	assert.Equal(taskEstimate{start: now.Add(10 * time.Minute), finish: now.Add(40 * time.Minute)}, estimates["test"])
+	assert.Equal(taskEstimate{start: now.Add(40 * time.Minute), finish: now.Add(41 * time.Minute)}, estimates["package"])
+	assert.Equal(taskEstimate{start: now, finish: now.Add(time.Minute)}, estimates["cross_version"])
+
+	// dependency cycles don't recurse forever
+	finish, queuePosition = estimateVersion([]task.Task{
+		{Id: "a", ExpectedDuration: time.Minute, DependsOn: []task.Dependency{{TaskId: "b"}}},
+		{Id: "b", ExpectedDuration: time.Minute, DependsOn: []task.Dependency{{TaskId: "a"}}},
+	}, map[string]taskEstimate{}, now)
+	assert.Equal(now.Add(2*time.Minute), finish)
+	assert.Equal(0, queuePosition)
+}
+
+func TestEstimateErrorStats(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(0, estimateErrorStats(nil)["count"])
+
+	stats := estimateErrorStats([]time.Duration{time.Minute, -3 * time.Minute})
+	assert.Equal(2, stats["count"])
+	assert.Equal(-60.0, stats["mean_error_secs"])
+	assert.Equal(120.0, stats["mean_abs_error_secs"])
+	assert.Equal(180.0, stats["max_abs_error_secs"])
+}
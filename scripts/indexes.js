@@ -41,6 +41,7 @@ db.patches.ensureIndex({ "branch" : 1, "create_time" : 1 })
 db.patches.ensureIndex({ "version" : 1 })
 db.patches.ensureIndex({ "author" : 1, "create_time" : 1 })
 db.patches.ensureIndex({ "github_patch_data.pr_number" : 1, "github_patch_data.base_repo" : 1,"github_patch_data.base_owner" : 1, "create_time" : 1 }, { "sparse": true })
+db.patches.ensureIndex({ "est_finish" : 1 }, { "sparse": true })
 
 //======project_ref======//
 db.project_ref.ensureIndex({ "identifier" : 1 })
@@ -76,6 +77,7 @@ db.tasks.ensureIndex({ "branch": 1, "r" : 1, "build_variant" : 1})
 db.tasks.ensureIndex({ "finish_time": 1, "_id": 1})
 db.tasks.ensureIndex({ "build_variant": 1, "branch" : 1, "order" : 1})
 db.tasks.ensureIndex({ "execution_tasks": 1})
+db.tasks.ensureIndex({ "est_finish": 1}, { "sparse": true })
 
 //======old_tasks======//
 db.old_tasks.ensureIndex({ "branch": 1, "r" : 1, "display_name" : 1})
@@ -88,6 +90,7 @@ db.versions.ensureIndex({ "builds" : 1 })
 db.versions.ensureIndex({ "identifier" : 1, "r" : 1, "order" : 1 })
 db.versions.ensureIndex({ "branch" : 1, "gitspec" : 1 })
 db.versions.ensureIndex({ "versions.build_variant_status.build_variant" : 1, "versions.build_variant_status.activated" : 1, "r": 1 })
+db.versions.ensureIndex({ "est_finish" : 1 }, { "sparse": true })
 
 //======alerts=======//
 db.alerts.ensureIndex({ "queue_status" : 1 })
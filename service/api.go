@@ -13,6 +13,7 @@ import (
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/auth"
 	"github.com/evergreen-ci/evergreen/cloud"
+	"github.com/evergreen-ci/evergreen/metrics"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/artifact"
 	"github.com/evergreen-ci/evergreen/model/event"
@@ -244,7 +245,7 @@ func (as *APIServer) checkHost(next http.HandlerFunc) http.HandlerFunc {
 
 		// if the task is attached to the context, check host-task relationship
 		t := GetTask(r)
-		if t != nil && h.RunningTask != t.Id {
+		if t != nil && !h.IsRunningTask(t.Id) {
 			as.LoggedError(w, r, http.StatusConflict,
 				errors.Errorf("Host %v should be running %v, not %v", h.Id, h.RunningTask, t.Id))
 			return
@@ -698,11 +699,14 @@ func (as *APIServer) AttachRoutes(root *mux.Router) {
 	AttachRESTHandler(root, as)
 	// attaches /rest/v2 routes
 	APIV2Prefix := evergreen.APIRoutePrefix + "/" + evergreen.RestRoutePrefix
-	route.AttachHandler(root, as.queue, as.Settings.ApiUrl, APIV2Prefix, as.Settings.SuperUsers, []byte(as.Settings.Api.GithubWebhookSecret))
+	route.AttachHandler(root, as.queue, as.Settings.ApiUrl, APIV2Prefix, as.Settings.SuperUsers, []byte(as.Settings.Api.GithubWebhookSecret), []byte(as.Settings.Gitlab.WebhookSecret))
 
 	r := root.PathPrefix("/api/2/").Subrouter()
 	r.HandleFunc("/", home)
 
+	// Prometheus metrics
+	root.Handle("/metrics", metrics.Handler()).Methods("GET")
+
 	apiRootOld := root.PathPrefix("/api/").Subrouter()
 
 	// Project lookup and validation routes
@@ -296,6 +296,10 @@ func (as *APIServer) existingPatchRequest(w http.ResponseWriter, r *http.Request
 			as.LoggedError(w, r, http.StatusInternalServerError, err)
 			return
 		}
+		if err = patchedProject.ExpandTestSharding(); err != nil {
+			as.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "error sharding tests"))
+			return
+		}
 		projectYamlBytes, err := yaml.Marshal(patchedProject)
 		if err != nil {
 			as.LoggedError(w, r, http.StatusInternalServerError, errors.Wrap(err, "error marshaling patched config"))
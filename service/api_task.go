@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -16,6 +17,7 @@ import (
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
 	"github.com/evergreen-ci/evergreen/taskrunner"
+	"github.com/evergreen-ci/evergreen/tracing"
 	"github.com/evergreen-ci/evergreen/units"
 	"github.com/evergreen-ci/evergreen/util"
 	"github.com/mongodb/grip"
@@ -56,6 +58,13 @@ func (as *APIServer) StartTask(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if t.Requester == evergreen.GitlabMRRequester && updates.PatchNewStatus == evergreen.PatchStarted {
+		job := units.NewGitlabStatusUpdateJobForPatchWithVersion(t.Version)
+		if err := as.queue.Put(job); err != nil {
+			as.LoggedError(w, r, http.StatusInternalServerError, errors.New("error queuing gitlab status api update"))
+			return
+		}
+	}
 
 	h, err := host.FindOne(host.ByRunningTaskId(t.Id))
 	if err != nil {
@@ -156,6 +165,12 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 				as.LoggedError(w, r, http.StatusInternalServerError, errors.New("couldn't queue job to update github status"))
 				return
 			}
+			if as.Settings.GithubApp.Enabled() {
+				if err = as.queue.Put(units.NewGithubCheckRunJob(t.BuildId)); err != nil {
+					as.LoggedError(w, r, http.StatusInternalServerError, errors.New("couldn't queue job to create github check run"))
+					return
+				}
+			}
 		}
 
 		if updates.PatchNewStatus == evergreen.PatchFailed || updates.PatchNewStatus == evergreen.PatchSucceeded {
@@ -166,6 +181,30 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 			}
 		}
 	}
+	if t.Requester == evergreen.GitlabMRRequester {
+		if updates.BuildNewStatus == evergreen.BuildFailed || updates.BuildNewStatus == evergreen.BuildSucceeded {
+			if err = as.queue.Put(units.NewGitlabStatusUpdateJobForBuild(t.BuildId)); err != nil {
+				as.LoggedError(w, r, http.StatusInternalServerError, errors.New("couldn't queue job to update gitlab status"))
+				return
+			}
+		}
+
+		if updates.PatchNewStatus == evergreen.PatchFailed || updates.PatchNewStatus == evergreen.PatchSucceeded {
+			if err = as.queue.Put(units.NewGitlabStatusUpdateJobForPatchWithVersion(t.Version)); err != nil {
+				as.LoggedError(w, r, http.StatusInternalServerError, errors.New("couldn't queue job to update gitlab status"))
+				return
+			}
+		}
+	}
+	if t.Requester == evergreen.RepotrackerVersionRequester || t.Requester == evergreen.TriggerRequester {
+		job := units.NewDownstreamTriggerJob(t.Id, t.Execution)
+		if err = as.queue.Put(job); err != nil {
+			grip.Error(message.WrapError(err, message.Fields{
+				"message": "problem queueing downstream triggers",
+				"task":    t.Id,
+			}))
+		}
+	}
 	// the task was aborted if it is still in undispatched.
 	// the active state should be inactive.
 	if details.Status == evergreen.TaskUndispatched {
@@ -183,7 +222,7 @@ func (as *APIServer) EndTask(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// clear the running task on the host now that the task has finished
-	if err = currentHost.ClearRunningTask(t.Id, time.Now()); err != nil {
+	if err = currentHost.ClearTask(t.Id, time.Now()); err != nil {
 		message := fmt.Errorf("error clearing running task %s for host %s : %v", t.Id, currentHost.Id, err)
 		grip.Errorf(message.Error())
 		as.LoggedError(w, r, http.StatusInternalServerError, message)
@@ -286,7 +325,11 @@ func (as *APIServer) updateTaskCost(t *task.Task, h *host.Host, finishTime time.
 // assignNextAvailableTask gets the next task from the queue and sets the running task field
 // of currentHost.
 func assignNextAvailableTask(taskQueue *model.TaskQueue, currentHost *host.Host) (*task.Task, error) {
-	if currentHost.RunningTask != "" {
+	if !currentHost.HasFreeSlot() {
+		if currentHost.Distro.HasTaskSlots() {
+			return nil, errors.Errorf("Error host %v has no free task slots, it is running %v",
+				currentHost.Id, currentHost.RunningTasks)
+		}
 		return nil, errors.Errorf("Error host %v must have an unset running task field but has running task %v",
 			currentHost.Id, currentHost.RunningTask)
 	}
@@ -343,6 +386,21 @@ func assignNextAvailableTask(taskQueue *model.TaskQueue, currentHost *host.Host)
 			continue
 		}
 
+		// hosts that run several tasks at once take the task in one of
+		// their free slots.
+		if currentHost.Distro.HasTaskSlots() {
+			ok, err := currentHost.AddRunningTask(nextTaskId)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
+			if !ok {
+				// the last free slot was taken by another task on the
+				// host; the scheduler queues the task again on its next run.
+				return nil, nil
+			}
+			return nextTask, nil
+		}
+
 		// attempt to update the host. TODO: double check Last task completed thing...
 		// TODO: get rid of last task completed field in update running task.
 		ok, err := currentHost.UpdateRunningTask(currentHost.LastTaskCompleted, nextTaskId, time.Now())
@@ -357,6 +415,41 @@ func assignNextAvailableTask(taskQueue *model.TaskQueue, currentHost *host.Host)
 	return nil, nil
 }
 
+// dispatchTask marks a task as dispatched to a host, and records in the
+// task's trace the time the task waited in its queue and its dispatch. The
+// dispatch span is stored on the task for the agent to continue the trace
+// from. Tasks that were not traced when they were created start a new trace.
+func dispatchTask(ctx context.Context, t *task.Task, h *host.Host) error {
+	taskCtx := context.Background()
+	if t.Trace != nil {
+		taskCtx = tracing.ContextWithRemoteParent(taskCtx, *t.Trace)
+	}
+	_, span := tracing.StartSpan(taskCtx, "api.DispatchTask")
+	defer span.End()
+	span.SetAttribute("task_id", t.Id)
+	span.SetAttribute("host_id", h.Id)
+	span.SetAttribute("distro_id", h.Distro.Id)
+	span.SetAttribute("request_trace_id", tracing.SpanContextFromContext(ctx).TraceID)
+
+	if err := model.MarkTaskDispatched(t, h.Id, h.Distro.Id); err != nil {
+		span.SetError(err)
+		return errors.WithStack(err)
+	}
+
+	if !util.IsZeroTime(t.ScheduledTime) {
+		_, queued := tracing.StartSpan(taskCtx, "task.Queued", tracing.WithStartTime(t.ScheduledTime))
+		queued.SetAttribute("task_id", t.Id)
+		queued.SetAttribute("distro_id", h.Distro.Id)
+		queued.EndAt(t.DispatchTime)
+	}
+
+	grip.Warning(message.WrapError(t.SetTrace(span.Context()), message.Fields{
+		"message": "problem storing trace of task dispatch",
+		"task_id": t.Id,
+	}))
+	return nil
+}
+
 // NextTask retrieves the next task's id given the host name and host secret by retrieving the task queue
 // and popping the next task off the task queue.
 func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
@@ -365,6 +458,11 @@ func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
 		ShouldExit: false,
 	}
 
+	ctx, span := tracing.StartSpan(tracing.Extract(r.Context(), r.Header), "api.NextTask")
+	defer span.End()
+	span.SetAttribute("host_id", h.Id)
+	span.SetAttribute("distro_id", h.Distro.Id)
+
 	adminSettings, err := admin.GetSettings()
 	if err != nil {
 		err = errors.Wrap(err, "error retrieving admin settings")
@@ -414,7 +512,7 @@ func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
 
 		// if the task can be dispatched and activated dispatch it
 		if t.IsDispatchable() {
-			err = errors.WithStack(model.MarkTaskDispatched(t, h.Id, h.Distro.Id))
+			err = errors.WithStack(dispatchTask(ctx, t, h))
 			if err != nil {
 				grip.Error(err)
 				as.WriteJSON(w, http.StatusInternalServerError,
@@ -426,6 +524,9 @@ func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
 		if t.Activated {
 			response.TaskId = t.Id
 			response.TaskSecret = t.Secret
+			if t.Trace != nil {
+				response.TraceParent = tracing.FormatTraceParent(*t.Trace)
+			}
 			as.WriteJSON(w, http.StatusOK, response)
 			return
 		}
@@ -444,6 +545,14 @@ func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// hosts that run several tasks at once only take another task when one
+	// of their slots is free
+	if h.Distro.HasTaskSlots() && !h.HasFreeSlot() {
+		grip.Infof("host %s has no free task slots, it is running %v", h.Id, h.RunningTasks)
+		as.WriteJSON(w, http.StatusOK, response)
+		return
+	}
+
 	// retrieve the next task off the task queue and attempt to assign it to the host.
 	// If there is already a host that has the task, it will error
 	taskQueue, err := model.FindTaskQueueForDistro(h.Distro.Id)
@@ -477,7 +586,7 @@ func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// mark the task as dispatched
-	if err := model.MarkTaskDispatched(nextTask, h.Id, h.Distro.Id); err != nil {
+	if err := dispatchTask(ctx, nextTask, h); err != nil {
 		err = errors.WithStack(err)
 		grip.Error(err)
 		as.WriteJSON(w, http.StatusInternalServerError, err)
@@ -485,6 +594,9 @@ func (as *APIServer) NextTask(w http.ResponseWriter, r *http.Request) {
 	}
 	response.TaskId = nextTask.Id
 	response.TaskSecret = nextTask.Secret
+	if nextTask.Trace != nil {
+		response.TraceParent = tracing.FormatTraceParent(*nextTask.Trace)
+	}
 	grip.Infof("assigned task %s to host %s", nextTask.Id, h.Id)
 	as.WriteJSON(w, http.StatusOK, response)
 }
@@ -42,6 +42,9 @@ type uiVersion struct {
 	ActiveTasks int
 	RepoOwner   string `json:"repo_owner"`
 	Repo        string `json:"repo_name"`
+
+	// versions created by triggers on this version
+	DownstreamVersions []version.Version `json:",omitempty"`
 }
 
 type uiPatch struct {
@@ -162,7 +165,7 @@ func getTimelineData(projectName, requester string, versionsToSkip, versionsPerP
 	data := &timelineData{}
 
 	// get the total number of versions in the database (used for pagination)
-	totalVersions, err := version.Count(version.ByProjectId(projectName))
+	totalVersions, err := version.Count(version.ByMostRecentForRequester(projectName, requester))
 	if err != nil {
 		return nil, err
 	}
@@ -17,11 +17,12 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/metrics"
 	"github.com/gorilla/mux"
 	"github.com/urfave/negroni"
 )
 
-// GetHandlerPprof returns a handler for pprof endpoints.
+// GetHandlerPprof returns a handler for pprof and metrics endpoints.
 func GetHandlerPprof(settings *evergreen.Settings) http.Handler {
 	router := mux.NewRouter()
 
@@ -37,6 +38,8 @@ func GetHandlerPprof(settings *evergreen.Settings) http.Handler {
 	root.HandleFunc("/symbol", http.HandlerFunc(symbol))
 	root.HandleFunc("/trace", http.HandlerFunc(trace))
 
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+
 	n := negroni.New()
 	n.Use(NewRecoveryLogger())
 	n.UseHandler(router)
@@ -149,7 +149,11 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 			Provider string                 `json:"provider"`
 			Settings map[string]interface{} `json:"settings"`
 		} `json:"alert_config"`
-		SetupGithubHook bool `json:"setup_github_hook"`
+		Triggers            []model.TriggerDefinition       `json:"triggers"`
+		PeriodicBuilds      []model.PeriodicBuildDefinition `json:"periodic_builds"`
+		GitTagVersions      []model.GitTagDefinition        `json:"git_tag_versions"`
+		CommentCommandUsers []string                        `json:"comment_command_users"`
+		SetupGithubHook     bool                            `json:"setup_github_hook"`
 	}{}
 
 	if err = util.ReadJSONInto(util.NewRequestReader(r), &responseRef); err != nil {
@@ -176,6 +180,26 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 			errs = append(errs, fmt.Sprintf("task regex #%d is invalid", i+1))
 		}
 	}
+	for i, trigger := range responseRef.Triggers {
+		if err := trigger.Validate(id); err != nil {
+			errs = append(errs, fmt.Sprintf("trigger #%d is invalid: %s", i+1, err.Error()))
+		}
+	}
+	periodicBuildIds := map[string]bool{}
+	for i, periodicBuild := range responseRef.PeriodicBuilds {
+		if err := periodicBuild.Validate(); err != nil {
+			errs = append(errs, fmt.Sprintf("periodic build #%d is invalid: %s", i+1, err.Error()))
+		}
+		if periodicBuildIds[periodicBuild.ID] {
+			errs = append(errs, fmt.Sprintf("periodic build #%d has duplicate id '%s'", i+1, periodicBuild.ID))
+		}
+		periodicBuildIds[periodicBuild.ID] = true
+	}
+	for i, gitTag := range responseRef.GitTagVersions {
+		if err := gitTag.Validate(); err != nil {
+			errs = append(errs, fmt.Sprintf("git tag pattern #%d is invalid: %s", i+1, err.Error()))
+		}
+	}
 	if len(errs) > 0 {
 		errMsg := ""
 		for _, err := range errs {
@@ -196,6 +220,10 @@ func (uis *UIServer) modifyProject(w http.ResponseWriter, r *http.Request) {
 	projectRef.DeactivatePrevious = responseRef.DeactivatePrevious
 	projectRef.Repo = responseRef.Repo
 	projectRef.Admins = responseRef.Admins
+	projectRef.Triggers = responseRef.Triggers
+	projectRef.PeriodicBuilds = responseRef.PeriodicBuilds
+	projectRef.GitTagVersions = responseRef.GitTagVersions
+	projectRef.CommentCommandUsers = responseRef.CommentCommandUsers
 	projectRef.Identifier = id
 
 	projectRef.Alerts = map[string][]model.AlertConfig{}
@@ -161,8 +161,19 @@ func (restapi restAPI) getTaskInfo(w http.ResponseWriter, r *http.Request) {
 		return
 
 	}
+	settings := restapi.GetSettings()
+	u := GetUser(r)
 	for _, entry := range entries {
-		for _, _file := range entry.Files {
+		// files aren't stripped by visibility, since evergreen fetch
+		// downloads all of them
+		files, err := model.SignArtifactLinks(srcTask.Id, entry.Files, projCtx.ProjectRef, u, &settings)
+		if err != nil {
+			msg := fmt.Sprintf("Error signing artifact links for task '%v'", srcTask.Id)
+			grip.Errorf("%v: %+v", msg, err)
+			restapi.WriteJSON(w, http.StatusInternalServerError, responseError{Message: msg})
+			return
+		}
+		for _, _file := range files {
 			file := taskFile{
 				Name: _file.Name,
 				URL:  _file.Link,
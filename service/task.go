@@ -12,6 +12,7 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/apimodels"
 	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
 	"github.com/evergreen-ci/evergreen/model/event"
 	"github.com/evergreen-ci/evergreen/model/host"
 	"github.com/evergreen-ci/evergreen/model/task"
@@ -535,6 +536,38 @@ func (uis *UIServer) taskLog(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// downloadArtifact records that a task's artifact was downloaded through a
+// signed download link, and redirects to an expiring S3 link for it. The
+// link's signature authorizes the download, so it doesn't need a user.
+func (uis *UIServer) downloadArtifact(w http.ResponseWriter, r *http.Request) {
+	if !uis.Settings.ArtifactSigning.Enabled() {
+		http.Error(w, "Not found", http.StatusNotFound)
+		return
+	}
+	signer, err := artifact.NewLinkSigner(uis.Settings.ArtifactSigning)
+	if err != nil {
+		uis.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	download, err := signer.VerifyDownload(mux.Vars(r)["task_id"], r.URL.Query(), time.Now())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+	signed, ok, err := signer.Sign(download.File)
+	if err != nil {
+		uis.LoggedError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if !ok {
+		http.Error(w, "artifact is not stored in S3", http.StatusBadRequest)
+		return
+	}
+
+	event.LogTaskArtifactDownloaded(download.TaskID, download.UserID, download.File.Name)
+	http.Redirect(w, r, signed.Link, http.StatusFound)
+}
+
 func (uis *UIServer) taskLogRaw(w http.ResponseWriter, r *http.Request) {
 	projCtx := MustHaveProjectContext(r)
 
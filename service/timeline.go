@@ -8,10 +8,20 @@ import (
 	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/util"
 	"github.com/gorilla/mux"
 	"github.com/pkg/errors"
 )
 
+// timelineRequesters are the requesters of the versions that the timeline
+// can be filtered to.
+var timelineRequesters = []string{
+	evergreen.RepotrackerVersionRequester,
+	evergreen.TriggerRequester,
+	evergreen.PeriodicBuildRequester,
+	evergreen.GitTagRequester,
+}
+
 func (uis *UIServer) timelineJson(w http.ResponseWriter, r *http.Request) {
 	projCtx := MustHaveProjectContext(r)
 	project, err := projCtx.GetProject()
@@ -20,8 +30,20 @@ func (uis *UIServer) timelineJson(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the timeline shows the versions created by the repotracker, unless it
+	// is filtered to the versions created by triggers, periodic builds or git
+	// tags
+	requester := r.FormValue("requester")
+	if requester == "" {
+		requester = evergreen.RepotrackerVersionRequester
+	}
+	if !util.StringSliceContains(timelineRequesters, requester) {
+		http.Error(w, fmt.Sprintf("invalid requester '%s'", requester), http.StatusBadRequest)
+		return
+	}
+
 	skip, perPage := getSkipAndLimit(r, DefaultSkip, DefaultLimit)
-	data, err := getTimelineData(project.Identifier, evergreen.RepotrackerVersionRequester, skip, perPage)
+	data, err := getTimelineData(project.Identifier, requester, skip, perPage)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error getting timeline data: %v", err.Error()), http.StatusInternalServerError)
 		return
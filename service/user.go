@@ -112,6 +112,11 @@ func (uis *UIServer) userSettingsModify(w http.ResponseWriter, r *http.Request)
 		uis.LoggedError(w, r, http.StatusBadRequest, err)
 		return
 	}
+	if userSettings.SpawnHostAutoStopHours < 0 {
+		uis.LoggedError(w, r, http.StatusBadRequest,
+			errors.New("Spawn host auto-stop hours can not be negative"))
+		return
+	}
 
 	if err := model.SaveUserSettings(currentUser.Username(), userSettings); err != nil {
 		uis.LoggedError(w, r, http.StatusInternalServerError,
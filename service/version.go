@@ -34,6 +34,13 @@ func (uis *UIServer) versionPage(w http.ResponseWriter, r *http.Request) {
 		Repo:      projCtx.ProjectRef.Repo,
 	}
 
+	versionAsUI.DownstreamVersions, err = version.Find(version.ByUpstreamVersion(projCtx.Version.Id).
+		WithFields(version.IdKey, version.IdentifierKey, version.RevisionKey, version.StatusKey))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	dbBuilds, err := build.Find(build.ByIds(projCtx.Version.BuildIds))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
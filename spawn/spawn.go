@@ -255,6 +255,37 @@ func TerminateHost(host *host.Host, settings *evergreen.Settings) error {
 	return nil
 }
 
+// StopHost stops a running spawn host through its cloud provider, keeping its
+// disks so that it can be started again.
+func StopHost(host *host.Host, settings *evergreen.Settings) error {
+	if host.Status == evergreen.HostStopped || host.Status == evergreen.HostStopping {
+		return errors.New("Host is already stopped")
+	}
+	if host.Status != evergreen.HostRunning {
+		return errors.Errorf("Host is %s, only running hosts can be stopped", host.Status)
+	}
+	cloudHost, err := cloud.GetCloudHost(host, settings)
+	if err != nil {
+		return err
+	}
+	return cloudHost.StopInstance()
+}
+
+// StartHost starts a stopped spawn host through its cloud provider.
+func StartHost(host *host.Host, settings *evergreen.Settings) error {
+	if host.Status == evergreen.HostStopping {
+		return errors.New("Host is still stopping")
+	}
+	if host.Status != evergreen.HostStopped {
+		return errors.Errorf("Host is %s, only stopped hosts can be started", host.Status)
+	}
+	cloudHost, err := cloud.GetCloudHost(host, settings)
+	if err != nil {
+		return err
+	}
+	return cloudHost.StartInstance()
+}
+
 func MakeExtendedHostExpiration(host *host.Host, extendBy time.Duration) (time.Time, error) {
 	newExp := host.ExpirationTime.Add(extendBy)
 	remainingDuration := newExp.Sub(time.Now()) //nolint
@@ -18,6 +18,7 @@ type localCmd struct {
 	Shell            string    `json:"shell"`
 	Environment      []string  `json:"environment"`
 	ScriptMode       bool      `json:"script"`
+	Container        string    `json:"container,omitempty"`
 	Stdout           io.Writer `json:"-"`
 	Stderr           io.Writer `json:"-"`
 	cmd              *exec.Cmd
@@ -34,6 +35,21 @@ func NewLocalCommand(cmdString, workingDir, shell string, env []string, scriptMo
 	}
 }
 
+// NewContainerCommand returns a command that runs in the given container
+// with docker exec, rather than on the host. The working directory must be
+// mounted in the container at the same path. Only the variables in env are
+// set in the container's environment.
+func NewContainerCommand(container, cmdString, workingDir, shell string, env []string, scriptMode bool) Command {
+	return &localCmd{
+		CmdString:        cmdString,
+		WorkingDirectory: workingDir,
+		Shell:            shell,
+		Environment:      env,
+		ScriptMode:       scriptMode,
+		Container:        container,
+	}
+}
+
 func (lc *localCmd) Run(ctx context.Context) error {
 	err := lc.Start(ctx)
 	if err != nil {
@@ -86,6 +102,10 @@ func (lc *localCmd) Start(ctx context.Context) error {
 		lc.Shell = "sh"
 	}
 
+	if lc.Container != "" {
+		return lc.startInContainer(ctx)
+	}
+
 	var cmd *exec.Cmd
 	if lc.ScriptMode {
 		cmd = exec.CommandContext(ctx, lc.Shell)
@@ -112,6 +132,45 @@ func (lc *localCmd) Start(ctx context.Context) error {
 	return cmd.Start()
 }
 
+// startInContainer starts the command in the container with docker exec.
+// It must be called with the lock held.
+func (lc *localCmd) startInContainer(ctx context.Context) error {
+	args := containerExecArgs(lc.Container, lc.WorkingDirectory, lc.Environment)
+	args = append(args, lc.Shell)
+
+	var cmd *exec.Cmd
+	if lc.ScriptMode {
+		cmd = exec.CommandContext(ctx, "docker", args...)
+		cmd.Stdin = strings.NewReader(lc.CmdString)
+	} else {
+		cmd = exec.CommandContext(ctx, "docker", append(args, "-c", lc.CmdString)...)
+	}
+	// the values are passed through the docker client's environment so
+	// that they don't show up in its arguments
+	cmd.Env = append(os.Environ(), lc.Environment...)
+	cmd.Stdout = lc.Stdout
+	cmd.Stderr = lc.Stderr
+
+	lc.cmd = cmd
+
+	return cmd.Start()
+}
+
+// containerExecArgs returns the docker arguments that run a process in the
+// container with the given working directory and environment. Only the
+// names of the variables are passed, so docker takes their values from its
+// own environment.
+func containerExecArgs(container, workingDir string, env []string) []string {
+	args := []string{"exec", "--interactive"}
+	if workingDir != "" {
+		args = append(args, "--workdir", workingDir)
+	}
+	for _, e := range env {
+		args = append(args, "--env", strings.SplitN(e, "=", 2)[0])
+	}
+	return append(args, container)
+}
+
 func (lc *localCmd) Stop() error {
 	lc.mutex.RLock()
 	defer lc.mutex.RUnlock()
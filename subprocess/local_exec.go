@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"sort"
 	"sync"
 
 	"github.com/mongodb/grip"
@@ -61,6 +62,25 @@ func NewLocalExec(binary string, args []string, env map[string]string, workingdi
 	return c, nil
 }
 
+// NewContainerExec returns a command that runs the binary in the given
+// container with docker exec. The working directory must be mounted in the
+// container at the same path. Only the variables in env are set in the
+// container's environment.
+func NewContainerExec(container, binary string, args []string, env map[string]string, workingdir string) (Command, error) {
+	if container == "" {
+		return nil, errors.New("must specify a container")
+	}
+
+	envList := []string{}
+	for k, v := range env {
+		envList = append(envList, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(envList)
+
+	dockerArgs := append(containerExecArgs(container, workingdir, envList), binary)
+	return NewLocalExec("docker", append(dockerArgs, args...), env, workingdir)
+}
+
 func (c *localExec) Run(ctx context.Context) error {
 	if err := c.Start(ctx); err != nil {
 		return errors.WithStack(err)
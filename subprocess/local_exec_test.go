@@ -71,3 +71,21 @@ func TestLocalExec(t *testing.T) {
 	assert.NoError(cmd.Stop())
 
 }
+
+func TestContainerExec(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewContainerExec("", "make", nil, nil, "")
+	assert.Error(err)
+
+	cmd, err := NewContainerExec("evg-task", "make", []string{"test"},
+		map[string]string{"B": "2", "A": "1"}, "")
+	assert.NoError(err)
+	exec, ok := cmd.(*localExec)
+	assert.True(ok)
+	assert.Equal("docker", exec.binary)
+	assert.Equal([]string{"exec", "--interactive", "--env", "A", "--env", "B",
+		"evg-task", "make", "test"}, exec.args)
+	assert.Contains(exec.env, "A=1")
+	assert.Contains(exec.env, "B=2")
+}
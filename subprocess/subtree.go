@@ -13,21 +13,14 @@ const (
 	MarkerAgentPID = "EVR_AGENT_PID"
 )
 
+// markerMatcher reports whether a process with the given environment was
+// started for the task with the given key.
+type markerMatcher func(key string, env []string) bool
+
 func envHasMarkers(key string, env []string) bool {
 	// If this agent was started by an integration test, only kill a proc if it was started by this agent
 	if os.Getenv(testutil.EnvAll) != "" {
-		for _, envVar := range env {
-			if strings.HasPrefix(envVar, MarkerTaskID) {
-				split := strings.Split(envVar, "=")
-				if len(split) != 2 {
-					continue
-				}
-				if split[1] == key {
-					return true
-				}
-			}
-		}
-		return false
+		return envHasTaskMarker(key, env)
 	}
 
 	// Otherwise, kill any proc started by any agent
@@ -39,9 +32,31 @@ func envHasMarkers(key string, env []string) bool {
 	return false
 }
 
+// envHasTaskMarker returns true only for procs started for the given task.
+func envHasTaskMarker(key string, env []string) bool {
+	for _, envVar := range env {
+		if strings.HasPrefix(envVar, MarkerTaskID) {
+			split := strings.Split(envVar, "=")
+			if len(split) != 2 {
+				continue
+			}
+			if split[1] == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // KillSpawnedProcs cleans up any tasks that were spawned by the given task.
 func KillSpawnedProcs(key string, logger grip.Journaler) error {
 	// Clean up all shell processes spawned during the execution of this task by this agent,
 	// by calling the platform-specific "cleanup" function
-	return cleanup(key, logger)
+	return cleanup(key, envHasMarkers, logger)
+}
+
+// KillTaskProcs cleans up only the processes that were spawned by the given
+// task, leaving those of any other tasks that the agent is running.
+func KillTaskProcs(key string, logger grip.Journaler) error {
+	return cleanup(key, envHasTaskMarker, logger)
 }
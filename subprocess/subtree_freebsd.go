@@ -19,7 +19,7 @@ func TrackProcess(key string, pid int, logger grip.Journaler) {
 	// cleanup() and we don't need to do any special bookkeeping up-front.
 }
 
-func cleanup(key string, logger grip.Journaler) error {
+func cleanup(key string, matches markerMatcher, logger grip.Journaler) error {
 	out, err := exec.Command("ps", "-E", "-e", "-o", "pid,command").CombinedOutput()
 	if err != nil {
 		m := "cleanup failed to get output of 'ps'"
@@ -40,7 +40,7 @@ func cleanup(key string, logger grip.Journaler) error {
 		pid := splitLine[0]
 		env := splitLine[2:]
 
-		if pid != myPid && envHasMarkers(key, env) {
+		if pid != myPid && matches(key, env) {
 			// add it to the list of processes to clean up
 			pidAsInt, err := strconv.Atoi(pid)
 			if err != nil {
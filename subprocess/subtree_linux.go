@@ -36,7 +36,7 @@ func getEnv(pid int) ([]string, error) {
 	return results, nil
 }
 
-func cleanup(key string, logger grip.Journaler) error {
+func cleanup(key string, matches markerMatcher, logger grip.Journaler) error {
 	myPid := os.Getpid()
 	pids, err := listProc()
 	if err != nil {
@@ -48,7 +48,7 @@ func cleanup(key string, logger grip.Journaler) error {
 		if err != nil {
 			continue
 		}
-		if pid != myPid && envHasMarkers(key, env) {
+		if pid != myPid && matches(key, env) {
 			p := os.Process{}
 			p.Pid = pid
 			if err := p.Kill(); err != nil {
@@ -12,6 +12,7 @@ import (
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/logging"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestSubtreeCleanup(t *testing.T) {
@@ -40,3 +41,12 @@ func TestSubtreeCleanup(t *testing.T) {
 		})
 	})
 }
+
+func TestEnvHasTaskMarker(t *testing.T) {
+	assert := assert.New(t)
+	env := []string{"PATH=/bin", "EVR_TASK_ID=task_one", "EVR_AGENT_PID=12345"}
+
+	assert.True(envHasTaskMarker("task_one", env))
+	assert.False(envHasTaskMarker("task_two", env))
+	assert.False(envHasTaskMarker("task_one", []string{"PATH=/bin", "EVR_AGENT_PID=12345"}))
+}
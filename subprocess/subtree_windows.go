@@ -155,7 +155,8 @@ func TrackProcess(taskId string, pid int, logger grip.Journaler) {
 // cleanup() has a windows-specific implementation which finds the job object associated with the
 // given task key, and if it exists, terminates it. This will guarantee that any shell processes
 // started throughout the task run are destroyed, as long as they were captured in trackProcess.
-func cleanup(key string, logger grip.Journaler) error {
+// The job objects are already kept per task, so the matcher is not needed.
+func cleanup(key string, matches markerMatcher, logger grip.Journaler) error {
 	job, err := processMapping.getJob(key)
 	if err != nil {
 		return nil
@@ -44,3 +44,10 @@ func listProc() ([]int, error) {
 	}
 	return results, nil
 }
+
+// HasTaskMarker reports whether the process with the given pid was started
+// for the task with the given key.
+func HasTaskMarker(key string, pid int) bool {
+	env, err := getEnv(pid)
+	return err == nil && envHasTaskMarker(key, env)
+}
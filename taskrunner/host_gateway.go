@@ -26,6 +26,9 @@ const (
 	// SSHTimeout defines the timeout for the SSH commands in this package.
 	sshTimeout = 30 * time.Second
 	agentFile  = "agent"
+
+	// agentStatusPort is the default port of the agent's status server.
+	agentStatusPort = 2285
 )
 
 // HostGateway is responsible for kicking off tasks on remote machines.
@@ -187,27 +190,66 @@ func (agbh *AgentHostGateway) prepRemoteHost(ctx context.Context, hostObj host.H
 	return agbh.GetAgentRevision()
 }
 
-// Start the agent process on the specified remote host.
-func startAgentOnRemote(settings *evergreen.Settings, hostObj *host.Host, sshOptions []string) error {
+// agentCommands returns the commands that start the agents on the host.
+// Hosts of distros that run tasks in containers run one agent for each
+// container slot, each with its own working directory and status port,
+// while hosts of distros with max concurrent tasks run a single agent that
+// runs that many tasks at once.
+func agentCommands(settings *evergreen.Settings, hostObj *host.Host) []string {
 	// the path to the agent binary on the remote machine
 	pathToExecutable := filepath.Join("~", "evergreen")
 	if hostutil.IsWindows(&hostObj.Distro) {
 		pathToExecutable += ".exe"
 	}
 
-	agentCmdParts := []string{
-		pathToExecutable,
-		"agent",
-		fmt.Sprintf("--api_server='%s'", settings.ApiUrl),
-		fmt.Sprintf("--host_id='%s'", hostObj.Id),
-		fmt.Sprintf("--host_secret='%s'", hostObj.Secret),
-		fmt.Sprintf("--log_prefix='%s'", filepath.Join(hostObj.Distro.WorkDir, agentFile)),
-		fmt.Sprintf("--working_directory='%s'", hostObj.Distro.WorkDir),
-		"--cleanup",
+	numAgents := 1
+	if hostObj.Distro.UsesContainers() {
+		numAgents = hostObj.Distro.TaskSlots()
 	}
 
-	// build the command to run on the remote machine
-	remoteCmd := strings.Join(agentCmdParts, " ")
+	cmds := []string{}
+	for slot := 0; slot < numAgents; slot++ {
+		workDir := hostObj.Distro.WorkDir
+		if hostObj.Distro.UsesContainers() {
+			workDir = filepath.Join(workDir, fmt.Sprintf("slot_%d", slot))
+		}
+
+		agentCmdParts := []string{
+			pathToExecutable,
+			"agent",
+			fmt.Sprintf("--api_server='%s'", settings.ApiUrl),
+			fmt.Sprintf("--host_id='%s'", hostObj.Id),
+			fmt.Sprintf("--host_secret='%s'", hostObj.Secret),
+			fmt.Sprintf("--log_prefix='%s'", filepath.Join(workDir, agentFile)),
+			fmt.Sprintf("--working_directory='%s'", workDir),
+			"--cleanup",
+		}
+		if hostObj.Distro.UsesContainers() {
+			agentCmdParts = append(agentCmdParts, fmt.Sprintf("--status_port=%d", agentStatusPort+slot))
+		} else if hostObj.Distro.TaskSlots() > 1 {
+			agentCmdParts = append(agentCmdParts, fmt.Sprintf("--max_concurrent_tasks=%d", hostObj.Distro.TaskSlots()))
+		}
+		cmds = append(cmds, strings.Join(agentCmdParts, " "))
+	}
+
+	return cmds
+}
+
+// Start the agent processes on the specified remote host.
+func startAgentOnRemote(settings *evergreen.Settings, hostObj *host.Host, sshOptions []string) error {
+	for _, remoteCmd := range agentCommands(settings, hostObj) {
+		if err := startAgentProcess(settings, hostObj, sshOptions, remoteCmd); err != nil {
+			return err
+		}
+	}
+
+	event.LogHostAgentDeployed(hostObj.Id)
+
+	return nil
+}
+
+// startAgentProcess runs the command that starts an agent on the remote host.
+func startAgentProcess(settings *evergreen.Settings, hostObj *host.Host, sshOptions []string, remoteCmd string) error {
 	grip.Info(message.Fields{
 		"message": "starting agent on host",
 		"host":    hostObj.Id,
@@ -274,7 +316,5 @@ func startAgentOnRemote(settings *evergreen.Settings, hostObj *host.Host, sshOpt
 		return errors.Wrapf(err, "error starting agent (%v): %v", hostObj.Id, cmdOutBuff.String())
 	}
 
-	event.LogHostAgentDeployed(hostObj.Id)
-
 	return nil
 }
@@ -2,6 +2,7 @@ package thirdparty
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -286,6 +287,39 @@ func GetBranchEvent(oauthToken, repoOwner, repo, branch string) (*BranchEvent,
 	return branchEvent, nil
 }
 
+// GetGithubTags returns the most recent page of a repository's tags.
+func GetGithubTags(oauthToken, repoOwner, repo string) ([]GithubTag, error) {
+	tagsURL := fmt.Sprintf("%v/repos/%v/%v/tags", GithubAPIBase, repoOwner, repo)
+
+	resp, err := tryGithubGet(oauthToken, tagsURL)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
+	if err != nil {
+		return nil, APIResponseError{fmt.Sprintf("error querying '%v': %v", tagsURL, err)}
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, ResponseReadError{err.Error()}
+	}
+	grip.Debugf("Github API response: %s. %d bytes", resp.Status, len(respBody))
+
+	if resp.StatusCode != http.StatusOK {
+		requestError := APIRequestError{}
+		if err = json.Unmarshal(respBody, &requestError); err != nil {
+			return nil, APIRequestError{Message: string(respBody)}
+		}
+		return nil, requestError
+	}
+
+	tags := []GithubTag{}
+	if err = json.Unmarshal(respBody, &tags); err != nil {
+		return nil, APIUnmarshalError{string(respBody), err.Error()}
+	}
+	return tags, nil
+}
+
 // githubRequest performs the specified http request. If the oauth token field is empty it will not use oauth
 func githubRequest(method string, url string, oauthToken string, data interface{}) (*http.Response, error) {
 	req, err := http.NewRequest(method, url, nil)
@@ -401,6 +435,42 @@ func GetGithubFileURL(owner, repo, remotePath, revision string) string {
 	)
 }
 
+// GetGithubFileContents returns the decoded contents of a file within a
+// repository at the given revision.
+func GetGithubFileContents(oauthToken, owner, repo, remotePath, revision string) ([]byte, error) {
+	githubFile, err := GetGithubFile(oauthToken, GetGithubFileURL(owner, repo, remotePath, revision))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(githubFile.Content)
+	if err != nil {
+		return nil, FileDecodeError{err.Error()}
+	}
+	return data, nil
+}
+
+// ParseGithubRepoURL returns the owner and name of a github repository from
+// its clone URL, in either the "git@github.com:owner/repo.git" or the
+// "https://github.com/owner/repo" form.
+func ParseGithubRepoURL(uri string) (owner, repo string, err error) {
+	path := strings.TrimSuffix(uri, ".git")
+	switch {
+	case strings.HasPrefix(path, "git@github.com:"):
+		path = strings.TrimPrefix(path, "git@github.com:")
+	case strings.HasPrefix(path, GithubBase+"/"):
+		path = strings.TrimPrefix(path, GithubBase+"/")
+	default:
+		return "", "", errors.Errorf("'%s' is not a github repository", uri)
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf("'%s' is not a github repository", uri)
+	}
+	return parts[0], parts[1], nil
+}
+
 // NextPageLink returns the link to the next page for a given header's 'Link'
 // key based on http://developer.github.com/v3/#pagination
 // For full details see http://tools.ietf.org/html/rfc5988
@@ -0,0 +1,241 @@
+package thirdparty
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	GithubCheckRunCompleted = "completed"
+
+	GithubCheckRunSuccess = "success"
+	GithubCheckRunFailure = "failure"
+
+	GithubAnnotationFailure = "failure"
+
+	// GithubCheckRunEvent is the webhook event sent when a check run is
+	// created, or when a user requests one of its actions.
+	GithubCheckRunEvent = "check_run"
+
+	// GithubCheckRunRequestedAction is the action of a check run webhook
+	// event when a user clicks one of the check run's action buttons.
+	GithubCheckRunRequestedAction = "requested_action"
+
+	// GithubCheckRunRerunFailed identifies the check run action that
+	// restarts the failed tasks of the build the check run is for.
+	GithubCheckRunRerunFailed = "rerun-failed"
+
+	// GithubMaxCheckRunAnnotations is the maximum number of annotations
+	// GitHub accepts in a single check run request.
+	GithubMaxCheckRunAnnotations = 50
+
+	// the checks API is in preview, and apps are authenticated through the
+	// machine man preview
+	githubChecksMediaType = "application/vnd.github.antiope-preview+json"
+	githubAppsMediaType   = "application/vnd.github.machine-man-preview+json"
+
+	// GitHub rejects app tokens that expire more than 10 minutes out
+	githubAppJWTExpiration = 9 * time.Minute
+)
+
+// GithubCheckRun is a check run created through the GitHub checks API.
+type GithubCheckRun struct {
+	ID          int64                  `json:"id,omitempty"`
+	Name        string                 `json:"name"`
+	HeadSHA     string                 `json:"head_sha"`
+	ExternalID  string                 `json:"external_id,omitempty"`
+	DetailsURL  string                 `json:"details_url,omitempty"`
+	Status      string                 `json:"status,omitempty"`
+	Conclusion  string                 `json:"conclusion,omitempty"`
+	CompletedAt *time.Time             `json:"completed_at,omitempty"`
+	Output      *GithubCheckRunOutput  `json:"output,omitempty"`
+	Actions     []GithubCheckRunAction `json:"actions,omitempty"`
+}
+
+type GithubCheckRunOutput struct {
+	Title       string                     `json:"title"`
+	Summary     string                     `json:"summary"`
+	Annotations []GithubCheckRunAnnotation `json:"annotations,omitempty"`
+}
+
+type GithubCheckRunAnnotation struct {
+	Path            string `json:"path"`
+	StartLine       int    `json:"start_line"`
+	EndLine         int    `json:"end_line"`
+	AnnotationLevel string `json:"annotation_level"`
+	Title           string `json:"title,omitempty"`
+	Message         string `json:"message"`
+	RawDetails      string `json:"raw_details,omitempty"`
+}
+
+// GithubCheckRunAction is a button shown on a check run, which sends a
+// check run webhook event with its identifier when clicked.
+type GithubCheckRunAction struct {
+	Label       string `json:"label"`
+	Description string `json:"description"`
+	Identifier  string `json:"identifier"`
+}
+
+// GithubCheckRunWebhook is the payload of a check run webhook event.
+type GithubCheckRunWebhook struct {
+	Action          string         `json:"action"`
+	CheckRun        GithubCheckRun `json:"check_run"`
+	RequestedAction *struct {
+		Identifier string `json:"identifier"`
+	} `json:"requested_action,omitempty"`
+}
+
+// NewGithubAppJWT returns a token that authenticates as the GitHub App,
+// signed with the app's PEM encoded private key.
+func NewGithubAppJWT(appID int64, privateKey string, now time.Time) (string, error) {
+	block, _ := pem.Decode([]byte(privateKey))
+	if block == nil {
+		return "", errors.New("github app private key is not PEM encoded")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return "", errors.Wrap(err, "error parsing github app private key")
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	claims, err := json.Marshal(map[string]int64{
+		// allow for clock drift between us and github
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(githubAppJWTExpiration).Unix(),
+		"iss": appID,
+	})
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", errors.Wrap(err, "error signing github app token")
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// GetGithubAppInstallationToken returns an access token for the installation
+// of the GitHub App on the repository.
+func GetGithubAppInstallationToken(ctx context.Context, conf evergreen.GithubAppConfig, owner, repo string) (string, error) {
+	jwt, err := NewGithubAppJWT(conf.AppID, conf.PrivateKey, time.Now())
+	if err != nil {
+		return "", err
+	}
+	authorization := "Bearer " + jwt
+	baseURL := githubAppAPIBase(conf)
+
+	installation := struct {
+		ID int64 `json:"id"`
+	}{}
+	err = githubAppRequest(ctx, http.MethodGet, fmt.Sprintf("%s/repos/%s/%s/installation", baseURL, owner, repo),
+		authorization, githubAppsMediaType, nil, &installation)
+	if err != nil {
+		return "", errors.Wrapf(err, "error finding github app installation for %s/%s", owner, repo)
+	}
+
+	token := struct {
+		Token string `json:"token"`
+	}{}
+	err = githubAppRequest(ctx, http.MethodPost, fmt.Sprintf("%s/app/installations/%d/access_tokens", baseURL, installation.ID),
+		authorization, githubAppsMediaType, nil, &token)
+	if err != nil {
+		return "", errors.Wrapf(err, "error creating token for github app installation %d", installation.ID)
+	}
+	if token.Token == "" {
+		return "", errors.Errorf("github returned an empty token for installation %d", installation.ID)
+	}
+
+	return token.Token, nil
+}
+
+// CreateGithubCheckRun creates the check run on the repository, and returns
+// its id.
+func CreateGithubCheckRun(ctx context.Context, conf evergreen.GithubAppConfig, installationToken, owner, repo string, run *GithubCheckRun) (int64, error) {
+	created := GithubCheckRun{}
+	err := githubAppRequest(ctx, http.MethodPost, fmt.Sprintf("%s/repos/%s/%s/check-runs", githubAppAPIBase(conf), owner, repo),
+		"token "+installationToken, githubChecksMediaType, run, &created)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error creating check run '%s' for %s/%s", run.Name, owner, repo)
+	}
+
+	return created.ID, nil
+}
+
+func githubAppAPIBase(conf evergreen.GithubAppConfig) string {
+	if conf.APIURL != "" {
+		return strings.TrimSuffix(conf.APIURL, "/")
+	}
+	return GithubAPIBase
+}
+
+func githubAppRequest(ctx context.Context, method, url, authorization, mediaType string, data, out interface{}) error {
+	var body []byte
+	if data != nil {
+		var err error
+		body, err = json.Marshal(data)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Add("Authorization", authorization)
+	req.Header.Add("Accept", mediaType)
+	req.Header.Add("Content-Type", "application/json")
+
+	client := util.GetHttpClient()
+	defer util.PutHttpClient(client)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return ResponseReadError{err.Error()}
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		requestError := APIRequestError{}
+		if err = json.Unmarshal(respBody, &requestError); err != nil || requestError.Message == "" {
+			requestError = APIRequestError{Message: string(respBody)}
+		}
+		return errors.Wrapf(requestError, "github responded %s", resp.Status)
+	}
+
+	if out != nil {
+		if err = json.Unmarshal(respBody, out); err != nil {
+			return APIUnmarshalError{string(respBody), err.Error()}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package thirdparty
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeGithub serves the parts of the GitHub API used to create check runs.
+type fakeGithub struct {
+	appKey   *rsa.PublicKey
+	checkRun GithubCheckRun
+	failRuns bool
+}
+
+func (f *fakeGithub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	authorization := r.Header.Get("Authorization")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/repos/evergreen-ci/evergreen/installation":
+		if !f.validJWT(strings.TrimPrefix(authorization, "Bearer ")) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": 42}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/app/installations/42/access_tokens":
+		if !f.validJWT(strings.TrimPrefix(authorization, "Bearer ")) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"token": "installation-token"}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/evergreen-ci/evergreen/check-runs":
+		if authorization != "token installation-token" || f.failRuns {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"message": "Invalid request"}`))
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&f.checkRun); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 7}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeGithub) validJWT(token string) bool {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	return rsa.VerifyPKCS1v15(f.appKey, crypto.SHA256, hash[:], signature) == nil
+}
+
+type githubChecksSuite struct {
+	github *fakeGithub
+	server *httptest.Server
+	conf   evergreen.GithubAppConfig
+	suite.Suite
+}
+
+func TestGithubChecks(t *testing.T) {
+	suite.Run(t, new(githubChecksSuite))
+}
+
+func (s *githubChecksSuite) SetupSuite() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+
+	s.github = &fakeGithub{appKey: &key.PublicKey}
+	s.server = httptest.NewServer(s.github)
+	s.conf = evergreen.GithubAppConfig{
+		AppID: 1234,
+		PrivateKey: string(pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(key),
+		})),
+		APIURL: s.server.URL + "/",
+	}
+}
+
+func (s *githubChecksSuite) TearDownSuite() {
+	s.server.Close()
+}
+
+func (s *githubChecksSuite) SetupTest() {
+	s.github.checkRun = GithubCheckRun{}
+	s.github.failRuns = false
+}
+
+func (s *githubChecksSuite) TestJWTClaims() {
+	now := time.Now()
+	token, err := NewGithubAppJWT(s.conf.AppID, s.conf.PrivateKey, now)
+	s.Require().NoError(err)
+	s.True(s.github.validJWT(token))
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[1])
+	s.Require().NoError(err)
+	claims := map[string]int64{}
+	s.Require().NoError(json.Unmarshal(payload, &claims))
+	s.Equal(int64(1234), claims["iss"])
+	s.True(claims["iat"] <= now.Unix())
+	s.True(claims["exp"] <= now.Add(10*time.Minute).Unix())
+
+	_, err = NewGithubAppJWT(s.conf.AppID, "not a key", now)
+	s.Error(err)
+}
+
+func (s *githubChecksSuite) TestInstallationToken() {
+	token, err := GetGithubAppInstallationToken(context.Background(), s.conf, "evergreen-ci", "evergreen")
+	s.NoError(err)
+	s.Equal("installation-token", token)
+
+	_, err = GetGithubAppInstallationToken(context.Background(), s.conf, "evergreen-ci", "other")
+	s.Error(err)
+}
+
+func (s *githubChecksSuite) TestCreateCheckRun() {
+	completedAt := time.Now().Round(time.Second)
+	run := &GithubCheckRun{
+		Name:        "evergreen/ubuntu",
+		HeadSHA:     "abcdef",
+		ExternalID:  "build",
+		Status:      GithubCheckRunCompleted,
+		Conclusion:  GithubCheckRunFailure,
+		CompletedAt: &completedAt,
+		Output: &GithubCheckRunOutput{
+			Title:   "1 failed",
+			Summary: "1 of 1 tasks failed",
+			Annotations: []GithubCheckRunAnnotation{
+				{Path: "test.js", StartLine: 1, EndLine: 1, AnnotationLevel: GithubAnnotationFailure, Message: "logs"},
+			},
+		},
+		Actions: []GithubCheckRunAction{
+			{Label: "Re-run failed", Description: "restart", Identifier: GithubCheckRunRerunFailed},
+		},
+	}
+
+	id, err := CreateGithubCheckRun(context.Background(), s.conf, "installation-token", "evergreen-ci", "evergreen", run)
+	s.NoError(err)
+	s.Equal(int64(7), id)
+	s.Equal("evergreen/ubuntu", s.github.checkRun.Name)
+	s.Equal("build", s.github.checkRun.ExternalID)
+	s.Require().NotNil(s.github.checkRun.Output)
+	s.Len(s.github.checkRun.Output.Annotations, 1)
+	s.Require().Len(s.github.checkRun.Actions, 1)
+	s.Equal(GithubCheckRunRerunFailed, s.github.checkRun.Actions[0].Identifier)
+
+	s.github.failRuns = true
+	_, err = CreateGithubCheckRun(context.Background(), s.conf, "installation-token", "evergreen-ci", "evergreen", run)
+	s.Error(err)
+	s.Contains(err.Error(), "Invalid request")
+}
@@ -0,0 +1,31 @@
+package thirdparty
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// GithubIssueCommentEvent is the webhook event sent when an issue or a
+	// pull request is commented on.
+	GithubIssueCommentEvent = "issue_comment"
+
+	// GithubReactionPlusOne and GithubReactionConfused are the reactions
+	// Evergreen leaves on comments to acknowledge them.
+	GithubReactionPlusOne  = "+1"
+	GithubReactionConfused = "confused"
+
+	// reactions are in preview
+	githubReactionsMediaType = "application/vnd.github.squirrel-girl-preview+json"
+)
+
+// AddGithubCommentReaction reacts to the issue or pull request comment.
+func AddGithubCommentReaction(ctx context.Context, oauthToken, owner, repo string, commentID int, reaction string) error {
+	err := githubAppRequest(ctx, http.MethodPost,
+		fmt.Sprintf("%s/repos/%s/%s/issues/comments/%d/reactions", GithubAPIBase, owner, repo, commentID),
+		oauthToken, githubReactionsMediaType, map[string]string{"content": reaction}, nil)
+	return errors.Wrapf(err, "error reacting to comment %d on %s/%s", commentID, owner, repo)
+}
@@ -61,6 +61,11 @@ type Link struct {
 	Html string
 }
 
+type GithubTag struct {
+	Name   string
+	Commit Parent
+}
+
 type Parent struct {
 	Url string
 	Sha string
@@ -6,6 +6,7 @@ import (
 
 	"github.com/evergreen-ci/evergreen/testutil"
 	. "github.com/smartystreets/goconvey/convey"
+	"github.com/stretchr/testify/assert"
 )
 
 var repoKind = "github"
@@ -55,3 +56,28 @@ func TestCheckGithubAPILimit(t *testing.T) {
 		So(err, ShouldBeNil)
 	})
 }
+
+func TestParseGithubRepoURL(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, uri := range []string{
+		"git@github.com:evergreen-ci/sample.git",
+		"https://github.com/evergreen-ci/sample",
+		"https://github.com/evergreen-ci/sample.git",
+	} {
+		owner, repo, err := ParseGithubRepoURL(uri)
+		assert.NoError(err)
+		assert.Equal("evergreen-ci", owner)
+		assert.Equal("sample", repo)
+	}
+
+	for _, uri := range []string{
+		"",
+		"git@gitlab.com:evergreen-ci/sample.git",
+		"https://github.com/evergreen-ci",
+		"https://github.com/evergreen-ci/sample/tree/master",
+	} {
+		_, _, err := ParseGithubRepoURL(uri)
+		assert.Error(err)
+	}
+}
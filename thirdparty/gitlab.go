@@ -0,0 +1,246 @@
+package thirdparty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// GitlabEventHeader and GitlabTokenHeader are the headers of a GitLab
+	// webhook request that name its event, and carry the webhook's secret
+	// token.
+	GitlabEventHeader = "X-Gitlab-Event"
+	GitlabTokenHeader = "X-Gitlab-Token"
+
+	// GitlabMergeRequestHook is the event of merge request webhooks.
+	GitlabMergeRequestHook = "Merge Request Hook"
+
+	GitlabStatusPending  = "pending"
+	GitlabStatusRunning  = "running"
+	GitlabStatusSuccess  = "success"
+	GitlabStatusFailed   = "failed"
+	GitlabStatusCanceled = "canceled"
+
+	// GitlabDeveloperAccess is the access level of the members of a GitLab
+	// project who can push to it.
+	GitlabDeveloperAccess = 30
+
+	gitlabAPIPath = "/api/v4"
+)
+
+// GitlabCommitStatus is the status of a commit, which GitLab shows on the
+// merge requests that include the commit.
+type GitlabCommitStatus struct {
+	State       string `json:"state"`
+	Name        string `json:"name"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// gitlabMergeRequestChange is the diff of a file changed by a merge request.
+type gitlabMergeRequestChange struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	AMode       string `json:"a_mode"`
+	BMode       string `json:"b_mode"`
+	NewFile     bool   `json:"new_file"`
+	RenamedFile bool   `json:"renamed_file"`
+	DeletedFile bool   `json:"deleted_file"`
+	Diff        string `json:"diff"`
+}
+
+// GetGitlabMergeRequestDiff returns the changes of a merge request as a
+// diff that git can apply.
+func GetGitlabMergeRequestDiff(ctx context.Context, conf evergreen.GitlabConfig, projectID, mrNumber int) (string, error) {
+	mr := struct {
+		Changes  []gitlabMergeRequestChange `json:"changes"`
+		Overflow bool                       `json:"overflow"`
+	}{}
+	_, err := gitlabRequest(ctx, conf, http.MethodGet,
+		fmt.Sprintf("/projects/%d/merge_requests/%d/changes", projectID, mrNumber), nil, nil, &mr)
+	if err != nil {
+		return "", errors.Wrapf(err, "error fetching changes of merge request !%d of project %d", mrNumber, projectID)
+	}
+	if mr.Overflow {
+		return "", errors.Errorf("merge request !%d of project %d is too large to diff", mrNumber, projectID)
+	}
+
+	diff := &bytes.Buffer{}
+	for _, change := range mr.Changes {
+		writeGitlabChange(diff, change)
+	}
+	return diff.String(), nil
+}
+
+// writeGitlabChange writes the change in the format of 'git diff'. GitLab
+// only returns the hunks of each file, so the headers are rebuilt.
+func writeGitlabChange(diff *bytes.Buffer, change gitlabMergeRequestChange) {
+	fmt.Fprintf(diff, "diff --git a/%s b/%s\n", change.OldPath, change.NewPath)
+	switch {
+	case change.NewFile:
+		fmt.Fprintf(diff, "new file mode %s\n", change.BMode)
+	case change.DeletedFile:
+		fmt.Fprintf(diff, "deleted file mode %s\n", change.AMode)
+	case change.RenamedFile:
+		fmt.Fprintf(diff, "rename from %s\nrename to %s\n", change.OldPath, change.NewPath)
+	}
+	if change.Diff == "" {
+		return
+	}
+
+	oldPath := "a/" + change.OldPath
+	if change.NewFile {
+		oldPath = "/dev/null"
+	}
+	newPath := "b/" + change.NewPath
+	if change.DeletedFile {
+		newPath = "/dev/null"
+	}
+	fmt.Fprintf(diff, "--- %s\n+++ %s\n", oldPath, newPath)
+	diff.WriteString(change.Diff)
+	if !strings.HasSuffix(change.Diff, "\n") {
+		diff.WriteString("\n")
+	}
+}
+
+// GetGitlabMergeBase returns the best common ancestor of the refs.
+func GetGitlabMergeBase(ctx context.Context, conf evergreen.GitlabConfig, projectID int, refs ...string) (string, error) {
+	query := url.Values{"refs[]": refs}
+	commit := struct {
+		ID string `json:"id"`
+	}{}
+	_, err := gitlabRequest(ctx, conf, http.MethodGet,
+		fmt.Sprintf("/projects/%d/repository/merge_base", projectID), query, nil, &commit)
+	if err != nil {
+		return "", errors.Wrapf(err, "error finding merge base of %s in project %d", strings.Join(refs, ", "), projectID)
+	}
+	if commit.ID == "" {
+		return "", errors.Errorf("gitlab returned no merge base of %s in project %d", strings.Join(refs, ", "), projectID)
+	}
+	return commit.ID, nil
+}
+
+// GetGitlabFileContents returns the contents of a file of the project at the
+// revision. It returns a FileNotFoundError if the file does not exist.
+func GetGitlabFileContents(ctx context.Context, conf evergreen.GitlabConfig, projectID int, path, revision string) ([]byte, error) {
+	filePath := fmt.Sprintf("/projects/%d/repository/files/%s/raw", projectID, url.PathEscape(path))
+	contents, err := gitlabRequest(ctx, conf, http.MethodGet, filePath, url.Values{"ref": {revision}}, nil, nil)
+	if err != nil {
+		if apiErr, ok := errors.Cause(err).(gitlabStatusError); ok && apiErr.StatusCode == http.StatusNotFound {
+			return nil, FileNotFoundError{filepath: path}
+		}
+		return nil, errors.Wrapf(err, "error fetching '%s' of project %d at %s", path, projectID, revision)
+	}
+	return contents, nil
+}
+
+// GetGitlabAccessLevel returns the access level of the user to the project,
+// including the access inherited from the project's groups, or 0 if the user
+// is not an active member.
+func GetGitlabAccessLevel(ctx context.Context, conf evergreen.GitlabConfig, projectID, userID int) (int, error) {
+	member := struct {
+		AccessLevel int    `json:"access_level"`
+		State       string `json:"state"`
+	}{}
+	_, err := gitlabRequest(ctx, conf, http.MethodGet,
+		fmt.Sprintf("/projects/%d/members/all/%d", projectID, userID), nil, nil, &member)
+	if err != nil {
+		if apiErr, ok := errors.Cause(err).(gitlabStatusError); ok && apiErr.StatusCode == http.StatusNotFound {
+			return 0, nil
+		}
+		return 0, errors.Wrapf(err, "error finding access of user %d to project %d", userID, projectID)
+	}
+	if member.State != "" && member.State != "active" {
+		return 0, nil
+	}
+	return member.AccessLevel, nil
+}
+
+// SetGitlabCommitStatus sets the status of the commit.
+func SetGitlabCommitStatus(ctx context.Context, conf evergreen.GitlabConfig, projectID int, sha string, status GitlabCommitStatus) error {
+	_, err := gitlabRequest(ctx, conf, http.MethodPost,
+		fmt.Sprintf("/projects/%d/statuses/%s", projectID, sha), nil, status, nil)
+	return errors.Wrapf(err, "error setting status '%s' of %s in project %d", status.Name, sha, projectID)
+}
+
+// CreateGitlabMergeRequestNote comments on the merge request.
+func CreateGitlabMergeRequestNote(ctx context.Context, conf evergreen.GitlabConfig, projectID, mrNumber int, body string) error {
+	_, err := gitlabRequest(ctx, conf, http.MethodPost,
+		fmt.Sprintf("/projects/%d/merge_requests/%d/notes", projectID, mrNumber), nil,
+		map[string]string{"body": body}, nil)
+	return errors.Wrapf(err, "error commenting on merge request !%d of project %d", mrNumber, projectID)
+}
+
+// gitlabStatusError is returned for GitLab responses that are not
+// successful.
+type gitlabStatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e gitlabStatusError) Error() string {
+	return fmt.Sprintf("gitlab responded %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// gitlabRequest sends a request to the GitLab API, and returns the body of
+// the response. If out is not nil, the JSON response is unmarshalled into it.
+func gitlabRequest(ctx context.Context, conf evergreen.GitlabConfig, method, path string, query url.Values, data, out interface{}) ([]byte, error) {
+	if !conf.Enabled() {
+		return nil, errors.New("gitlab is not configured")
+	}
+
+	var body []byte
+	if data != nil {
+		var err error
+		body, err = json.Marshal(data)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	requestURL := strings.TrimSuffix(conf.URL, "/") + gitlabAPIPath + path
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, requestURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Add("PRIVATE-TOKEN", conf.Token)
+	req.Header.Add("Content-Type", "application/json")
+
+	client := util.GetHttpClient()
+	defer util.PutHttpClient(client)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, ResponseReadError{err.Error()}
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, gitlabStatusError{StatusCode: resp.StatusCode, Message: string(respBody)}
+	}
+
+	if out != nil {
+		if err = json.Unmarshal(respBody, out); err != nil {
+			return nil, APIUnmarshalError{string(respBody), err.Error()}
+		}
+	}
+	return respBody, nil
+}
@@ -0,0 +1,206 @@
+package thirdparty
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeGitlab serves the parts of the GitLab API used to test merge requests.
+type fakeGitlab struct {
+	statuses map[string]GitlabCommitStatus
+	notes    []string
+}
+
+func (f *fakeGitlab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("PRIVATE-TOKEN") != "gitlab-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/3/merge_requests/12/changes":
+		_, _ = w.Write([]byte(`{"changes": [
+			{"old_path": "README.md", "new_path": "README.md", "a_mode": "100644", "b_mode": "100644",
+			 "diff": "@@ -1 +1 @@\n-evergreen\n+Evergreen\n"},
+			{"old_path": "main.go", "new_path": "main.go", "a_mode": "0", "b_mode": "100644", "new_file": true,
+			 "diff": "@@ -0,0 +1 @@\n+package main"},
+			{"old_path": "old.go", "new_path": "new.go", "a_mode": "100644", "b_mode": "100644", "renamed_file": true,
+			 "diff": ""}
+		]}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/3/merge_requests/13/changes":
+		_, _ = w.Write([]byte(`{"changes": [], "overflow": true}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/3/repository/merge_base":
+		refs := r.URL.Query()["refs[]"]
+		if len(refs) != 2 || refs[0] != "master" || refs[1] != "abcdef" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "123456"}`))
+	case r.Method == http.MethodGet && r.URL.EscapedPath() == "/api/v4/projects/3/repository/files/etc%2Fevergreen.yml/raw":
+		if r.URL.Query().Get("ref") != "123456" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("tasks: []\n"))
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/3/members/all/1":
+		_, _ = w.Write([]byte(`{"id": 1, "username": "root", "access_level": 40, "state": "active"}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/3/members/all/2":
+		_, _ = w.Write([]byte(`{"id": 2, "username": "blocked", "access_level": 30, "state": "blocked"}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v4/projects/3/statuses/abcdef":
+		status := GitlabCommitStatus{}
+		if err := json.NewDecoder(r.Body).Decode(&status); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.statuses[status.Name] = status
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/api/v4/projects/3/merge_requests/12/notes":
+		note := struct {
+			Body string `json:"body"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&note); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.notes = append(f.notes, note.Body)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "404 Not Found"}`))
+	}
+}
+
+type GitlabSuite struct {
+	gitlab *fakeGitlab
+	server *httptest.Server
+	conf   evergreen.GitlabConfig
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	suite.Suite
+}
+
+func TestGitlabSuite(t *testing.T) {
+	suite.Run(t, new(GitlabSuite))
+}
+
+func (s *GitlabSuite) SetupTest() {
+	s.gitlab = &fakeGitlab{statuses: map[string]GitlabCommitStatus{}}
+	s.server = httptest.NewServer(s.gitlab)
+	s.conf = evergreen.GitlabConfig{
+		URL:           s.server.URL + "/",
+		Token:         "gitlab-token",
+		WebhookSecret: "secret",
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+}
+
+func (s *GitlabSuite) TearDownTest() {
+	s.cancel()
+	s.server.Close()
+}
+
+func (s *GitlabSuite) TestGetMergeRequestDiff() {
+	diff, err := GetGitlabMergeRequestDiff(s.ctx, s.conf, 3, 12)
+	s.Require().NoError(err)
+	s.Equal(`diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -1 +1 @@
+-evergreen
++Evergreen
+diff --git a/main.go b/main.go
+new file mode 100644
+--- /dev/null
++++ b/main.go
+@@ -0,0 +1 @@
++package main
+diff --git a/old.go b/new.go
+rename from old.go
+rename to new.go
+`, diff)
+
+	summaries, err := GetPatchSummaries(diff)
+	s.NoError(err)
+	s.Len(summaries, 3)
+
+	_, err = GetGitlabMergeRequestDiff(s.ctx, s.conf, 3, 13)
+	s.Error(err)
+	_, err = GetGitlabMergeRequestDiff(s.ctx, s.conf, 4, 12)
+	s.Error(err)
+}
+
+func (s *GitlabSuite) TestGetMergeBase() {
+	base, err := GetGitlabMergeBase(s.ctx, s.conf, 3, "master", "abcdef")
+	s.NoError(err)
+	s.Equal("123456", base)
+
+	_, err = GetGitlabMergeBase(s.ctx, s.conf, 3, "master")
+	s.Error(err)
+}
+
+func (s *GitlabSuite) TestGetFileContents() {
+	contents, err := GetGitlabFileContents(s.ctx, s.conf, 3, "etc/evergreen.yml", "123456")
+	s.NoError(err)
+	s.Equal("tasks: []\n", string(contents))
+
+	_, err = GetGitlabFileContents(s.ctx, s.conf, 3, "etc/evergreen.yml", "abcdef")
+	s.True(IsFileNotFound(err))
+
+	s.conf.Token = "wrong-token"
+	_, err = GetGitlabFileContents(s.ctx, s.conf, 3, "etc/evergreen.yml", "123456")
+	s.Error(err)
+	s.False(IsFileNotFound(err))
+}
+
+func (s *GitlabSuite) TestGetAccessLevel() {
+	level, err := GetGitlabAccessLevel(s.ctx, s.conf, 3, 1)
+	s.NoError(err)
+	s.Equal(40, level)
+
+	level, err = GetGitlabAccessLevel(s.ctx, s.conf, 3, 2)
+	s.NoError(err)
+	s.Zero(level)
+
+	level, err = GetGitlabAccessLevel(s.ctx, s.conf, 3, 3)
+	s.NoError(err)
+	s.Zero(level)
+
+	s.conf.Token = "wrong-token"
+	_, err = GetGitlabAccessLevel(s.ctx, s.conf, 3, 1)
+	s.Error(err)
+}
+
+func (s *GitlabSuite) TestSetCommitStatus() {
+	status := GitlabCommitStatus{
+		State:       GitlabStatusSuccess,
+		Name:        "evergreen",
+		TargetURL:   "https://evergreen.example.com/version/v",
+		Description: "patch finished in 1m0s",
+	}
+	s.NoError(SetGitlabCommitStatus(s.ctx, s.conf, 3, "abcdef", status))
+	s.Equal(status, s.gitlab.statuses["evergreen"])
+
+	s.Error(SetGitlabCommitStatus(s.ctx, s.conf, 3, "123456", status))
+}
+
+func (s *GitlabSuite) TestCreateMergeRequestNote() {
+	s.NoError(CreateGitlabMergeRequestNote(s.ctx, s.conf, 3, 12, "evergreen failed"))
+	s.Equal([]string{"evergreen failed"}, s.gitlab.notes)
+
+	s.Error(CreateGitlabMergeRequestNote(s.ctx, s.conf, 3, 13, "evergreen failed"))
+}
+
+func (s *GitlabSuite) TestNotConfigured() {
+	s.conf.Token = ""
+	_, err := GetGitlabMergeBase(s.ctx, s.conf, 3, "master", "abcdef")
+	s.Error(err)
+}
@@ -0,0 +1,108 @@
+package tracing
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+// StandardOutput is the exporter output that writes spans to standard
+// output.
+const StandardOutput = "stdout"
+
+// Exporter sends finished spans to wherever they are stored or viewed.
+type Exporter interface {
+	ExportSpan(SpanData) error
+}
+
+var (
+	exporterMu sync.RWMutex
+	exporter   Exporter = noopExporter{}
+)
+
+// SetExporter sets the exporter of the process. A nil exporter disables
+// exporting.
+func SetExporter(e Exporter) {
+	exporterMu.Lock()
+	defer exporterMu.Unlock()
+	if e == nil {
+		e = noopExporter{}
+	}
+	exporter = e
+}
+
+// GetExporter returns the exporter of the process.
+func GetExporter() Exporter {
+	exporterMu.RLock()
+	defer exporterMu.RUnlock()
+	return exporter
+}
+
+func export(data SpanData) {
+	grip.Warning(message.WrapError(GetExporter().ExportSpan(data), message.Fields{
+		"message":  "problem exporting span",
+		"span":     data.Name,
+		"trace_id": data.TraceID,
+	}))
+}
+
+type noopExporter struct{}
+
+func (noopExporter) ExportSpan(SpanData) error { return nil }
+
+// WriterExporter writes spans to a writer as JSON, one span per line.
+type WriterExporter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterExporter returns an exporter that writes to w.
+func NewWriterExporter(w io.Writer) *WriterExporter {
+	return &WriterExporter{w: w}
+}
+
+// ExportSpan writes the span.
+func (e *WriterExporter) ExportSpan(data SpanData) error {
+	out, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling span")
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err = e.w.Write(append(out, '\n'))
+	return errors.Wrap(err, "error writing span")
+}
+
+// Close closes the underlying writer, if it can be closed.
+func (e *WriterExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if closer, ok := e.w.(io.Closer); ok && e.w != os.Stdout {
+		return closer.Close()
+	}
+	return nil
+}
+
+// NewExporter returns the exporter for an output setting: spans are written
+// to standard output for "stdout", and appended to the file at the path
+// otherwise. An empty output returns a nil exporter, which disables
+// exporting.
+func NewExporter(output string) (Exporter, error) {
+	switch output {
+	case "":
+		return nil, nil
+	case StandardOutput:
+		return NewWriterExporter(os.Stdout), nil
+	default:
+		f, err := os.OpenFile(output, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error opening trace file '%s'", output)
+		}
+		return NewWriterExporter(f), nil
+	}
+}
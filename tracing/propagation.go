@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// TraceParentHeader is the W3C trace context header that carries the span
+// context of a request.
+const TraceParentHeader = "traceparent"
+
+var traceParentRegexp = regexp.MustCompile(`^00-([0-9a-f]{32})-([0-9a-f]{16})-[0-9a-f]{2}$`)
+
+// FormatTraceParent formats a span context as a traceparent header value.
+// It returns an empty string for invalid span contexts.
+func FormatTraceParent(sc SpanContext) string {
+	if !sc.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-01", sc.TraceID, sc.SpanID)
+}
+
+// ParseTraceParent parses a traceparent header value.
+func ParseTraceParent(value string) (SpanContext, error) {
+	matches := traceParentRegexp.FindStringSubmatch(value)
+	if matches == nil {
+		return SpanContext{}, errors.Errorf("invalid traceparent '%s'", value)
+	}
+	return SpanContext{TraceID: matches[1], SpanID: matches[2]}, nil
+}
+
+// Inject sets the traceparent header of an outgoing request to the span
+// context of the context, if there is one.
+func Inject(ctx context.Context, h http.Header) {
+	if traceParent := FormatTraceParent(parentFromContext(ctx)); traceParent != "" {
+		h.Set(TraceParentHeader, traceParent)
+	}
+}
+
+// Extract returns a context whose spans are children of the span in the
+// traceparent header of an incoming request. The context is returned
+// unchanged if the header is missing or invalid.
+func Extract(ctx context.Context, h http.Header) context.Context {
+	sc, err := ParseTraceParent(h.Get(TraceParentHeader))
+	if err != nil {
+		return ctx
+	}
+	return ContextWithRemoteParent(ctx, sc)
+}
@@ -0,0 +1,202 @@
+/*
+Package tracing records spans that describe where time goes as work moves
+through Evergreen: from the repotracker creating a version, through the
+scheduler and host provisioning, to the API server dispatching a task and the
+agent running its commands.
+
+Spans are started from a context, and a span started from a context that
+holds another span becomes its child. Span contexts travel between processes
+in the W3C traceparent header, and are stored on task documents so that the
+spans of a task, recorded by different processes, share one trace.
+
+Finished spans are handed to the process's Exporter, which is a no-op unless
+one is configured with SetExporter.
+*/
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SpanContext identifies a span within a trace.
+type SpanContext struct {
+	TraceID string `bson:"trace_id" json:"trace_id" yaml:"trace_id"`
+	SpanID  string `bson:"span_id" json:"span_id" yaml:"span_id"`
+}
+
+// IsValid returns true if the span context identifies a span.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != "" && sc.SpanID != ""
+}
+
+// SpanData is the record of a span that is exported when the span ends.
+type SpanData struct {
+	Name       string                 `json:"name"`
+	TraceID    string                 `json:"trace_id"`
+	SpanID     string                 `json:"span_id"`
+	ParentID   string                 `json:"parent_id,omitempty"`
+	Start      time.Time              `json:"start"`
+	End        time.Time              `json:"end"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// Duration returns how long the span lasted.
+func (d SpanData) Duration() time.Duration {
+	return d.End.Sub(d.Start)
+}
+
+// Span is an operation in a trace. Its methods are safe to call on a nil
+// span, and after the span has ended, in which case they do nothing.
+type Span struct {
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// SpanOption changes how a span is started.
+type SpanOption func(*SpanData)
+
+// WithStartTime starts the span at the given time rather than now, to
+// record an operation that is only known to have happened once it is over,
+// such as the time a task spent in its queue.
+func WithStartTime(t time.Time) SpanOption {
+	return func(d *SpanData) { d.Start = t }
+}
+
+type spanKey struct{}
+type remoteParentKey struct{}
+
+// StartSpan starts a span that is a child of the span in the context, or of
+// the remote parent set in the context, or that starts a new trace if the
+// context holds neither. The returned context holds the new span.
+func StartSpan(ctx context.Context, name string, opts ...SpanOption) (context.Context, *Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	span := &Span{data: SpanData{
+		Name:       name,
+		SpanID:     newID(8),
+		Start:      time.Now(),
+		Attributes: map[string]interface{}{},
+	}}
+	if parent := parentFromContext(ctx); parent.IsValid() {
+		span.data.TraceID = parent.TraceID
+		span.data.ParentID = parent.SpanID
+	} else {
+		span.data.TraceID = newID(16)
+	}
+	for _, opt := range opts {
+		opt(&span.data)
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// SpanFromContext returns the span in the context, or nil.
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// ContextWithRemoteParent returns a context whose spans are children of a
+// span that was started in another process. Invalid span contexts are
+// ignored.
+func ContextWithRemoteParent(ctx context.Context, sc SpanContext) context.Context {
+	if !sc.IsValid() {
+		return ctx
+	}
+	return context.WithValue(ctx, remoteParentKey{}, sc)
+}
+
+// SpanContextFromContext returns the context of the span in the context, or
+// of its remote parent.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	return parentFromContext(ctx)
+}
+
+func parentFromContext(ctx context.Context) SpanContext {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.Context()
+	}
+	if ctx == nil {
+		return SpanContext{}
+	}
+	sc, _ := ctx.Value(remoteParentKey{}).(SpanContext)
+	return sc
+}
+
+// Context returns the identity of the span.
+func (s *Span) Context() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return SpanContext{TraceID: s.data.TraceID, SpanID: s.data.SpanID}
+}
+
+// SetAttribute annotates the span with a value.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ended {
+		s.data.Attributes[key] = value
+	}
+}
+
+// SetError records that the operation failed. Nil errors are ignored.
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.ended {
+		s.data.Error = err.Error()
+	}
+}
+
+// End ends the span now and exports it.
+func (s *Span) End() {
+	s.EndAt(time.Now())
+}
+
+// EndAt ends the span at the given time and exports it. Spans are only
+// exported the first time they end.
+func (s *Span) EndAt(t time.Time) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = t
+	data := s.data
+	s.mu.Unlock()
+
+	export(data)
+}
+
+// newID returns a random hex encoded id of n bytes.
+func newID(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		// fall back to the clock rather than leave the span without an id
+		return fmt.Sprintf("%0*x", n*2, time.Now().UnixNano())[:n*2]
+	}
+	return hex.EncodeToString(b)
+}
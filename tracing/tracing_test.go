@@ -0,0 +1,124 @@
+package tracing
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readSpans(t *testing.T, buf *bytes.Buffer) []SpanData {
+	spans := []SpanData{}
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		span := SpanData{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &span))
+		spans = append(spans, span)
+	}
+	return spans
+}
+
+func TestSpans(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	buf := &bytes.Buffer{}
+	SetExporter(NewWriterExporter(buf))
+	defer SetExporter(nil)
+
+	ctx, root := StartSpan(context.Background(), "scheduler.Schedule")
+	assert.Equal(root, SpanFromContext(ctx))
+	queued := time.Now().Add(-time.Minute)
+	_, child := StartSpan(ctx, "task.Queued", WithStartTime(queued))
+	child.SetAttribute("task_id", "t1")
+	child.SetError(errors.New("no hosts"))
+	child.SetError(nil)
+	child.End()
+	child.SetAttribute("ignored", true)
+	child.End()
+	root.End()
+
+	spans := readSpans(t, buf)
+	require.Len(spans, 2)
+	assert.Equal("task.Queued", spans[0].Name)
+	assert.Equal(root.Context().TraceID, spans[0].TraceID)
+	assert.Equal(root.Context().SpanID, spans[0].ParentID)
+	assert.Equal(map[string]interface{}{"task_id": "t1"}, spans[0].Attributes)
+	assert.Equal("no hosts", spans[0].Error)
+	assert.True(spans[0].Start.Equal(queued))
+	assert.True(spans[0].Duration() >= time.Minute)
+	assert.Equal("scheduler.Schedule", spans[1].Name)
+	assert.Empty(spans[1].ParentID)
+	assert.Len(spans[1].TraceID, 32)
+	assert.Len(spans[1].SpanID, 16)
+
+	// a nil span does nothing
+	var span *Span
+	span.SetAttribute("key", "value")
+	span.End()
+	assert.False(span.Context().IsValid())
+}
+
+func TestPropagation(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	ctx, span := StartSpan(context.Background(), "agent.RunTask")
+	header := http.Header{}
+	Inject(ctx, header)
+	traceParent := header.Get(TraceParentHeader)
+	assert.Equal(FormatTraceParent(span.Context()), traceParent)
+
+	sc, err := ParseTraceParent(traceParent)
+	require.NoError(err)
+	assert.Equal(span.Context(), sc)
+
+	remote := Extract(context.Background(), header)
+	assert.Equal(span.Context(), SpanContextFromContext(remote))
+	_, child := StartSpan(remote, "api.NextTask")
+	assert.Equal(span.Context().TraceID, child.Context().TraceID)
+	assert.NotEqual(span.Context().SpanID, child.Context().SpanID)
+
+	for _, invalid := range []string{"", "00-abc-def-01", "01-" + sc.TraceID + "-" + sc.SpanID + "-01"} {
+		_, err = ParseTraceParent(invalid)
+		assert.Error(err, invalid)
+		assert.False(SpanContextFromContext(Extract(context.Background(), http.Header{TraceParentHeader: {invalid}})).IsValid())
+	}
+
+	header = http.Header{}
+	Inject(context.Background(), header)
+	assert.Empty(header.Get(TraceParentHeader))
+	assert.Empty(FormatTraceParent(SpanContext{}))
+}
+
+func TestNewExporter(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	exporter, err := NewExporter("")
+	assert.NoError(err)
+	assert.Nil(exporter)
+
+	exporter, err = NewExporter(StandardOutput)
+	assert.NoError(err)
+	assert.NotNil(exporter)
+
+	dir, err := ioutil.TempDir("", "tracing")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "spans.json")
+	exporter, err = NewExporter(path)
+	require.NoError(err)
+	require.NoError(exporter.ExportSpan(SpanData{Name: "hostinit.ProvisionHost"}))
+	require.NoError(exporter.(*WriterExporter).Close())
+}
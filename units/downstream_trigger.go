@@ -0,0 +1,236 @@
+package units
+
+import (
+	"fmt"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/artifact"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/evergreen-ci/evergreen/repotracker"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const downstreamTriggerJobName = "downstream-trigger"
+
+func init() {
+	registry.AddJobType(downstreamTriggerJobName, func() amboy.Job { return makeDownstreamTriggerJob() })
+}
+
+type downstreamTriggerJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+	env      evergreen.Environment
+
+	TaskID    string `bson:"task_id" json:"task_id" yaml:"task_id"`
+	Execution int    `bson:"execution" json:"execution" yaml:"execution"`
+}
+
+func makeDownstreamTriggerJob() *downstreamTriggerJob {
+	return &downstreamTriggerJob{
+		env: evergreen.GetEnvironment(),
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    downstreamTriggerJobName,
+				Version: 0,
+				Format:  amboy.BSON,
+			},
+		},
+	}
+}
+
+// NewDownstreamTriggerJob creates a job that runs the triggers of other
+// projects on a task that just finished, and on its build and version if
+// they finished with it.
+func NewDownstreamTriggerJob(taskID string, execution int) amboy.Job {
+	j := makeDownstreamTriggerJob()
+	j.TaskID = taskID
+	j.Execution = execution
+	j.SetID(fmt.Sprintf("%s-%s-%d", downstreamTriggerJobName, taskID, execution))
+	return j
+}
+
+// upstream is the task that finished, along with its build and version.
+type upstream struct {
+	task    *task.Task
+	build   *build.Build
+	version *version.Version
+}
+
+func (j *downstreamTriggerJob) Run() {
+	defer j.MarkComplete()
+
+	t, err := task.FindOne(task.ById(j.TaskID))
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "error finding task %s", j.TaskID))
+		return
+	}
+	if t == nil {
+		j.AddError(errors.Errorf("task %s not found", j.TaskID))
+		return
+	}
+	refs, err := model.FindDownstreamProjectRefs(t.Project)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	if len(refs) == 0 {
+		return
+	}
+
+	// execution tasks trigger through the display task they are part of
+	displayTask, err := task.FindOne(task.ByExecutionTask(t.Id))
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "error finding display task of %s", t.Id))
+		return
+	}
+	if displayTask != nil {
+		t = displayTask
+	}
+	b, err := build.FindOne(build.ById(t.BuildId))
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "error finding build %s", t.BuildId))
+		return
+	}
+	v, err := version.FindOne(version.ById(t.Version))
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "error finding version %s", t.Version))
+		return
+	}
+	if b == nil || v == nil {
+		j.AddError(errors.Errorf("build or version of task %s not found", t.Id))
+		return
+	}
+	up := upstream{task: t, build: b, version: v}
+
+	settings := j.env.Settings()
+	for i := range refs {
+		ref := &refs[i]
+		if v.IsTriggeredBy(ref.Identifier) {
+			grip.Info(message.Fields{
+				"job":              downstreamTriggerJobName,
+				"message":          "not triggering project that is already part of the chain of triggers",
+				"project":          ref.Identifier,
+				"upstream_version": v.Id,
+			})
+			continue
+		}
+		for index, trigger := range ref.Triggers {
+			if trigger.Project != t.Project {
+				continue
+			}
+			info := up.triggerInfo(trigger)
+			if info == nil {
+				continue
+			}
+			upstreamId := info.Version
+			if trigger.Level == model.TriggerLevelBuild {
+				upstreamId = info.Build
+			} else if trigger.Level == model.TriggerLevelTask {
+				upstreamId = info.Task
+			}
+			id := model.DownstreamVersionId(ref.Identifier, index, upstreamId)
+			existing, err := version.FindOne(version.ById(id).WithFields(version.IdKey))
+			if err != nil {
+				j.AddError(errors.Wrapf(err, "error finding version %s", id))
+				continue
+			}
+			if existing != nil {
+				continue
+			}
+			if info.Artifacts, err = up.artifacts(trigger.Level); err != nil {
+				j.AddError(err)
+				continue
+			}
+			if _, err = repotracker.CreateDownstreamVersion(settings, ref, trigger, id, info); err != nil {
+				j.AddError(errors.Wrapf(err, "error creating version of %s triggered by %s", ref.Identifier, upstreamId))
+			}
+		}
+	}
+}
+
+// triggerInfo returns the description of the upstream version, build or
+// task for a trigger, or nil if the trigger does not match them.
+func (u *upstream) triggerInfo(trigger model.TriggerDefinition) *version.TriggerInfo {
+	info := &version.TriggerInfo{
+		Project:  u.version.Identifier,
+		Level:    trigger.Level,
+		Version:  u.version.Id,
+		Revision: u.version.Revision,
+		Chain:    []string{u.version.Identifier},
+	}
+	if u.version.TriggeredBy != nil {
+		info.Chain = append(append([]string{}, u.version.TriggeredBy.Chain...), u.version.Identifier)
+	}
+
+	switch trigger.Level {
+	case model.TriggerLevelVersion:
+		if !trigger.Matches(trigger.Level, u.version.Status, "", "") {
+			return nil
+		}
+		info.Status = u.version.Status
+	case model.TriggerLevelBuild:
+		if !trigger.Matches(trigger.Level, u.build.Status, u.build.BuildVariant, "") {
+			return nil
+		}
+		info.Status = u.build.Status
+		info.Build = u.build.Id
+	case model.TriggerLevelTask:
+		if !trigger.Matches(trigger.Level, u.task.Status, u.task.BuildVariant, u.task.DisplayName) {
+			return nil
+		}
+		info.Status = u.task.Status
+		info.Build = u.build.Id
+		info.Task = u.task.Id
+	default:
+		return nil
+	}
+	return info
+}
+
+// artifacts returns the visible files attached to the upstream tasks at the
+// level of a trigger.
+func (u *upstream) artifacts(level string) ([]version.TriggerArtifact, error) {
+	var taskIds []string
+	switch level {
+	case model.TriggerLevelTask:
+		taskIds = append([]string{u.task.Id}, u.task.ExecutionTasks...)
+	case model.TriggerLevelBuild:
+		tasks, err := task.Find(task.ByBuildId(u.build.Id).WithFields(task.IdKey))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error finding tasks of build %s", u.build.Id)
+		}
+		for _, t := range tasks {
+			taskIds = append(taskIds, t.Id)
+		}
+	default:
+		tasks, err := task.Find(task.ByVersion(u.version.Id).WithFields(task.IdKey))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error finding tasks of version %s", u.version.Id)
+		}
+		for _, t := range tasks {
+			taskIds = append(taskIds, t.Id)
+		}
+	}
+
+	entries, err := artifact.FindAll(artifact.ByTaskIds(taskIds))
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding upstream artifacts")
+	}
+	artifacts := []version.TriggerArtifact{}
+	for _, entry := range entries {
+		for _, f := range entry.Files {
+			if f.Visibility == artifact.None {
+				continue
+			}
+			artifacts = append(artifacts, version.TriggerArtifact{Name: f.Name, Link: f.Link})
+		}
+	}
+	return artifacts, nil
+}
@@ -0,0 +1,60 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownstreamTriggerInfo(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	up := upstream{
+		task:  &task.Task{Id: "compile", BuildVariant: "linux", DisplayName: "compile", Status: evergreen.TaskSucceeded},
+		build: &build.Build{Id: "linux_build", BuildVariant: "linux", Status: evergreen.BuildStarted},
+		version: &version.Version{
+			Id:         "server_abcdef",
+			Identifier: "server",
+			Revision:   "abcdef",
+			Status:     evergreen.VersionStarted,
+			TriggeredBy: &version.TriggerInfo{
+				Project: "tools",
+				Chain:   []string{"tools"},
+			},
+		},
+	}
+
+	info := up.triggerInfo(model.TriggerDefinition{Project: "server", Level: model.TriggerLevelTask, TaskRegex: "^compile$"})
+	require.NotNil(info)
+	assert.Equal("server", info.Project)
+	assert.Equal("server_abcdef", info.Version)
+	assert.Equal("linux_build", info.Build)
+	assert.Equal("compile", info.Task)
+	assert.Equal("abcdef", info.Revision)
+	assert.Equal(evergreen.TaskSucceeded, info.Status)
+	assert.Equal([]string{"tools", "server"}, info.Chain)
+	assert.Equal([]string{"tools"}, up.version.TriggeredBy.Chain)
+
+	assert.Nil(up.triggerInfo(model.TriggerDefinition{Project: "server", Level: model.TriggerLevelTask, Status: evergreen.TaskFailed}))
+	// the build and version have not finished yet
+	assert.Nil(up.triggerInfo(model.TriggerDefinition{Project: "server", Level: model.TriggerLevelBuild}))
+	assert.Nil(up.triggerInfo(model.TriggerDefinition{Project: "server", Level: model.TriggerLevelVersion}))
+
+	up.build.Status = evergreen.BuildFailed
+	up.version.Status = evergreen.VersionFailed
+	info = up.triggerInfo(model.TriggerDefinition{Project: "server", Level: model.TriggerLevelBuild, BuildVariantRegex: "linux"})
+	require.NotNil(info)
+	assert.Equal("linux_build", info.Build)
+	assert.Empty(info.Task)
+	info = up.triggerInfo(model.TriggerDefinition{Project: "server", Level: model.TriggerLevelVersion})
+	require.NotNil(info)
+	assert.Empty(info.Build)
+	assert.Equal(evergreen.VersionFailed, info.Status)
+}
@@ -0,0 +1,104 @@
+package units
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/admin"
+	"github.com/evergreen-ci/evergreen/model/event"
+	"github.com/evergreen-ci/evergreen/notify"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/sometimes"
+	"github.com/pkg/errors"
+)
+
+const (
+	eventNotifierJobName = "event-notifier"
+
+	// eventNotifierWindow bounds how far back the job looks for events
+	// that have not been evaluated against subscriptions, so that events
+	// logged while notifications were disabled do not all notify at once.
+	eventNotifierWindow = time.Hour
+)
+
+func init() {
+	registry.AddJobType(eventNotifierJobName, func() amboy.Job { return makeEventNotifierJob() })
+}
+
+type eventNotifierJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+	env      evergreen.Environment
+}
+
+func makeEventNotifierJob() *eventNotifierJob {
+	return &eventNotifierJob{
+		env: evergreen.GetEnvironment(),
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    eventNotifierJobName,
+				Version: 0,
+				Format:  amboy.BSON,
+			},
+		},
+	}
+}
+
+// NewEventNotifierJob creates a job that evaluates the events that have not
+// been processed yet against the users' notification subscriptions.
+func NewEventNotifierJob(id string) amboy.Job {
+	j := makeEventNotifierJob()
+	j.SetID(fmt.Sprintf("%s-%s", eventNotifierJobName, id))
+	return j
+}
+
+func (j *eventNotifierJob) Run() {
+	defer j.MarkComplete()
+
+	adminSettings, err := admin.GetSettings()
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error retrieving admin settings"))
+		return
+	}
+	if adminSettings.ServiceFlags.NotificationsDisabled {
+		grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+			"job":     eventNotifierJobName,
+			"message": "notifications are disabled, not processing events",
+		})
+		return
+	}
+
+	events, err := event.Find(event.AllLogCollection,
+		event.UnprocessedEvents(time.Now().Add(-eventNotifierWindow), notify.SubscriptionEventTypes))
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error finding unprocessed events"))
+		return
+	}
+
+	settings := j.env.Settings()
+	for i := range events {
+		e := &events[i]
+		// the event is claimed before it is evaluated, so that notifiers
+		// that overlap don't both send its notifications
+		claimed, err := e.MarkProcessed()
+		if err != nil {
+			j.AddError(errors.Wrapf(err, "error marking event for %s processed", e.ResourceId))
+			return
+		}
+		if !claimed {
+			continue
+		}
+		// a failed notification is logged rather than retried, so that
+		// one broken subscriber does not hold up everyone else's
+		grip.Error(message.WrapError(notify.ProcessSubscriptionEvent(settings, e), message.Fields{
+			"job":      eventNotifierJobName,
+			"message":  "problem processing event",
+			"event":    e.EventType,
+			"resource": e.ResourceId,
+		}))
+	}
+}
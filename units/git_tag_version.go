@@ -0,0 +1,156 @@
+package units
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/admin"
+	"github.com/evergreen-ci/evergreen/repotracker"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/sometimes"
+	"github.com/pkg/errors"
+)
+
+const (
+	gitTagVersionJobName = "git-tag-version"
+)
+
+func init() {
+	registry.AddJobType(gitTagVersionJobName, func() amboy.Job { return makeGitTagVersionJob() })
+}
+
+type gitTagVersionJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+	env      evergreen.Environment
+
+	Owner string `bson:"owner" json:"owner" yaml:"owner"`
+	Repo  string `bson:"repo" json:"repo" yaml:"repo"`
+	Tag   string `bson:"tag" json:"tag" yaml:"tag"`
+
+	// Revision is the commit the tag points to. If the push event did not
+	// include the commit, it is looked up by the tag's name.
+	Revision model.Revision `bson:"revision" json:"revision" yaml:"revision"`
+}
+
+func makeGitTagVersionJob() *gitTagVersionJob {
+	return &gitTagVersionJob{
+		env: evergreen.GetEnvironment(),
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    gitTagVersionJobName,
+				Version: 0,
+				Format:  amboy.BSON,
+			},
+		},
+	}
+}
+
+// NewGitTagVersionJob creates a job that creates versions of the projects of
+// a repository that build a tag pushed to it.
+func NewGitTagVersionJob(msgID, owner, repo, tag string, revision model.Revision) amboy.Job {
+	j := makeGitTagVersionJob()
+	j.Owner = owner
+	j.Repo = repo
+	j.Tag = tag
+	j.Revision = revision
+
+	j.SetID(fmt.Sprintf("%s:%s/%s@%s-%s", gitTagVersionJobName, owner, repo, tag, msgID))
+	return j
+}
+
+func (j *gitTagVersionJob) Run() {
+	defer j.MarkComplete()
+
+	adminSettings, err := admin.GetSettings()
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error retrieving admin settings"))
+		return
+	}
+	if adminSettings.ServiceFlags.RepotrackerPushEventDisabled {
+		grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+			"job":     gitTagVersionJobName,
+			"message": "github push events triggering repotracker is disabled",
+		})
+		j.AddError(errors.New("github push events triggering repotracker is disabled"))
+		return
+	}
+
+	settings := j.env.Settings()
+	if settings == nil {
+		j.AddError(errors.New("settings is empty"))
+		return
+	}
+	token, err := settings.GetGithubOauthToken()
+	if err != nil {
+		j.AddError(errors.New("github token is missing"))
+		return
+	}
+
+	refs, err := model.FindGitTagProjectRefs(j.Owner, j.Repo)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	for i := range refs {
+		ref := &refs[i]
+		if ref.GitTagDefinitionFor(j.Tag) == nil {
+			continue
+		}
+		if j.Revision.Revision == "" {
+			if err = j.fetchRevision(token); err != nil {
+				j.AddError(err)
+				return
+			}
+		}
+
+		tracker := &repotracker.RepoTracker{
+			Settings:   settings,
+			ProjectRef: ref,
+			RepoPoller: repotracker.NewGithubRepositoryPoller(ref, token),
+		}
+		v, err := tracker.CreateGitTagVersion(j.Tag, j.Revision)
+		if err != nil {
+			grip.Info(message.WrapError(err, message.Fields{
+				"job":     gitTagVersionJobName,
+				"job_id":  j.ID(),
+				"project": ref.Identifier,
+				"tag":     j.Tag,
+			}))
+			j.AddError(err)
+			continue
+		}
+		if v == nil {
+			grip.Info(message.Fields{
+				"job":     gitTagVersionJobName,
+				"job_id":  j.ID(),
+				"project": ref.Identifier,
+				"tag":     j.Tag,
+				"message": "version for tag already exists",
+			})
+		}
+	}
+}
+
+// fetchRevision looks up the commit the tag points to.
+func (j *gitTagVersionJob) fetchRevision(token string) error {
+	commit, err := thirdparty.GetCommitEvent(token, j.Owner, j.Repo, j.Tag)
+	if err != nil {
+		return errors.Wrapf(err, "error finding the commit of tag %s", j.Tag)
+	}
+	j.Revision = model.Revision{
+		Author:          commit.Commit.Author.Name,
+		AuthorEmail:     commit.Commit.Author.Email,
+		RevisionMessage: commit.Commit.Message,
+		Revision:        commit.SHA,
+		CreateTime:      time.Now(),
+	}
+	return nil
+}
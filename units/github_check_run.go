@@ -0,0 +1,255 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/admin"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/sometimes"
+	"github.com/pkg/errors"
+)
+
+const (
+	githubCheckRunJobName = "github-check-run"
+
+	githubCheckRunTimeout = 30 * time.Second
+)
+
+func init() {
+	registry.AddJobType(githubCheckRunJobName, func() amboy.Job { return makeGithubCheckRunJob() })
+}
+
+type githubCheckRunJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+	env      evergreen.Environment
+
+	BuildID string `bson:"build_id" json:"build_id" yaml:"build_id"`
+}
+
+func makeGithubCheckRunJob() *githubCheckRunJob {
+	return &githubCheckRunJob{
+		env: evergreen.GetEnvironment(),
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    githubCheckRunJobName,
+				Version: 0,
+				Format:  amboy.BSON,
+			},
+		},
+	}
+}
+
+// NewGithubCheckRunJob creates a job to create a GitHub check run for a
+// finished pull request build. The check run is named
+// 'evergreen/[build variant name]', and annotates the pull request with the
+// build's failed tests.
+func NewGithubCheckRunJob(buildID string) amboy.Job {
+	j := makeGithubCheckRunJob()
+	j.BuildID = buildID
+
+	j.SetID(fmt.Sprintf("%s:%s-%s", githubCheckRunJobName, buildID, time.Now().String()))
+	return j
+}
+
+func (j *githubCheckRunJob) Run() {
+	defer j.MarkComplete()
+	ctx, cancel := context.WithTimeout(context.Background(), githubCheckRunTimeout)
+	defer cancel()
+
+	adminSettings, err := admin.GetSettings()
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error retrieving admin settings"))
+		return
+	}
+	if adminSettings.ServiceFlags.GithubPRTestingDisabled {
+		grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+			"job":     githubCheckRunJobName,
+			"message": "github pr testing is disabled, not creating check run",
+		})
+		return
+	}
+
+	settings := j.env.Settings()
+	if settings == nil || !settings.GithubApp.Enabled() {
+		j.AddError(errors.New("github app is not configured"))
+		return
+	}
+
+	b, err := build.FindOne(build.ById(j.BuildID))
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "error finding build %s", j.BuildID))
+		return
+	}
+	if b == nil {
+		j.AddError(errors.Errorf("can't find build %s", j.BuildID))
+		return
+	}
+	if !b.IsFinished() {
+		j.AddError(errors.Errorf("build %s is not finished; refusing to create check run", b.Id))
+		return
+	}
+
+	patchDoc, err := patch.FindOne(patch.ByVersion(b.Version))
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "error finding patch for version %s", b.Version))
+		return
+	}
+	if patchDoc == nil {
+		j.AddError(errors.Errorf("can't find patch for version %s", b.Version))
+		return
+	}
+	owner := patchDoc.GithubPatchData.BaseOwner
+	repo := patchDoc.GithubPatchData.BaseRepo
+
+	checkRun, err := makeGithubCheckRun(b, patchDoc.GithubPatchData.HeadHash, settings.Ui.Url)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	token, err := thirdparty.GetGithubAppInstallationToken(ctx, settings.GithubApp, owner, repo)
+	if err == nil {
+		_, err = thirdparty.CreateGithubCheckRun(ctx, settings.GithubApp, token, owner, repo, checkRun)
+	}
+	if err != nil {
+		grip.Alert(message.WrapError(err, message.Fields{
+			"message": "github API failure",
+			"source":  "check runs",
+			"job":     j.ID(),
+			"build":   b.Id,
+			"repo":    repoReference(owner, repo, patchDoc.GithubPatchData.PRNumber, checkRun.HeadSHA),
+		}))
+		j.AddError(err)
+	}
+}
+
+// makeGithubCheckRun summarizes a finished build as a check run. Each failed
+// test is an annotation on its test file that links to the test's logs, and
+// the check run of a failed build has an action to restart its failed tasks.
+func makeGithubCheckRun(b *build.Build, headSHA, evergreenBaseURL string) (*thirdparty.GithubCheckRun, error) {
+	failedTasks, err := findFailedBuildTasks(b)
+	if err != nil {
+		return nil, err
+	}
+
+	completedAt := b.FinishTime
+	checkRun := &thirdparty.GithubCheckRun{
+		Name:        fmt.Sprintf("evergreen/%s", b.BuildVariant),
+		HeadSHA:     headSHA,
+		ExternalID:  b.Id,
+		DetailsURL:  fmt.Sprintf("%s/build/%s", evergreenBaseURL, b.Id),
+		Status:      thirdparty.GithubCheckRunCompleted,
+		Conclusion:  thirdparty.GithubCheckRunSuccess,
+		CompletedAt: &completedAt,
+		Output: &thirdparty.GithubCheckRunOutput{
+			Title:   taskStatusToDesc(b),
+			Summary: fmt.Sprintf("All %d tasks succeeded.", len(b.Tasks)),
+		},
+	}
+	if b.Status != evergreen.BuildFailed {
+		return checkRun, nil
+	}
+
+	checkRun.Conclusion = thirdparty.GithubCheckRunFailure
+	checkRun.Actions = []thirdparty.GithubCheckRunAction{
+		{
+			Label:       "Re-run failed",
+			Description: "Restart the failed tasks of this build",
+			Identifier:  thirdparty.GithubCheckRunRerunFailed,
+		},
+	}
+
+	summary := []string{fmt.Sprintf("%d of %d tasks failed:", len(failedTasks), len(b.Tasks)), ""}
+	annotations := []thirdparty.GithubCheckRunAnnotation{}
+	for _, t := range failedTasks {
+		summary = append(summary, fmt.Sprintf("* [%s](%s/task/%s/%d)", t.DisplayName, evergreenBaseURL, t.Id, t.Execution))
+		for _, result := range t.LocalTestResults {
+			if result.Status != evergreen.TestFailedStatus {
+				continue
+			}
+			annotations = append(annotations, thirdparty.GithubCheckRunAnnotation{
+				Path:            result.TestFile,
+				StartLine:       1,
+				EndLine:         1,
+				AnnotationLevel: thirdparty.GithubAnnotationFailure,
+				Title:           fmt.Sprintf("%s failed in %s", result.TestFile, t.DisplayName),
+				Message:         fmt.Sprintf("Logs: %s", testLogURL(evergreenBaseURL, t, result)),
+			})
+		}
+	}
+	if len(annotations) > thirdparty.GithubMaxCheckRunAnnotations {
+		summary = append(summary, "", fmt.Sprintf("Only the first %d of %d failed tests are annotated.",
+			thirdparty.GithubMaxCheckRunAnnotations, len(annotations)))
+		annotations = annotations[:thirdparty.GithubMaxCheckRunAnnotations]
+	}
+	checkRun.Output.Summary = strings.Join(summary, "\n")
+	checkRun.Output.Annotations = annotations
+
+	return checkRun, nil
+}
+
+// findFailedBuildTasks returns the failed tasks of the build with their test
+// results. The test results of a failed display task are the results of its
+// execution tasks.
+func findFailedBuildTasks(b *build.Build) ([]task.Task, error) {
+	failedIds := []string{}
+	for _, t := range b.Tasks {
+		if t.Status == evergreen.TaskFailed {
+			failedIds = append(failedIds, t.Id)
+		}
+	}
+	if len(failedIds) == 0 {
+		return nil, nil
+	}
+
+	failedTasks, err := task.Find(task.ByIds(failedIds))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error finding failed tasks of build %s", b.Id)
+	}
+	for i := range failedTasks {
+		t := &failedTasks[i]
+		if !t.DisplayOnly {
+			if err = t.MergeNewTestResults(); err != nil {
+				return nil, errors.Wrapf(err, "error finding test results of task %s", t.Id)
+			}
+			continue
+		}
+
+		executionTasks, err := task.Find(task.ByIds(t.ExecutionTasks))
+		if err != nil {
+			return nil, errors.Wrapf(err, "error finding execution tasks of %s", t.Id)
+		}
+		executionTasks, err = task.MergeTestResultsBulk(executionTasks, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error finding test results of execution tasks of %s", t.Id)
+		}
+		for _, et := range executionTasks {
+			t.LocalTestResults = append(t.LocalTestResults, et.LocalTestResults...)
+		}
+	}
+
+	return failedTasks, nil
+}
+
+func testLogURL(evergreenBaseURL string, t task.Task, result task.TestResult) string {
+	if result.LogId != "" {
+		return fmt.Sprintf("%s/test_log/%s", evergreenBaseURL, result.LogId)
+	}
+	if result.URL != "" {
+		return result.URL
+	}
+	return fmt.Sprintf("%s/test_log/%s/%d/%s", evergreenBaseURL, t.Id, t.Execution, url.PathEscape(result.TestFile))
+}
@@ -0,0 +1,195 @@
+package units
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/admin"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/model/task"
+	"github.com/evergreen-ci/evergreen/model/testresult"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type githubCheckRunSuite struct {
+	suite.Suite
+	buildDoc *build.Build
+	appKey   string
+	cancel   func()
+}
+
+func TestGithubCheckRun(t *testing.T) {
+	suite.Run(t, new(githubCheckRunSuite))
+}
+
+func (s *githubCheckRunSuite) SetupSuite() {
+	evergreen.ResetEnvironment()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.Require().NoError(evergreen.GetEnvironment().Configure(ctx, filepath.Join(evergreen.FindEvergreenHome(), testutil.TestDir, testutil.TestSettings)))
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.Require().NoError(err)
+	s.appKey = string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	}))
+}
+
+func (s *githubCheckRunSuite) TearDownSuite() {
+	s.cancel()
+	evergreen.ResetEnvironment()
+}
+
+func (s *githubCheckRunSuite) SetupTest() {
+	s.NoError(db.ClearCollections(admin.Collection, patch.Collection, build.Collection, task.Collection, testresult.Collection))
+	patchDoc := &patch.Patch{
+		Id:      bson.NewObjectId(),
+		Version: bson.NewObjectId().Hex(),
+		Status:  evergreen.PatchFailed,
+		GithubPatchData: patch.GithubPatch{
+			BaseOwner: "evergreen-ci",
+			BaseRepo:  "evergreen",
+			PRNumber:  448,
+			HeadHash:  "776f608b5b12cd27b8d931c8ee4ca0c13f857299",
+		},
+	}
+	s.NoError(patchDoc.Insert())
+
+	tasks := []task.Task{
+		{Id: "compile", DisplayName: "compile", Status: evergreen.TaskSucceeded},
+		{Id: "test", DisplayName: "test", Status: evergreen.TaskFailed},
+		{Id: "lint", DisplayName: "lint", Status: evergreen.TaskFailed, DisplayOnly: true, ExecutionTasks: []string{"lint-js"}},
+		{Id: "lint-js", DisplayName: "lint-js", Status: evergreen.TaskFailed},
+	}
+	for _, t := range tasks {
+		s.NoError(t.Insert())
+	}
+	results := []testresult.TestResult{
+		{TaskID: "test", TestFile: "passing.js", Status: evergreen.TestSucceededStatus},
+		{TaskID: "test", TestFile: "failing.js", Status: evergreen.TestFailedStatus, LogID: "log"},
+		{TaskID: "lint-js", TestFile: "app.js", Status: evergreen.TestFailedStatus},
+	}
+	for _, r := range results {
+		s.NoError(r.Insert())
+	}
+
+	startTime := time.Now().Add(-time.Hour)
+	s.buildDoc = &build.Build{
+		Id:           bson.NewObjectId().Hex(),
+		BuildVariant: "ubuntu",
+		Version:      patchDoc.Version,
+		Status:       evergreen.BuildFailed,
+		StartTime:    startTime,
+		FinishTime:   startTime.Add(10 * time.Minute),
+		Tasks: []build.TaskCache{
+			{Id: "compile", Status: evergreen.TaskSucceeded},
+			{Id: "test", Status: evergreen.TaskFailed},
+			{Id: "lint", Status: evergreen.TaskFailed},
+		},
+	}
+	s.NoError(s.buildDoc.Insert())
+}
+
+func (s *githubCheckRunSuite) TestFailedBuildCheckRun() {
+	checkRun, err := makeGithubCheckRun(s.buildDoc, "abcdef", "https://example.com")
+	s.Require().NoError(err)
+
+	s.Equal("evergreen/ubuntu", checkRun.Name)
+	s.Equal("abcdef", checkRun.HeadSHA)
+	s.Equal(s.buildDoc.Id, checkRun.ExternalID)
+	s.Equal(thirdparty.GithubCheckRunCompleted, checkRun.Status)
+	s.Equal(thirdparty.GithubCheckRunFailure, checkRun.Conclusion)
+	s.Require().Len(checkRun.Actions, 1)
+	s.Equal(thirdparty.GithubCheckRunRerunFailed, checkRun.Actions[0].Identifier)
+	s.True(len(checkRun.Actions[0].Label) <= 20)
+	s.True(len(checkRun.Actions[0].Description) <= 40)
+
+	s.Contains(checkRun.Output.Summary, "2 of 3 tasks failed")
+	s.Contains(checkRun.Output.Summary, "[test](https://example.com/task/test/0)")
+	s.Require().Len(checkRun.Output.Annotations, 2)
+	paths := map[string]string{}
+	for _, a := range checkRun.Output.Annotations {
+		paths[a.Path] = a.Message
+	}
+	s.Contains(paths["failing.js"], "https://example.com/test_log/log")
+	s.Contains(paths["app.js"], "https://example.com/test_log/lint-js/0/app.js")
+}
+
+func (s *githubCheckRunSuite) TestSucceededBuildCheckRun() {
+	s.buildDoc.Status = evergreen.BuildSucceeded
+	s.buildDoc.Tasks = []build.TaskCache{{Id: "compile", Status: evergreen.TaskSucceeded}}
+
+	checkRun, err := makeGithubCheckRun(s.buildDoc, "abcdef", "https://example.com")
+	s.Require().NoError(err)
+	s.Equal(thirdparty.GithubCheckRunSuccess, checkRun.Conclusion)
+	s.Empty(checkRun.Actions)
+	s.Empty(checkRun.Output.Annotations)
+}
+
+func (s *githubCheckRunSuite) TestRunCreatesCheckRun() {
+	created := thirdparty.GithubCheckRun{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/installation"):
+			_, _ = w.Write([]byte(`{"id": 1}`))
+		case strings.HasSuffix(r.URL.Path, "/access_tokens"):
+			_, _ = w.Write([]byte(`{"token": "token"}`))
+		case r.URL.Path == "/repos/evergreen-ci/evergreen/check-runs":
+			s.NoError(json.NewDecoder(r.Body).Decode(&created))
+			_, _ = w.Write([]byte(`{"id": 2}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	j := makeGithubCheckRunJob()
+	j.BuildID = s.buildDoc.Id
+	j.env = &mockSettingsEnv{Environment: evergreen.GetEnvironment(), settings: &evergreen.Settings{
+		Ui: evergreen.UIConfig{Url: "https://example.com"},
+		GithubApp: evergreen.GithubAppConfig{
+			AppID:      1,
+			PrivateKey: s.appKey,
+			APIURL:     server.URL,
+		},
+	}}
+
+	j.Run()
+	s.NoError(j.Error())
+	s.Equal("evergreen/ubuntu", created.Name)
+	s.Equal("776f608b5b12cd27b8d931c8ee4ca0c13f857299", created.HeadSHA)
+}
+
+func (s *githubCheckRunSuite) TestRunWithoutGithubAppFails() {
+	j := makeGithubCheckRunJob()
+	j.BuildID = s.buildDoc.Id
+	j.env = &mockSettingsEnv{Environment: evergreen.GetEnvironment(), settings: &evergreen.Settings{}}
+
+	j.Run()
+	s.Error(j.Error())
+}
+
+type mockSettingsEnv struct {
+	evergreen.Environment
+	settings *evergreen.Settings
+}
+
+func (e *mockSettingsEnv) Settings() *evergreen.Settings { return e.settings }
@@ -0,0 +1,348 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/admin"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/evergreen-ci/evergreen/util"
+	"github.com/google/go-github/github"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/sometimes"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	githubCommentCommandJobName = "github-comment-command"
+
+	// githubCommentCommandPrefix is the first word of pull request comments
+	// that are Evergreen commands.
+	githubCommentCommandPrefix = "evergreen"
+
+	GithubCommentRetry    = "retry"
+	GithubCommentPatch    = "patch"
+	GithubCommentAbort    = "abort"
+	GithubCommentPriority = "priority"
+
+	githubCommentCommandTimeout = 30 * time.Second
+)
+
+func init() {
+	registry.AddJobType(githubCommentCommandJobName, func() amboy.Job { return makeGithubCommentCommandJob() })
+}
+
+// GithubCommentCommand is an Evergreen command in a pull request comment,
+// such as 'evergreen retry' or 'evergreen patch lint'.
+type GithubCommentCommand struct {
+	Name string `bson:"name" json:"name" yaml:"name"`
+	Arg  string `bson:"arg" json:"arg" yaml:"arg"`
+}
+
+// ParseGithubCommentCommand returns the command on the first line of the
+// comment, and false if the comment is not an Evergreen command.
+func ParseGithubCommentCommand(comment string) (GithubCommentCommand, bool) {
+	line := strings.SplitN(strings.TrimSpace(comment), "\n", 2)[0]
+	fields := strings.Fields(line)
+	if len(fields) == 0 || fields[0] != githubCommentCommandPrefix {
+		return GithubCommentCommand{}, false
+	}
+
+	command := GithubCommentCommand{}
+	if len(fields) > 1 {
+		command.Name = strings.ToLower(fields[1])
+		command.Arg = strings.Join(fields[2:], " ")
+	}
+	return command, true
+}
+
+func (c GithubCommentCommand) String() string {
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s", githubCommentCommandPrefix, c.Name, c.Arg))
+}
+
+// Validate returns an error describing how to use the command if its
+// arguments are wrong, or if it is not a command.
+func (c GithubCommentCommand) Validate() error {
+	switch c.Name {
+	case GithubCommentRetry, GithubCommentAbort:
+		if c.Arg != "" {
+			return errors.Errorf("'%s' takes no arguments", c.Name)
+		}
+
+	case GithubCommentPatch:
+		if c.Arg == "" || len(strings.Fields(c.Arg)) != 1 {
+			return errors.New("'patch' takes the name of an alias")
+		}
+
+	case GithubCommentPriority:
+		priority, err := strconv.ParseInt(c.Arg, 10, 64)
+		if err != nil {
+			return errors.New("'priority' takes a number")
+		}
+		if priority > evergreen.MaxTaskPriority {
+			return errors.Errorf("priority can be at most %d", evergreen.MaxTaskPriority)
+		}
+
+	default:
+		return errors.Errorf("unknown command '%s'; the commands are 'retry', 'patch <alias>', 'abort' and 'priority <n>'", c.Name)
+	}
+
+	return nil
+}
+
+type githubCommentCommandJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+	env      evergreen.Environment
+
+	MsgID     string               `bson:"msg_id" json:"msg_id" yaml:"msg_id"`
+	Owner     string               `bson:"owner" json:"owner" yaml:"owner"`
+	Repo      string               `bson:"repo" json:"repo" yaml:"repo"`
+	PRNumber  int                  `bson:"pr_number" json:"pr_number" yaml:"pr_number"`
+	CommentID int                  `bson:"comment_id" json:"comment_id" yaml:"comment_id"`
+	User      string               `bson:"user" json:"user" yaml:"user"`
+	Command   GithubCommentCommand `bson:"command" json:"command" yaml:"command"`
+}
+
+func makeGithubCommentCommandJob() *githubCommentCommandJob {
+	return &githubCommentCommandJob{
+		env: evergreen.GetEnvironment(),
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    githubCommentCommandJobName,
+				Version: 0,
+				Format:  amboy.BSON,
+			},
+		},
+	}
+}
+
+// NewGithubCommentCommandJob creates a job to run the command that the user
+// commented on the pull request, and acknowledge the comment.
+func NewGithubCommentCommandJob(msgID, owner, repo string, prNumber, commentID int, user string, command GithubCommentCommand) amboy.Job {
+	j := makeGithubCommentCommandJob()
+	j.MsgID = msgID
+	j.Owner = owner
+	j.Repo = repo
+	j.PRNumber = prNumber
+	j.CommentID = commentID
+	j.User = user
+	j.Command = command
+
+	j.SetID(fmt.Sprintf("%s:%s/%s#%d-%s", githubCommentCommandJobName, owner, repo, prNumber, msgID))
+	return j
+}
+
+func (j *githubCommentCommandJob) Run() {
+	defer j.MarkComplete()
+	ctx, cancel := context.WithTimeout(context.Background(), githubCommentCommandTimeout)
+	defer cancel()
+
+	adminSettings, err := admin.GetSettings()
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error retrieving admin settings"))
+		return
+	}
+	if adminSettings.ServiceFlags.GithubPRTestingDisabled {
+		grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+			"job":     githubCommentCommandJobName,
+			"message": "github pr testing is disabled, not running comment command",
+		})
+		j.AddError(errors.New("github pr testing is disabled, not running comment command"))
+		return
+	}
+
+	githubOauthToken, err := j.env.Settings().GetGithubOauthToken()
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	httpClient, err := util.GetHttpClientForOauth2(githubOauthToken)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	defer util.PutHttpClientForOauth2(httpClient)
+	client := github.NewClient(httpClient)
+
+	projectRef, err := model.FindOneProjectRefByRepo(j.Owner, j.Repo)
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "could not find project ref for repo '%s/%s'", j.Owner, j.Repo))
+		return
+	}
+
+	authorized, err := j.isAuthorized(ctx, client, projectRef)
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+	if !authorized {
+		grip.Info(message.Fields{
+			"message":   "rejecting pull request comment command from unauthorized user",
+			"job":       j.ID(),
+			"user":      j.User,
+			"repo":      fmt.Sprintf("%s/%s", j.Owner, j.Repo),
+			"pr_number": j.PRNumber,
+			"command":   j.Command.String(),
+		})
+		j.reply(ctx, client, fmt.Sprintf("@%s you are not authorized to run Evergreen commands on this pull request.", j.User))
+		return
+	}
+
+	if err = j.runCommand(ctx, client, projectRef); err != nil {
+		j.AddError(err)
+		j.reply(ctx, client, fmt.Sprintf("@%s Evergreen could not run `%s`: %s", j.User, j.Command.String(), err.Error()))
+		return
+	}
+
+	j.AddError(thirdparty.AddGithubCommentReaction(ctx, githubOauthToken, j.Owner, j.Repo,
+		j.CommentID, thirdparty.GithubReactionPlusOne))
+}
+
+// isAuthorized returns true if the user can write to the repository, or is
+// one of the project's comment command users.
+func (j *githubCommentCommandJob) isAuthorized(ctx context.Context, client *github.Client, projectRef *model.ProjectRef) (bool, error) {
+	if util.StringSliceContains(projectRef.CommentCommandUsers, j.User) {
+		return true, nil
+	}
+
+	level, _, err := client.Repositories.GetPermissionLevel(ctx, j.Owner, j.Repo, j.User)
+	if err != nil {
+		return false, errors.Wrapf(err, "error fetching permission of %s on %s/%s", j.User, j.Owner, j.Repo)
+	}
+	switch level.GetPermission() {
+	case "admin", "write":
+		return true, nil
+	}
+	return false, nil
+}
+
+func (j *githubCommentCommandJob) runCommand(ctx context.Context, client *github.Client, projectRef *model.ProjectRef) error {
+	if err := j.Command.Validate(); err != nil {
+		return err
+	}
+
+	switch j.Command.Name {
+	case GithubCommentRetry:
+		return j.retry()
+
+	case GithubCommentPatch:
+		return j.patch(ctx, client, projectRef)
+
+	case GithubCommentAbort:
+		return errors.Wrap(model.CancelPatchesWithGithubPatchData(time.Now(), j.Owner, j.Repo, j.PRNumber),
+			"error aborting patches")
+
+	case GithubCommentPriority:
+		return j.setPriority()
+	}
+
+	return errors.Errorf("unknown command '%s'", j.Command.Name)
+}
+
+// retry restarts the failed tasks of the pull request's latest patch.
+func (j *githubCommentCommandJob) retry() error {
+	p, err := j.latestPatch()
+	if err != nil {
+		return err
+	}
+
+	builds, err := build.Find(build.ByVersion(p.Version))
+	if err != nil {
+		return errors.Wrapf(err, "error finding builds of patch %s", p.Id.Hex())
+	}
+	taskIds := []string{}
+	for _, b := range builds {
+		for _, t := range b.Tasks {
+			if t.Status == evergreen.TaskFailed {
+				taskIds = append(taskIds, t.Id)
+			}
+		}
+	}
+	if len(taskIds) == 0 {
+		return errors.New("the latest patch has no failed tasks")
+	}
+
+	return errors.Wrap(model.RestartVersion(p.Version, taskIds, false, evergreen.GithubPatchUser),
+		"error restarting failed tasks")
+}
+
+// patch creates a patch of the pull request that runs the variants and
+// tasks of the alias.
+func (j *githubCommentCommandJob) patch(ctx context.Context, client *github.Client, projectRef *model.ProjectRef) error {
+	alias := j.Command.Arg
+	aliases, err := model.FindProjectAliases(projectRef.Identifier, alias)
+	if err != nil {
+		return errors.Wrapf(err, "error finding alias '%s'", alias)
+	}
+	if len(aliases) == 0 {
+		return errors.Errorf("project '%s' has no alias '%s'", projectRef.Identifier, alias)
+	}
+
+	pr, _, err := client.PullRequests.Get(ctx, j.Owner, j.Repo, j.PRNumber)
+	if err != nil {
+		return errors.Wrap(err, "error fetching pull request")
+	}
+
+	intent, err := patch.NewGithubCommentIntent(j.MsgID, j.User, alias, pr)
+	if err != nil {
+		return err
+	}
+	if err = intent.Insert(); err != nil {
+		return errors.Wrap(err, "error saving patch intent")
+	}
+
+	return errors.Wrap(j.env.RemoteQueue().Put(NewPatchIntentProcessor(bson.NewObjectId(), intent)),
+		"error queueing patch")
+}
+
+// setPriority sets the priority of the tasks of the pull request's latest
+// patch.
+func (j *githubCommentCommandJob) setPriority() error {
+	priority, err := strconv.ParseInt(j.Command.Arg, 10, 64)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	p, err := j.latestPatch()
+	if err != nil {
+		return err
+	}
+
+	return errors.Wrap(model.SetVersionPriority(p.Version, priority), "error setting priority")
+}
+
+// latestPatch returns the most recent patch of the pull request that has
+// been finalized.
+func (j *githubCommentCommandJob) latestPatch() (*patch.Patch, error) {
+	patches, err := patch.Find(patch.ByGithubPRAndCreatedBefore(time.Now(), j.Owner, j.Repo, j.PRNumber).
+		Sort([]string{"-" + patch.CreateTimeKey}))
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding patches")
+	}
+	for i := range patches {
+		if patches[i].Version != "" {
+			return &patches[i], nil
+		}
+	}
+
+	return nil, errors.New("the pull request has no patches")
+}
+
+func (j *githubCommentCommandJob) reply(ctx context.Context, client *github.Client, body string) {
+	_, _, err := client.Issues.CreateComment(ctx, j.Owner, j.Repo, j.PRNumber, &github.IssueComment{
+		Body: github.String(body),
+	})
+	j.AddError(errors.Wrap(err, "error replying to comment"))
+}
@@ -0,0 +1,67 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGithubCommentCommand(t *testing.T) {
+	assert := assert.New(t)
+
+	for comment, expected := range map[string]GithubCommentCommand{
+		"evergreen retry":                    {Name: GithubCommentRetry},
+		"  evergreen Abort  ":                {Name: GithubCommentAbort},
+		"evergreen patch lint\nthanks!":      {Name: GithubCommentPatch, Arg: "lint"},
+		"evergreen priority 50":              {Name: GithubCommentPriority, Arg: "50"},
+		"evergreen patch lint and unit test": {Name: GithubCommentPatch, Arg: "lint and unit test"},
+		"evergreen":                          {},
+	} {
+		command, ok := ParseGithubCommentCommand(comment)
+		assert.True(ok, comment)
+		assert.Equal(expected, command, comment)
+	}
+
+	for _, comment := range []string{
+		"",
+		"LGTM",
+		"Evergreen retry",
+		"please evergreen retry",
+		"@octocat Evergreen could not run `evergreen retry`: the pull request has no patches",
+		"thanks!\nevergreen retry",
+	} {
+		_, ok := ParseGithubCommentCommand(comment)
+		assert.False(ok, comment)
+	}
+}
+
+func TestGithubCommentCommandValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, command := range []GithubCommentCommand{
+		{Name: GithubCommentRetry},
+		{Name: GithubCommentAbort},
+		{Name: GithubCommentPatch, Arg: "lint"},
+		{Name: GithubCommentPriority, Arg: "100"},
+		{Name: GithubCommentPriority, Arg: "-1"},
+	} {
+		assert.NoError(command.Validate(), command.String())
+	}
+
+	for _, command := range []GithubCommentCommand{
+		{},
+		{Name: "restart"},
+		{Name: GithubCommentRetry, Arg: "now"},
+		{Name: GithubCommentAbort, Arg: "all"},
+		{Name: GithubCommentPatch},
+		{Name: GithubCommentPatch, Arg: "lint and unit test"},
+		{Name: GithubCommentPriority},
+		{Name: GithubCommentPriority, Arg: "high"},
+		{Name: GithubCommentPriority, Arg: "101"},
+	} {
+		assert.Error(command.Validate(), command.String())
+	}
+
+	assert.Equal("evergreen patch lint", GithubCommentCommand{Name: GithubCommentPatch, Arg: "lint"}.String())
+	assert.Equal("evergreen retry", GithubCommentCommand{Name: GithubCommentRetry}.String())
+}
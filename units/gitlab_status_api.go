@@ -0,0 +1,222 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model/admin"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/sometimes"
+	"github.com/pkg/errors"
+)
+
+const (
+	gitlabStatusUpdateJobName = "gitlab-status-update"
+
+	gitlabStatusUpdateTimeout = 10 * time.Second
+)
+
+func init() {
+	registry.AddJobType(gitlabStatusUpdateJobName, func() amboy.Job { return makeGitlabStatusUpdateJob() })
+}
+
+// gitlabStatus is a commit status of a merge request's head commit, and
+// the note to comment on the merge request, if any.
+type gitlabStatus struct {
+	ProjectID int    `json:"project_id"`
+	MRNumber  int    `json:"mr_number"`
+	Ref       string `json:"ref"`
+	URLPath   string `json:"url_path"`
+	Note      string `json:"note"`
+
+	Status thirdparty.GitlabCommitStatus `json:"status"`
+}
+
+type gitlabStatusUpdateJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+	env      evergreen.Environment
+
+	FetchID    string `bson:"fetch_id" json:"fetch_id" yaml:"fetch_id"`
+	UpdateType string `bson:"update_type" json:"update_type" yaml:"update_type"`
+}
+
+func makeGitlabStatusUpdateJob() *gitlabStatusUpdateJob {
+	return &gitlabStatusUpdateJob{
+		env: evergreen.GetEnvironment(),
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    gitlabStatusUpdateJobName,
+				Version: 0,
+				Format:  amboy.BSON,
+			},
+		},
+	}
+}
+
+// NewGitlabStatusUpdateJobForBuild creates a job to set the GitLab commit
+// status of a finished merge request build. Status will be reported as
+// 'evergreen-[build variant name]'
+func NewGitlabStatusUpdateJobForBuild(buildID string) amboy.Job {
+	job := makeGitlabStatusUpdateJob()
+	job.FetchID = buildID
+	job.UpdateType = githubUpdateTypeBuild
+
+	job.SetID(fmt.Sprintf("%s:%s-%s-%s", gitlabStatusUpdateJobName, job.UpdateType, buildID, time.Now().String()))
+	return job
+}
+
+// NewGitlabStatusUpdateJobForPatchWithVersion creates a job to set the
+// GitLab commit status of a merge request patch with the specified version.
+// Status will be reported as 'evergreen', and failed patches are also
+// commented on the merge request.
+func NewGitlabStatusUpdateJobForPatchWithVersion(version string) amboy.Job {
+	job := makeGitlabStatusUpdateJob()
+	job.FetchID = version
+	job.UpdateType = githubUpdateTypePatchWithVersion
+
+	job.SetID(fmt.Sprintf("%s:%s-%s-%s", gitlabStatusUpdateJobName, job.UpdateType, version, time.Now().String()))
+	return job
+}
+
+func (j *gitlabStatusUpdateJob) fetch(status *gitlabStatus) error {
+	patchVersion := j.FetchID
+	if j.UpdateType == githubUpdateTypeBuild {
+		b, err := build.FindOne(build.ById(j.FetchID))
+		if err != nil {
+			return err
+		}
+		if b == nil {
+			return errors.New("can't find build")
+		}
+
+		patchVersion = b.Version
+		status.Status.Name = fmt.Sprintf("evergreen-%s", b.BuildVariant)
+		status.Status.Description = taskStatusToDesc(b)
+		status.URLPath = fmt.Sprintf("/build/%s", b.Id)
+
+		switch b.Status {
+		case evergreen.BuildSucceeded:
+			status.Status.State = thirdparty.GitlabStatusSuccess
+
+		case evergreen.BuildFailed:
+			status.Status.State = thirdparty.GitlabStatusFailed
+
+		default:
+			return errors.New("build status is pending; refusing to update status")
+		}
+	}
+
+	patchDoc, err := patch.FindOne(patch.ByVersion(patchVersion))
+	if err != nil {
+		return err
+	}
+	if patchDoc == nil {
+		return errors.New("can't find patch")
+	}
+
+	if j.UpdateType == githubUpdateTypePatchWithVersion {
+		status.URLPath = fmt.Sprintf("/version/%s", patchVersion)
+		status.Status.Name = "evergreen"
+
+		switch patchDoc.Status {
+		case evergreen.PatchSucceeded:
+			status.Status.State = thirdparty.GitlabStatusSuccess
+			status.Status.Description = fmt.Sprintf("patch finished in %s", patchDoc.FinishTime.Sub(patchDoc.StartTime).String())
+
+		case evergreen.PatchFailed:
+			status.Status.State = thirdparty.GitlabStatusFailed
+			status.Status.Description = fmt.Sprintf("patch finished in %s", patchDoc.FinishTime.Sub(patchDoc.StartTime).String())
+			status.Note = fmt.Sprintf("Evergreen patch failed for %s", patchDoc.GitlabPatchData.HeadHash)
+
+		case evergreen.PatchCreated:
+			status.Status.State = thirdparty.GitlabStatusPending
+			status.Status.Description = "preparing to run tasks"
+
+		case evergreen.PatchStarted:
+			status.Status.State = thirdparty.GitlabStatusRunning
+			status.Status.Description = "tasks are running"
+
+		default:
+			return errors.New("unknown patch status")
+		}
+	}
+
+	status.ProjectID = patchDoc.GitlabPatchData.ProjectID
+	status.MRNumber = patchDoc.GitlabPatchData.MRNumber
+	status.Ref = patchDoc.GitlabPatchData.HeadHash
+	if status.ProjectID == 0 || status.MRNumber == 0 || status.Ref == "" {
+		return errors.Errorf("patch %s is not for a gitlab merge request", patchDoc.Id.Hex())
+	}
+	return nil
+}
+
+func (j *gitlabStatusUpdateJob) sendStatusUpdate(ctx context.Context, status *gitlabStatus) error {
+	settings := j.env.Settings()
+	if settings == nil || settings.Ui.Url == "" {
+		return errors.New("ui not configured")
+	}
+	if !settings.Gitlab.Enabled() {
+		return errors.New("gitlab is not configured")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, gitlabStatusUpdateTimeout)
+	defer cancel()
+
+	targetURL := fmt.Sprintf("%s%s", settings.Ui.Url, status.URLPath)
+	status.Status.TargetURL = targetURL
+	if err := thirdparty.SetGitlabCommitStatus(ctx, settings.Gitlab, status.ProjectID, status.Ref, status.Status); err != nil {
+		return err
+	}
+	if status.Note == "" {
+		return nil
+	}
+	note := fmt.Sprintf("%s: %s ([details](%s))", status.Note, status.Status.Description, targetURL)
+	return thirdparty.CreateGitlabMergeRequestNote(ctx, settings.Gitlab, status.ProjectID, status.MRNumber, note)
+}
+
+func (j *gitlabStatusUpdateJob) Run() {
+	defer j.MarkComplete()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	adminSettings, err := admin.GetSettings()
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error retrieving admin settings"))
+		return
+	}
+	if adminSettings.ServiceFlags.GitlabMRTestingDisabled {
+		grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+			"job":     gitlabStatusUpdateJobName,
+			"message": "gitlab mr testing is disabled, not updating status",
+		})
+		j.AddError(errors.New("gitlab mr testing is disabled, not updating status"))
+		return
+	}
+
+	status := gitlabStatus{}
+	if err := j.fetch(&status); err != nil {
+		j.AddError(err)
+		return
+	}
+
+	if err := j.sendStatusUpdate(ctx, &status); err != nil {
+		grip.Alert(message.WrapError(err, message.Fields{
+			"message":     "gitlab API failure",
+			"source":      "status updates",
+			"job":         j.ID(),
+			"status":      status,
+			"fetch_id":    j.FetchID,
+			"update_type": j.UpdateType,
+		}))
+		j.AddError(err)
+	}
+}
@@ -0,0 +1,173 @@
+package units
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/db"
+	"github.com/evergreen-ci/evergreen/model/admin"
+	"github.com/evergreen-ci/evergreen/model/build"
+	"github.com/evergreen-ci/evergreen/model/patch"
+	"github.com/evergreen-ci/evergreen/testutil"
+	"github.com/evergreen-ci/evergreen/thirdparty"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type gitlabStatusUpdateSuite struct {
+	suite.Suite
+	patchDoc *patch.Patch
+	buildDoc *build.Build
+	cancel   func()
+}
+
+func TestGitlabStatusUpdate(t *testing.T) {
+	suite.Run(t, new(gitlabStatusUpdateSuite))
+}
+
+func (s *gitlabStatusUpdateSuite) SetupSuite() {
+	evergreen.ResetEnvironment()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.Require().NoError(evergreen.GetEnvironment().Configure(ctx, filepath.Join(evergreen.FindEvergreenHome(), testutil.TestDir, testutil.TestSettings)))
+}
+
+func (s *gitlabStatusUpdateSuite) TearDownSuite() {
+	s.cancel()
+	evergreen.ResetEnvironment()
+}
+
+func (s *gitlabStatusUpdateSuite) SetupTest() {
+	s.NoError(db.ClearCollections(admin.Collection, patch.Collection, build.Collection))
+	startTime := time.Now()
+	s.patchDoc = &patch.Patch{
+		Id:         bson.NewObjectId(),
+		Version:    bson.NewObjectId().Hex(),
+		Status:     evergreen.PatchFailed,
+		StartTime:  startTime,
+		FinishTime: startTime.Add(10 * time.Minute),
+		GitlabPatchData: patch.GitlabPatch{
+			ProjectID:    3,
+			MRNumber:     12,
+			Project:      "evergreen-ci/evergreen",
+			TargetBranch: "master",
+			HeadHash:     "da1560886d4f094c3e6c9ef40349f7d38b5d27d7",
+		},
+	}
+
+	s.buildDoc = &build.Build{
+		Id:           bson.NewObjectId().Hex(),
+		BuildVariant: "testvariant",
+		Version:      s.patchDoc.Version,
+		Status:       evergreen.BuildFailed,
+	}
+
+	s.NoError(s.patchDoc.Insert())
+	s.NoError(s.buildDoc.Insert())
+}
+
+func (s *gitlabStatusUpdateSuite) TestFetchForBuild() {
+	job, ok := NewGitlabStatusUpdateJobForBuild(s.buildDoc.Id).(*gitlabStatusUpdateJob)
+	s.Require().True(ok)
+
+	status := gitlabStatus{}
+	s.NoError(job.fetch(&status))
+	s.Equal(3, status.ProjectID)
+	s.Equal(12, status.MRNumber)
+	s.Equal("da1560886d4f094c3e6c9ef40349f7d38b5d27d7", status.Ref)
+	s.Equal("/build/"+s.buildDoc.Id, status.URLPath)
+	s.Equal("evergreen-testvariant", status.Status.Name)
+	s.Equal(thirdparty.GitlabStatusFailed, status.Status.State)
+	s.Empty(status.Note)
+}
+
+func (s *gitlabStatusUpdateSuite) TestFetchForPatch() {
+	job, ok := NewGitlabStatusUpdateJobForPatchWithVersion(s.patchDoc.Version).(*gitlabStatusUpdateJob)
+	s.Require().True(ok)
+
+	status := gitlabStatus{}
+	s.NoError(job.fetch(&status))
+	s.Equal("/version/"+s.patchDoc.Version, status.URLPath)
+	s.Equal("evergreen", status.Status.Name)
+	s.Equal(thirdparty.GitlabStatusFailed, status.Status.State)
+	s.Equal("patch finished in 10m0s", status.Status.Description)
+	s.NotEmpty(status.Note)
+
+	s.Require().NoError(patch.UpdateOne(patch.ById(s.patchDoc.Id),
+		bson.M{"$set": bson.M{patch.StatusKey: evergreen.PatchStarted}}))
+	status = gitlabStatus{}
+	s.NoError(job.fetch(&status))
+	s.Equal(thirdparty.GitlabStatusRunning, status.Status.State)
+	s.Empty(status.Note)
+}
+
+func (s *gitlabStatusUpdateSuite) TestFetchForGithubPatch() {
+	p := &patch.Patch{
+		Id:      bson.NewObjectId(),
+		Version: bson.NewObjectId().Hex(),
+		Status:  evergreen.PatchSucceeded,
+	}
+	s.Require().NoError(p.Insert())
+
+	job, ok := NewGitlabStatusUpdateJobForPatchWithVersion(p.Version).(*gitlabStatusUpdateJob)
+	s.Require().True(ok)
+	s.Error(job.fetch(&gitlabStatus{}))
+}
+
+func (s *gitlabStatusUpdateSuite) TestRunPostsStatusAndNote() {
+	statuses := []thirdparty.GitlabCommitStatus{}
+	notes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/projects/3/statuses/da1560886d4f094c3e6c9ef40349f7d38b5d27d7":
+			status := thirdparty.GitlabCommitStatus{}
+			s.NoError(json.NewDecoder(r.Body).Decode(&status))
+			statuses = append(statuses, status)
+		case "/api/v4/projects/3/merge_requests/12/notes":
+			notes++
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 1}`))
+	}))
+	defer server.Close()
+
+	j, ok := NewGitlabStatusUpdateJobForPatchWithVersion(s.patchDoc.Version).(*gitlabStatusUpdateJob)
+	s.Require().True(ok)
+	j.env = &mockSettingsEnv{Environment: evergreen.GetEnvironment(), settings: &evergreen.Settings{
+		Ui: evergreen.UIConfig{Url: "https://example.com"},
+		Gitlab: evergreen.GitlabConfig{
+			URL:           server.URL,
+			Token:         "gitlab-token",
+			WebhookSecret: "secret",
+		},
+	}}
+
+	j.Run()
+	s.NoError(j.Error())
+	s.Require().Len(statuses, 1)
+	s.Equal("evergreen", statuses[0].Name)
+	s.Equal(thirdparty.GitlabStatusFailed, statuses[0].State)
+	s.Equal("https://example.com/version/"+s.patchDoc.Version, statuses[0].TargetURL)
+	s.Equal(1, notes)
+}
+
+func (s *gitlabStatusUpdateSuite) TestRunWithoutGitlab() {
+	j, ok := NewGitlabStatusUpdateJobForBuild(s.buildDoc.Id).(*gitlabStatusUpdateJob)
+	s.Require().True(ok)
+	j.env = &mockSettingsEnv{Environment: evergreen.GetEnvironment(), settings: &evergreen.Settings{
+		Ui: evergreen.UIConfig{Url: "https://example.com"},
+	}}
+
+	j.Run()
+	s.Error(j.Error())
+}
@@ -30,7 +30,11 @@ import (
 	yaml "gopkg.in/yaml.v2"
 )
 
-const patchIntentJobName = "patch-intent-processor"
+const (
+	patchIntentJobName = "patch-intent-processor"
+
+	gitlabRequestTimeout = 30 * time.Second
+)
 
 func init() {
 	registry.AddJobType(patchIntentJobName,
@@ -103,9 +107,31 @@ func (j *patchIntentProcessor) Run() {
 			"intent_id":          j.Intent.ID(),
 		}))
 
-		j.AddError(model.CancelPatchesWithGithubPatchData(patchDoc.CreateTime,
-			patchDoc.GithubPatchData.BaseOwner, patchDoc.GithubPatchData.BaseRepo,
-			patchDoc.GithubPatchData.PRNumber))
+		// patches of other aliases, requested by pull request comments,
+		// run alongside the pull request's patch rather than replacing it
+		if j.Intent.GetAlias() == patch.GithubAlias {
+			j.AddError(model.CancelPatchesWithGithubPatchData(patchDoc.CreateTime,
+				patchDoc.GithubPatchData.BaseOwner, patchDoc.GithubPatchData.BaseRepo,
+				patchDoc.GithubPatchData.PRNumber))
+		}
+	}
+
+	if j.Intent.GetType() == patch.GitlabIntentType {
+		update := NewGitlabStatusUpdateJobForPatchWithVersion(patchDoc.Version)
+		err = j.env.LocalQueue().Put(update)
+		j.AddError(err)
+		grip.ErrorWhen(err != nil, message.WrapError(err, message.Fields{
+			"message":            "Failed to queue status update",
+			"job":                j.ID(),
+			"patch_id":           j.PatchID,
+			"update_id":          update.ID(),
+			"update_for_version": patchDoc.Version,
+			"intent_type":        j.Intent.GetType(),
+			"intent_id":          j.Intent.ID(),
+		}))
+
+		j.AddError(model.CancelPatchesWithGitlabPatchData(patchDoc.CreateTime,
+			patchDoc.GitlabPatchData.ProjectID, patchDoc.GitlabPatchData.MRNumber))
 	}
 }
 
@@ -119,6 +145,9 @@ func (j *patchIntentProcessor) finishPatch(patchDoc *patch.Patch, githubOauthTok
 	case patch.GithubIntentType:
 		catcher.Add(j.buildGithubPatchDoc(patchDoc, githubOauthToken))
 
+	case patch.GitlabIntentType:
+		catcher.Add(j.buildGitlabPatchDoc(patchDoc))
+
 	default:
 		return errors.Errorf("Intent type '%s' is unknown", j.Intent.GetType())
 	}
@@ -150,7 +179,15 @@ func (j *patchIntentProcessor) finishPatch(patchDoc *patch.Patch, githubOauthTok
 	}
 
 	// Get and validate patched config and add it to the patch document
-	project, err := validator.GetPatchedProject(patchDoc, githubOauthToken)
+	var project *model.Project
+	var err error
+	if j.Intent.GetType() == patch.GitlabIntentType {
+		ctx, cancel := context.WithTimeout(context.Background(), gitlabRequestTimeout)
+		project, err = validator.GetPatchedGitlabProject(ctx, patchDoc, j.env.Settings().Gitlab)
+		cancel()
+	} else {
+		project, err = validator.GetPatchedProject(patchDoc, githubOauthToken)
+	}
 	if err != nil {
 		return errors.Wrap(err, "invalid patched config")
 	}
@@ -178,6 +215,10 @@ func (j *patchIntentProcessor) finishPatch(patchDoc *patch.Patch, githubOauthTok
 		}
 	}
 
+	if err = project.ExpandTestSharding(); err != nil {
+		return errors.Wrap(err, "error sharding tests")
+	}
+
 	// add the project config
 	projectYamlBytes, err := yaml.Marshal(project)
 	if err != nil {
@@ -385,6 +426,140 @@ func (j *patchIntentProcessor) buildGithubPatchDoc(patchDoc *patch.Patch, github
 	return errors.Wrap(err, "failed to create github pull request user")
 }
 
+func (j *patchIntentProcessor) buildGitlabPatchDoc(patchDoc *patch.Patch) (err error) {
+	adminSettings, err := admin.GetSettings()
+	if err != nil {
+		return errors.Wrap(err, "gitlab mr testing is disabled, error retrieving admin settings")
+	}
+	if adminSettings.ServiceFlags.GitlabMRTestingDisabled {
+		grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+			"job":     patchIntentJobName,
+			"message": "gitlab mr testing is disabled, not processing merge request",
+
+			"intent_type": j.Intent.GetType(),
+			"intent_id":   j.Intent.ID(),
+		})
+		return errors.New("gitlab mr testing is disabled, not processing merge request")
+	}
+	defer j.Intent.SetProcessed()
+
+	conf := j.env.Settings().Gitlab
+	if !conf.Enabled() {
+		return errors.New("GitLab merge request testing not configured correctly; requires a GitLab URL, token and webhook secret")
+	}
+
+	mrData := patchDoc.GitlabPatchData
+	owner, repo := splitGitlabProjectName(mrData.Project)
+	projectRef, err := model.FindOneProjectRefByRepo(owner, repo)
+	if err != nil {
+		return errors.Wrapf(err, "Could not fetch project ref for repo '%s'", mrData.Project)
+	}
+	if projectRef == nil {
+		return errors.Errorf("Could not find project ref for repo '%s'", mrData.Project)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), gitlabRequestTimeout)
+	defer cancel()
+
+	// like pull requests, only merge requests by users who can push to the
+	// project are tested
+	accessLevel, err := thirdparty.GetGitlabAccessLevel(ctx, conf, mrData.ProjectID, mrData.AuthorID)
+	if err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message":    "gitlab API failure",
+			"source":     "patch intents",
+			"job":        j.ID(),
+			"patch_id":   j.PatchID,
+			"project":    mrData.Project,
+			"project_id": mrData.ProjectID,
+			"mr_number":  mrData.MRNumber,
+
+			"intent_type": j.Intent.GetType(),
+			"intent_id":   j.Intent.ID(),
+		}))
+		return err
+	}
+	if accessLevel < thirdparty.GitlabDeveloperAccess {
+		return errors.Errorf("user %s does not have developer access to %s", mrData.Author, mrData.Project)
+	}
+
+	patchDoc.Githash, err = thirdparty.GetGitlabMergeBase(ctx, conf, mrData.ProjectID, mrData.TargetBranch, mrData.HeadHash)
+	if err == nil {
+		var patchContent string
+		patchContent, err = thirdparty.GetGitlabMergeRequestDiff(ctx, conf, mrData.ProjectID, mrData.MRNumber)
+		if err == nil {
+			err = j.addGitlabPatchFile(patchDoc, patchContent)
+		}
+	}
+	if err != nil {
+		grip.Error(message.WrapError(err, message.Fields{
+			"message":    "gitlab API failure",
+			"source":     "patch intents",
+			"job":        j.ID(),
+			"patch_id":   j.PatchID,
+			"project":    mrData.Project,
+			"project_id": mrData.ProjectID,
+			"mr_number":  mrData.MRNumber,
+
+			"intent_type": j.Intent.GetType(),
+			"intent_id":   j.Intent.ID(),
+		}))
+		return err
+	}
+	patchDoc.Project = projectRef.Identifier
+
+	j.user, err = user.FindOne(user.ById(evergreen.GitlabPatchUser))
+	if err != nil {
+		return err
+	}
+	if j.user == nil {
+		j.user = &user.DBUser{
+			Id:       evergreen.GitlabPatchUser,
+			DispName: "GitLab Merge Requests",
+			APIKey:   util.RandomString(),
+		}
+		err = j.user.Insert()
+	}
+
+	return errors.Wrap(err, "failed to create gitlab merge request user")
+}
+
+// addGitlabPatchFile stores the diff of a merge request as the patch's file.
+func (j *patchIntentProcessor) addGitlabPatchFile(patchDoc *patch.Patch, patchContent string) error {
+	if len(patchContent) == 0 || len(patchContent) > patch.SizeLimit {
+		return errors.Errorf("Patch contents must be at least 1 byte and no greater than %d bytes; was %d bytes",
+			patch.SizeLimit, len(patchContent))
+	}
+
+	summaries, err := thirdparty.GetPatchSummaries(patchContent)
+	if err != nil {
+		return err
+	}
+
+	patchFileID := fmt.Sprintf("%s_%s", patchDoc.Id.Hex(), patchDoc.Githash)
+	patchDoc.Patches = append(patchDoc.Patches, patch.ModulePatch{
+		ModuleName: "",
+		Githash:    patchDoc.Githash,
+		PatchSet: patch.PatchSet{
+			PatchFileId: patchFileID,
+			Summary:     summaries,
+		},
+	})
+
+	return errors.Wrap(db.WriteGridFile(patch.GridFSPrefix, patchFileID, strings.NewReader(patchContent)),
+		"failed to write patch file to db")
+}
+
+// splitGitlabProjectName returns the owner and repository of a project ref
+// that tests a GitLab project, which are the project's namespace and name.
+func splitGitlabProjectName(name string) (string, string) {
+	i := strings.LastIndex(name, "/")
+	if i < 0 {
+		return "", name
+	}
+	return name[:i], name[i+1:]
+}
+
 func authAndFetchPRMergeBase(ctx context.Context, patchDoc *patch.Patch, requiredOrganization, githubUser, githubOauthToken string) (bool, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
@@ -0,0 +1,98 @@
+package units
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/model"
+	"github.com/evergreen-ci/evergreen/model/admin"
+	"github.com/evergreen-ci/evergreen/repotracker"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/sometimes"
+	"github.com/pkg/errors"
+)
+
+const periodicBuildsJobName = "periodic-builds"
+
+func init() {
+	registry.AddJobType(periodicBuildsJobName, func() amboy.Job { return makePeriodicBuildsJob() })
+}
+
+type periodicBuildsJob struct {
+	job.Base `bson:"job_base" json:"job_base" yaml:"job_base"`
+	env      evergreen.Environment
+
+	// Scheduled is the last minute the job creates the versions of the
+	// periodic builds for.
+	Scheduled time.Time `bson:"scheduled" json:"scheduled" yaml:"scheduled"`
+}
+
+func makePeriodicBuildsJob() *periodicBuildsJob {
+	return &periodicBuildsJob{
+		env: evergreen.GetEnvironment(),
+		Base: job.Base{
+			JobType: amboy.JobType{
+				Name:    periodicBuildsJobName,
+				Version: 0,
+				Format:  amboy.BSON,
+			},
+		},
+	}
+}
+
+// NewPeriodicBuildsJob creates a job that creates the versions of all of the
+// periodic builds scheduled since their last runs, up to the minute of the
+// given time.
+func NewPeriodicBuildsJob(ts time.Time) amboy.Job {
+	j := makePeriodicBuildsJob()
+	j.Scheduled = ts.UTC().Truncate(time.Minute)
+	j.SetID(fmt.Sprintf("%s-%d", periodicBuildsJobName, j.Scheduled.Unix()))
+	return j
+}
+
+func (j *periodicBuildsJob) Run() {
+	defer j.MarkComplete()
+
+	adminSettings, err := admin.GetSettings()
+	if err != nil {
+		j.AddError(errors.Wrap(err, "error retrieving admin settings"))
+		return
+	}
+	if adminSettings.ServiceFlags.RepotrackerDisabled {
+		grip.InfoWhen(sometimes.Percent(evergreen.DegradedLoggingPercent), message.Fields{
+			"job":     periodicBuildsJobName,
+			"message": "repotracker is disabled, not creating periodic builds",
+		})
+		return
+	}
+
+	refs, err := model.FindPeriodicBuildProjectRefs()
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	settings := j.env.Settings()
+	for i := range refs {
+		ref := &refs[i]
+		for _, definition := range ref.PeriodicBuilds {
+			// a run that fails is retried by the next job, since the last
+			// run isn't moved past it
+			for _, scheduled := range definition.DueRuns(ref.PeriodicBuildsLastRun[definition.ID], j.Scheduled) {
+				if _, err = repotracker.CreatePeriodicVersion(settings, ref, definition, scheduled); err != nil {
+					j.AddError(errors.Wrapf(err, "error creating periodic build '%s' of %s", definition.ID, ref.Identifier))
+					break
+				}
+				if err = ref.SetPeriodicBuildLastRun(definition.ID, scheduled); err != nil {
+					j.AddError(err)
+					break
+				}
+			}
+		}
+	}
+}
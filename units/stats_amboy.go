@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	"github.com/evergreen-ci/evergreen"
+	"github.com/evergreen-ci/evergreen/metrics"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/job"
 	"github.com/mongodb/amboy/registry"
@@ -18,9 +19,13 @@ const (
 	numAmboyJobsToReport       = 128
 )
 
+var amboyJobs = metrics.NewGaugeVec("evergreen_amboy_jobs",
+	"Number of jobs in the amboy queues of the process, by queue and state.", "queue", "state")
+
 func init() {
 	registry.AddJobType(amboyStatsCollectorJobName,
 		func() amboy.Job { return makeAmboyStatsCollector() })
+	metrics.Default.AddCollector(collectAmboyMetrics)
 }
 
 type amboyStatsCollector struct {
@@ -79,3 +84,29 @@ func (j *amboyStatsCollector) Run() {
 		})
 	}
 }
+
+// collectAmboyMetrics sets the job counts of the queues that are running in
+// this process.
+func collectAmboyMetrics() error {
+	env := evergreen.GetEnvironment()
+	if env == nil {
+		return nil
+	}
+
+	amboyJobs.Reset()
+	for name, queue := range map[string]amboy.Queue{
+		"local":  env.LocalQueue(),
+		"remote": env.RemoteQueue(),
+	} {
+		if queue == nil || !queue.Started() {
+			continue
+		}
+		stats := queue.Stats()
+		amboyJobs.Set(float64(stats.Total), name, "total")
+		amboyJobs.Set(float64(stats.Pending), name, "pending")
+		amboyJobs.Set(float64(stats.Running), name, "running")
+		amboyJobs.Set(float64(stats.Completed), name, "completed")
+		amboyJobs.Set(float64(stats.Blocked), name, "blocked")
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// cronDescriptors are the shorthands accepted in place of the five fields of
+// a cron specification.
+var cronDescriptors = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@nightly":  "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+type cronField struct {
+	min, max int
+}
+
+var cronFields = []cronField{
+	{0, 59}, // minute
+	{0, 23}, // hour
+	{1, 31}, // day of month
+	{1, 12}, // month
+	{0, 7},  // day of week, where both 0 and 7 are Sunday
+}
+
+// CronSchedule is a parsed cron specification, with the minute, hour, day of
+// month, month and day of week fields. Schedules are evaluated in UTC.
+type CronSchedule struct {
+	spec   string
+	values [5]map[int]bool
+	// domRestricted and dowRestricted record whether the day of month and
+	// day of week fields are something other than "*", since a day matches
+	// if either of them matches when both are restricted.
+	domRestricted bool
+	dowRestricted bool
+}
+
+// ParseCron parses a cron specification of five space separated fields,
+// each of which may be "*", a number, a range, a list, or have a step, as in
+// "*/15 2-4,22 * * 1-5". The descriptors @hourly, @daily, @midnight,
+// @nightly, @weekly and @monthly are also accepted.
+func ParseCron(spec string) (*CronSchedule, error) {
+	expanded := strings.TrimSpace(spec)
+	if descriptor, ok := cronDescriptors[expanded]; ok {
+		expanded = descriptor
+	}
+	fields := strings.Fields(expanded)
+	if len(fields) != len(cronFields) {
+		return nil, errors.Errorf("cron spec '%s' must have %d fields", spec, len(cronFields))
+	}
+
+	schedule := &CronSchedule{spec: spec}
+	for i, field := range fields {
+		values, err := parseCronField(field, cronFields[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid cron spec '%s'", spec)
+		}
+		schedule.values[i] = values
+	}
+	if schedule.values[4][7] {
+		schedule.values[4][0] = true
+	}
+	schedule.domRestricted = fields[2] != "*"
+	schedule.dowRestricted = fields[4] != "*"
+	return schedule, nil
+}
+
+func parseCronField(field string, bounds cronField) (map[int]bool, error) {
+	values := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			var err error
+			step, err = strconv.Atoi(part[idx+1:])
+			if err != nil || step <= 0 {
+				return nil, errors.Errorf("invalid step in '%s'", part)
+			}
+			part = part[:idx]
+		}
+
+		start, end := bounds.min, bounds.max
+		if part != "*" {
+			limits := strings.SplitN(part, "-", 2)
+			var err error
+			if start, err = strconv.Atoi(limits[0]); err != nil {
+				return nil, errors.Errorf("invalid value '%s'", limits[0])
+			}
+			if len(limits) == 2 {
+				if end, err = strconv.Atoi(limits[1]); err != nil {
+					return nil, errors.Errorf("invalid value '%s'", limits[1])
+				}
+			} else if step == 1 {
+				end = start
+			}
+			// otherwise "n/step" runs from n to the end of the range
+		}
+		if start < bounds.min || end > bounds.max || start > end {
+			return nil, errors.Errorf("'%s' is out of the range %d-%d", part, bounds.min, bounds.max)
+		}
+		for v := start; v <= end; v += step {
+			values[v] = true
+		}
+	}
+	return values, nil
+}
+
+// Matches returns true if the schedule runs at the minute of the given time.
+func (s *CronSchedule) Matches(t time.Time) bool {
+	t = t.UTC()
+	if !s.values[0][t.Minute()] || !s.values[1][t.Hour()] || !s.values[3][int(t.Month())] {
+		return false
+	}
+	dom := s.values[2][t.Day()]
+	dow := s.values[4][int(t.Weekday())]
+	if s.domRestricted && s.dowRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// String returns the spec the schedule was parsed from.
+func (s *CronSchedule) String() string {
+	return s.spec
+}
@@ -0,0 +1,72 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	assert := assert.New(t)
+
+	for _, valid := range []string{"* * * * *", "0 2 * * *", "*/15 2-4,22 * * 1-5", "30 1 1,15 * *", "5/10 * * * *", "0 0 * * 7", "@nightly", " @weekly "} {
+		_, err := ParseCron(valid)
+		assert.NoError(err, valid)
+	}
+	for _, invalid := range []string{"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *",
+		"* * * 13 *", "* * * * 8", "*/0 * * * *", "5-1 * * * *", "a * * * *", "@yearly"} {
+		_, err := ParseCron(invalid)
+		assert.Error(err, invalid)
+	}
+}
+
+func TestCronScheduleMatches(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	// 2018-03-05 was a Monday
+	monday := time.Date(2018, time.March, 5, 2, 0, 0, 0, time.UTC)
+
+	schedule, err := ParseCron("0 2 * * *")
+	require.NoError(err)
+	assert.True(schedule.Matches(monday))
+	assert.True(schedule.Matches(monday.Add(30 * time.Second)))
+	assert.False(schedule.Matches(monday.Add(time.Minute)))
+	assert.False(schedule.Matches(monday.Add(time.Hour)))
+	assert.True(schedule.Matches(monday.In(time.FixedZone("EST", -5*60*60))))
+
+	schedule, err = ParseCron("*/15 2-4,22 * * 1-5")
+	require.NoError(err)
+	assert.True(schedule.Matches(monday.Add(45 * time.Minute)))
+	assert.True(schedule.Matches(monday.Add(20 * time.Hour)))
+	assert.False(schedule.Matches(monday.Add(10 * time.Minute)))
+	assert.False(schedule.Matches(monday.Add(5 * 24 * time.Hour)))
+
+	schedule, err = ParseCron("5/20 * * * *")
+	require.NoError(err)
+	assert.True(schedule.Matches(monday.Add(45 * time.Minute)))
+	assert.False(schedule.Matches(monday))
+
+	// either the day of month or the day of week may match when both are
+	// restricted
+	schedule, err = ParseCron("0 2 1 * 1")
+	require.NoError(err)
+	assert.True(schedule.Matches(monday))
+	assert.True(schedule.Matches(time.Date(2018, time.April, 1, 2, 0, 0, 0, time.UTC)))
+	assert.False(schedule.Matches(monday.Add(24 * time.Hour)))
+
+	schedule, err = ParseCron("@weekly")
+	require.NoError(err)
+	assert.True(schedule.Matches(time.Date(2018, time.March, 4, 0, 0, 0, 0, time.UTC)))
+	assert.False(schedule.Matches(time.Date(2018, time.March, 5, 0, 0, 0, 0, time.UTC)))
+	assert.Equal("@weekly", schedule.String())
+
+	// 7 is also Sunday
+	schedule, err = ParseCron("0 0 * * 5-7")
+	require.NoError(err)
+	assert.True(schedule.Matches(time.Date(2018, time.March, 4, 0, 0, 0, 0, time.UTC)))
+	assert.True(schedule.Matches(time.Date(2018, time.March, 3, 0, 0, 0, 0, time.UTC)))
+	assert.False(schedule.Matches(time.Date(2018, time.March, 5, 0, 0, 0, 0, time.UTC)))
+}
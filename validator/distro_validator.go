@@ -18,6 +18,8 @@ var distroSyntaxValidators = []distroValidator{
 	ensureValidSSHOptions,
 	ensureValidExpansions,
 	ensureStaticHostsAreNotSpawnable,
+	ensureValidContainerSettings,
+	ensureValidMaxConcurrentTasks,
 }
 
 // CheckDistro checks if the distro configuration syntax is valid. Returns
@@ -55,6 +57,66 @@ func ensureStaticHostsAreNotSpawnable(d *distro.Distro, s *evergreen.Settings) [
 	return nil
 }
 
+// ensureValidContainerSettings checks that a distro that runs tasks in
+// containers names an image and has sensible resource limits.
+func ensureValidContainerSettings(d *distro.Distro, s *evergreen.Settings) []ValidationError {
+	if d.Containers == nil {
+		return nil
+	}
+	errs := []ValidationError{}
+
+	if d.Containers.Image == "" {
+		errs = append(errs, ValidationError{
+			Message: fmt.Sprintf("distro '%s' must name the image to run tasks in", d.Id),
+			Level:   Error,
+		})
+	}
+	if d.Containers.Slots < 1 {
+		errs = append(errs, ValidationError{
+			Message: fmt.Sprintf("distro '%s' must run at least one container on each host", d.Id),
+			Level:   Error,
+		})
+	}
+	if d.Containers.CPUs < 0 || d.Containers.MemoryMB < 0 {
+		errs = append(errs, ValidationError{
+			Message: fmt.Sprintf("distro '%s' container limits cannot be negative", d.Id),
+			Level:   Error,
+		})
+	}
+	if d.IsWindows() {
+		errs = append(errs, ValidationError{
+			Message: fmt.Sprintf("windows distro '%s' cannot run tasks in containers", d.Id),
+			Level:   Error,
+		})
+	}
+
+	return errs
+}
+
+// ensureValidMaxConcurrentTasks checks that a distro whose agents run
+// several tasks at once does not also run tasks in containers, which
+// already gives each host several slots.
+func ensureValidMaxConcurrentTasks(d *distro.Distro, s *evergreen.Settings) []ValidationError {
+	if d.MaxConcurrentTasks < 0 {
+		return []ValidationError{
+			{
+				Message: fmt.Sprintf("distro '%s' cannot run a negative number of concurrent tasks", d.Id),
+				Level:   Error,
+			},
+		}
+	}
+	if d.MaxConcurrentTasks > 1 && d.UsesContainers() {
+		return []ValidationError{
+			{
+				Message: fmt.Sprintf("distro '%s' cannot set max concurrent tasks when it runs tasks in containers", d.Id),
+				Level:   Error,
+			},
+		}
+	}
+
+	return nil
+}
+
 // ensureHasRequiredFields check that the distro configuration has all the required fields
 func ensureHasRequiredFields(d *distro.Distro, s *evergreen.Settings) []ValidationError {
 	errs := []ValidationError{}
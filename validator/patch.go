@@ -1,9 +1,11 @@
 package validator
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 
+	"github.com/evergreen-ci/evergreen"
 	"github.com/evergreen-ci/evergreen/model"
 	"github.com/evergreen-ci/evergreen/model/patch"
 	"github.com/evergreen-ci/evergreen/thirdparty"
@@ -22,42 +24,98 @@ func GetPatchedProject(p *patch.Patch, githubOauthToken string) (*model.Project,
 	}
 
 	// try to get the remote project file data at the requested revision
-	var projectFileBytes []byte
-	projectFileURL := thirdparty.GetGithubFileURL(
-		projectRef.Owner,
-		projectRef.Repo,
-		projectRef.RemotePath,
-		p.Githash,
-	)
-	githubFile, err := thirdparty.GetGithubFile(githubOauthToken, projectFileURL)
-	if err != nil {
-		// if the project file doesn't exist, but our patch includes a project file,
-		// we try to apply the diff and proceed.
-		if !(p.ConfigChanged(projectRef.RemotePath) && thirdparty.IsFileNotFound(err)) {
-			// return an error if the github error is network/auth-related or we aren't patching the config
-			return nil, errors.Wrapf(err, "Could not get github file at %v", projectFileURL)
+	fetchConfig := func() ([]byte, error) {
+		projectFileURL := thirdparty.GetGithubFileURL(
+			projectRef.Owner,
+			projectRef.Repo,
+			projectRef.RemotePath,
+			p.Githash,
+		)
+		githubFile, err := thirdparty.GetGithubFile(githubOauthToken, projectFileURL)
+		if err != nil {
+			return nil, err
 		}
-	} else {
 		// we successfully got the project file in base64, so we decode it
-		projectFileBytes, err = base64.StdEncoding.DecodeString(githubFile.Content)
+		projectFileBytes, err := base64.StdEncoding.DecodeString(githubFile.Content)
 		if err != nil {
 			return nil, errors.Wrapf(err, "Could not decode github file at %v", projectFileURL)
 		}
+		return projectFileBytes, nil
 	}
 
-	project := &model.Project{}
+	return patchProject(p, projectRef, fetchConfig,
+		model.GithubIncludeFetcher(githubOauthToken, projectRef.Owner, projectRef.Repo, p.Githash))
+}
+
+// GetPatchedGitlabProject creates and validates the project of a patch of a
+// GitLab merge request, reading the project's configuration at the patch's
+// base revision through the GitLab API. Included files are read from the
+// same GitLab project; modules can't be included.
+func GetPatchedGitlabProject(ctx context.Context, p *patch.Patch, conf evergreen.GitlabConfig) (*model.Project, error) {
+	if p.Version != "" {
+		return nil, errors.Errorf("Patch %v already finalized", p.Version)
+	}
+	projectRef, err := model.FindOneProjectRef(p.Project)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	projectID := p.GitlabPatchData.ProjectID
+	fetchConfig := func() ([]byte, error) {
+		return thirdparty.GetGitlabFileContents(ctx, conf, projectID, projectRef.RemotePath, p.Githash)
+	}
+	fetchInclude := func(inc model.Include, module *model.Module) ([]byte, error) {
+		if module != nil {
+			return nil, errors.Errorf("can't include '%s' from module '%s' in a gitlab project", inc.FileName, module.Name)
+		}
+		return thirdparty.GetGitlabFileContents(ctx, conf, projectID, inc.FileName, p.Githash)
+	}
+
+	return patchProject(p, projectRef, fetchConfig, fetchInclude)
+}
+
+// patchProject applies the patch to the project's configuration, which is
+// read with fetchConfig, and to the files it includes, which are read with
+// fetchInclude.
+func patchProject(p *patch.Patch, projectRef *model.ProjectRef, fetchConfig func() ([]byte, error), fetchInclude model.IncludeFetcher) (*model.Project, error) {
+	projectFileBytes, err := fetchConfig()
+	if err != nil {
+		// if the project file doesn't exist, but our patch includes a project file,
+		// we try to apply the diff and proceed.
+		if !(p.ConfigChanged(projectRef.RemotePath) && thirdparty.IsFileNotFound(err)) {
+			// return an error if the error is network/auth-related or we aren't patching the config
+			return nil, errors.Wrapf(err, "Could not get project file '%s' at %s", projectRef.RemotePath, p.Githash)
+		}
+	}
 
 	// if the patched config exists, use that as the project file bytes.
+	configChanged := false
 	if p.PatchedConfig != "" {
 		projectFileBytes = []byte(p.PatchedConfig)
-	}
+	} else {
+		// apply remote configuration patch if needed
+		if p.ConfigChanged(projectRef.RemotePath) {
+			configChanged = true
+			projectFileBytes, err = model.MakePatchedFile(p, projectRef.RemotePath, string(projectFileBytes))
+			if err != nil {
+				return nil, errors.Wrapf(err, "Could not patch remote configuration file")
+			}
+		}
 
-	// apply remote configuration patch if needed
-	if p.ConfigChanged(projectRef.RemotePath) && p.PatchedConfig == "" {
-		project, err = model.MakePatchedConfig(p, projectRef.RemotePath, string(projectFileBytes))
+		// merge the included files, with the patch applied to the ones it changes
+		projectFileBytes, err = model.ResolveIncludes(projectFileBytes,
+			patchedIncludeFetcher(p, fetchInclude, &configChanged))
 		if err != nil {
-			return nil, errors.Wrapf(err, "Could not patch remote configuration file")
+			return nil, errors.WithStack(err)
 		}
+	}
+
+	project := &model.Project{}
+	if err = model.LoadProjectInto(projectFileBytes, projectRef.Identifier, project); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if configChanged {
 		// overwrite project fields with the project ref to disallow tracking a
 		// different project or doing other crazy things via config patches
 		verrs, err := CheckProjectSyntax(project)
@@ -72,11 +130,26 @@ func GetPatchedProject(p *patch.Patch, githubOauthToken string) (*model.Project,
 			}
 			return nil, errors.New(message)
 		}
-	} else {
-		// configuration is not patched
-		if err = model.LoadProjectInto(projectFileBytes, projectRef.Identifier, project); err != nil {
-			return nil, errors.WithStack(err)
-		}
 	}
 	return project, nil
 }
+
+// patchedIncludeFetcher returns an IncludeFetcher that reads the included
+// files of the project's repository at the patch's base revision with fetch,
+// and applies the patch to the ones it changes. It records whether the patch
+// changed any included file.
+func patchedIncludeFetcher(p *patch.Patch, fetch model.IncludeFetcher, changed *bool) model.IncludeFetcher {
+	return func(inc model.Include, module *model.Module) ([]byte, error) {
+		data, err := fetch(inc, module)
+		if module != nil || !p.ConfigChanged(inc.FileName) {
+			return data, err
+		}
+		// the patch may add the included file
+		if err != nil && !thirdparty.IsFileNotFound(err) {
+			return nil, err
+		}
+
+		*changed = true
+		return model.MakePatchedFile(p, inc.FileName, string(data))
+	}
+}
@@ -3,6 +3,7 @@ package validator
 import (
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -53,6 +54,10 @@ var projectSyntaxValidators = []projectValidator{
 	checkAllDependenciesSpec,
 	validateProjectTaskNames,
 	validateProjectTaskIdsAndTags,
+	validateConditions,
+	validateHangAnalysis,
+	validateStepbackMode,
+	validateTestSharding,
 }
 
 // Functions used to validate the semantics of a project configuration file.
@@ -579,6 +584,212 @@ func validatePluginCommands(project *model.Project) []ValidationError {
 	return errs
 }
 
+// validateConditions parses the `if:` conditions of the project's tasks and
+// commands, and checks the types of their values.
+func validateConditions(project *model.Project) []ValidationError {
+	errs := []ValidationError{}
+	check := func(where, condition string) {
+		for _, msg := range checkCondition(condition) {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("condition of %v in project '%v': %v", where, project.Identifier, msg),
+			})
+		}
+	}
+	checkCommands := func(section string, cmds []model.PluginCommandConf) {
+		for _, c := range cmds {
+			if c.Function != "" {
+				check(fmt.Sprintf("call to function '%v' in %v", c.Function, section), c.If)
+			} else {
+				check(fmt.Sprintf("command '%v' in %v", c.GetDisplayName(), section), c.If)
+			}
+		}
+	}
+
+	for funcName, commands := range project.Functions {
+		checkCommands(fmt.Sprintf("function '%v'", funcName), commands.List())
+	}
+	if project.Pre != nil {
+		checkCommands("pre", project.Pre.List())
+	}
+	if project.Post != nil {
+		checkCommands("post", project.Post.List())
+	}
+	if project.Timeout != nil {
+		checkCommands("timeout", project.Timeout.List())
+	}
+	for _, task := range project.Tasks {
+		check(fmt.Sprintf("task '%v'", task.Name), task.If)
+		checkCommands(fmt.Sprintf("task '%v'", task.Name), task.Commands)
+	}
+	return errs
+}
+
+// checkCondition parses a condition and checks the types of its values,
+// returning a message for each problem.
+func checkCondition(condition string) []string {
+	if strings.TrimSpace(condition) == "" {
+		return nil
+	}
+	node, err := model.ParseCondition(condition)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	msgs := []string{}
+	t := conditionType(node, &msgs)
+	if t != "" && t != model.ConditionBool {
+		msgs = append(msgs, fmt.Sprintf("condition is a %v, not a bool", t))
+	}
+	for i := range msgs {
+		msgs[i] = fmt.Sprintf("'%v': %v", condition, msgs[i])
+	}
+	return msgs
+}
+
+// conditionType returns the type of a node of a condition, adding a message
+// for each problem. The type is empty if it cannot be known because of an
+// earlier problem.
+func conditionType(node model.ConditionNode, msgs *[]string) model.ConditionType {
+	switch n := node.(type) {
+	case model.ConditionLiteral:
+		if _, ok := n.Value.(bool); ok {
+			return model.ConditionBool
+		}
+		return model.ConditionString
+	case model.ConditionExpansion:
+		return model.ConditionString
+	case model.ConditionVariable:
+		t, ok := model.ConditionVariables[n.Name]
+		if !ok {
+			names := []string{}
+			for name := range model.ConditionVariables {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			*msgs = append(*msgs, fmt.Sprintf("unknown variable '%v', the variables are %v",
+				n.Name, strings.Join(names, ", ")))
+		}
+		return t
+	case model.ConditionNegation:
+		if t := conditionType(n.Operand, msgs); t != "" && t != model.ConditionBool {
+			*msgs = append(*msgs, fmt.Sprintf("cannot negate '%v', which is a %v", n.Operand, t))
+		}
+		return model.ConditionBool
+	case model.ConditionBinary:
+		left, right := conditionType(n.Left, msgs), conditionType(n.Right, msgs)
+		if left == "" || right == "" {
+			return model.ConditionBool
+		}
+		switch n.Op {
+		case model.ConditionAnd, model.ConditionOr:
+			if left != model.ConditionBool || right != model.ConditionBool {
+				*msgs = append(*msgs, fmt.Sprintf("'%v' needs bools, not a %v and a %v", n, left, right))
+			}
+		case model.ConditionIn:
+			if left != model.ConditionString || right != model.ConditionList {
+				*msgs = append(*msgs, fmt.Sprintf("'%v' needs a string and a list, not a %v and a %v", n, left, right))
+			}
+		default:
+			if left != right || left == model.ConditionList {
+				*msgs = append(*msgs, fmt.Sprintf("cannot compare a %v to a %v in '%v'", left, right, n))
+				break
+			}
+			checkConditionValue(n.Left, n.Right, msgs)
+			checkConditionValue(n.Right, n.Left, msgs)
+		}
+		return model.ConditionBool
+	}
+	*msgs = append(*msgs, fmt.Sprintf("unknown condition node %T", node))
+	return ""
+}
+
+// checkConditionValue adds a message if a variable that can only take a
+// fixed set of values is compared to a string that is not one of them.
+func checkConditionValue(variable, value model.ConditionNode, msgs *[]string) {
+	v, ok := variable.(model.ConditionVariable)
+	if !ok {
+		return
+	}
+	literal, ok := value.(model.ConditionLiteral)
+	if !ok {
+		return
+	}
+	values, ok := model.ConditionVariableValues[v.Name]
+	if !ok {
+		return
+	}
+	if s, ok := literal.Value.(string); ok && !util.StringSliceContains(values, s) {
+		*msgs = append(*msgs, fmt.Sprintf("%v is never '%v', it is one of %v",
+			v.Name, s, strings.Join(values, ", ")))
+	}
+}
+
+// validateHangAnalysis ensures that the limit on the size of core dumps is
+// valid, and that the upload of the diagnostics is a valid s3.put command.
+func validateHangAnalysis(project *model.Project) []ValidationError {
+	errs := []ValidationError{}
+	conf := project.HangAnalysis
+	if conf == nil {
+		return errs
+	}
+
+	if conf.MaxCoreDumpMB < 0 {
+		errs = append(errs, ValidationError{
+			Message: fmt.Sprintf("project '%v' must have a non-negative 'max_core_dump_mb' for hang analysis",
+				project.Identifier),
+		})
+	}
+	if conf.Upload != nil {
+		factory, ok := command.GetCommandFactory("s3.put")
+		if !ok {
+			return append(errs, ValidationError{Message: "s3.put command is not registered"})
+		}
+		if err := factory().ParseParams(conf.UploadParams("hang_analysis.tgz", "task")); err != nil {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("project '%v' has an invalid hang analysis upload: %v",
+					project.Identifier, err),
+			})
+		}
+	}
+	return errs
+}
+
+// validateStepbackMode ensures that the project's stepback mode is one of the
+// supported modes.
+func validateStepbackMode(project *model.Project) []ValidationError {
+	if util.StringSliceContains(model.ValidStepbackModes, project.StepbackMode) {
+		return nil
+	}
+	return []ValidationError{{
+		Message: fmt.Sprintf("project '%v' has invalid stepback_mode '%v', must be '%v' or '%v'",
+			project.Identifier, project.StepbackMode, model.StepbackModeLinear, model.StepbackModeBisect),
+	}}
+}
+
+// validateTestSharding ensures that tasks which shard their tests have a
+// number of shards or a target time, and that neither is negative.
+func validateTestSharding(project *model.Project) []ValidationError {
+	errs := []ValidationError{}
+	for _, task := range project.Tasks {
+		conf := task.TestSharding
+		if conf == nil {
+			continue
+		}
+		if conf.Shards < 0 || conf.TargetSecs < 0 {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("task '%v' in project '%v' must have a non-negative "+
+					"'shards' and 'target_secs' for test sharding", task.Name, project.Identifier),
+			})
+		} else if conf.Shards == 0 && conf.TargetSecs == 0 {
+			errs = append(errs, ValidationError{
+				Message: fmt.Sprintf("task '%v' in project '%v' must have 'shards' or "+
+					"'target_secs' for test sharding", task.Name, project.Identifier),
+			})
+		}
+	}
+	return errs
+}
+
 // Ensures there aren't any duplicate task names for this project
 func validateProjectTaskNames(project *model.Project) []ValidationError {
 	errs := []ValidationError{}
@@ -1448,3 +1448,107 @@ func TestEnsureHasNecessaryBVFields(t *testing.T) {
 		})
 	})
 }
+
+func TestValidateConditions(t *testing.T) {
+	assert := assert.New(t)
+
+	project := &model.Project{
+		Identifier: "project",
+		Functions: map[string]*model.YAMLCommandSet{
+			"fn": {SingleCommand: &model.PluginCommandConf{Command: "shell.exec", If: `os == "linux"`}},
+		},
+		Tasks: []model.ProjectTask{
+			{
+				Name: "compile",
+				If:   `requester == "patch" || requester == "gitlab_mr" || requester == "git_tag" || ${force} == "true"`,
+				Commands: []model.PluginCommandConf{
+					{Function: "fn", If: `status == "success" && "nightly" in tags`},
+				},
+			},
+		},
+	}
+	assert.Empty(validateConditions(project))
+
+	for condition, msg := range map[string]string{
+		`requester == `:               "invalid condition",
+		`requester`:                   "condition is a string, not a bool",
+		`versoin == "1"`:              "unknown variable 'versoin'",
+		`tags == "linux"`:             "cannot compare a list to a string",
+		`variant in "linux"`:          "needs a string and a list",
+		`!os`:                         "cannot negate 'os', which is a string",
+		`requester == "pull_request"`: "requester is never 'pull_request'",
+		`"failure" != status`:         "status is never 'failure'",
+		`os == "linux" && "true"`:     "needs bools",
+	} {
+		project.Tasks[0].Commands[0].If = condition
+		errs := validateConditions(project)
+		if assert.Len(errs, 1, condition) {
+			assert.Contains(errs[0].Message, msg, condition)
+			assert.Contains(errs[0].Message, "call to function 'fn' in task 'compile'", condition)
+		}
+	}
+}
+
+func TestValidateHangAnalysis(t *testing.T) {
+	assert := assert.New(t)
+
+	project := &model.Project{Identifier: "project"}
+	assert.Empty(validateHangAnalysis(project))
+
+	project.HangAnalysis = &model.HangAnalysis{
+		CoreDumps:     true,
+		MaxCoreDumpMB: 512,
+		Upload: map[string]interface{}{
+			"aws_key":     "${aws_key}",
+			"aws_secret":  "${aws_secret}",
+			"bucket":      "hang-analysis",
+			"permissions": "public-read",
+		},
+	}
+	assert.Empty(validateHangAnalysis(project))
+
+	project.HangAnalysis.MaxCoreDumpMB = -1
+	delete(project.HangAnalysis.Upload, "aws_key")
+	errs := validateHangAnalysis(project)
+	if assert.Len(errs, 2) {
+		assert.Contains(errs[0].Message, "max_core_dump_mb")
+		assert.Contains(errs[1].Message, "aws_key cannot be blank")
+	}
+}
+
+func TestValidateStepbackMode(t *testing.T) {
+	assert := assert.New(t)
+
+	project := &model.Project{Identifier: "project"}
+	assert.Empty(validateStepbackMode(project))
+	project.StepbackMode = model.StepbackModeBisect
+	assert.Empty(validateStepbackMode(project))
+
+	project.StepbackMode = "binary"
+	errs := validateStepbackMode(project)
+	if assert.Len(errs, 1) {
+		assert.Contains(errs[0].Message, "stepback_mode")
+	}
+}
+
+func TestValidateTestSharding(t *testing.T) {
+	assert := assert.New(t)
+
+	project := &model.Project{
+		Identifier: "project",
+		Tasks: []model.ProjectTask{
+			{Name: "compile"},
+			{Name: "unit", TestSharding: &model.TestShardingConfig{Shards: 4}},
+			{Name: "integration", TestSharding: &model.TestShardingConfig{Shards: 8, TargetSecs: 600}},
+		},
+	}
+	assert.Empty(validateTestSharding(project))
+
+	project.Tasks[1].TestSharding.Shards = -1
+	project.Tasks[2].TestSharding = &model.TestShardingConfig{}
+	errs := validateTestSharding(project)
+	if assert.Len(errs, 2) {
+		assert.Contains(errs[0].Message, "non-negative")
+		assert.Contains(errs[1].Message, "'shards' or 'target_secs'")
+	}
+}